@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show which config file and profile are in use and manage backups",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "config:   %s\n", config.Path())
+		fmt.Fprintf(out, "dir:      %s\n", config.Dir())
+		fmt.Fprintf(out, "backups:  %s\n", config.BackupDir())
+		if name := config.ProfileName(); name != "" {
+			fmt.Fprintf(out, "profile:  %s\n", name)
+		}
+		if cfg, err := config.Load(); err == nil && len(cfg.Profiles) > 0 {
+			fmt.Fprintf(out, "profiles: %s\n", strings.Join(cfg.ProfileNames(), ", "))
+		}
+		return nil
+	},
+}
+
+var configBackupsCmd = &cobra.Command{
+	Use:   "backups",
+	Short: "List the copies of the config saved before each change, newest first",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		backups, err := config.Backups()
+		if err != nil {
+			return err
+		}
+		if len(backups) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "no backups yet; one is saved before every change to the config")
+			return nil
+		}
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "#\tSAVED\tFILE")
+		for i, b := range backups {
+			fmt.Fprintf(w, "%d\t%s\t%s\n", i+1, b.Time.Format(time.DateTime), filepath.Base(b.Path))
+		}
+		return w.Flush()
+	},
+}
+
+var configRollbackCmd = &cobra.Command{
+	Use:   "rollback [n|file]",
+	Short: "Restore a backup of the config (default: the newest, i.e. undo the last change)",
+	Long: `Restore the config from a backup. n is the number shown by 'blackbox config
+backups' (1 is the newest); a file name or path picks a backup directly.
+
+The config being replaced is backed up first, so running rollback again
+undoes the rollback.`,
+	Example: `  blackbox config rollback
+  blackbox config rollback 3
+  blackbox config rollback config-20260101T120000.000000000.json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		backups, err := config.Backups()
+		if err != nil {
+			return err
+		}
+		path := ""
+		switch {
+		case len(args) == 0 || isIndex(args[0]):
+			n := 1
+			if len(args) == 1 {
+				n, _ = strconv.Atoi(args[0])
+			}
+			if n < 1 || n > len(backups) {
+				return fmt.Errorf("no backup #%d (%d available, see 'blackbox config backups')", n, len(backups))
+			}
+			path = backups[n-1].Path
+		case filepath.Base(args[0]) == args[0]:
+			path = filepath.Join(config.BackupDir(), args[0])
+		default:
+			path = args[0]
+		}
+
+		cfg, err := config.Rollback(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "restored %s (%d endpoints, %d alert rules)\n", filepath.Base(path), len(cfg.Endpoints), len(cfg.Alerts))
+		return nil
+	},
+}
+
+func isIndex(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
+
+func init() {
+	configCmd.AddCommand(configBackupsCmd, configRollbackCmd)
+	rootCmd.AddCommand(configCmd)
+}
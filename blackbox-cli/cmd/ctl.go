@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var ctlFlags struct {
+	socket string
+}
+
+var ctlCmd = &cobra.Command{
+	Use:   "ctl <command> [args]",
+	Short: "Drive a running dashboard started with --control",
+	Long: `Send one command to a dashboard's control socket:
+
+  switch <endpoint>   select an endpoint by name
+  pause | resume      freeze or unfreeze the data panel and charts
+  toggle-pause
+  export [file]       current endpoint's history as JSON (to file, or stdout)
+  export-models [file]
+                      its history per model, as a JSON object keyed by model ID
+  status              selected endpoint, pause state and history length`,
+	Example: `  blackbox --control &
+  blackbox ctl switch gpu-a
+  blackbox ctl export history.json
+  blackbox ctl export-models models.json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// The dashboard may run in another directory
+		if (args[0] == "export" || args[0] == "export-models") && len(args) > 1 {
+			abs, err := filepath.Abs(args[1])
+			if err != nil {
+				return err
+			}
+			args[1] = abs
+		}
+		conn, err := net.DialTimeout("unix", ctlFlags.socket, 2*time.Second)
+		if err != nil {
+			return fmt.Errorf("no dashboard listening on %s (start it with --control): %w", ctlFlags.socket, err)
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(15 * time.Second))
+		if _, err := fmt.Fprintln(conn, strings.Join(args, " ")); err != nil {
+			return err
+		}
+		reply, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil && reply == "" {
+			return fmt.Errorf("failed to read reply: %w", err)
+		}
+		reply = strings.TrimSpace(reply)
+		if msg, ok := strings.CutPrefix(reply, "error: "); ok {
+			return fmt.Errorf("%s", msg)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), strings.TrimSpace(strings.TrimPrefix(reply, "ok")))
+		return nil
+	},
+}
+
+// addControlFlag registers --control on a command that launches the dashboard.
+// A bare --control uses the default socket path.
+func addControlFlag(c *cobra.Command) {
+	c.Flags().StringVar(&rf.control, "control", "", "listen for 'blackbox ctl' commands on a unix socket (bare flag: "+ui.DefaultControlSocket()+")")
+	c.Flags().Lookup("control").NoOptDefVal = ui.DefaultControlSocket()
+}
+
+func init() {
+	ctlCmd.Flags().StringVar(&ctlFlags.socket, "socket", ui.DefaultControlSocket(), "dashboard control socket")
+	rootCmd.AddCommand(ctlCmd)
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var dashboardFlags struct {
+	kiosk bool
+	dwell time.Duration
+	grid  bool
+}
+
+var dashboardCmd = &cobra.Command{
+	Use:   "dashboard",
+	Short: "Interactive dashboard (same as running blackbox without a command)",
+	Example: `  blackbox dashboard --kiosk --dwell 30s
+  blackbox dashboard --kiosk --grid`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !dashboardFlags.kiosk && cmd.Flags().Changed("grid") {
+			return fmt.Errorf("--grid requires --kiosk")
+		}
+		return runDashboard(func(m *ui.DashboardModel) {
+			m.SetCycleDwell(dashboardFlags.dwell)
+			if dashboardFlags.kiosk {
+				m.SetKiosk(dashboardFlags.dwell, dashboardFlags.grid)
+			}
+		})
+	},
+}
+
+func init() {
+	dashboardCmd.Flags().BoolVar(&dashboardFlags.kiosk, "kiosk", false, "read-only fullscreen display: no key bindings except ctrl+c, rotates through endpoints")
+	durationVar(dashboardCmd.Flags(), &dashboardFlags.dwell, "dwell", 15*time.Second, time.Second, "time on each endpoint before rotating (kiosk and carousel)")
+	dashboardCmd.Flags().BoolVar(&dashboardFlags.grid, "grid", false, "show the fleet grid instead of rotating (kiosk)")
+	dashboardCmd.Flags().Float64Var(&rf.smooth, "smooth-alpha", 0.3, "EMA weight of the newest sample for smoothed charts (0-1, toggle with S)")
+	dashboardCmd.Flags().StringVar(&rf.groupBy, "group-by", "", "group the endpoints panel by this tag (e.g. env; cycle with T)")
+	dashboardCmd.Flags().StringVar(&rf.theme, "theme", "", "color theme for this run: dark, light, high-contrast, solarized, mono or one of the config's ui.themes (default: ui.theme)")
+	addControlFlag(dashboardCmd)
+	rootCmd.AddCommand(dashboardCmd)
+}
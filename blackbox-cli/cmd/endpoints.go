@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/client"
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var endpointsAddFlags struct {
+	typ     string
+	noProbe bool
+}
+
+var endpointsCmd = &cobra.Command{
+	Use:   "endpoints",
+	Short: "Manage the endpoints in the config",
+}
+
+var endpointsAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add the endpoint at --url to the config, after checking that it answers",
+	Long: `Add the endpoint at --url and --endpoint to the config, with --timeout and
+--proxy. Before it's saved, one snapshot is fetched and checked against the
+schema, and its latency, VRAM, models and the optional APIs the server
+answers are printed, so a typo in the URL or path shows up right away.
+--no-probe saves it without checking, e.g. for a host that is down for now.`,
+	Example: `  blackbox endpoints add gpu01 --url http://gpu01:6767
+  blackbox endpoints add vllm1 --url http://vllm1:8000 --type vllm
+  blackbox endpoints add lab --url http://lab:6767 --no-probe`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ep := config.Endpoint{
+			Name:     args[0],
+			BaseURL:  rf.baseURL,
+			Endpoint: rf.endpoint,
+			Timeout:  rf.timeout.String(),
+			Proxy:    rf.proxy,
+		}
+		switch endpointsAddFlags.typ {
+		case "", config.EndpointTypeBlackbox:
+		case config.EndpointTypeVLLM:
+			ep.Type = config.EndpointTypeVLLM
+			if !cmd.Flags().Changed("endpoint") {
+				ep.Endpoint = client.DefaultVLLMMetricsPath
+			}
+		case config.EndpointTypeLocal:
+			ep.Type = config.EndpointTypeLocal
+			ep.BaseURL, ep.Endpoint = "", ""
+		default:
+			return fmt.Errorf("invalid --type %q (want blackbox, vllm or local)", endpointsAddFlags.typ)
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		// Checked before probing too, so a taken name doesn't wait on the probe
+		for _, e := range cfg.Endpoints {
+			if e.Name == ep.Name {
+				return fmt.Errorf("endpoint with name '%s' already exists", ep.Name)
+			}
+		}
+
+		out := cmd.OutOrStdout()
+		if !endpointsAddFlags.noProbe {
+			fmt.Fprintf(out, "checking %s%s... ", ep.BaseURL, ep.Endpoint)
+			ctx, cancel := context.WithTimeout(cmd.Context(), rf.timeout+5*time.Second)
+			defer cancel()
+			p, err := client.ProbeEndpoint(ctx, client.FromEndpoint(ep, rf.timeout))
+			if err != nil {
+				fmt.Fprintln(out, "failed")
+				return fmt.Errorf("%s didn't answer: %w (check --url and --endpoint, or pass --no-probe to add it anyway)", ep.Name, err)
+			}
+			fmt.Fprintln(out, "ok, "+p.Summary())
+		}
+		if err := config.AddEndpoint(cfg, ep); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "added endpoint %s to %s\n", ep.Name, config.Path())
+		return nil
+	},
+}
+
+func init() {
+	endpointsAddCmd.Flags().StringVar(&endpointsAddFlags.typ, "type", "", "endpoint type: blackbox (default), vllm or local")
+	endpointsAddCmd.Flags().BoolVar(&endpointsAddFlags.noProbe, "no-probe", false, "save the endpoint without checking that it answers")
+	endpointsCmd.AddCommand(endpointsAddCmd)
+	rootCmd.AddCommand(endpointsCmd)
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Lower bounds for duration flags; polling faster than minInterval only loads the server
+const (
+	minTimeout  = 100 * time.Millisecond
+	minInterval = 500 * time.Millisecond
+)
+
+// durationFlag is a duration flag that also takes bare seconds ("--interval 5")
+// and rejects values below min when the flags are parsed
+type durationFlag struct {
+	value *time.Duration
+	min   time.Duration
+}
+
+func durationVar(fs *pflag.FlagSet, p *time.Duration, name string, value, min time.Duration, usage string) {
+	*p = value
+	fs.Var(&durationFlag{value: p, min: min}, name, usage)
+}
+
+func (f *durationFlag) Set(s string) error {
+	d, err := utils.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	if d < f.min {
+		return fmt.Errorf("%s is below the minimum of %s", d, f.min)
+	}
+	*f.value = d
+	return nil
+}
+
+func (f *durationFlag) String() string { return f.value.String() }
+func (f *durationFlag) Type() string   { return "duration" }
+
+// envFlags can also be set with BLACKBOX_<NAME>, e.g. BLACKBOX_TIMEOUT=5 or
+// BLACKBOX_LOG_FILE=/tmp/bb.log. A flag given on the command line wins.
+var envFlags = []string{"url", "endpoint", "timeout", "interval", "proxy", "rate-limit", "debug", "log-file", "trace-http", "otel-endpoint", "user-agent", "dwell", "config", "portable", "profile", "select"}
+
+func envName(flag string) string {
+	return "BLACKBOX_" + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
+
+// applyEnv fills unset flags of cmd from the environment, validating them like flags
+func applyEnv(cmd *cobra.Command) error {
+	for _, name := range envFlags {
+		v := os.Getenv(envName(name))
+		if v == "" {
+			continue
+		}
+		f := cmd.Flags().Lookup(name)
+		if f == nil || f.Changed {
+			continue
+		}
+		if err := f.Value.Set(v); err != nil {
+			return fmt.Errorf("invalid %s=%q: %w", envName(name), v, err)
+		}
+	}
+	return nil
+}
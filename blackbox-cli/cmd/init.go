@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/maxdcmn/blackbox-cli/internal/service"
+	"github.com/spf13/cobra"
+)
+
+var initFlags struct {
+	sinks   []string
+	addr    string
+	user    string
+	env     []string
+	format  string
+	exec    string
+	output  string
+	install bool
+}
+
+// serviceModes are the long-running commands init can install
+var serviceModes = []string{"exporter", "serve-ui"}
+
+var initCmd = &cobra.Command{
+	Use:   "init [exporter|serve-ui] [-- extra args]",
+	Short: "Generate (and optionally install) a systemd unit or launchd plist for exporter or serve-ui",
+	Long: `Generate a service definition that runs 'blackbox exporter' or 'blackbox serve-ui'
+in the background, with the right binary path, user, HOME (where the config is read
+from) and environment. Arguments after -- are appended to the command line.
+
+Run from a terminal without a mode or sinks, init asks for them. The unit is printed
+unless --output or --install is given; --install writes it to /etc/systemd/system
+(run with sudo) or ~/Library/LaunchAgents and prints the commands that start it.`,
+	Example: `  sudo blackbox init exporter --sink mqtt://broker:1883 --install
+  blackbox init serve-ui --addr 0.0.0.0:8787 --env HTTPS_PROXY=http://proxy:3128 -o blackbox-serve-ui.service
+  blackbox init exporter --sink nats://nats:4222 -- --interval 5 --log-file /var/log/blackbox.log`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		extra := []string{}
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			args, extra = args[:dash], args[dash:]
+		}
+		if len(args) > 1 {
+			return fmt.Errorf("expected one mode (%s), got %q", strings.Join(serviceModes, " or "), args)
+		}
+
+		var in *bufio.Reader
+		if isatty.IsTerminal(os.Stdin.Fd()) {
+			in = bufio.NewReader(os.Stdin)
+		}
+		out := cmd.ErrOrStderr()
+
+		mode := ""
+		if len(args) == 1 {
+			mode = args[0]
+		} else if in != nil {
+			var err error
+			if mode, err = ask(in, out, "Mode ("+strings.Join(serviceModes, "/")+")", serviceModes[0]); err != nil {
+				return err
+			}
+		} else {
+			return fmt.Errorf("mode required: blackbox init %s", strings.Join(serviceModes, "|"))
+		}
+
+		spec := service.Spec{Name: mode, Env: map[string]string{}}
+		switch mode {
+		case "exporter":
+			sinks := initFlags.sinks
+			for in != nil && len(initFlags.sinks) == 0 {
+				s, err := ask(in, out, "Sink URL (mqtt://, nats://, kafka://; empty to finish)", "")
+				if err != nil {
+					return err
+				}
+				if s == "" {
+					break
+				}
+				sinks = append(sinks, s)
+			}
+			if len(sinks) == 0 {
+				return fmt.Errorf("exporter needs at least one --sink")
+			}
+			spec.Args = []string{"exporter"}
+			for _, s := range sinks {
+				if _, err := url.Parse(s); err != nil {
+					return fmt.Errorf("invalid --sink %q: %w", s, err)
+				}
+				spec.Args = append(spec.Args, "--sink", s)
+			}
+		case "serve-ui":
+			// The service user's config may not opt in, so the unit does
+			spec.Args = []string{"serve-ui", "--enable-experimental"}
+			if initFlags.addr != "" {
+				spec.Args = append(spec.Args, "--addr", initFlags.addr)
+			}
+		default:
+			return fmt.Errorf("unknown mode %q (expected %s)", mode, strings.Join(serviceModes, " or "))
+		}
+		spec.Args = append(spec.Args, extra...)
+
+		for _, kv := range initFlags.env {
+			k, v, ok := strings.Cut(kv, "=")
+			if !ok || k == "" {
+				return fmt.Errorf("invalid --env %q (expected KEY=VALUE)", kv)
+			}
+			spec.Env[k] = v
+		}
+
+		exe, err := serviceExecutable()
+		if err != nil {
+			return err
+		}
+		spec.Exec = exe
+
+		format := initFlags.format
+		if format == "" {
+			format = service.DefaultFormat()
+		}
+		if err := serviceUser(&spec, format); err != nil {
+			return err
+		}
+
+		unit, err := spec.Render(format)
+		if err != nil {
+			return err
+		}
+
+		path := initFlags.output
+		install := initFlags.install
+		if path == "" && !install && in != nil {
+			answer, err := ask(in, out, "Install to "+spec.InstallPath(format)+"? [y/N]", "")
+			if err != nil {
+				return err
+			}
+			install = strings.EqualFold(answer, "y") || strings.EqualFold(answer, "yes")
+		}
+		if install {
+			path = spec.InstallPath(format)
+		}
+		if path == "" {
+			fmt.Fprint(cmd.OutOrStdout(), unit)
+			return nil
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+		}
+		// 0600: sink URLs and --env values may carry credentials
+		if err := os.WriteFile(path, []byte(unit), 0600); err != nil {
+			if os.IsPermission(err) && format == service.FormatSystemd {
+				return fmt.Errorf("failed to write %s: %w (run with sudo, or use -o to write elsewhere)", path, err)
+			}
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		fmt.Fprintf(out, "wrote %s\n", path)
+		if install {
+			fmt.Fprintln(out, "start it with:")
+			for _, step := range spec.NextSteps(format) {
+				fmt.Fprintln(out, "  "+step)
+			}
+		}
+		return nil
+	},
+}
+
+// serviceExecutable is --exec or the running binary, symlinks resolved
+func serviceExecutable() (string, error) {
+	if initFlags.exec != "" {
+		return filepath.Abs(initFlags.exec)
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the blackbox binary (pass --exec): %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	if strings.Contains(exe, "go-build") {
+		return "", fmt.Errorf("%s is a temporary 'go run' build; pass --exec with the installed binary", exe)
+	}
+	return exe, nil
+}
+
+// serviceUser fills in the user and HOME: --user, else whoever ran sudo,
+// else the current user. launchd agents always run as the current user.
+func serviceUser(spec *service.Spec, format string) error {
+	name := initFlags.user
+	if name == "" {
+		name = os.Getenv("SUDO_USER")
+	}
+	var u *user.User
+	var err error
+	if name == "" || format == service.FormatLaunchd {
+		u, err = user.Current()
+	} else {
+		u, err = user.Lookup(name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up service user: %w", err)
+	}
+	if format == service.FormatSystemd {
+		spec.User = u.Username
+	}
+	spec.Home = u.HomeDir
+	return nil
+}
+
+// ask prints question and reads one line, returning def for an empty answer
+func ask(in *bufio.Reader, out io.Writer, question, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(out, "%s: ", question)
+	}
+	line, err := in.ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("no answer: %w", err)
+	}
+	if line = strings.TrimSpace(line); line == "" {
+		return def, nil
+	}
+	return line, nil
+}
+
+func init() {
+	initCmd.Flags().StringArrayVar(&initFlags.sinks, "sink", nil, "exporter sink URL, repeatable")
+	initCmd.Flags().StringVar(&initFlags.addr, "addr", "", "serve-ui listen address (default: serve-ui's own)")
+	initCmd.Flags().StringVar(&initFlags.user, "user", "", "user the systemd service runs as (default: $SUDO_USER or the current user)")
+	initCmd.Flags().StringArrayVar(&initFlags.env, "env", nil, "KEY=VALUE set in the service environment, repeatable (e.g. BLACKBOX_LOG_FILE, HTTPS_PROXY)")
+	initCmd.Flags().StringVar(&initFlags.format, "format", "", "systemd or launchd (default: launchd on macOS, systemd elsewhere)")
+	initCmd.Flags().StringVar(&initFlags.exec, "exec", "", "path to the blackbox binary the service runs (default: this one)")
+	initCmd.Flags().StringVarP(&initFlags.output, "output", "o", "", "write the unit to this file instead of stdout")
+	initCmd.Flags().BoolVar(&initFlags.install, "install", false, "write the unit to the system location and print how to start it")
+	rootCmd.AddCommand(initCmd)
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/mattn/go-isatty"
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+	"github.com/maxdcmn/blackbox-cli/internal/keyring"
+	"github.com/spf13/cobra"
+)
+
+var keyringCmd = &cobra.Command{
+	Use:   "keyring",
+	Short: "Keep endpoint tokens and HF tokens in the OS keyring instead of the config",
+	Long: `Secrets stored with 'blackbox keyring set <name>' go to the macOS Keychain,
+the Secret Service (GNOME Keyring, KWallet) on Linux or the Windows Credential
+Manager. Reference one from the config as "keyring:<name>" in place of an
+endpoint header value or "hf_token"; it is looked up when the endpoint is used.
+
+For configs kept in a dotfile repo, 'blackbox keyring encrypt' turns a secret
+into an "age:<base64>" value that is safe to commit. It's decrypted when used,
+with the age identity file from "age_identity" in the config, $BLACKBOX_AGE_IDENTITY,
+or age.key next to the config.`,
+	Example: `  blackbox keyring set prod-token
+  # then in the config: "headers": {"Authorization": "keyring:prod-token"}
+  age-keygen -o ~/.config/blackbox/age.key
+  blackbox keyring encrypt   # prints age:..., for "hf_token" or a header value`,
+}
+
+var keyringEncryptFlags struct {
+	recipients []string
+}
+
+var keyringEncryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt a secret, read from a prompt or stdin, as an age: value for the config",
+	Long: `Encrypt a secret with age and print it as an "age:<base64>" value to paste
+into the config in place of "hf_token" or a header value. It's encrypted to
+the --recipient public keys, or by default to the identity file that will
+decrypt it.`,
+	Example: `  blackbox keyring encrypt
+  echo "$HF_TOKEN" | blackbox keyring encrypt -r age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		recipients := keyringEncryptFlags.recipients
+		if len(recipients) == 0 {
+			if _, err := config.Load(); err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			path := keyring.AgeIdentity()
+			if path == "" {
+				return fmt.Errorf("no age identity to encrypt to: pass --recipient, set age_identity in the config or %s", keyring.AgeIdentityEnv)
+			}
+			var err error
+			if recipients, err = keyring.AgeRecipients(path); err != nil {
+				return err
+			}
+		}
+		secret, err := readSecret(cmd, "age")
+		if err != nil {
+			return err
+		}
+		if secret == "" {
+			return errors.New("empty secret, nothing encrypted")
+		}
+		value, err := keyring.EncryptAge(secret, recipients)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), value)
+		return nil
+	},
+}
+
+var keyringSetCmd = &cobra.Command{
+	Use:   "set <name>",
+	Short: "Store a secret, read from a prompt or stdin",
+	Example: `  blackbox keyring set hf
+  vault read -field=token secret/hf | blackbox keyring set hf`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		secret, err := readSecret(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		if secret == "" {
+			return errors.New("empty secret, nothing stored")
+		}
+		if err := keyring.Set(args[0], secret); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "stored %q; reference it in the config as \"keyring:%s\"\n", args[0], args[0])
+		return nil
+	},
+}
+
+var keyringDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Remove a secret from the keyring",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := keyring.Delete(args[0]); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "deleted %q\n", args[0])
+		return nil
+	},
+}
+
+var keyringCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "List the keyring references and age: values in the config and whether each resolves",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		type ref struct{ endpoint, field, name, value string }
+		var refs []ref
+		add := func(endpoint, field, value string) {
+			if name, ok := keyring.Ref(value); ok {
+				refs = append(refs, ref{endpoint, field, name, value})
+			} else if keyring.IsAge(value) {
+				refs = append(refs, ref{endpoint, field, "(age)", value})
+			}
+		}
+		for _, ep := range cfg.AllEndpoints() {
+			add(ep.Name, "hf_token", ep.HFToken)
+			keys := make([]string, 0, len(ep.Headers))
+			for k := range ep.Headers {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				add(ep.Name, "headers."+k, ep.Headers[k])
+			}
+		}
+		if len(refs) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "no keyring:<name> references or age: values in the config")
+			return nil
+		}
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ENDPOINT\tFIELD\tSECRET\tSTATUS")
+		missing := 0
+		for _, r := range refs {
+			status := "ok"
+			if _, err := keyring.Resolve(r.value); err != nil {
+				status = err.Error()
+				missing++
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.endpoint, r.field, r.name, status)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		if missing > 0 {
+			return fmt.Errorf("%d of %d references don't resolve", missing, len(refs))
+		}
+		return nil
+	},
+}
+
+// readSecret prompts for the secret without echo on a terminal, or reads
+// the first line of stdin otherwise
+func readSecret(cmd *cobra.Command, name string) (string, error) {
+	if isatty.IsTerminal(os.Stdin.Fd()) {
+		fmt.Fprintf(cmd.ErrOrStderr(), "secret for %q: ", name)
+		b, err := term.ReadPassword(os.Stdin.Fd())
+		fmt.Fprintln(cmd.ErrOrStderr())
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret: %w", err)
+		}
+		return strings.TrimSpace(string(b)), nil
+	}
+	line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read secret from stdin: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func init() {
+	keyringEncryptCmd.Flags().StringArrayVarP(&keyringEncryptFlags.recipients, "recipient", "r", nil, "age public key to encrypt to (repeatable; default: the configured identity's)")
+	keyringCmd.AddCommand(keyringSetCmd, keyringDeleteCmd, keyringCheckCmd, keyringEncryptCmd)
+	rootCmd.AddCommand(keyringCmd)
+}
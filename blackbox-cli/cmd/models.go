@@ -5,9 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"time"
 
 	"github.com/maxdcmn/blackbox-cli/internal/client"
+	"github.com/maxdcmn/blackbox-cli/internal/config"
 	"github.com/spf13/cobra"
 )
 
@@ -15,19 +15,45 @@ var modelsCmd = &cobra.Command{
 	Use:   "models",
 	Short: "List all deployed models",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		timeout, err := time.ParseDuration(rf.timeout)
-		if err != nil {
-			return fmt.Errorf("invalid --timeout: %w", err)
+		if modelsSchema {
+			return printSchema("models")
 		}
 
-		c := client.New(rf.baseURL, rf.endpoint, timeout)
-		ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
-		defer cancel()
+		timeout := rf.timeout
 
-		models, err := c.ListModels(ctx)
-		if err != nil {
+		switch modelsFlags.status {
+		case "", client.ModelStatusRunning, client.ModelStatusStopped:
+		default:
+			return fmt.Errorf("invalid --status %q (expected running or stopped)", modelsFlags.status)
+		}
+
+		c := client.New(rf.baseURL, rf.endpoint, timeout, flagClientOptions()...)
+		opts := client.ListModelsOptions{Offset: modelsFlags.offset, Status: modelsFlags.status, Sort: modelsFlags.sort}
+		if modelsFlags.limit > 0 {
+			opts.Limit = min(modelsFlags.limit, client.DefaultModelsPageSize)
+		}
+
+		// Each page gets its own timeout so long listings don't starve
+		models := &client.ModelsResponse{Models: []client.DeployedModel{}}
+		it := client.NewModelIterator(c, opts)
+		for {
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			ok := it.Next(ctx)
+			cancel()
+			if !ok || (modelsFlags.limit > 0 && len(models.Models) >= modelsFlags.limit) {
+				break
+			}
+			models.Models = append(models.Models, it.Model())
+		}
+		if err := it.Err(); err != nil {
 			return err
 		}
+		if it.Truncated() {
+			fmt.Fprintf(os.Stderr, "warning: stopped after %d pages; narrow the listing with --status or --offset\n", client.MaxModelPages)
+		}
+		if p := it.Page(); p != nil {
+			models.Total, models.Running, models.MaxAllowed = p.Total, p.Running, p.MaxAllowed
+		}
 
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
@@ -40,12 +66,12 @@ var spindownCmd = &cobra.Command{
 	Short: "Stop and remove a deployed model",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		timeout, err := time.ParseDuration(rf.timeout)
-		if err != nil {
-			return fmt.Errorf("invalid --timeout: %w", err)
+		if err := checkWritable("spindown"); err != nil {
+			return err
 		}
+		timeout := rf.timeout
 
-		c := client.New(rf.baseURL, rf.endpoint, timeout)
+		c := client.New(rf.baseURL, rf.endpoint, timeout, flagClientOptions()...)
 		ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
 		defer cancel()
 
@@ -69,12 +95,12 @@ var optimizeCmd = &cobra.Command{
 	Use:   "optimize",
 	Short: "Optimize GPU utilization by restarting overallocated models",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		timeout, err := time.ParseDuration(rf.timeout)
-		if err != nil {
-			return fmt.Errorf("invalid --timeout: %w", err)
+		if err := checkWritable("optimize"); err != nil {
+			return err
 		}
+		timeout := rf.timeout
 
-		c := client.New(rf.baseURL, rf.endpoint, timeout)
+		c := client.New(rf.baseURL, rf.endpoint, timeout, flagClientOptions()...)
 		ctx, cancel := context.WithTimeout(cmd.Context(), timeout*5)
 		defer cancel()
 
@@ -99,7 +125,33 @@ var optimizeCmd = &cobra.Command{
 	},
 }
 
+// checkWritable refuses action when the config marks the endpoint at --url read_only
+func checkWritable(action string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if ep, ok := cfg.ReadOnlyAt(rf.baseURL); ok {
+		return fmt.Errorf("refusing to %s: %s is endpoint %q, which is read_only in %s", action, rf.baseURL, ep.Name, config.Path())
+	}
+	return nil
+}
+
+var modelsSchema bool
+
+var modelsFlags struct {
+	offset int
+	limit  int
+	status string
+	sort   string
+}
+
 func init() {
+	modelsCmd.Flags().BoolVar(&modelsSchema, "schema", false, "print the JSON Schema of the output and exit")
+	modelsCmd.Flags().IntVar(&modelsFlags.offset, "offset", 0, "skip this many models")
+	modelsCmd.Flags().IntVar(&modelsFlags.limit, "limit", 0, "list at most this many models (0: all, fetched in pages)")
+	modelsCmd.Flags().StringVar(&modelsFlags.status, "status", "", "only running or stopped models")
+	modelsCmd.Flags().StringVar(&modelsFlags.sort, "sort", "", "sort by model_id, status or vram; prefix - for descending")
 	rootCmd.AddCommand(modelsCmd)
 	rootCmd.AddCommand(spindownCmd)
 	rootCmd.AddCommand(optimizeCmd)
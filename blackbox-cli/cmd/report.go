@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/client"
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+	"github.com/maxdcmn/blackbox-cli/internal/export"
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+	"github.com/maxdcmn/blackbox-cli/internal/usage"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var reportFlags struct {
+	format  string
+	output  string
+	window  time.Duration
+	samples int
+	every   time.Duration
+}
+
+type endpointReport struct {
+	Name       string                    `json:"name"`
+	BaseURL    string                    `json:"base_url"`
+	Error      string                    `json:"error,omitempty"`
+	Aggregated *model.AggregatedSnapshot `json:"aggregated,omitempty"`
+	History    []reportSample            `json:"history,omitempty"`
+}
+
+type reportSample struct {
+	Time               time.Time `json:"time"`
+	AllocatedVRAMBytes int64     `json:"allocated_vram_bytes"`
+	UsedKVCacheBytes   int64     `json:"used_kv_cache_bytes"`
+	PrefixCacheHitRate float64   `json:"prefix_cache_hit_rate"`
+}
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Collect aggregated stats for all configured endpoints (JSON, xlsx or HTML)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		timeout := rf.timeout
+		if reportFlags.format != "json" && reportFlags.format != "xlsx" && reportFlags.format != "html" {
+			return fmt.Errorf("invalid --format %q (expected json, xlsx or html)", reportFlags.format)
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		endpoints, err := selectEndpoints(cfg)
+		if err != nil {
+			return err
+		}
+
+		reports := make([]endpointReport, len(endpoints))
+		clients := make([]client.MetricsClient, len(endpoints))
+		for i, ep := range endpoints {
+			reports[i] = endpointReport{Name: utils.Redact(ep.Name), BaseURL: utils.RedactRawURL(ep.BaseURL)}
+			clients[i] = client.FromEndpoint(ep, timeout)
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout+reportFlags.window)
+			agg, err := clients[i].AggregatedSnapshot(ctx, int(reportFlags.window/time.Second))
+			cancel()
+			if err != nil {
+				reports[i].Error = utils.Redact(err.Error())
+				continue
+			}
+			for j := range agg.Models {
+				agg.Models[j].ModelID = utils.Redact(agg.Models[j].ModelID)
+			}
+			reports[i].Aggregated = agg
+		}
+
+		for n := 0; n < reportFlags.samples; n++ {
+			if n > 0 {
+				select {
+				case <-cmd.Context().Done():
+					return cmd.Context().Err()
+				case <-time.After(reportFlags.every):
+				}
+			}
+			for i, c := range clients {
+				if reports[i].Error != "" {
+					continue
+				}
+				ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+				snap, err := c.Snapshot(ctx)
+				cancel()
+				if err != nil {
+					continue
+				}
+				reports[i].History = append(reports[i].History, reportSample{
+					Time:               time.Now(),
+					AllocatedVRAMBytes: snap.AllocatedVRAMBytes,
+					UsedKVCacheBytes:   snap.UsedKVCacheBytes,
+					PrefixCacheHitRate: snap.PrefixCacheHitRate,
+				})
+			}
+		}
+
+		if reportFlags.format == "json" {
+			out := os.Stdout
+			if reportFlags.output != "" {
+				f, err := os.Create(reportFlags.output)
+				if err != nil {
+					return fmt.Errorf("failed to create output: %w", err)
+				}
+				defer f.Close()
+				out = f
+			}
+			enc := json.NewEncoder(out)
+			enc.SetIndent("", "  ")
+			return enc.Encode(reports)
+		}
+
+		path := reportFlags.output
+		if path == "" {
+			path = fmt.Sprintf("blackbox-report-%s.%s", time.Now().Format("20060102-150405"), reportFlags.format)
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create output: %w", err)
+		}
+		defer f.Close()
+
+		sheets := make([]export.Sheet, len(reports))
+		for i, r := range reports {
+			sheets[i] = reportSheet(r)
+		}
+		if reportFlags.format == "html" {
+			// Each endpoint's daily peaks over the past year, as the dashboard recorded them
+			store := usage.Open(usage.Path())
+			sections := make([]export.Section, len(sheets))
+			for i, ep := range endpoints {
+				sections[i].Sheet = sheets[i]
+				if days := store.Days(ep.Name); len(days) > 0 {
+					for _, metric := range usage.Metrics {
+						if cal := usage.NewCalendar(days, metric, time.Now(), 53); cal.Busiest() >= 0 {
+							sections[i].Calendars = append(sections[i].Calendars, cal)
+						}
+					}
+				}
+			}
+			err = export.WriteHTML(f, "Blackbox report", sections)
+		} else {
+			err = export.WriteXLSX(f, sheets)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stderr, "wrote", path)
+		return nil
+	},
+}
+
+func reportSheet(r endpointReport) export.Sheet {
+	rows := [][]interface{}{
+		{"Endpoint", r.Name},
+		{"Base URL", r.BaseURL},
+	}
+	if r.Error != "" {
+		rows = append(rows, []interface{}{"Error", r.Error})
+		return export.Sheet{Name: r.Name, Rows: rows}
+	}
+
+	agg := r.Aggregated
+	rows = append(rows,
+		[]interface{}{"Window (s)", agg.WindowSeconds},
+		[]interface{}{"Samples", agg.SampleCount},
+		[]interface{}{"Total VRAM (bytes)", agg.TotalVRAMBytes},
+		nil,
+		[]interface{}{"Metric", "Min", "Avg", "Max", "P95", "P99", "Count"},
+	)
+	stats := []struct {
+		name string
+		s    model.AggregatedStats
+	}{
+		{"Allocated VRAM (bytes)", agg.AllocatedVRAMBytes},
+		{"Used KV Cache (bytes)", agg.UsedKVCacheBytes},
+		{"Prefix Cache Hit Rate (%)", agg.PrefixCacheHitRate},
+		{"Requests Running", agg.NumRequestsRunning},
+		{"Requests Waiting", agg.NumRequestsWaiting},
+	}
+	for _, st := range stats {
+		rows = append(rows, []interface{}{st.name, st.s.Min, st.s.Avg, st.s.Max, st.s.P95, st.s.P99, st.s.Count})
+	}
+
+	rows = append(rows, nil, []interface{}{"Model", "Port", "Allocated VRAM (bytes)", "Used KV Cache (bytes)"})
+	for _, m := range agg.Models {
+		rows = append(rows, []interface{}{m.ModelID, m.Port, m.AllocatedVRAMBytes, m.UsedKVCacheBytes})
+	}
+
+	if len(r.History) > 0 {
+		rows = append(rows, nil, []interface{}{"Time", "Allocated VRAM (bytes)", "Used KV Cache (bytes)", "Prefix Cache Hit Rate (%)"})
+		for _, s := range r.History {
+			rows = append(rows, []interface{}{s.Time, s.AllocatedVRAMBytes, s.UsedKVCacheBytes, s.PrefixCacheHitRate})
+		}
+	}
+
+	return export.Sheet{Name: r.Name, Rows: rows}
+}
+
+func init() {
+	reportCmd.Flags().StringVar(&reportFlags.format, "format", "json", "output format (json, xlsx, html)")
+	reportCmd.Flags().StringVarP(&reportFlags.output, "output", "o", "", "output file (default: stdout for json, timestamped file for xlsx and html)")
+	durationVar(reportCmd.Flags(), &reportFlags.window, "window", time.Minute, time.Second, "aggregation window, in whole seconds (e.g. 60 or 5m)")
+	reportCmd.Flags().IntVar(&reportFlags.samples, "samples", 0, "number of snapshots to record as a history table")
+	durationVar(reportCmd.Flags(), &reportFlags.every, "every", 5*time.Second, minInterval, "delay between history samples")
+	rootCmd.AddCommand(reportCmd)
+}
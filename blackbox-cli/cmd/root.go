@@ -1,24 +1,49 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/maxdcmn/blackbox-cli/internal/client"
 	"github.com/maxdcmn/blackbox-cli/internal/config"
+	"github.com/maxdcmn/blackbox-cli/internal/features"
+	"github.com/maxdcmn/blackbox-cli/internal/instance"
+	"github.com/maxdcmn/blackbox-cli/internal/store"
+	"github.com/maxdcmn/blackbox-cli/internal/telemetry"
+	"github.com/maxdcmn/blackbox-cli/internal/tracing"
 	"github.com/maxdcmn/blackbox-cli/internal/ui"
+	"github.com/maxdcmn/blackbox-cli/internal/usage"
 	"github.com/maxdcmn/blackbox-cli/internal/utils"
 	"github.com/spf13/cobra"
 )
 
 type rootFlags struct {
-	baseURL  string
-	endpoint string
-	timeout  string
-	interval string
-	debug    bool
-	logFile  string
+	baseURL      string
+	endpoint     string
+	timeout      time.Duration
+	interval     time.Duration
+	debug        bool
+	logFile      string
+	proxy        string
+	smooth       float64
+	traceHTTP    bool
+	control      string
+	experimental bool
+	rateLimit    float64
+	otelEndpoint string
+	userAgent    string
+	strictSchema bool
+	configPath   string
+	portable     bool
+	profile      string
+	selects      []string
+	selector     config.Selector
+	groupBy      string
+	theme        string
 }
 
 var rf rootFlags
@@ -28,37 +53,166 @@ var rootCmd = &cobra.Command{
 	Short:         "blackbox: CLI monitor for blackbox-server (vLLM KPIs + semantics)",
 	SilenceUsage:  true,
 	SilenceErrors: true,
-	RunE: func(cmd *cobra.Command, args []string) error {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := applyEnv(cmd); err != nil {
+			return err
+		}
+		if rf.portable {
+			if err := config.SetPortable(); err != nil {
+				return err
+			}
+		}
+		config.SetPath(rf.configPath)
+		config.SetProfile(rf.profile)
+		sel, err := config.ParseSelector(rf.selects...)
+		if err != nil {
+			return fmt.Errorf("invalid --select: %w", err)
+		}
+		rf.selector = sel
 		if err := utils.InitLogger(rf.debug, rf.logFile); err != nil {
 			return fmt.Errorf("failed to init logger: %w", err)
 		}
-		defer utils.CloseLogger()
-
-		cfg, err := config.Load()
-		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
+		utils.SetHTTPTrace(rf.traceHTTP)
+		if rf.userAgent == "" {
+			rf.userAgent = "blackbox-cli/" + ui.Version
 		}
-
-		timeout, err := time.ParseDuration(rf.timeout)
-		if err != nil {
-			timeout = 10 * time.Second
+		client.SetUserAgent(rf.userAgent)
+		client.SetStrictSchema(rf.strictSchema)
+		utils.Debug("%s: request ids start with %s", rf.userAgent, client.InvocationID())
+		// A broken config is reported by the command that needs it
+		var enabled map[string]bool
+		var tel config.Telemetry
+		if cfg, err := config.Load(); err == nil {
+			utils.AddSecrets(cfg.Secrets()...)
+			// The keyring commands are how a bad age: value gets fixed, and
+			// keyring check lists every one that doesn't decrypt
+			if cmd != keyringCmd && cmd.Parent() != keyringCmd {
+				if err := cfg.CheckAgeValues(); err != nil {
+					return err
+				}
+			}
+			enabled = cfg.Features
+			if cfg.Telemetry != nil {
+				tel = *cfg.Telemetry
+			}
 		}
-		interval, err := time.ParseDuration(rf.interval)
-		if err != nil {
-			interval = 3 * time.Second
+		features.Init(enabled, rf.experimental)
+		otelEndpoint := rf.otelEndpoint
+		if otelEndpoint == "" {
+			otelEndpoint = os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
 		}
-
-		m := ui.NewDashboard(cfg, interval, timeout)
-		p := tea.NewProgram(m, tea.WithAltScreen())
-		if _, err := p.Run(); err != nil {
+		if err := tracing.Init(otelEndpoint, ui.Version); err != nil {
 			return err
 		}
+		tracing.StartCommand(cmd.CommandPath())
+		telemetry.Init(tel.Enabled, tel.Endpoint, config.Dir(), ui.Version)
+		telemetry.Command(cmd.CommandPath())
 		return nil
 	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		utils.CloseLogger()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDashboard(nil)
+	},
+}
+
+// runDashboard starts the TUI; configure may adjust the model before it runs
+func runDashboard(configure func(*ui.DashboardModel)) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if _, err := selectEndpoints(cfg); err != nil {
+		return err
+	}
+
+	m := ui.NewDashboard(cfg, rf.interval, rf.timeout)
+	m.SetSmoothingAlpha(rf.smooth)
+	m.SetSelector(rf.selector)
+	m.SetGroupBy(rf.groupBy)
+	if err := m.SetTheme(rf.theme); err != nil {
+		return err
+	}
+	m.SetUsageStore(usage.Open(usage.Path()))
+	if cfg.History == nil || !cfg.History.Disabled {
+		if history, err := store.Open(store.Path(cfg.History), store.OptionsFrom(cfg.History)); err == nil {
+			defer history.Close()
+			m.SetHistoryStore(history)
+		} else {
+			utils.Warn("chart history kept in memory only: %v", err)
+		}
+	}
+	m.RestoreSelection()
+	// Other dashboards share the fleet poller; on failure just poll alone
+	if coord, err := instance.Open(instance.DefaultDir()); err == nil {
+		defer coord.Close()
+		m.SetCoordinator(coord)
+	} else {
+		utils.Warn("fleet polling not shared: %v", err)
+	}
+	if configure != nil {
+		configure(m)
+	}
+	// Cancelled on the way out however the program ends, so no request outlives it
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m.SetContext(ctx)
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithContext(ctx))
+	// Endpoints added or edited elsewhere show up live; without a watcher they need a restart
+	if stop, err := ui.WatchConfig(p, config.Path()); err == nil {
+		defer stop()
+	} else {
+		utils.Warn("config changes need a restart: %v", err)
+	}
+	if rf.control != "" {
+		stop, err := ui.ServeControl(p, rf.control)
+		if err != nil {
+			return err
+		}
+		defer stop()
+	}
+	if _, err := p.Run(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// selectEndpoints returns the config's endpoints that --select picks
+func selectEndpoints(cfg *config.Config) ([]config.Endpoint, error) {
+	eps := rf.selector.Filter(cfg.Endpoints)
+	if len(eps) == 0 && len(cfg.Endpoints) > 0 {
+		return nil, fmt.Errorf("no endpoint matches --select %s (tags in use: %s)", rf.selector, tagSummary(cfg.Endpoints))
+	}
+	return eps, nil
+}
+
+// tagSummary lists the tag keys of eps for error messages
+func tagSummary(eps []config.Endpoint) string {
+	keys := config.TagKeys(eps)
+	if len(keys) == 0 {
+		return "none"
+	}
+	return strings.Join(keys, ", ")
+}
+
+// flagClientOptions returns client options derived from global flags for commands using --url
+func flagClientOptions(opts ...client.Option) []client.Option {
+	if rf.proxy != "" {
+		opts = append(opts, client.WithProxy(rf.proxy))
+	}
+	return append(opts, client.WithRateLimit(rf.rateLimit))
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	// PostRun hooks are skipped when a command fails, so counts are saved here
+	telemetry.Error(err)
+	telemetry.Flush(context.Background())
+	tracing.EndCommand(err)
+	tracing.Shutdown()
+	if err != nil {
 		fmt.Fprintln(os.Stderr, "error:", err)
 		os.Exit(1)
 	}
@@ -67,10 +221,26 @@ func Execute() {
 func init() {
 	rootCmd.PersistentFlags().StringVar(&rf.baseURL, "url", "http://127.0.0.1:6767", "blackbox-server base URL")
 	rootCmd.PersistentFlags().StringVar(&rf.endpoint, "endpoint", "/vram", "VRAM endpoint path")
-	rootCmd.PersistentFlags().StringVar(&rf.timeout, "timeout", "10s", "HTTP timeout (e.g. 10s, 500ms)")
-	rootCmd.PersistentFlags().StringVar(&rf.interval, "interval", "3s", "polling interval (e.g. 3s, 1s)")
+	durationVar(rootCmd.PersistentFlags(), &rf.timeout, "timeout", 10*time.Second, minTimeout, "HTTP timeout (e.g. 10s, 500ms or 5 for seconds)")
+	durationVar(rootCmd.PersistentFlags(), &rf.interval, "interval", 3*time.Second, minInterval, "polling interval (e.g. 3s, 1s or 5 for seconds)")
+	rootCmd.PersistentFlags().StringVar(&rf.configPath, "config", "", "config file to use (.json, .yaml or .yml; default: config.json or config.yaml in the config directory, see 'blackbox config')")
+	rootCmd.PersistentFlags().BoolVar(&rf.portable, "portable", false, "keep the config and local state next to the blackbox binary instead of $BLACKBOX_CONFIG_DIR, $XDG_CONFIG_HOME/blackbox or ~/.config/blackbox")
+	rootCmd.PersistentFlags().StringVar(&rf.profile, "profile", "", "use the endpoints of this profile from the config's \"profiles\" (e.g. prod, lab)")
+	rootCmd.PersistentFlags().StringArrayVar(&rf.selects, "select", nil, "only use endpoints whose tags match: tag=value, tag!=value, tag or !tag; value may be a|b (repeat or comma-separate to require all)")
 	rootCmd.PersistentFlags().BoolVar(&rf.debug, "debug", false, "enable debug logging")
 	rootCmd.PersistentFlags().StringVar(&rf.logFile, "log-file", "", "write logs to file (default: stderr)")
+	rootCmd.PersistentFlags().StringVar(&rf.proxy, "proxy", "", "proxy for --url requests (http://, socks5://; default: HTTP(S)_PROXY env)")
+	rootCmd.PersistentFlags().Float64Var(&rf.rateLimit, "rate-limit", client.DefaultRateLimit, "max requests per second to --url (negative: unlimited; config endpoints use \"rate_limit\")")
+
+	rootCmd.PersistentFlags().StringVar(&rf.otelEndpoint, "otel-endpoint", "", "export OpenTelemetry traces of every request to this OTLP/HTTP collector (e.g. http://localhost:4318; default: OTEL_EXPORTER_OTLP_ENDPOINT env)")
+	rootCmd.PersistentFlags().StringVar(&rf.userAgent, "user-agent", "", "User-Agent sent with every request (default: blackbox-cli/<version>; an endpoint's \"User-Agent\" header wins)")
+	rootCmd.PersistentFlags().BoolVar(&rf.strictSchema, "strict-schema", false, "treat response fields this CLI doesn't know as errors (missing fields and wrong types always are)")
+	rootCmd.PersistentFlags().BoolVar(&rf.traceHTTP, "trace-http", false, "log every HTTP request/response (secrets redacted; use with --log-file for the dashboard)")
+	rootCmd.PersistentFlags().BoolVar(&rf.experimental, "enable-experimental", false, "turn on every experimental feature (see 'blackbox version'); config \"features\" entries still apply")
+	rootCmd.Flags().Float64Var(&rf.smooth, "smooth-alpha", 0.3, "EMA weight of the newest sample for smoothed charts (0-1, toggle with S)")
+	rootCmd.Flags().StringVar(&rf.groupBy, "group-by", "", "group the endpoints panel by this tag (e.g. env; cycle with T)")
+	rootCmd.Flags().StringVar(&rf.theme, "theme", "", "color theme for this run: dark, light, high-contrast, solarized, mono or one of the config's ui.themes (default: ui.theme)")
+	addControlFlag(rootCmd)
 
 	rootCmd.AddCommand(statCmd)
 }
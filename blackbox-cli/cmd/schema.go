@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/maxdcmn/blackbox-cli/internal/client"
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+	"github.com/maxdcmn/blackbox-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+var schemaTargets = map[string]interface{}{
+	"snapshot":   model.Snapshot{},
+	"snapshots":  []endpointSnapshot{}, // stat --all
+	"forecast":   forecastSnapshot{},   // stat --forecast
+	"aggregated": model.AggregatedSnapshot{},
+	"models":     client.ModelsResponse{},
+}
+
+func schemaNames() []string {
+	names := make([]string, 0, len(schemaTargets))
+	for name := range schemaTargets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var schemaCmd = &cobra.Command{
+	Use:       "schema <" + strings.Join(schemaNames(), "|") + ">",
+	Short:     "Print the JSON Schema of stat/stream/models output",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: schemaNames(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, ok := schemaTargets[args[0]]; !ok {
+			return fmt.Errorf("unknown schema %q (expected one of: %s)", args[0], strings.Join(schemaNames(), ", "))
+		}
+		return printSchema(args[0])
+	},
+}
+
+// printSchema writes the named schema to stdout; used by --schema on data commands
+func printSchema(name string) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(schema.For(name, schemaTargets[name]))
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+}
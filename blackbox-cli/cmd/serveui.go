@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/client"
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+	"github.com/maxdcmn/blackbox-cli/internal/features"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+	"github.com/maxdcmn/blackbox-cli/internal/webui"
+	"github.com/spf13/cobra"
+)
+
+var serveUIFlags struct {
+	addr    string
+	history int
+}
+
+var serveUICmd = &cobra.Command{
+	Use:   "serve-ui",
+	Short: "Serve a read-only web dashboard of all configured endpoints (experimental)",
+	Example: `  blackbox serve-ui
+  blackbox serve-ui --addr 0.0.0.0:8787 --interval 5s`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !features.Enabled(features.WebUI) {
+			return fmt.Errorf("serve-ui is experimental: run with --enable-experimental or set \"features\": {\"%s\": true} in config", features.WebUI)
+		}
+		timeout := rf.timeout
+		interval := rf.interval
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		if len(cfg.Endpoints) == 0 {
+			return fmt.Errorf("no endpoints configured")
+		}
+		endpoints, err := selectEndpoints(cfg)
+		if err != nil {
+			return err
+		}
+
+		sources := make([]webui.Source, len(endpoints))
+		for i, ep := range endpoints {
+			epTimeout := timeout
+			if d, err := utils.ParseDuration(ep.Timeout); err == nil && d > 0 {
+				epTimeout = d
+			}
+			sources[i] = webui.Source{Name: ep.Name, BaseURL: ep.BaseURL, Client: client.FromEndpoint(ep, epTimeout), Timeout: epTimeout, HistorySize: ep.HistorySize}
+			if d, err := utils.ParseDuration(ep.Interval); err == nil && d > 0 {
+				sources[i].Interval = d
+			}
+		}
+		srv := webui.New(sources, interval, serveUIFlags.history)
+
+		ln, err := net.Listen("tcp", serveUIFlags.addr)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", serveUIFlags.addr, err)
+		}
+		httpSrv := &http.Server{Handler: srv.Handler(), ReadHeaderTimeout: 10 * time.Second}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		go srv.Run(ctx)
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			httpSrv.Shutdown(shutdownCtx)
+		}()
+
+		fmt.Fprintf(os.Stderr, "serving %d endpoints at http://%s (ctrl+c to stop)\n", len(sources), ln.Addr())
+		utils.Info("serve-ui listening on %s", ln.Addr())
+		if err := httpSrv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	serveUICmd.Flags().StringVar(&serveUIFlags.addr, "addr", "127.0.0.1:8787", "listen address (use 0.0.0.0:8787 to share on the network)")
+	serveUICmd.Flags().IntVar(&serveUIFlags.history, "history", 120, "samples of history kept per endpoint")
+	rootCmd.AddCommand(serveUICmd)
+}
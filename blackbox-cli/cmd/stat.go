@@ -8,51 +8,155 @@ import (
 	"time"
 
 	"github.com/maxdcmn/blackbox-cli/internal/client"
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+	"github.com/maxdcmn/blackbox-cli/internal/model"
 	"github.com/spf13/cobra"
 )
 
 var statFlags struct {
 	watch    bool
-	interval string
+	interval time.Duration
 	compact  bool
+	schema   bool
+	all      bool
+	forecast bool
+	window   time.Duration
+}
+
+// statRequestsWindow is how many seconds of aggregated samples the request
+// counts, which /vram snapshots don't carry, are taken from
+const statRequestsWindow = 10
+
+// endpointSnapshot is one endpoint's entry in stat --all output
+type endpointSnapshot struct {
+	Endpoint string          `json:"endpoint"`
+	Error    string          `json:"error,omitempty"`
+	Snapshot *model.Snapshot `json:"snapshot,omitempty"`
+}
+
+// forecastSnapshot is stat --forecast output: the snapshot, plus where its
+// allocated VRAM is heading
+type forecastSnapshot struct {
+	*model.Snapshot
+	Forecast *model.Forecast `json:"forecast,omitempty"` // Left out until two samples are in
+}
+
+// statTrend keeps the samples of the last --forecast-window for stat --forecast
+type statTrend struct {
+	window  time.Duration
+	samples []model.VRAMSample
+}
+
+func (t *statTrend) add(snap *model.Snapshot) forecastSnapshot {
+	now := time.Now()
+	t.samples = append(t.samples, model.VRAMSample{At: now, AllocatedVRAMBytes: snap.AllocatedVRAMBytes})
+	for now.Sub(t.samples[0].At) > t.window {
+		t.samples = t.samples[1:]
+	}
+	out := forecastSnapshot{Snapshot: snap}
+	if f, ok := model.ForecastVRAM(t.samples, snap.TotalVRAMBytes); ok {
+		out.Forecast = &f
+	}
+	return out
 }
 
 var statCmd = &cobra.Command{
 	Use:   "stat",
 	Short: "Print a snapshot (JSON) or watch snapshots",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		timeout, err := time.ParseDuration(rf.timeout)
-		if err != nil {
-			return fmt.Errorf("invalid --timeout: %w", err)
+		if statFlags.forecast && statFlags.all {
+			return fmt.Errorf("--forecast fits one endpoint's trend and can't be combined with --all")
 		}
-		interval, err := time.ParseDuration(statFlags.interval)
-		if err != nil {
-			return fmt.Errorf("invalid --interval: %w", err)
+		if statFlags.schema {
+			switch {
+			case statFlags.all:
+				return printSchema("snapshots")
+			case statFlags.forecast:
+				return printSchema("forecast")
+			}
+			return printSchema("snapshot")
 		}
+		timeout := rf.timeout
+		c := client.New(rf.baseURL, rf.endpoint, timeout, flagClientOptions()...)
 
-		c := client.New(rf.baseURL, rf.endpoint, timeout)
+		encode := func(v interface{}) error {
+			enc := json.NewEncoder(os.Stdout)
+			if !statFlags.compact {
+				enc.SetIndent("", "  ")
+			}
+			return enc.Encode(v)
+		}
 
-		printOnce := func() error {
+		fetch := func() (*model.Snapshot, error) {
 			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
 			defer cancel()
 
 			snap, err := c.Snapshot(ctx)
+			if err != nil {
+				return nil, err
+			}
+			client.FillRequestCounts(ctx, c, snap, statRequestsWindow)
+			return snap, nil
+		}
+
+		printOnce := func() error {
+			snap, err := fetch()
 			if err != nil {
 				return err
 			}
+			return encode(snap)
+		}
 
-			enc := json.NewEncoder(os.Stdout)
-			if !statFlags.compact {
-				enc.SetIndent("", "  ")
+		var trend *statTrend
+		if statFlags.forecast {
+			trend = &statTrend{window: statFlags.window}
+			printOnce = func() error {
+				snap, err := fetch()
+				if err != nil {
+					return err
+				}
+				return encode(trend.add(snap))
+			}
+		}
+
+		if statFlags.all {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			endpoints, err := selectEndpoints(cfg)
+			if err != nil {
+				return err
+			}
+			multi := client.NewMulti(endpoints, timeout)
+			printOnce = func() error {
+				res := multi.SnapshotWithRequests(cmd.Context(), statRequestsWindow)
+				out := make([]endpointSnapshot, 0, len(endpoints))
+				for _, ep := range multi.Endpoints() {
+					entry := endpointSnapshot{Endpoint: ep.Name, Snapshot: res.Snapshots[ep.Name]}
+					if err := res.Errors[ep.Name]; err != nil {
+						entry.Error = err.Error()
+					}
+					out = append(out, entry)
+				}
+				if err := encode(out); err != nil {
+					return err
+				}
+				if len(res.Snapshots) == 0 && len(res.Errors) > 0 {
+					return fmt.Errorf("no endpoint answered")
+				}
+				return nil
 			}
-			return enc.Encode(snap)
 		}
 
 		if !statFlags.watch {
+			if trend != nil {
+				return printForecast(cmd.Context(), trend, fetch, encode)
+			}
 			return printOnce()
 		}
 
-		ticker := time.NewTicker(interval)
+		ticker := time.NewTicker(statFlags.interval)
 		defer ticker.Stop()
 
 		for {
@@ -69,8 +173,36 @@ var statCmd = &cobra.Command{
 	},
 }
 
+// printForecast samples every --interval for the trend's window, then prints
+// the last snapshot with the trend fitted through them all
+func printForecast(ctx context.Context, trend *statTrend, fetch func() (*model.Snapshot, error), encode func(interface{}) error) error {
+	fmt.Fprintf(os.Stderr, "sampling every %s for %s to fit a trend...\n", statFlags.interval, trend.window)
+	deadline := time.Now().Add(trend.window)
+	ticker := time.NewTicker(statFlags.interval)
+	defer ticker.Stop()
+	for {
+		snap, err := fetch()
+		if err != nil {
+			return err
+		}
+		out := trend.add(snap)
+		if !time.Now().Before(deadline) {
+			return encode(out)
+		}
+		select {
+		case <-ctx.Done():
+			return encode(out)
+		case <-ticker.C:
+		}
+	}
+}
+
 func init() {
 	statCmd.Flags().BoolVar(&statFlags.watch, "watch", false, "watch snapshots continuously")
-	statCmd.Flags().StringVar(&statFlags.interval, "interval", "3s", "watch interval (e.g. 3s, 1s)")
+	durationVar(statCmd.Flags(), &statFlags.interval, "interval", 3*time.Second, minInterval, "watch interval (e.g. 3s, 1s or 5 for seconds)")
 	statCmd.Flags().BoolVar(&statFlags.compact, "compact", false, "print compact JSON (no indentation)")
+	statCmd.Flags().BoolVar(&statFlags.all, "all", false, "fetch a snapshot from every configured endpoint concurrently (each with its own timeout)")
+	statCmd.Flags().BoolVar(&statFlags.forecast, "forecast", false, "add a \"forecast\" of when allocated VRAM is full at its current growth, fitted over --forecast-window (sampled first unless --watch)")
+	durationVar(statCmd.Flags(), &statFlags.window, "forecast-window", time.Minute, minInterval, "how much recent history --forecast fits (e.g. 1m, 10m)")
+	statCmd.Flags().BoolVar(&statFlags.schema, "schema", false, "print the JSON Schema of the output and exit")
 }
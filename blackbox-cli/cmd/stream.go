@@ -1,83 +1,71 @@
 package cmd
 
 import (
-	"bufio"
-	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
-	"strings"
+	"time"
 
+	"github.com/maxdcmn/blackbox-cli/internal/client"
 	"github.com/maxdcmn/blackbox-cli/internal/model"
 	"github.com/spf13/cobra"
 )
 
 var streamCmd = &cobra.Command{
 	Use:   "stream",
-	Short: "Stream real-time VRAM metrics via SSE",
+	Short: "Stream real-time VRAM metrics via SSE or WebSocket",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		streamURL := rf.baseURL + "/vram/stream"
-		if strings.HasPrefix(streamURL, "http:/") && !strings.HasPrefix(streamURL, "http://") {
-			streamURL = strings.Replace(streamURL, "http:/", "http://", 1)
+		if streamFlags.schema {
+			return printSchema("snapshot")
 		}
-		if strings.HasPrefix(streamURL, "https:/") && !strings.HasPrefix(streamURL, "https://") {
-			streamURL = strings.Replace(streamURL, "https:/", "https://", 1)
-		}
-
-		ctx, cancel := context.WithCancel(cmd.Context())
-		defer cancel()
 
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, streamURL, nil)
-		if err != nil {
-			return fmt.Errorf("failed to create request: %w", err)
+		switch streamFlags.transport {
+		case client.TransportSSE, client.TransportWebSocket, client.TransportAuto, client.TransportGRPC:
+		default:
+			return fmt.Errorf("invalid --transport %q (expected sse, ws, auto or grpc)", streamFlags.transport)
 		}
 
-		client := &http.Client{Timeout: 0}
-		resp, err := client.Do(req)
-		if err != nil {
-			return fmt.Errorf("request failed: %w", err)
-		}
-		defer resp.Body.Close()
+		timeout := rf.timeout
 
-		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			return fmt.Errorf("server returned %s", resp.Status)
+		stall := streamFlags.stallTimeout
+		if stall == 0 {
+			stall = -1 // off
 		}
+		c := client.New(rf.baseURL, rf.endpoint, timeout, flagClientOptions(client.WithStreamTransport(streamFlags.transport), client.WithStallTimeout(stall))...)
 
-		scanner := bufio.NewScanner(resp.Body)
 		enc := json.NewEncoder(os.Stdout)
 		if !streamFlags.compact {
 			enc.SetIndent("", "  ")
 		}
 
-		for scanner.Scan() {
-			line := scanner.Text()
-			if strings.HasPrefix(line, "data: ") {
-				data := line[6:]
-				var snap model.Snapshot
-				if err := json.Unmarshal([]byte(data), &snap); err == nil {
-					if err := enc.Encode(snap); err != nil {
-						fmt.Fprintf(os.Stderr, "error encoding: %v\n", err)
-					}
-				}
+		err := c.Stream(cmd.Context(), func(snap *model.Snapshot) error {
+			if err := enc.Encode(snap); err != nil {
+				fmt.Fprintf(os.Stderr, "error encoding: %v\n", err)
 			}
-		}
-
-		if err := scanner.Err(); err != nil && err != io.EOF {
+			return nil
+		}, func(state client.StreamState) {
+			if state == client.StreamReconnecting || state == client.StreamStalled {
+				fmt.Fprintln(os.Stderr, "stream:", state)
+			}
+		})
+		if err != nil && cmd.Context().Err() == nil {
 			return fmt.Errorf("stream error: %w", err)
 		}
-
 		return nil
 	},
 }
 
 var streamFlags struct {
-	compact bool
+	compact      bool
+	schema       bool
+	transport    string
+	stallTimeout time.Duration
 }
 
 func init() {
 	streamCmd.Flags().BoolVar(&streamFlags.compact, "compact", false, "print compact JSON (no indentation)")
+	streamCmd.Flags().BoolVar(&streamFlags.schema, "schema", false, "print the JSON Schema of each event and exit")
+	streamCmd.Flags().StringVar(&streamFlags.transport, "transport", client.TransportSSE, "stream transport (sse, ws, auto, grpc)")
+	durationVar(streamCmd.Flags(), &streamFlags.stallTimeout, "stall-timeout", client.DefaultStallTimeout, 0, "report the stream stalled after this long without events or heartbeats, and reconnect after three times as long (0 turns it off)")
 	rootCmd.AddCommand(streamCmd)
 }
-
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+	"github.com/maxdcmn/blackbox-cli/internal/telemetry"
+	"github.com/maxdcmn/blackbox-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var telemetryCollector string
+
+var telemetryCmd = &cobra.Command{
+	Use:   "telemetry",
+	Short: "Show what opt-in usage telemetry collects and whether it is on",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		out := cmd.OutOrStdout()
+		fmt.Fprintln(out, telemetry.Description)
+		fmt.Fprintln(out)
+		switch {
+		case cfg.Telemetry == nil || !cfg.Telemetry.Enabled:
+			fmt.Fprintln(out, "status: off (enable with 'blackbox telemetry enable')")
+		case cfg.Telemetry.Endpoint == "":
+			fmt.Fprintln(out, "status: on, counting locally (no collector endpoint, nothing is sent)")
+		default:
+			fmt.Fprintf(out, "status: on, reporting daily to %s\n", cfg.Telemetry.Endpoint)
+		}
+		return nil
+	},
+}
+
+var telemetryEnableCmd = &cobra.Command{
+	Use:     "enable",
+	Short:   "Opt in to anonymous usage counts",
+	Example: `  blackbox telemetry enable --collector https://telemetry.example.com/v1/report`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		err = config.Update(cfg, func(cfg *config.Config) error {
+			if cfg.Telemetry == nil {
+				cfg.Telemetry = &config.Telemetry{}
+			}
+			cfg.Telemetry.Enabled = true
+			if cmd.Flags().Changed("collector") {
+				cfg.Telemetry.Endpoint = telemetryCollector
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		telemetry.Init(true, cfg.Telemetry.Endpoint, config.Dir(), ui.Version)
+		fmt.Fprintln(cmd.OutOrStdout(), "telemetry enabled; run 'blackbox telemetry preview' to see what is reported")
+		return nil
+	},
+}
+
+var telemetryDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Opt out and delete the local counts and install ID",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		if cfg.Telemetry != nil {
+			err := config.Update(cfg, func(cfg *config.Config) error {
+				if cfg.Telemetry != nil {
+					cfg.Telemetry.Enabled = false
+				}
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+		}
+		if err := telemetry.Forget(); err != nil {
+			return fmt.Errorf("failed to delete telemetry data: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), "telemetry disabled; local counts deleted")
+		return nil
+	},
+}
+
+var telemetryPreviewCmd = &cobra.Command{
+	Use:   "preview",
+	Short: "Print exactly what the next telemetry report would send",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := telemetry.Preview()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return nil
+	},
+}
+
+func init() {
+	telemetryEnableCmd.Flags().StringVar(&telemetryCollector, "collector", "", "collector URL to send daily reports to (default: count locally only)")
+	telemetryCmd.AddCommand(telemetryEnableCmd, telemetryDisableCmd, telemetryPreviewCmd)
+	rootCmd.AddCommand(telemetryCmd)
+}
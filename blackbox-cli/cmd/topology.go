@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/maxdcmn/blackbox-cli/internal/client"
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+	"github.com/maxdcmn/blackbox-cli/internal/topology"
+	"github.com/spf13/cobra"
+)
+
+var topologyFlags struct {
+	format string
+	output string
+}
+
+var topologyCmd = &cobra.Command{
+	Use:   "topology",
+	Short: "Draw the fleet's endpoints, GPUs and deployed models as a Mermaid or DOT diagram",
+	Long: `Fetch a snapshot and the model list from every configured endpoint and
+write a diagram of them: one box per endpoint holding its GPU, with an arrow
+to each deployed model labelled with the model's VRAM and its share of the
+endpoint's. Stopped models and unreachable endpoints are drawn dashed.
+
+Mermaid output pastes into Markdown runbooks as a mermaid code block; render
+DOT with Graphviz.`,
+	Example: `  blackbox topology > fleet.mmd
+  blackbox topology --format dot --select env=prod | dot -Tsvg -o fleet.svg`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !slices.Contains(topology.Formats, topologyFlags.format) {
+			return fmt.Errorf("invalid --format %q (expected %s)", topologyFlags.format, strings.Join(topology.Formats, " or "))
+		}
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		endpoints, err := selectEndpoints(cfg)
+		if err != nil {
+			return err
+		}
+
+		multi := client.NewMulti(endpoints, rf.timeout)
+		snaps := multi.Snapshot(cmd.Context())
+		models := multi.ListModels(cmd.Context(), client.ListModelsOptions{})
+		for _, ep := range endpoints {
+			if err := snaps.Errors[ep.Name]; err != nil {
+				fmt.Fprintf(os.Stderr, "warning: %s: snapshot: %v\n", ep.Name, err)
+			}
+			if err := models.Errors[ep.Name]; err != nil {
+				fmt.Fprintf(os.Stderr, "warning: %s: models: %v\n", ep.Name, err)
+			}
+		}
+		fleet := topology.Build(endpoints, snaps, models)
+
+		out := os.Stdout
+		if topologyFlags.output != "" {
+			f, err := os.Create(topologyFlags.output)
+			if err != nil {
+				return fmt.Errorf("failed to create output: %w", err)
+			}
+			defer f.Close()
+			out = f
+		}
+		return topology.Write(out, fleet, topologyFlags.format)
+	},
+}
+
+func init() {
+	topologyCmd.Flags().StringVar(&topologyFlags.format, "format", "mermaid", "diagram format ("+strings.Join(topology.Formats, ", ")+")")
+	topologyCmd.Flags().StringVarP(&topologyFlags.output, "output", "o", "", "output file (default: stdout)")
+	rootCmd.AddCommand(topologyCmd)
+}
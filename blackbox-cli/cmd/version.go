@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/maxdcmn/blackbox-cli/internal/features"
+	"github.com/maxdcmn/blackbox-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the version and which experimental features are enabled",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "blackbox %s\n", ui.Version)
+		enabled := features.EnabledNames()
+		if len(enabled) == 0 {
+			fmt.Fprintln(out, "experimental features: none")
+		} else {
+			fmt.Fprintf(out, "experimental features: %s\n", strings.Join(enabled, ", "))
+		}
+		for _, f := range features.Experimental {
+			state := "off"
+			if features.Enabled(f.Name) {
+				state = "on"
+			}
+			fmt.Fprintf(out, "  %-8s %-3s  %s\n", f.Name, state, f.Description)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+}
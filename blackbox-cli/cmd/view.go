@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/maxdcmn/blackbox-cli/internal/share"
+	"github.com/maxdcmn/blackbox-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var viewCmd = &cobra.Command{
+	Use:   "view <file|gist-url|blob>",
+	Short: "Open a shared snapshot (saved with x in the dashboard) read-only",
+	Example: `  blackbox view blackbox-gpu-a-20260101-120000.bbx
+  blackbox view https://gist.github.com/someone/0123456789abcdef`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+		defer cancel()
+		b, err := share.Load(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", args[0], err)
+		}
+		_, err = tea.NewProgram(ui.NewViewer(b), tea.WithAltScreen()).Run()
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(viewCmd)
+}
@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"os/signal"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/client"
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+	"github.com/maxdcmn/blackbox-cli/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+var whenFlags struct {
+	run      string
+	hold     time.Duration
+	repeat   time.Duration
+	cooldown time.Duration
+	once     bool
+	all      bool
+}
+
+// placeholderRe matches {{name}} in --run
+var placeholderRe = regexp.MustCompile(`\{\{\s*([a-z_]+)\s*\}\}`)
+
+var whenCmd = &cobra.Command{
+	Use:   "when <condition>",
+	Short: "Run a command when a condition on streamed snapshots becomes true",
+	Long: `Stream snapshots and evaluate the condition on each one. When it becomes
+true the --run command is started, or a line is printed without --run.
+
+A condition compares metrics with numbers using > >= < <= == !=, joined by
+&& (and), || (or) and ! (not), with parentheses. Metrics: ` + strings.Join(watch.Metrics(), ", ") + `.
+
+--run goes through the shell with {{endpoint}}, {{url}}, {{condition}} and
+{{<metric>}} replaced; the same values are in BLACKBOX_ENDPOINT, BLACKBOX_URL
+and BLACKBOX_<METRIC>. A command still running for an endpoint isn't
+started again for it.
+
+The command runs once each time the condition becomes true. --for waits
+until it has held that long, --repeat runs it again while it keeps holding,
+--cooldown spaces out runs, and --once exits after the first run, failing
+if the command fails.`,
+	Example: `  blackbox when 'allocated_vram_percent > 95' --run './scale_down.sh {{endpoint}}'
+  blackbox when 'prefix_cache_hit_rate < 40 && models > 0' --for 2m --all --select env=prod
+  blackbox when 'free_vram_gb >= 20' --once && ./deploy_next.sh`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cond, err := watch.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid condition: %w", err)
+		}
+		if err := checkPlaceholders(whenFlags.run); err != nil {
+			return err
+		}
+		timeout := rf.timeout
+
+		type source struct {
+			name, url string
+			client    client.MetricsClient
+		}
+		var sources []source
+		if whenFlags.all || len(rf.selector) > 0 {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			endpoints, err := selectEndpoints(cfg)
+			if err != nil {
+				return err
+			}
+			for _, ep := range endpoints {
+				sources = append(sources, source{ep.Name, ep.BaseURL, client.FromEndpoint(ep, timeout)})
+			}
+		} else {
+			c := client.New(rf.baseURL, rf.endpoint, timeout, flagClientOptions()...)
+			sources = append(sources, source{rf.baseURL, rf.baseURL, c})
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var (
+			out     sync.Mutex // Keeps lines from different endpoints whole
+			runs    sync.WaitGroup
+			onceErr error
+			done    atomic.Bool
+		)
+		fire := func(name, url string, snap *model.Snapshot, running *atomic.Bool) {
+			vals := watch.Values(snap)
+			out.Lock()
+			fmt.Fprintf(cmd.OutOrStdout(), "%s %s: %s (%s)\n", time.Now().Format(time.RFC3339), name, cond, formatValues(cond.Metrics(), vals))
+			out.Unlock()
+			if whenFlags.run == "" {
+				if whenFlags.once {
+					done.Store(true)
+					cancel()
+				}
+				return
+			}
+			if !running.CompareAndSwap(false, true) {
+				utils.Warn("%s: previous --run command still running, not starting another", name)
+				return
+			}
+			runs.Add(1)
+			go func() {
+				defer runs.Done()
+				defer running.Store(false)
+				err := runAction(ctx, whenFlags.run, name, url, cond.String(), vals)
+				if err != nil && ctx.Err() == nil && !whenFlags.once {
+					utils.Error("%s: --run command failed: %v", name, err)
+				}
+				if whenFlags.once && done.CompareAndSwap(false, true) {
+					if err != nil {
+						onceErr = fmt.Errorf("--run command failed: %w", err)
+					}
+					cancel()
+				}
+			}()
+		}
+
+		var wg sync.WaitGroup
+		var failed atomic.Int32
+		for _, src := range sources {
+			wg.Add(1)
+			go func(src source) {
+				defer wg.Done()
+				trigger := &watch.Trigger{For: whenFlags.hold, Repeat: whenFlags.repeat, Cooldown: whenFlags.cooldown}
+				var running atomic.Bool
+				err := src.client.Stream(ctx, func(snap *model.Snapshot) error {
+					// With --once, nothing fires after the first run starts
+					if whenFlags.once && (done.Load() || running.Load()) {
+						return nil
+					}
+					if trigger.Observe(cond.Eval(watch.Values(snap)), time.Now()) {
+						fire(src.name, src.url, snap, &running)
+					}
+					return nil
+				}, func(state client.StreamState) {
+					utils.Info("%s: stream %s", src.name, state)
+				})
+				if err != nil && ctx.Err() == nil {
+					failed.Add(1)
+					utils.Warn("%s: stream stopped: %v", src.name, err)
+				}
+			}(src)
+		}
+		wg.Wait()
+		runs.Wait()
+		if int(failed.Load()) == len(sources) {
+			return errors.New("no endpoint could be streamed")
+		}
+		return onceErr
+	},
+}
+
+// checkPlaceholders rejects {{names}} in run that runAction wouldn't fill in
+func checkPlaceholders(run string) error {
+	known := map[string]bool{"endpoint": true, "url": true, "condition": true}
+	for _, name := range watch.Metrics() {
+		known[name] = true
+	}
+	for _, m := range placeholderRe.FindAllStringSubmatch(run, -1) {
+		if !known[m[1]] {
+			return fmt.Errorf("unknown placeholder {{%s}} in --run (have: endpoint, url, condition and the metrics)", m[1])
+		}
+	}
+	if strings.Contains(placeholderRe.ReplaceAllString(run, ""), "{{") {
+		return fmt.Errorf("malformed placeholder in --run; write {{endpoint}}, {{url}}, {{condition}} or {{<metric>}}")
+	}
+	return nil
+}
+
+// runAction runs the --run template through the shell with the endpoint's
+// values filled in, each quoted so the shell reads it as one word: a condition's
+// '>' mustn't become a redirect, nor a space in a name split it
+func runAction(ctx context.Context, run, endpoint, url, condition string, vals map[string]float64) error {
+	subst := map[string]string{"endpoint": endpoint, "url": url, "condition": condition}
+	env := append(os.Environ(), "BLACKBOX_ENDPOINT="+endpoint, "BLACKBOX_URL="+url, "BLACKBOX_CONDITION="+condition)
+	for name, v := range vals {
+		subst[name] = formatValue(v)
+		env = append(env, "BLACKBOX_"+strings.ToUpper(name)+"="+formatValue(v))
+	}
+	line := placeholderRe.ReplaceAllStringFunc(run, func(p string) string {
+		return shellQuote(subst[placeholderRe.FindStringSubmatch(p)[1]])
+	})
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.CommandContext(ctx, "cmd", "/C", line)
+	} else {
+		c = exec.CommandContext(ctx, "sh", "-c", line)
+	}
+	c.Env = env
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	utils.Debug("%s: running %s", endpoint, line)
+	return c.Run()
+}
+
+// shellQuote quotes s as a single word for the shell runAction uses
+func shellQuote(s string) string {
+	if runtime.GOOS == "windows" {
+		return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// formatValues renders the metrics a condition reads, e.g. "allocated_vram_percent=96.2"
+func formatValues(names []string, vals map[string]float64) string {
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + "=" + formatValue(vals[name])
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+func init() {
+	whenCmd.Flags().StringVar(&whenFlags.run, "run", "", "shell command to run when the condition becomes true ({{endpoint}}, {{url}}, {{condition}}, {{<metric>}})")
+	durationVar(whenCmd.Flags(), &whenFlags.hold, "for", 0, 0, "only fire once the condition has held this long (e.g. 30s)")
+	durationVar(whenCmd.Flags(), &whenFlags.repeat, "repeat", 0, 0, "fire again every this long while the condition keeps holding (0: once per time it becomes true)")
+	durationVar(whenCmd.Flags(), &whenFlags.cooldown, "cooldown", 0, 0, "least time between two runs for the same endpoint")
+	whenCmd.Flags().BoolVar(&whenFlags.once, "once", false, "exit after the first run (or printed line), failing if the command fails")
+	whenCmd.Flags().BoolVar(&whenFlags.all, "all", false, "watch every configured endpoint (implied by --select) instead of --url")
+	rootCmd.AddCommand(whenCmd)
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/maxdcmn/blackbox-cli/internal/client"
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var whereFlags struct {
+	json bool
+}
+
+var whereCmd = &cobra.Command{
+	Use:   "where <model-id-glob>",
+	Short: "Find which endpoints are running a model",
+	Long: `Query /models on every configured endpoint and print each host and port
+running a matching model. Matching ignores case; a plain pattern matches
+anywhere in the model ID, while * and ? must match the whole ID.`,
+	Example: `  blackbox where llama-3-70b
+  blackbox where 'meta-llama/*-Instruct' --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		timeout := rf.timeout
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		endpoints, err := selectEndpoints(cfg)
+		if err != nil {
+			return err
+		}
+
+		locs, errs := client.FindModels(cmd.Context(), client.NewMulti(endpoints, timeout), args[0])
+		for _, err := range errs {
+			fmt.Fprintln(os.Stderr, "warning:", err)
+		}
+
+		if whereFlags.json {
+			if locs == nil {
+				locs = []client.ModelLocation{}
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(locs); err != nil {
+				return err
+			}
+		} else if len(locs) > 0 {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ENDPOINT\tADDRESS\tMODEL\tSTATUS")
+			for _, l := range locs {
+				addr := l.Host
+				if l.Port > 0 {
+					addr = fmt.Sprintf("%s:%d", l.Host, l.Port)
+				}
+				status := "running"
+				if !l.Running {
+					status = "stopped"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", l.Endpoint, addr, l.ModelID, status)
+			}
+			w.Flush()
+		}
+
+		if len(locs) == 0 {
+			return fmt.Errorf("no model matching %q (searched %d of %d endpoints)", args[0], len(endpoints)-len(errs), len(endpoints))
+		}
+		return nil
+	},
+}
+
+func init() {
+	whereCmd.Flags().BoolVar(&whereFlags.json, "json", false, "print matches as JSON")
+	rootCmd.AddCommand(whereCmd)
+}
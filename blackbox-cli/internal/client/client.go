@@ -1,14 +1,16 @@
 package client
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/maxdcmn/blackbox-cli/internal/model"
@@ -16,22 +18,189 @@ import (
 )
 
 type Client struct {
-	baseURL  string
-	endpoint string
-	http     *http.Client
+	baseURL          string
+	endpoint         string
+	http             *http.Client
+	streamTransport  string
+	proxy            func(*http.Request) (*url.URL, error)
+	proxyKey         string
+	timeout          time.Duration
+	timeouts         Timeouts
+	stallTimeout     time.Duration
+	pins             *pins
+	settingErr       error // A setting that can't be applied; every request fails with it
+	maxResponseBytes int64
+	sse              *http.Client
+	sseOnce          sync.Once
+	headers          http.Header
+	transfer         *compressionTransport
+	grpcAddr         string
+	rateLimit        float64
+	limiter          *limiter
+	name             string
+	etags            etagCache
+	noFields         atomic.Bool // the server answered ?fields with a 4xx
+	noModelsQuery    atomic.Bool // the server answered /models with a query string with 404
+	newerSchema      atomic.Bool // a newer snapshot schema was warned about
 }
 
-func New(baseURL, endpoint string, timeout time.Duration) *Client {
-	return &Client{
-		baseURL:  baseURL,
-		endpoint: endpoint,
-		http: &http.Client{
-			Timeout: timeout,
-		},
+// Option configures optional Client behaviour
+type Option func(*Client)
+
+// Stream transports selectable per endpoint
+const (
+	TransportSSE       = "sse"
+	TransportWebSocket = "ws"
+	TransportAuto      = "auto"
+	TransportGRPC      = "grpc"
+)
+
+// WithStreamTransport selects how Stream receives snapshots: "sse" (default), "ws", "auto"
+// which tries a WebSocket upgrade first and falls back to SSE if the server doesn't support it,
+// or "grpc" which streams over gRPC and falls back to SSE when it isn't available.
+func WithStreamTransport(transport string) Option {
+	return func(c *Client) {
+		c.streamTransport = transport
+	}
+}
+
+// WithProxy routes all requests through proxyURL (http://, https:// or socks5://)
+// instead of the HTTP_PROXY/HTTPS_PROXY/NO_PROXY environment.
+func WithProxy(proxyURL string) Option {
+	return func(c *Client) {
+		u, err := url.Parse(proxyURL)
+		if err == nil && u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5" {
+			err = fmt.Errorf("unsupported proxy scheme %q", u.Scheme)
+		}
+		if err != nil {
+			// Surface the bad setting on every request rather than silently going direct
+			err = fmt.Errorf("invalid proxy %q: %w", proxyURL, err)
+			c.proxy = func(*http.Request) (*url.URL, error) { return nil, err }
+			c.proxyKey = "invalid " + proxyURL
+			return
+		}
+		c.proxy = http.ProxyURL(u)
+		c.proxyKey = proxyURL
+	}
+}
+
+// WithHeaders adds headers to every request, including stream connections.
+// A "Host" entry overrides the request host for reverse-proxy routing.
+func WithHeaders(headers map[string]string) Option {
+	return func(c *Client) {
+		if c.headers == nil {
+			c.headers = make(http.Header)
+		}
+		for k, v := range headers {
+			c.headers.Set(k, v)
+		}
+	}
+}
+
+// header is what every connection to the server carries, whatever the
+// transport: the schema version the CLI reads and the endpoint's headers
+func (c *Client) header() http.Header {
+	header := c.headers.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	if header.Get(schemaVersionHeader) == "" {
+		header.Set(schemaVersionHeader, strconv.Itoa(model.SchemaVersion))
+	}
+	return header
+}
+
+func (c *Client) setHeaders(req *http.Request) {
+	for k, vs := range c.header() {
+		if k == "Host" {
+			req.Host = vs[0]
+			continue
+		}
+		req.Header[k] = vs
+	}
+}
+
+var (
+	sharedTransportsMu sync.Mutex
+	sharedTransports   = map[string]*http.Transport{}
+)
+
+// sharedTransport returns the connection pool for a proxy, timeout and pin
+// setting, so every client talking through the same proxy (or none) reuses connections.
+func sharedTransport(proxyKey string, proxy func(*http.Request) (*url.URL, error), timeouts Timeouts, pins *pins) *http.Transport {
+	key := proxyKey + "|" + timeouts.key() + "|" + pins.key()
+	sharedTransportsMu.Lock()
+	defer sharedTransportsMu.Unlock()
+	if t, ok := sharedTransports[key]; ok {
+		return t
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = proxy
+	t.DialContext = timeouts.dialer().DialContext
+	t.TLSHandshakeTimeout = timeouts.TLSHandshake
+	t.ResponseHeaderTimeout = timeouts.ResponseHeader
+	if cfg := pins.tlsConfig(); cfg != nil {
+		t.TLSClientConfig = cfg
+	}
+	t.DisableCompression = true // compressionTransport negotiates encoding itself
+	sharedTransports[key] = t
+	return t
+}
+
+// New builds a client; timeout is the per-request deadline applied when the
+// caller's context has none. Clients are cheap and share connection pools.
+func New(baseURL, endpoint string, timeout time.Duration, opts ...Option) *Client {
+	c := &Client{
+		baseURL:          baseURL,
+		endpoint:         endpoint,
+		streamTransport:  TransportSSE,
+		proxy:            http.ProxyFromEnvironment,
+		timeout:          timeout,
+		timeouts:         defaultTimeouts(),
+		stallTimeout:     DefaultStallTimeout,
+		maxResponseBytes: DefaultMaxResponseBytes,
+		rateLimit:        DefaultRateLimit,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.rateLimit > 0 {
+		c.limiter = sharedLimiter(baseURL, c.rateLimit)
+	}
+	if c.pins != nil && c.pins.err != nil && c.settingErr == nil {
+		c.settingErr = c.pins.err
+	}
+	if c.settingErr != nil {
+		// Fail every request, like an invalid proxy, rather than connect unpinned or unauthenticated
+		err := c.settingErr
+		c.proxy = func(*http.Request) (*url.URL, error) { return nil, err }
+		c.proxyKey = "invalid setting " + err.Error()
+	}
+
+	c.transfer = newCompressionTransport(sharedTransport(c.proxyKey, c.proxy, c.timeouts, c.pins), c.maxResponseBytes)
+	c.http = &http.Client{
+		Transport: c.limit(c.identified(c.traced(&traceTransport{base: c.transfer}))),
+	}
+	return c
+}
+
+// requestContext applies timeout unless ctx already carries a deadline
+func requestContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || timeout <= 0 {
+		return context.WithCancel(ctx)
 	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// TransferStats reports response bytes received so far (streams excluded)
+func (c *Client) TransferStats() TransferStats {
+	return TransferStats{Wire: c.transfer.wire.Load(), Decoded: c.transfer.decoded.Load()}
 }
 
 func (c *Client) Snapshot(ctx context.Context) (*model.Snapshot, error) {
+	ctx, cancel := requestContext(ctx, c.timeout)
+	defer cancel()
+
 	fullURL := c.baseURL + c.endpoint
 
 	if strings.HasPrefix(fullURL, "http:/") && !strings.HasPrefix(fullURL, "http://") {
@@ -45,33 +214,20 @@ func (c *Client) Snapshot(ctx context.Context) (*model.Snapshot, error) {
 		return nil, fmt.Errorf("invalid URL %q: %w", fullURL, err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("server returned %s", resp.Status)
-	}
-
 	var snap model.Snapshot
-	if err := json.NewDecoder(resp.Body).Decode(&snap); err != nil {
-		if ctx.Err() != nil {
-			return nil, fmt.Errorf("request timeout: %w", ctx.Err())
-		}
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := c.getSnapshot(ctx, fullURL, &snap); err != nil {
+		return nil, err
 	}
+	c.noteSchemaVersion(snap.SchemaVersion)
 
 	return &snap, nil
 }
 
 func (c *Client) AggregatedSnapshot(ctx context.Context, windowSeconds int) (*model.AggregatedSnapshot, error) {
+	// Aggregation takes up to the window to answer, so allow window + 10 seconds
+	ctx, cancel := requestContext(ctx, time.Duration(windowSeconds+10)*time.Second)
+	defer cancel()
+
 	baseURL := c.baseURL
 	if strings.HasPrefix(baseURL, "http:/") && !strings.HasPrefix(baseURL, "http://") {
 		baseURL = strings.Replace(baseURL, "http:/", "http://", 1)
@@ -89,33 +245,11 @@ func (c *Client) AggregatedSnapshot(ctx context.Context, windowSeconds int) (*mo
 		return nil, fmt.Errorf("invalid URL %q: %w", aggURL, err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, aggURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Use a longer timeout for aggregated requests (window + 10 seconds buffer)
-	aggClient := &http.Client{
-		Timeout: time.Duration(windowSeconds+10) * time.Second,
-	}
-
-	resp, err := aggClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("server returned %s", resp.Status)
-	}
-
 	var aggSnap model.AggregatedSnapshot
-	if err := json.NewDecoder(resp.Body).Decode(&aggSnap); err != nil {
-		if ctx.Err() != nil {
-			return nil, fmt.Errorf("request timeout: %w", ctx.Err())
-		}
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := c.getSnapshot(ctx, aggURL, &aggSnap); err != nil {
+		return nil, err
 	}
+	c.noteSchemaVersion(aggSnap.SchemaVersion)
 
 	utils.Debug("AggregatedSnapshot received: window=%ds, samples=%d, used_kv_cache_bytes.avg=%.2f, used_kv_cache_bytes.count=%d, models=%d",
 		aggSnap.WindowSeconds, aggSnap.SampleCount, aggSnap.UsedKVCacheBytes.Avg, aggSnap.UsedKVCacheBytes.Count, len(aggSnap.Models))
@@ -126,164 +260,20 @@ func (c *Client) AggregatedSnapshot(ctx context.Context, windowSeconds int) (*mo
 	return &aggSnap, nil
 }
 
-func (c *Client) Stream(ctx context.Context, onSnapshot func(*model.Snapshot) error) error {
-	streamURL := c.baseURL + "/vram/stream"
-	if strings.HasPrefix(streamURL, "http:/") && !strings.HasPrefix(streamURL, "http://") {
-		streamURL = strings.Replace(streamURL, "http:/", "http://", 1)
-	}
-	if strings.HasPrefix(streamURL, "https:/") && !strings.HasPrefix(streamURL, "https://") {
-		streamURL = strings.Replace(streamURL, "https:/", "https://", 1)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, streamURL, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Set SSE-specific headers
-	req.Header.Set("Accept", "text/event-stream")
-	req.Header.Set("Cache-Control", "no-cache")
-	req.Header.Set("Connection", "keep-alive")
-
-	// Create a completely isolated HTTP client for SSE
-	// The server sends multiple HTTP responses on the same connection (each SSE event is a full HTTP response)
-	// We need to disable connection pooling entirely to prevent "unsolicited response" errors
-	transport := &http.Transport{
-		DisableKeepAlives:   true, // Disable keep-alive to prevent connection reuse
-		MaxIdleConns:        0,    // No connection pooling
-		MaxIdleConnsPerHost: 0,    // No per-host pooling
-		IdleConnTimeout:     0,    // No timeout
-		DisableCompression:  true, // Disable compression for SSE
-		// Force new connection for each request
-		ForceAttemptHTTP2: false, // Disable HTTP/2 which has different connection handling
-	}
-
-	// Create a dedicated client that won't interfere with other requests
-	streamClient := &http.Client{
-		Timeout:   0, // No timeout for streaming
-		Transport: transport,
-		// Don't follow redirects
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			return http.ErrUseLastResponse
-		},
-	}
-
-	resp, err := streamClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("server returned %s", resp.Status)
-	}
-
-	// Verify content type
-	contentType := resp.Header.Get("Content-Type")
-	if !strings.Contains(contentType, "text/event-stream") {
-		return fmt.Errorf("unexpected content type: %s (expected text/event-stream)", contentType)
-	}
-
-	// The server sends each SSE event as a separate HTTP response
-	// We need to read the raw stream and parse multiple HTTP responses
-	reader := bufio.NewReader(resp.Body)
-	var currentData strings.Builder
-	skipUntilEmptyLine := false
-
-	for {
-		// Check for context cancellation
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-
-		// Read line by line
-		line, err := reader.ReadString('\n')
-		if err != nil {
-			if err == io.EOF {
-				// Process any remaining data before EOF
-				if currentData.Len() > 0 {
-					data := currentData.String()
-					currentData.Reset()
-					var snap model.Snapshot
-					if json.Unmarshal([]byte(data), &snap) == nil {
-						onSnapshot(&snap)
-					}
-				}
-				return nil
-			}
-			return fmt.Errorf("stream read error: %w", err)
-		}
-
-		line = strings.TrimRight(line, "\r\n")
-
-		// Handle HTTP response headers that appear in the stream
-		// The server sends multiple HTTP responses, each starting with headers
-		if strings.HasPrefix(line, "HTTP/") {
-			// New HTTP response - skip headers until empty line
-			skipUntilEmptyLine = true
-			currentData.Reset()
-			continue
-		}
-
-		if skipUntilEmptyLine {
-			if line == "" {
-				// End of headers, start reading SSE data
-				skipUntilEmptyLine = false
-			}
-			continue
-		}
-
-		// Parse SSE format
-		if line == "" {
-			// Empty line indicates end of SSE event
-			if currentData.Len() > 0 {
-				data := currentData.String()
-				currentData.Reset()
-
-				var snap model.Snapshot
-				if err := json.Unmarshal([]byte(data), &snap); err != nil {
-					// Skip malformed JSON
-					continue
-				}
-
-				if err := onSnapshot(&snap); err != nil {
-					return err
-				}
-			}
-			continue
-		}
-
-		// Handle SSE field lines
-		if strings.HasPrefix(line, "data: ") {
-			// Extract data after "data: " prefix
-			data := strings.TrimSpace(line[6:])
-			if data != "" {
-				currentData.Reset()
-				currentData.WriteString(data)
-			}
-		} else if strings.HasPrefix(line, ":") {
-			// SSE comment - ignore
-			continue
-		} else if strings.HasPrefix(line, "event:") {
-			// SSE metadata - ignore
-			continue
-		} else if strings.HasPrefix(line, "id:") {
-			// SSE metadata - ignore
-			continue
-		}
-		// Ignore any other lines (could be HTTP headers from subsequent responses)
-	}
-}
-
+// DeployResponse is the server's answer to POST /deploy. Servers that deploy
+// asynchronously reply right away with a JobID to poll with GetDeployStatus;
+// older servers block until the model is up and leave it empty.
 type DeployResponse struct {
 	Success bool   `json:"success"`
 	Message string `json:"message"`
 	Port    int    `json:"port,omitempty"`
+	JobID   string `json:"job_id,omitempty"`
 }
 
 func (c *Client) DeployModel(ctx context.Context, modelID, hfToken, port string) (*DeployResponse, error) {
+	ctx, cancel := requestContext(ctx, c.timeout)
+	defer cancel()
+
 	baseURL := c.baseURL
 	if strings.HasPrefix(baseURL, "http:/") && !strings.HasPrefix(baseURL, "http://") {
 		baseURL = strings.Replace(baseURL, "http:/", "http://", 1)
@@ -317,20 +307,18 @@ func (c *Client) DeployModel(ctx context.Context, modelID, hfToken, port string)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	c.setHeaders(req)
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := c.http.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, requestError(err)
 	}
 	defer resp.Body.Close()
 
 	var deployResp DeployResponse
 	if err := json.NewDecoder(resp.Body).Decode(&deployResp); err != nil {
-		if ctx.Err() != nil {
-			return nil, fmt.Errorf("request timeout: %w", ctx.Err())
-		}
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return nil, decodeError(ctx, resp, err)
 	}
 
 	return &deployResp, nil
@@ -343,6 +331,9 @@ type SpindownResponse struct {
 }
 
 func (c *Client) SpindownModel(ctx context.Context, modelID, containerID string) (*SpindownResponse, error) {
+	ctx, cancel := requestContext(ctx, c.timeout)
+	defer cancel()
+
 	baseURL := c.baseURL
 	if strings.HasPrefix(baseURL, "http:/") && !strings.HasPrefix(baseURL, "http://") {
 		baseURL = strings.Replace(baseURL, "http:/", "http://", 1)
@@ -373,20 +364,18 @@ func (c *Client) SpindownModel(ctx context.Context, modelID, containerID string)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	c.setHeaders(req)
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := c.http.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, requestError(err)
 	}
 	defer resp.Body.Close()
 
 	var spindownResp SpindownResponse
 	if err := json.NewDecoder(resp.Body).Decode(&spindownResp); err != nil {
-		if ctx.Err() != nil {
-			return nil, fmt.Errorf("request timeout: %w", ctx.Err())
-		}
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return nil, decodeError(ctx, resp, err)
 	}
 
 	return &spindownResp, nil
@@ -397,22 +386,32 @@ type ModelsResponse struct {
 	Running    int             `json:"running"`
 	MaxAllowed int             `json:"max_allowed"`
 	Models     []DeployedModel `json:"models"`
+	NextCursor string          `json:"next_cursor,omitempty"` // Set by servers that page by cursor while more pages remain
 }
 
+// DeployedModel is one entry of GET /models. The omitempty fields are filled
+// in by some sources only (vLLM scrapes, nvidia-smi, newer servers).
 type DeployedModel struct {
 	ModelID                     string  `json:"model_id"`
 	ContainerID                 string  `json:"container_id"`
 	ContainerName               string  `json:"container_name"`
 	Port                        int     `json:"port"`
 	Running                     bool    `json:"running"`
-	ConfiguredMaxGPUUtilization float64 `json:"configured_max_gpu_utilization"`
-	AvgVRAMUsagePercent         float64 `json:"avg_vram_usage_percent"`
-	PeakVRAMUsagePercent        float64 `json:"peak_vram_usage_percent"`
-	GPUType                     string  `json:"gpu_type"`
-	PID                         int     `json:"pid"`
+	ConfiguredMaxGPUUtilization float64 `json:"configured_max_gpu_utilization,omitempty"`
+	AvgVRAMUsagePercent         float64 `json:"avg_vram_usage_percent,omitempty"`
+	PeakVRAMUsagePercent        float64 `json:"peak_vram_usage_percent,omitempty"`
+	GPUType                     string  `json:"gpu_type,omitempty"`
+	PID                         int     `json:"pid,omitempty"`
 }
 
-func (c *Client) ListModels(ctx context.Context) (*ModelsResponse, error) {
+// ListModels fetches one page of deployed models; see NewModelIterator to walk them all.
+// Pages are requested with If-None-Match, and a 304 returns a copy of the cached page.
+// A server that only routes a plain GET /models answers the query with 404; it's
+// asked again without one, and opts are applied to the full list here.
+func (c *Client) ListModels(ctx context.Context, opts ListModelsOptions) (*ModelsResponse, error) {
+	ctx, cancel := requestContext(ctx, c.timeout)
+	defer cancel()
+
 	baseURL := c.baseURL
 	if strings.HasPrefix(baseURL, "http:/") && !strings.HasPrefix(baseURL, "http://") {
 		baseURL = strings.Replace(baseURL, "http:/", "http://", 1)
@@ -421,7 +420,27 @@ func (c *Client) ListModels(ctx context.Context) (*ModelsResponse, error) {
 		baseURL = strings.Replace(baseURL, "https:/", "https://", 1)
 	}
 
-	modelsURL := baseURL + "/models"
+	query := opts.query()
+	if c.noModelsQuery.Load() {
+		query = ""
+	}
+	resp, err := c.listModels(ctx, baseURL+"/models"+query)
+	if errors.Is(err, ErrNotFound) && query != "" {
+		utils.Debug("%s: server has no /models%s, listing every model", c.baseURL, query)
+		c.noModelsQuery.Store(true)
+		query = ""
+		resp, err = c.listModels(ctx, baseURL+"/models")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if query == "" && opts.query() != "" {
+		return ApplyListOptions(resp, opts), nil
+	}
+	return resp, nil
+}
+
+func (c *Client) listModels(ctx context.Context, modelsURL string) (*ModelsResponse, error) {
 	if _, err := url.Parse(modelsURL); err != nil {
 		return nil, fmt.Errorf("invalid URL %q: %w", modelsURL, err)
 	}
@@ -430,24 +449,28 @@ func (c *Client) ListModels(ctx context.Context) (*ModelsResponse, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	c.setHeaders(req)
+	cached, hasCached := c.etags.prepare(req)
 
 	resp, err := c.http.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, requestError(err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("server returned %s", resp.Status)
+	if resp.StatusCode == http.StatusNotModified && hasCached {
+		utils.Debug("GET /models: not modified, reusing cached response")
+		return cached.value.(*ModelsResponse).clone(), nil
+	}
+	if !isSuccess(resp) {
+		return nil, statusError(resp)
 	}
 
 	var modelsResp ModelsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&modelsResp); err != nil {
-		if ctx.Err() != nil {
-			return nil, fmt.Errorf("request timeout: %w", ctx.Err())
-		}
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decodeBody(ctx, resp, &modelsResp); err != nil {
+		return nil, err
 	}
+	c.etags.store(resp, modelsResp.clone())
 
 	return &modelsResp, nil
 }
@@ -460,6 +483,9 @@ type OptimizeResponse struct {
 }
 
 func (c *Client) Optimize(ctx context.Context) (*OptimizeResponse, error) {
+	ctx, cancel := requestContext(ctx, c.timeout)
+	defer cancel()
+
 	baseURL := c.baseURL
 	if strings.HasPrefix(baseURL, "http:/") && !strings.HasPrefix(baseURL, "http://") {
 		baseURL = strings.Replace(baseURL, "http:/", "http://", 1)
@@ -477,19 +503,17 @@ func (c *Client) Optimize(ctx context.Context) (*OptimizeResponse, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	c.setHeaders(req)
 
 	resp, err := c.http.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, requestError(err)
 	}
 	defer resp.Body.Close()
 
 	var optimizeResp OptimizeResponse
 	if err := json.NewDecoder(resp.Body).Decode(&optimizeResp); err != nil {
-		if ctx.Err() != nil {
-			return nil, fmt.Errorf("request timeout: %w", ctx.Err())
-		}
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return nil, decodeError(ctx, resp, err)
 	}
 
 	return &optimizeResp, nil
@@ -0,0 +1,158 @@
+// Package clienttest provides an in-memory client.MetricsClient for exercising
+// UI and command logic without a running blackbox-server.
+package clienttest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/maxdcmn/blackbox-cli/internal/client"
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+)
+
+// Fake returns canned responses and records calls. Set the exported fields
+// before use; one left unset answers with an empty response, and a non-nil
+// Err makes every call fail with it.
+type Fake struct {
+	mu sync.Mutex
+
+	Snap       *model.Snapshot
+	Aggregated *model.AggregatedSnapshot
+	Models     *client.ModelsResponse
+	Deploy     *client.DeployResponse
+	// DeployStatuses are returned in order by GetDeployStatus, repeating the last
+	DeployStatuses []*client.DeployStatus
+	Spindown       *client.SpindownResponse
+	Optimized      *client.OptimizeResponse
+	Warmed         *client.WarmupResult
+	Logs           *client.LogsResponse
+	// StreamSnaps are delivered in order by Stream, which then blocks until ctx is done
+	StreamSnaps []*model.Snapshot
+	Err         error
+
+	Calls []string
+}
+
+var _ client.MetricsClient = (*Fake)(nil)
+
+func (f *Fake) record(call string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Calls = append(f.Calls, call)
+	return f.Err
+}
+
+// CallCount returns how many times the named method was called
+func (f *Fake) CallCount(call string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, c := range f.Calls {
+		if c == call {
+			n++
+		}
+	}
+	return n
+}
+
+// orEmpty stands in a zero response for an unset field, since callers only
+// check the error before reading the response
+func orEmpty[T any](v *T) *T {
+	if v == nil {
+		return new(T)
+	}
+	return v
+}
+
+func (f *Fake) Snapshot(ctx context.Context) (*model.Snapshot, error) {
+	if err := f.record("Snapshot"); err != nil {
+		return nil, err
+	}
+	return orEmpty(f.Snap), nil
+}
+
+func (f *Fake) AggregatedSnapshot(ctx context.Context, windowSeconds int) (*model.AggregatedSnapshot, error) {
+	if err := f.record("AggregatedSnapshot"); err != nil {
+		return nil, err
+	}
+	return orEmpty(f.Aggregated), nil
+}
+
+func (f *Fake) Stream(ctx context.Context, onSnapshot func(*model.Snapshot) error, onStateChange func(client.StreamState)) error {
+	notify := func(s client.StreamState) {
+		if onStateChange != nil {
+			onStateChange(s)
+		}
+	}
+	defer notify(client.StreamClosed)
+	if err := f.record("Stream"); err != nil {
+		return err
+	}
+	notify(client.StreamConnected)
+	for _, s := range f.StreamSnaps {
+		if err := onSnapshot(s); err != nil {
+			return err
+		}
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// ListModels pages Models in memory like a server honouring the query would
+func (f *Fake) ListModels(ctx context.Context, opts client.ListModelsOptions) (*client.ModelsResponse, error) {
+	if err := f.record("ListModels"); err != nil {
+		return nil, err
+	}
+	return client.ApplyListOptions(orEmpty(f.Models), opts), nil
+}
+
+func (f *Fake) DeployModel(ctx context.Context, modelID, hfToken, port string) (*client.DeployResponse, error) {
+	if err := f.record("DeployModel"); err != nil {
+		return nil, err
+	}
+	return orEmpty(f.Deploy), nil
+}
+
+func (f *Fake) GetDeployStatus(ctx context.Context, jobID string) (*client.DeployStatus, error) {
+	if err := f.record("GetDeployStatus"); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.DeployStatuses) == 0 {
+		return &client.DeployStatus{}, nil
+	}
+	s := f.DeployStatuses[0]
+	if len(f.DeployStatuses) > 1 {
+		f.DeployStatuses = f.DeployStatuses[1:]
+	}
+	return s, nil
+}
+
+func (f *Fake) SpindownModel(ctx context.Context, modelID, containerID string) (*client.SpindownResponse, error) {
+	if err := f.record("SpindownModel"); err != nil {
+		return nil, err
+	}
+	return orEmpty(f.Spindown), nil
+}
+
+func (f *Fake) Optimize(ctx context.Context) (*client.OptimizeResponse, error) {
+	if err := f.record("Optimize"); err != nil {
+		return nil, err
+	}
+	return orEmpty(f.Optimized), nil
+}
+
+func (f *Fake) Warmup(ctx context.Context, modelID string, port int, opts client.WarmupOptions) (*client.WarmupResult, error) {
+	if err := f.record("Warmup"); err != nil {
+		return nil, err
+	}
+	return orEmpty(f.Warmed), nil
+}
+
+func (f *Fake) ContainerLogs(ctx context.Context, modelID string, opts client.LogsOptions) (*client.LogsResponse, error) {
+	if err := f.record("ContainerLogs"); err != nil {
+		return nil, err
+	}
+	return orEmpty(f.Logs), nil
+}
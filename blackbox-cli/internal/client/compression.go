@@ -0,0 +1,138 @@
+package client
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+// TransferStats counts response body bytes as received on the wire and after decompression
+type TransferStats struct {
+	Wire    int64
+	Decoded int64
+}
+
+// compressionTransport negotiates gzip/deflate and decodes responses itself
+// (instead of net/http's built-in gzip handling) so wire bytes can be counted.
+type compressionTransport struct {
+	base     *http.Transport
+	maxBytes int64 // Limit on decoded body bytes, so a small gzip body can't expand without bound
+	wire     atomic.Int64
+	decoded  atomic.Int64
+}
+
+func newCompressionTransport(base *http.Transport, maxBytes int64) *compressionTransport {
+	return &compressionTransport{base: base, maxBytes: maxBytes}
+}
+
+func (t *compressionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Accept-Encoding") == "" && req.Header.Get("Range") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("Accept-Encoding", "gzip, deflate")
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	encoding := strings.ToLower(resp.Header.Get("Content-Encoding"))
+	if encoding != "" && encoding != "gzip" && encoding != "deflate" {
+		limitBody(resp, t.maxBytes)
+		return resp, nil
+	}
+	body := &countingBody{
+		wire:   &countingReader{r: resp.Body},
+		closer: resp.Body,
+		label:  req.Method + " " + req.URL.Path,
+		t:      t,
+	}
+	body.decoded = body.wire
+	if encoding != "" {
+		body.encoding = encoding
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		resp.Uncompressed = true
+	}
+	resp.Body = body
+	limitBody(resp, t.maxBytes)
+	return resp, nil
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// countingBody decompresses lazily on first read and logs the byte counts on Close
+type countingBody struct {
+	wire     *countingReader
+	decoded  io.Reader
+	closer   io.Closer
+	encoding string
+	read     int64
+	label    string
+	t        *compressionTransport
+	started  bool
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	if !b.started {
+		b.started = true
+		switch b.encoding {
+		case "gzip":
+			zr, err := gzip.NewReader(b.wire)
+			if err != nil {
+				return 0, err
+			}
+			b.decoded = zr
+		case "deflate":
+			zr, err := newDeflateReader(b.wire)
+			if err != nil {
+				return 0, err
+			}
+			b.decoded = zr
+		}
+	}
+	n, err := b.decoded.Read(p)
+	b.read += int64(n)
+	return n, err
+}
+
+// newDeflateReader decodes a Content-Encoding: deflate body, which is zlib
+// wrapped (RFC 9110). Some servers send raw DEFLATE instead, so a body without
+// a zlib header is read as that.
+func newDeflateReader(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(2)
+	if err == nil && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(br)
+	}
+	return flate.NewReader(br), nil
+}
+
+func (b *countingBody) Close() error {
+	err := b.closer.Close()
+	b.t.wire.Add(b.wire.n)
+	b.t.decoded.Add(b.read)
+	if b.encoding != "" && b.read > 0 {
+		utils.Debug("%s: %d bytes on the wire, %d decoded (%s, %.1fx smaller)",
+			b.label, b.wire.n, b.read, b.encoding, float64(b.read)/float64(max(1, b.wire.n)))
+	} else {
+		utils.Debug("%s: %d bytes (uncompressed)", b.label, b.wire.n)
+	}
+	return err
+}
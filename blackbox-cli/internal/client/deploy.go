@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Deploy job states reported by GET /deploy/{job_id}, in the order a healthy
+// deployment passes through them
+const (
+	DeployStateQueued  = "queued"
+	DeployStatePulling = "pulling_image"
+	DeployStateLoading = "loading_weights"
+	DeployStateServing = "serving"
+	DeployStateFailed  = "failed"
+)
+
+// DeployStatus is the progress of an asynchronous deploy job. Progress is
+// 0-1 within the current state when the server can tell, otherwise 0.
+type DeployStatus struct {
+	JobID    string  `json:"job_id"`
+	ModelID  string  `json:"model_id"`
+	State    string  `json:"state"`
+	Message  string  `json:"message,omitempty"`
+	Progress float64 `json:"progress,omitempty"`
+	Port     int     `json:"port,omitempty"`
+}
+
+// Done reports whether the job has finished, successfully or not
+func (s *DeployStatus) Done() bool {
+	return s.State == DeployStateServing || s.State == DeployStateFailed
+}
+
+// GetDeployStatus fetches the state of a deploy job started by DeployModel
+func (c *Client) GetDeployStatus(ctx context.Context, jobID string) (*DeployStatus, error) {
+	ctx, cancel := requestContext(ctx, c.timeout)
+	defer cancel()
+
+	baseURL := c.baseURL
+	if strings.HasPrefix(baseURL, "http:/") && !strings.HasPrefix(baseURL, "http://") {
+		baseURL = strings.Replace(baseURL, "http:/", "http://", 1)
+	}
+	if strings.HasPrefix(baseURL, "https:/") && !strings.HasPrefix(baseURL, "https://") {
+		baseURL = strings.Replace(baseURL, "https:/", "https://", 1)
+	}
+
+	statusURL := baseURL + "/deploy/" + url.PathEscape(jobID)
+	if _, err := url.Parse(statusURL); err != nil {
+		return nil, fmt.Errorf("invalid URL %q: %w", statusURL, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, statusURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.setHeaders(req)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, requestError(err)
+	}
+	defer resp.Body.Close()
+
+	if !isSuccess(resp) {
+		return nil, statusError(resp)
+	}
+
+	var status DeployStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, decodeError(ctx, resp, err)
+	}
+	if status.State == "" {
+		return nil, fmt.Errorf("deploy job %s: %w: missing state", jobID, ErrBadSchema)
+	}
+	return &status, nil
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+	"github.com/maxdcmn/blackbox-cli/internal/keyring"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+// FromEndpoint builds a client with the per-endpoint settings from config applied,
+// scraping vLLM directly for endpoints of type vllm and running nvidia-smi for local
+func FromEndpoint(ep config.Endpoint, timeout time.Duration, opts ...Option) MetricsClient {
+	if ep.Type == config.EndpointTypeLocal {
+		return NewLocal("", timeout)
+	}
+	epOpts := []Option{WithName(ep.Name), WithRateLimit(ep.RateLimit), WithTimeouts(EndpointTimeouts(ep))}
+	if ep.Transport != "" {
+		epOpts = append(epOpts, WithStreamTransport(ep.Transport))
+	}
+	if len(ep.PinSHA256) > 0 {
+		epOpts = append(epOpts, WithPins(ep.PinSHA256))
+	}
+	if ep.StallTimeout != "" {
+		epOpts = append(epOpts, WithStallTimeout(endpointStallTimeout(ep.StallTimeout)))
+	}
+	if ep.GRPCAddr != "" {
+		epOpts = append(epOpts, WithGRPCAddr(ep.GRPCAddr))
+	}
+	if ep.Proxy != "" {
+		epOpts = append(epOpts, WithProxy(ep.Proxy))
+	}
+	if len(ep.Headers) > 0 {
+		headers, err := keyring.ResolveMap(ep.Headers)
+		if err != nil {
+			epOpts = append(epOpts, withSettingError(fmt.Errorf("header %w", err)))
+		} else {
+			epOpts = append(epOpts, WithHeaders(headers))
+		}
+	}
+	if ep.Type == config.EndpointTypeVLLM {
+		gpuBytes := int64(ep.GPUMemoryGB * 1024 * 1024 * 1024)
+		return NewVLLM(ep.BaseURL, ep.Endpoint, timeout, gpuBytes, append(epOpts, opts...)...)
+	}
+	return New(ep.BaseURL, ep.Endpoint, timeout, append(epOpts, opts...)...)
+}
+
+// withSettingError fails every request with err, for a setting that couldn't be applied
+func withSettingError(err error) Option {
+	return func(c *Client) {
+		c.settingErr = err
+	}
+}
+
+// EndpointTimeouts reads ep's connection phase timeouts; unset or invalid ones are zero
+func EndpointTimeouts(ep config.Endpoint) Timeouts {
+	parse := func(s string) time.Duration {
+		d, _ := utils.ParseDuration(s)
+		return d
+	}
+	return Timeouts{
+		Dial:           parse(ep.DialTimeout),
+		TLSHandshake:   parse(ep.TLSHandshakeTimeout),
+		ResponseHeader: parse(ep.ResponseHeaderTimeout),
+	}
+}
+
+// endpointStallTimeout reads a stall_timeout setting: zero turns detection
+// off, anything unparseable keeps the default
+func endpointStallTimeout(s string) time.Duration {
+	d, err := utils.ParseDuration(s)
+	if err == nil && d == 0 {
+		return -1
+	}
+	return d
+}
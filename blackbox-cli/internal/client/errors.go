@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// Error classes callers can branch on with errors.Is
+var (
+	ErrTimeout      = errors.New("request timed out")
+	ErrUnauthorized = errors.New("auth required")
+	ErrNotFound     = errors.New("not found")
+	ErrBadSchema    = errors.New("unexpected response")
+	ErrUnsupported  = errors.New("not supported by this endpoint type")
+	ErrTooLarge     = errors.New("response too large")
+)
+
+// StatusError is returned for non-2xx responses. 401/403 match ErrUnauthorized
+// and 404 matches ErrNotFound. RequestID is the X-Request-ID that was sent.
+type StatusError struct {
+	StatusCode int
+	Status     string
+	RequestID  string
+}
+
+func (e *StatusError) Error() string {
+	if e.RequestID != "" {
+		return "server returned " + e.Status + " (request id " + e.RequestID + ")"
+	}
+	return "server returned " + e.Status
+}
+
+func (e *StatusError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	}
+	return false
+}
+
+func statusError(resp *http.Response) error {
+	err := &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	if resp.Request != nil {
+		err.RequestID = resp.Request.Header.Get(RequestIDHeader)
+	}
+	return err
+}
+
+func isSuccess(resp *http.Response) bool {
+	return resp.StatusCode >= 200 && resp.StatusCode < 300
+}
+
+func requestError(err error) error {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return fmt.Errorf("request failed: %w: %w", ErrTimeout, err)
+	}
+	return fmt.Errorf("request failed: %w", err)
+}
+
+// decodeError classifies a failed body decode: a body over the size limit, a
+// timeout, an error status whose body isn't the expected JSON, or a body that
+// doesn't match the schema.
+func decodeError(ctx context.Context, resp *http.Response, err error) error {
+	if tooLarge := tooLargeError(err); tooLarge != nil {
+		return tooLarge
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("%w: %w", ErrTimeout, ctx.Err())
+	}
+	if !isSuccess(resp) {
+		return statusError(resp)
+	}
+	return fmt.Errorf("failed to decode response: %w: %w", ErrBadSchema, err)
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"net/http"
+	"sync"
+)
+
+// maxETagEntries bounds the cache; paging through /models adds one entry per page
+const maxETagEntries = 64
+
+// etagCache keeps the last decoded response per URL with its ETag, so a 304
+// Not Modified answer is served without reading or decoding a body
+type etagCache struct {
+	mu      sync.Mutex
+	entries map[string]etagEntry
+}
+
+type etagEntry struct {
+	etag  string
+	value any
+}
+
+// prepare adds If-None-Match to req when a response for its URL is cached
+func (e *etagCache) prepare(req *http.Request) (etagEntry, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	entry, ok := e.entries[req.URL.String()]
+	if ok {
+		req.Header.Set("If-None-Match", entry.etag)
+	}
+	return entry, ok
+}
+
+// store remembers value for resp's URL if the server sent an ETag
+func (e *etagCache) store(resp *http.Response, value any) {
+	etag := resp.Header.Get("ETag")
+	if etag == "" || resp.Request == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.entries == nil || len(e.entries) >= maxETagEntries {
+		e.entries = make(map[string]etagEntry)
+	}
+	e.entries[resp.Request.URL.String()] = etagEntry{etag: etag, value: value}
+}
+
+// clone copies the response so callers can't modify a cached one
+func (r *ModelsResponse) clone() *ModelsResponse {
+	out := *r
+	out.Models = append([]DeployedModel(nil), r.Models...)
+	return &out
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+type fieldsKey struct{}
+
+// WithFields makes Snapshot and AggregatedSnapshot calls using the returned
+// context ask for only the named top-level JSON fields, e.g.
+// "allocated_vram_bytes" or "models", with ?fields=a,b. Servers without
+// projections ignore or turn down the parameter and send everything; either
+// way, fields that weren't asked for may decode as zero values. Clients that
+// don't talk to blackbox-server ignore it.
+func WithFields(ctx context.Context, fields ...string) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, fieldsKey{}, fields)
+}
+
+func fieldsFrom(ctx context.Context) []string {
+	fields, _ := ctx.Value(fieldsKey{}).([]string)
+	return fields
+}
+
+// withFields adds the fields query parameter to rawURL, unless the server
+// has already turned it down
+func (c *Client) withFields(rawURL string, fields []string) string {
+	if len(fields) == 0 || c.noFields.Load() {
+		return rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	q := u.Query()
+	q.Set("fields", strings.Join(fields, ","))
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// getSnapshot fetches rawURL into v, projected to ctx's fields. A server that
+// answers a projected request with a 4xx, such as 400 or the 404 of one that
+// routes only the exact path, is asked again without fields, and isn't sent
+// them again.
+func (c *Client) getSnapshot(ctx context.Context, rawURL string, v any) error {
+	fields := fieldsFrom(ctx)
+	projected := c.withFields(rawURL, fields)
+	resp, err := c.get(ctx, projected)
+	if err != nil {
+		return err
+	}
+	if rejectsFields(resp) && projected != rawURL {
+		resp.Body.Close()
+		utils.Debug("%s: server answered ?fields with %s, fetching full snapshots", c.baseURL, resp.Status)
+		c.noFields.Store(true)
+		fields = nil
+		if resp, err = c.get(ctx, rawURL); err != nil {
+			return err
+		}
+	}
+	defer resp.Body.Close()
+
+	if !isSuccess(resp) {
+		return statusError(resp)
+	}
+	if projected == rawURL {
+		fields = nil
+	}
+	return decodeBody(ctx, resp, v, fields...)
+}
+
+// rejectsFields reports whether resp turns down a projected request. Auth
+// failures and rate limiting would fail the plain request as well.
+func rejectsFields(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+		return false
+	}
+	return resp.StatusCode >= 400 && resp.StatusCode < 500
+}
+
+func (c *Client) get(ctx context.Context, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.setHeaders(req)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, requestError(err)
+	}
+	return resp, nil
+}
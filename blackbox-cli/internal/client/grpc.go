@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+	blackboxpb "github.com/maxdcmn/blackbox-cli/internal/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// errGRPCUnavailable is returned when the server can't be reached over gRPC or
+// doesn't implement the stream, letting Stream fall back to SSE.
+var errGRPCUnavailable = errors.New("server does not support grpc streaming")
+
+// WithGRPCAddr sets the host:port of the gRPC listener used by the grpc stream
+// transport; by default it is the host and port of the base URL.
+func WithGRPCAddr(addr string) Option {
+	return func(c *Client) {
+		c.grpcAddr = addr
+	}
+}
+
+// grpcTarget returns the dial target and whether to use TLS (when the base URL is https)
+func (c *Client) grpcTarget() (string, bool, error) {
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid URL %q: %w", c.baseURL, err)
+	}
+	secure := u.Scheme == "https"
+	if c.grpcAddr != "" {
+		return c.grpcAddr, secure, nil
+	}
+	if u.Host == "" {
+		return "", false, fmt.Errorf("invalid URL %q: no host", c.baseURL)
+	}
+	return u.Host, secure, nil
+}
+
+func (c *Client) streamGRPC(ctx context.Context, onSnapshot func(*model.Snapshot) error, onConnected func()) error {
+	target, secure, err := c.grpcTarget()
+	if err != nil {
+		return &permanentError{err}
+	}
+	if c.settingErr != nil {
+		return &permanentError{c.settingErr}
+	}
+	creds := insecure.NewCredentials()
+	if secure {
+		cfg := &tls.Config{}
+		if c.pins != nil {
+			host, _, err := net.SplitHostPort(target)
+			if err != nil {
+				host = target
+			}
+			cfg = c.pins.tlsConfigFor(host)
+		}
+		creds = credentials.NewTLS(cfg)
+	}
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return c.timeouts.dialer().DialContext(ctx, "tcp", addr)
+		}),
+	}
+
+	// Endpoint headers travel as metadata; Host becomes the :authority
+	header := c.header()
+	identify(header)
+	md := metadata.MD{}
+	for k, v := range header {
+		if strings.EqualFold(k, "Host") {
+			if len(v) > 0 {
+				dialOpts = append(dialOpts, grpc.WithAuthority(v[0]))
+			}
+			continue
+		}
+		if strings.EqualFold(k, "User-Agent") {
+			dialOpts = append(dialOpts, grpc.WithUserAgent(v[0]))
+			continue
+		}
+		md.Append(strings.ToLower(k), v...)
+	}
+
+	conn, err := grpc.NewClient(target, dialOpts...)
+	if err != nil {
+		return &permanentError{fmt.Errorf("grpc: %w", err)}
+	}
+	defer conn.Close()
+
+	stream, err := blackboxpb.NewVRAMClient(conn).StreamSnapshots(metadata.NewOutgoingContext(ctx, md), &blackboxpb.StreamRequest{})
+	if err != nil {
+		return grpcError(err, true)
+	}
+	connected := false
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return grpcError(err, !connected)
+		}
+		if !connected {
+			onConnected()
+			connected = true
+		}
+		if err := onSnapshot(snapshotFromProto(msg)); err != nil {
+			return err
+		}
+	}
+}
+
+// grpcError maps status codes onto the client's error classes. Unavailable and
+// Unimplemented before the first snapshot mean gRPC isn't served there.
+func grpcError(err error, beforeFirst bool) error {
+	switch status.Code(err) {
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return &permanentError{fmt.Errorf("grpc: %w: %w", ErrUnauthorized, err)}
+	case codes.DeadlineExceeded:
+		return fmt.Errorf("grpc: %w: %w", ErrTimeout, err)
+	case codes.Unavailable, codes.Unimplemented:
+		if beforeFirst {
+			return fmt.Errorf("%w: %w", errGRPCUnavailable, err)
+		}
+	}
+	return fmt.Errorf("grpc stream: %w", err)
+}
+
+func snapshotFromProto(p *blackboxpb.Snapshot) *model.Snapshot {
+	s := &model.Snapshot{
+		TotalVRAMBytes:     p.GetTotalVramBytes(),
+		AllocatedVRAMBytes: p.GetAllocatedVramBytes(),
+		UsedKVCacheBytes:   p.GetUsedKvCacheBytes(),
+		PrefixCacheHitRate: p.GetPrefixCacheHitRate(),
+	}
+	for _, m := range p.GetModels() {
+		s.Models = append(s.Models, model.ModelInfo{
+			ModelID:            m.GetModelId(),
+			Port:               int(m.GetPort()),
+			AllocatedVRAMBytes: m.GetAllocatedVramBytes(),
+			UsedKVCacheBytes:   m.GetUsedKvCacheBytes(),
+		})
+	}
+	return s
+}
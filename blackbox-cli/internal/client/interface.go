@@ -0,0 +1,24 @@
+package client
+
+import (
+	"context"
+
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+)
+
+// MetricsClient is the blackbox-server API used by the dashboard and commands.
+// *Client implements it; clienttest.Fake is an in-memory stand-in for tests.
+type MetricsClient interface {
+	Snapshot(ctx context.Context) (*model.Snapshot, error)
+	AggregatedSnapshot(ctx context.Context, windowSeconds int) (*model.AggregatedSnapshot, error)
+	Stream(ctx context.Context, onSnapshot func(*model.Snapshot) error, onStateChange func(StreamState)) error
+	ListModels(ctx context.Context, opts ListModelsOptions) (*ModelsResponse, error)
+	DeployModel(ctx context.Context, modelID, hfToken, port string) (*DeployResponse, error)
+	GetDeployStatus(ctx context.Context, jobID string) (*DeployStatus, error)
+	SpindownModel(ctx context.Context, modelID, containerID string) (*SpindownResponse, error)
+	Optimize(ctx context.Context) (*OptimizeResponse, error)
+	Warmup(ctx context.Context, modelID string, port int, opts WarmupOptions) (*WarmupResult, error)
+	ContainerLogs(ctx context.Context, modelID string, opts LogsOptions) (*LogsResponse, error)
+}
+
+var _ MetricsClient = (*Client)(nil)
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Size limits on what the server may send, so a misbehaving server or a URL
+// pointing at something else (a model's completion endpoint, a file server)
+// can't grow the CLI's memory without bound
+const (
+	// DefaultMaxResponseBytes caps one response body after decompression;
+	// a /models page of hundreds of containers is well under 1 MiB
+	DefaultMaxResponseBytes = 16 << 20
+	// maxStreamEventBytes caps one SSE line or WebSocket message
+	maxStreamEventBytes = 4 << 20
+)
+
+// WithMaxResponseBytes caps each response body at n bytes after
+// decompression; zero keeps DefaultMaxResponseBytes
+func WithMaxResponseBytes(n int64) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.maxResponseBytes = n
+		}
+	}
+}
+
+// limitBody makes reading past limit fail with an *http.MaxBytesError
+func limitBody(resp *http.Response, limit int64) {
+	if limit > 0 {
+		resp.Body = http.MaxBytesReader(nil, resp.Body, limit)
+	}
+}
+
+// tooLargeError reports err as ErrTooLarge when a size limit tripped, else returns nil
+func tooLargeError(err error) error {
+	var maxErr *http.MaxBytesError
+	if !errors.As(err, &maxErr) {
+		return nil
+	}
+	return fmt.Errorf("%w: more than %s (is the URL a blackbox-server?)", ErrTooLarge, formatLimit(maxErr.Limit))
+}
+
+func formatLimit(n int64) string {
+	if n >= 1<<20 && n%(1<<20) == 0 {
+		return fmt.Sprintf("%d MiB", n>>20)
+	}
+	return fmt.Sprintf("%d bytes", n)
+}
+
+// readLine is bufio.Reader.ReadString('\n') that fails with an
+// *http.MaxBytesError instead of buffering a line longer than limit
+func readLine(r *bufio.Reader, limit int) (string, error) {
+	var line []byte
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if len(line)+len(chunk) > limit {
+			return "", &http.MaxBytesError{Limit: int64(limit)}
+		}
+		line = append(line, chunk...)
+		if err != bufio.ErrBufferFull {
+			return string(line), err
+		}
+	}
+}
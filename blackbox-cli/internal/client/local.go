@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/collector"
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+)
+
+const localStreamInterval = time.Second
+
+// LocalClient reads this machine's GPUs through nvidia-smi, for workstations
+// without blackbox-server. It reports VRAM per GPU and GPU process but no KV cache or
+// prefix cache figures; model management calls return ErrUnsupported.
+type LocalClient struct {
+	smi     *collector.NvidiaSMI
+	timeout time.Duration
+}
+
+var _ MetricsClient = (*LocalClient)(nil)
+
+// NewLocal uses the nvidia-smi at path, or the one on PATH when path is empty
+func NewLocal(path string, timeout time.Duration) *LocalClient {
+	return &LocalClient{smi: &collector.NvidiaSMI{Path: path}, timeout: timeout}
+}
+
+func (l *LocalClient) Snapshot(ctx context.Context) (*model.Snapshot, error) {
+	ctx, cancel := requestContext(ctx, l.timeout)
+	defer cancel()
+	snap, err := l.smi.Snapshot(ctx)
+	if err != nil && ctx.Err() != nil {
+		return nil, fmt.Errorf("nvidia-smi: %w: %w", ErrTimeout, ctx.Err())
+	}
+	return snap, err
+}
+
+// AggregatedSnapshot returns a single reading as a one-sample aggregate
+func (l *LocalClient) AggregatedSnapshot(ctx context.Context, windowSeconds int) (*model.AggregatedSnapshot, error) {
+	snap, err := l.Snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &model.AggregatedSnapshot{
+		TotalVRAMBytes:     snap.TotalVRAMBytes,
+		WindowSeconds:      windowSeconds,
+		SampleCount:        1,
+		AllocatedVRAMBytes: singleSample(float64(snap.AllocatedVRAMBytes)),
+		UsedKVCacheBytes:   singleSample(0),
+		PrefixCacheHitRate: singleSample(0),
+		NumRequestsRunning: singleSample(0),
+		NumRequestsWaiting: singleSample(0),
+		Models:             snap.Models,
+		GPUs:               snap.GPUs,
+	}, nil
+}
+
+func (l *LocalClient) Stream(ctx context.Context, onSnapshot func(*model.Snapshot) error, onStateChange func(StreamState)) error {
+	return pollStream(ctx, localStreamInterval, l.Snapshot, onSnapshot, onStateChange)
+}
+
+// ListModels lists GPU compute processes, since there is no deployment registry
+func (l *LocalClient) ListModels(ctx context.Context, opts ListModelsOptions) (*ModelsResponse, error) {
+	ctx, cancel := requestContext(ctx, l.timeout)
+	defer cancel()
+	procs, err := l.smi.Processes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp := &ModelsResponse{Total: len(procs), Running: len(procs)}
+	for _, p := range procs {
+		resp.Models = append(resp.Models, DeployedModel{ModelID: filepath.Base(p.Name), Running: true, PID: p.PID})
+	}
+	return ApplyListOptions(resp, opts), nil
+}
+
+func (l *LocalClient) DeployModel(ctx context.Context, modelID, hfToken, port string) (*DeployResponse, error) {
+	return nil, fmt.Errorf("deploy: %w", ErrUnsupported)
+}
+
+func (l *LocalClient) GetDeployStatus(ctx context.Context, jobID string) (*DeployStatus, error) {
+	return nil, fmt.Errorf("deploy: %w", ErrUnsupported)
+}
+
+func (l *LocalClient) SpindownModel(ctx context.Context, modelID, containerID string) (*SpindownResponse, error) {
+	return nil, fmt.Errorf("spindown: %w", ErrUnsupported)
+}
+
+func (l *LocalClient) Optimize(ctx context.Context) (*OptimizeResponse, error) {
+	return nil, fmt.Errorf("optimize: %w", ErrUnsupported)
+}
+
+func (l *LocalClient) Warmup(ctx context.Context, modelID string, port int, opts WarmupOptions) (*WarmupResult, error) {
+	return nil, fmt.Errorf("warm-up: %w", ErrUnsupported)
+}
+
+func (l *LocalClient) ContainerLogs(ctx context.Context, modelID string, opts LogsOptions) (*LogsResponse, error) {
+	return nil, fmt.Errorf("logs: %w", ErrUnsupported)
+}
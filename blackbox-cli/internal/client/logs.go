@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultLogTail is how many lines ContainerLogs asks for when the options don't say
+	DefaultLogTail = 200
+	// MaxLogTail is the most lines blackbox-server returns for one request
+	MaxLogTail = 1000
+)
+
+// LogsOptions selects the lines of GET /logs. Since asks for the lines from
+// then on, to follow a log from the newest line read; zero for the last Tail.
+type LogsOptions struct {
+	Tail  int
+	Since time.Time
+}
+
+func (o LogsOptions) query(modelID string) string {
+	q := url.Values{}
+	q.Set("model", modelID)
+	tail := o.Tail
+	if tail <= 0 {
+		tail = DefaultLogTail
+	}
+	q.Set("tail", strconv.Itoa(tail))
+	if !o.Since.IsZero() {
+		q.Set("since", fmt.Sprintf("%d.%09d", o.Since.Unix(), o.Since.Nanosecond()))
+	}
+	return "?" + q.Encode()
+}
+
+// LogLine is one line of a model container's output. Time is zero for lines
+// docker didn't timestamp.
+type LogLine struct {
+	Time time.Time `json:"time"`
+	Text string    `json:"text"`
+}
+
+type LogsResponse struct {
+	ContainerName string    `json:"container_name"`
+	Lines         []LogLine `json:"lines"`
+}
+
+// ContainerLogs reads the latest log lines of a model's container, oldest
+// first. modelID may also be the container's name or ID.
+func (c *Client) ContainerLogs(ctx context.Context, modelID string, opts LogsOptions) (*LogsResponse, error) {
+	ctx, cancel := requestContext(ctx, c.timeout)
+	defer cancel()
+
+	baseURL := c.baseURL
+	if strings.HasPrefix(baseURL, "http:/") && !strings.HasPrefix(baseURL, "http://") {
+		baseURL = strings.Replace(baseURL, "http:/", "http://", 1)
+	}
+	if strings.HasPrefix(baseURL, "https:/") && !strings.HasPrefix(baseURL, "https://") {
+		baseURL = strings.Replace(baseURL, "https:/", "https://", 1)
+	}
+
+	logsURL := baseURL + "/logs" + opts.query(modelID)
+	if _, err := url.Parse(logsURL); err != nil {
+		return nil, fmt.Errorf("invalid URL %q: %w", logsURL, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, logsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.setHeaders(req)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, requestError(err)
+	}
+	defer resp.Body.Close()
+
+	if !isSuccess(resp) {
+		// The server says why, e.g. no container for the model
+		var failure struct {
+			Message string `json:"message"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&failure) == nil && failure.Message != "" {
+			return nil, fmt.Errorf("%s: %w", failure.Message, statusError(resp))
+		}
+		return nil, statusError(resp)
+	}
+
+	var logsResp LogsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&logsResp); err != nil {
+		return nil, decodeError(ctx, resp, err)
+	}
+
+	return &logsResp, nil
+}
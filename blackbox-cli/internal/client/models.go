@@ -0,0 +1,257 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+// DefaultModelsPageSize is the most models the models command asks for per request
+const DefaultModelsPageSize = 50
+
+// MaxModelPages is how many pages ModelIterator fetches before giving up, so a
+// server that never reports the end can't keep it paging forever
+const MaxModelPages = 200
+
+// Model status filters for ListModelsOptions.Status
+const (
+	ModelStatusRunning = "running"
+	ModelStatusStopped = "stopped"
+)
+
+// ListModelsOptions selects one page of /models. Sort is a field name
+// (model_id, status, vram) with a leading "-" for descending. Cursor is a
+// previous page's NextCursor and replaces Offset for servers that page by
+// cursor. Zero values are left out of the query so older servers see a
+// plain GET /models.
+type ListModelsOptions struct {
+	Offset int
+	Limit  int
+	Status string
+	Sort   string
+	Cursor string
+}
+
+func (o ListModelsOptions) query() string {
+	q := url.Values{}
+	if o.Cursor != "" {
+		q.Set("cursor", o.Cursor)
+	} else if o.Offset > 0 {
+		q.Set("offset", strconv.Itoa(o.Offset))
+	}
+	if o.Limit > 0 {
+		q.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Status != "" {
+		q.Set("status", o.Status)
+	}
+	if o.Sort != "" {
+		q.Set("sort", o.Sort)
+	}
+	if len(q) == 0 {
+		return ""
+	}
+	return "?" + q.Encode()
+}
+
+// ApplyListOptions filters, sorts and pages a full model list in memory, for
+// sources without server-side paging
+func ApplyListOptions(resp *ModelsResponse, opts ListModelsOptions) *ModelsResponse {
+	out := *resp
+	out.Models = nil
+	for _, m := range resp.Models {
+		if matchesStatus(m, opts.Status) {
+			out.Models = append(out.Models, m)
+		}
+	}
+	sortModels(out.Models, opts.Sort)
+	out.Total = len(out.Models)
+	start := min(opts.Offset, len(out.Models))
+	end := len(out.Models)
+	if opts.Limit > 0 {
+		end = min(start+opts.Limit, end)
+	}
+	out.Models = out.Models[start:end]
+	return &out
+}
+
+// MatchModelID reports whether modelID matches pattern, ignoring case. A
+// pattern with * or ? must match the whole ID, and * also spans "/"; a plain
+// pattern matches anywhere in the ID.
+func MatchModelID(pattern, modelID string) bool {
+	pattern, modelID = strings.ToLower(pattern), strings.ToLower(modelID)
+	if !strings.ContainsAny(pattern, "*?") {
+		return strings.Contains(modelID, pattern)
+	}
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+	re, err := regexp.Compile("^" + expr + "$")
+	return err == nil && re.MatchString(modelID)
+}
+
+func matchesStatus(m DeployedModel, status string) bool {
+	switch status {
+	case ModelStatusRunning:
+		return m.Running
+	case ModelStatusStopped:
+		return !m.Running
+	}
+	return true
+}
+
+func sortModels(models []DeployedModel, by string) {
+	desc := strings.HasPrefix(by, "-")
+	var less func(a, b DeployedModel) bool
+	switch strings.TrimPrefix(by, "-") {
+	case "model_id":
+		less = func(a, b DeployedModel) bool { return a.ModelID < b.ModelID }
+	case "status":
+		less = func(a, b DeployedModel) bool { return a.Running && !b.Running }
+	case "vram":
+		less = func(a, b DeployedModel) bool { return a.AvgVRAMUsagePercent < b.AvgVRAMUsagePercent }
+	default:
+		return
+	}
+	sort.SliceStable(models, func(i, j int) bool {
+		if desc {
+			return less(models[j], models[i])
+		}
+		return less(models[i], models[j])
+	})
+}
+
+// ModelIterator walks every page of ListModels, by offset or, when the
+// server returns next_cursor, by cursor:
+//
+//	it := client.NewModelIterator(c, client.ListModelsOptions{Status: client.ModelStatusRunning})
+//	for it.Next(ctx) {
+//		m := it.Model()
+//	}
+//	if err := it.Err(); err != nil { ... }
+//
+// NextPage walks a page at a time instead, e.g. to give each request its own
+// timeout or to show models as they arrive.
+type ModelIterator struct {
+	c    MetricsClient
+	opts ListModelsOptions
+
+	page      *ModelsResponse
+	idx       int
+	fetched   int
+	pages     int
+	done      bool
+	truncated bool
+	err       error
+}
+
+// NewModelIterator starts at opts.Offset and requests opts.Limit models per
+// page. Without a Limit the server picks the page size, and a server that
+// doesn't page sends every model in one plain GET /models.
+func NewModelIterator(c MetricsClient, opts ListModelsOptions) *ModelIterator {
+	return &ModelIterator{c: c, opts: opts}
+}
+
+// Next advances to the next model, fetching a page when needed
+func (it *ModelIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if it.page != nil && it.idx+1 < len(it.page.Models) {
+		it.idx++
+		return true
+	}
+	if !it.NextPage(ctx) {
+		return false
+	}
+	it.idx = 0
+	return true
+}
+
+// NextPage fetches the next page, available from Page, and reports whether it
+// had any models. Next continues after it.
+func (it *ModelIterator) NextPage(ctx context.Context) bool {
+	if it.err != nil || it.done {
+		return false
+	}
+	if it.pages >= MaxModelPages {
+		utils.Debug("models: stopping after %d pages (%d of %d models)", it.pages, it.fetched, it.page.Total)
+		it.done, it.truncated = true, true
+		return false
+	}
+	page, err := it.c.ListModels(ctx, it.opts)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	if page == nil {
+		page = &ModelsResponse{}
+	}
+	// Old servers ignore the query and return everything: filter here and stop after one page
+	if it.opts.Limit > 0 && len(page.Models) > it.opts.Limit {
+		page = ApplyListOptions(page, ListModelsOptions{Status: it.opts.Status, Sort: it.opts.Sort})
+		it.done = true
+	}
+	// Positioned on the page's last model, so Next fetches the following page
+	it.page, it.idx = page, len(page.Models)-1
+	it.pages++
+	it.fetched += len(page.Models)
+	it.opts.Offset += len(page.Models)
+	switch {
+	case page.NextCursor != "":
+		// The cursor says whether there's more; a repeated one would loop
+		it.done = len(page.Models) == 0 || page.NextCursor == it.opts.Cursor
+		it.opts.Cursor = page.NextCursor
+	case it.opts.Cursor != "":
+		it.done = true
+	case len(page.Models) == 0 || it.fetched >= page.Total:
+		it.done = true
+	case it.opts.Limit > 0 && len(page.Models) < it.opts.Limit:
+		it.done = true
+	}
+	return len(page.Models) > 0
+}
+
+// Done reports whether there are no more pages to fetch
+func (it *ModelIterator) Done() bool {
+	return it.done || it.err != nil
+}
+
+// Truncated reports whether paging stopped at MaxModelPages with models left
+func (it *ModelIterator) Truncated() bool {
+	return it.truncated
+}
+
+func (it *ModelIterator) Model() DeployedModel {
+	return it.page.Models[it.idx]
+}
+
+// Page returns the response the current model came from, for Total/Running/MaxAllowed
+func (it *ModelIterator) Page() *ModelsResponse {
+	return it.page
+}
+
+func (it *ModelIterator) Err() error {
+	return it.err
+}
+
+// AllModels collects every page into one response
+func AllModels(ctx context.Context, c MetricsClient, opts ListModelsOptions) (*ModelsResponse, error) {
+	it := NewModelIterator(c, opts)
+	all := &ModelsResponse{Models: []DeployedModel{}}
+	for it.Next(ctx) {
+		all.Models = append(all.Models, it.Model())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	if p := it.Page(); p != nil {
+		all.Total, all.Running, all.MaxAllowed = p.Total, p.Running, p.MaxAllowed
+	}
+	return all, nil
+}
@@ -0,0 +1,153 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+// Multi runs the same request against several endpoints concurrently, each
+// with its own timeout
+type Multi struct {
+	endpoints []config.Endpoint
+	clients   []MetricsClient
+	timeouts  []time.Duration
+}
+
+// SnapshotResults is keyed by endpoint name; each endpoint is in exactly one map
+type SnapshotResults struct {
+	Snapshots map[string]*model.Snapshot
+	Errors    map[string]error
+}
+
+// ModelsResults is keyed by endpoint name; each endpoint is in exactly one map
+type ModelsResults struct {
+	Models map[string]*ModelsResponse
+	Errors map[string]error
+}
+
+// NewMulti builds a client per endpoint with FromEndpoint. Endpoints use their
+// configured timeout, or timeout when they have none.
+func NewMulti(endpoints []config.Endpoint, timeout time.Duration, opts ...Option) *Multi {
+	return NewMultiFunc(endpoints, timeout, func(ep config.Endpoint) MetricsClient {
+		return FromEndpoint(ep, EndpointTimeout(ep, timeout), opts...)
+	})
+}
+
+// NewMultiFunc is NewMulti with the clients supplied by clientFor, which is
+// called for every endpoint before NewMultiFunc returns
+func NewMultiFunc(endpoints []config.Endpoint, timeout time.Duration, clientFor func(config.Endpoint) MetricsClient) *Multi {
+	m := &Multi{
+		endpoints: endpoints,
+		clients:   make([]MetricsClient, len(endpoints)),
+		timeouts:  make([]time.Duration, len(endpoints)),
+	}
+	for i, ep := range endpoints {
+		m.clients[i] = clientFor(ep)
+		m.timeouts[i] = EndpointTimeout(ep, timeout)
+	}
+	return m
+}
+
+// Endpoints returns the endpoints in the order they were given, for ordered output
+func (m *Multi) Endpoints() []config.Endpoint {
+	return m.endpoints
+}
+
+// Snapshot fetches a snapshot from every endpoint
+func (m *Multi) Snapshot(ctx context.Context) SnapshotResults {
+	return m.snapshot(ctx, 0)
+}
+
+// SnapshotWithRequests is Snapshot with the request counts of every snapshot
+// filled in from windowSeconds of aggregated samples, see FillRequestCounts
+func (m *Multi) SnapshotWithRequests(ctx context.Context, windowSeconds int) SnapshotResults {
+	return m.snapshot(ctx, windowSeconds)
+}
+
+func (m *Multi) snapshot(ctx context.Context, requestsWindow int) SnapshotResults {
+	snaps := make([]*model.Snapshot, len(m.endpoints))
+	errs := m.each(ctx, func(ctx context.Context, i int, c MetricsClient) (err error) {
+		if snaps[i], err = c.Snapshot(ctx); err == nil && requestsWindow > 0 {
+			FillRequestCounts(ctx, c, snaps[i], requestsWindow)
+		}
+		return err
+	})
+	res := SnapshotResults{Snapshots: make(map[string]*model.Snapshot), Errors: errs}
+	for i, ep := range m.endpoints {
+		if errs[ep.Name] == nil {
+			res.Snapshots[ep.Name] = snaps[i]
+		}
+	}
+	return res
+}
+
+// ListModels fetches every page of models from every endpoint; see AllModels
+func (m *Multi) ListModels(ctx context.Context, opts ListModelsOptions) ModelsResults {
+	models := make([]*ModelsResponse, len(m.endpoints))
+	errs := m.each(ctx, func(ctx context.Context, i int, c MetricsClient) (err error) {
+		models[i], err = AllModels(ctx, c, opts)
+		return err
+	})
+	res := ModelsResults{Models: make(map[string]*ModelsResponse), Errors: errs}
+	for i, ep := range m.endpoints {
+		if errs[ep.Name] == nil {
+			res.Models[ep.Name] = models[i]
+		}
+	}
+	return res
+}
+
+// each calls fn for every endpoint concurrently under that endpoint's timeout
+// and returns the errors by endpoint name
+func (m *Multi) each(ctx context.Context, fn func(ctx context.Context, i int, c MetricsClient) error) map[string]error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs = make(map[string]error)
+	)
+	for i := range m.endpoints {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, m.timeouts[i])
+			defer cancel()
+			if err := fn(ctx, i, m.clients[i]); err != nil {
+				mu.Lock()
+				errs[m.endpoints[i].Name] = err
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	return errs
+}
+
+// EndpointTimeout is ep's configured timeout, or fallback when it is unset or invalid
+func EndpointTimeout(ep config.Endpoint, fallback time.Duration) time.Duration {
+	timeout, err := utils.ParseDuration(ep.Timeout)
+	if err != nil || timeout == 0 {
+		return fallback
+	}
+	return timeout
+}
+
+// FillRequestCounts sets the running and waiting requests of s, which /vram
+// snapshots don't carry, from the server's last windowSeconds of aggregated
+// samples. It's best effort: when that fails they stay zero.
+func FillRequestCounts(ctx context.Context, c MetricsClient, s *model.Snapshot, windowSeconds int) {
+	if s.NumRequestsRunning != 0 || s.NumRequestsWaiting != 0 {
+		return
+	}
+	agg, err := c.AggregatedSnapshot(ctx, windowSeconds)
+	if err != nil {
+		utils.Debug("no request counts: %v", err)
+		return
+	}
+	// As the dashboard shows them, see poller.MergeAggregated
+	s.NumRequestsRunning, s.NumRequestsWaiting = agg.NumRequestsRunning.Avg, agg.NumRequestsWaiting.Max
+}
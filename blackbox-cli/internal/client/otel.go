@@ -0,0 +1,88 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/maxdcmn/blackbox-cli/internal/tracing"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithName labels the client's spans with the endpoint's configured name
+func WithName(name string) Option {
+	return func(c *Client) {
+		c.name = name
+	}
+}
+
+// otelTransport wraps each request in a client span and passes the span's
+// context to the server in traceparent headers. The span ends when the body
+// is closed, so streams are timed end to end.
+type otelTransport struct {
+	base http.RoundTripper
+	name string
+}
+
+func (t *otelTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !tracing.Enabled() {
+		return t.base.RoundTrip(req)
+	}
+	route := routeOf(req.URL.Path)
+	ctx, span := tracing.Tracer().Start(tracing.Parent(req.Context()), req.Method+" "+route,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.HTTPRoute(route),
+			semconv.URLFull(utils.RedactURL(req.URL)),
+			semconv.ServerAddress(req.URL.Hostname()),
+			attribute.String("blackbox.endpoint", t.name),
+			attribute.String("blackbox.request_id", req.Header.Get(RequestIDHeader)),
+		))
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		tracing.End(span, err)
+		return nil, err
+	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	resp.Body = &spanBody{ReadCloser: resp.Body, span: span}
+	return resp, nil
+}
+
+// routeOf turns a request path into a low-cardinality span name
+func routeOf(path string) string {
+	if strings.HasPrefix(path, "/deploy/") {
+		return "/deploy/{job_id}"
+	}
+	return path
+}
+
+type spanBody struct {
+	io.ReadCloser
+	span trace.Span
+	once sync.Once
+}
+
+func (b *spanBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() { b.span.End() })
+	return err
+}
+
+// traced wraps rt so each request gets a span once tracing is initialised
+func (c *Client) traced(rt http.RoundTripper) http.RoundTripper {
+	return &otelTransport{base: rt, name: c.name}
+}
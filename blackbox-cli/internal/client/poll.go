@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+)
+
+// pollStream emulates Stream for sources without a push endpoint by calling
+// snapshot every interval. Failed polls report StreamReconnecting and keep going.
+func pollStream(ctx context.Context, interval time.Duration, snapshot func(context.Context) (*model.Snapshot, error),
+	onSnapshot func(*model.Snapshot) error, onStateChange func(StreamState)) error {
+	notify := func(s StreamState) {
+		if onStateChange != nil {
+			onStateChange(s)
+		}
+	}
+	defer notify(StreamClosed)
+	notify(StreamConnecting)
+
+	connected := false
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		snap, err := snapshot(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case err != nil:
+			if connected {
+				notify(StreamReconnecting)
+			}
+			connected = false
+		default:
+			if !connected {
+				notify(StreamConnected)
+				connected = true
+			}
+			if err := onSnapshot(snap); err != nil {
+				return err
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// singleSample wraps one reading as an aggregate for sources that keep no history
+func singleSample(x float64) model.AggregatedStats {
+	return model.AggregatedStats{Min: x, Max: x, Avg: x, P95: x, P99: x, Count: 1}
+}
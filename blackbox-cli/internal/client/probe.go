@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+// Probe is what a quick check of an endpoint found
+type Probe struct {
+	Latency      time.Duration // Of the snapshot request
+	Snapshot     *model.Snapshot
+	Capabilities []string // Optional APIs that answered: "aggregated", "models"
+}
+
+// ProbeEndpoint fetches one snapshot from c, checked against the schema like
+// every poll, then tries the optional APIs the dashboard uses, so a typo in a
+// new endpoint's URL or path shows up before it's saved. Only the snapshot
+// failing is an error; the rest decides Capabilities.
+func ProbeEndpoint(ctx context.Context, c MetricsClient) (*Probe, error) {
+	start := time.Now()
+	s, err := c.Snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p := &Probe{Latency: time.Since(start), Snapshot: s}
+	if _, err := c.AggregatedSnapshot(ctx, 1); err == nil {
+		p.Capabilities = append(p.Capabilities, "aggregated")
+	} else {
+		utils.Debug("probe: no aggregated snapshots: %v", err)
+	}
+	if _, err := c.ListModels(ctx, ListModelsOptions{Limit: 1}); err == nil {
+		p.Capabilities = append(p.Capabilities, "models")
+	} else {
+		utils.Debug("probe: no model listing: %v", err)
+	}
+	return p, nil
+}
+
+// Summary describes p on one line, e.g.
+// "42ms, 80.00 GB VRAM, 3 models, supports aggregated, models"
+func (p *Probe) Summary() string {
+	parts := []string{p.Latency.Round(time.Millisecond).String()}
+	if p.Snapshot.TotalVRAMBytes > 0 {
+		parts = append(parts, fmt.Sprintf("%.2f GB VRAM", float64(p.Snapshot.TotalVRAMBytes)/(1024*1024*1024)))
+	}
+	parts = append(parts, fmt.Sprintf("%d models", len(p.Snapshot.Models)))
+	if len(p.Capabilities) > 0 {
+		parts = append(parts, "supports "+strings.Join(p.Capabilities, ", "))
+	} else {
+		parts = append(parts, "snapshots only")
+	}
+	return strings.Join(parts, ", ")
+}
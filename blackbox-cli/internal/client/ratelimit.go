@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+// DefaultRateLimit is the requests per second allowed to one endpoint when
+// nothing else is configured
+const DefaultRateLimit = 10
+
+// WithRateLimit caps requests to this client's base URL at rps per second,
+// shared by every client for the same URL in the process. Zero keeps
+// DefaultRateLimit; a negative rps turns limiting off.
+func WithRateLimit(rps float64) Option {
+	return func(c *Client) {
+		if rps != 0 {
+			c.rateLimit = rps
+		}
+	}
+}
+
+// limiter is a token bucket holding up to burst requests, refilled at rate per second
+type limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(rps float64) *limiter {
+	burst := math.Max(1, math.Ceil(rps))
+	return &limiter{rate: rps, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// wait takes a token, sleeping until one is available or ctx is done. Tokens
+// are handed out in call order by letting the bucket go negative.
+func (l *limiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens--
+	deficit := -l.tokens
+	l.mu.Unlock()
+	if deficit <= 0 {
+		return nil
+	}
+
+	delay := time.Duration(deficit / l.rate * float64(time.Second))
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++ // give back the token we never used
+		l.mu.Unlock()
+		return fmt.Errorf("rate limited (next slot in %s): %w", delay.Round(time.Millisecond), ctx.Err())
+	}
+}
+
+var (
+	sharedLimitersMu sync.Mutex
+	sharedLimiters   = map[string]*limiter{}
+)
+
+// sharedLimiter returns the bucket for an endpoint, so the dashboard, fleet
+// poller and popups polling the same host draw from one budget
+func sharedLimiter(baseURL string, rps float64) *limiter {
+	key := fmt.Sprintf("%s@%g", baseURL, rps)
+	sharedLimitersMu.Lock()
+	defer sharedLimitersMu.Unlock()
+	if l, ok := sharedLimiters[key]; ok {
+		return l
+	}
+	l := newLimiter(rps)
+	sharedLimiters[key] = l
+	return l
+}
+
+// rateLimitTransport waits for a token before each request
+type rateLimitTransport struct {
+	base    http.RoundTripper
+	limiter *limiter
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	if err := t.limiter.wait(req.Context()); err != nil {
+		return nil, err
+	}
+	if waited := time.Since(start); waited > 100*time.Millisecond {
+		utils.Debug("rate limit: held %s %s for %s", req.Method, req.URL.Path, waited.Round(time.Millisecond))
+	}
+	return t.base.RoundTrip(req)
+}
+
+// limit wraps rt with the client's limiter, if it has one
+func (c *Client) limit(rt http.RoundTripper) http.RoundTripper {
+	if c.limiter == nil {
+		return rt
+	}
+	return &rateLimitTransport{base: rt, limiter: c.limiter}
+}
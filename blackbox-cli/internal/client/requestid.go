@@ -0,0 +1,105 @@
+package client
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+// RequestIDHeader carries a per-request ID so server logs can be matched to
+// a CLI invocation. IDs share a random per-process prefix: <invocation>-<seq>.
+const RequestIDHeader = "X-Request-ID"
+
+var (
+	userAgent  = "blackbox-cli"
+	invocation = newInvocationID()
+	requestSeq atomic.Uint64
+)
+
+// SetUserAgent sets the User-Agent sent on every request; an endpoint's
+// "User-Agent" header still overrides it.
+func SetUserAgent(ua string) {
+	userAgent = ua
+}
+
+// InvocationID is the prefix shared by every request ID this process sends
+func InvocationID() string {
+	return invocation
+}
+
+func newInvocationID() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "000000000000"
+	}
+	return hex.EncodeToString(b)
+}
+
+func nextRequestID() string {
+	return fmt.Sprintf("%s-%04d", invocation, requestSeq.Add(1))
+}
+
+// identify fills in User-Agent and a fresh request ID unless h already has
+// them, and returns the request ID
+func identify(h http.Header) string {
+	if h.Get("User-Agent") == "" {
+		h.Set("User-Agent", userAgent)
+	}
+	id := h.Get(RequestIDHeader)
+	if id == "" {
+		id = nextRequestID()
+		h.Set(RequestIDHeader, id)
+	}
+	return id
+}
+
+// requestIDTransport stamps each request with identify and logs the ID of
+// requests that fail or get an error status
+type requestIDTransport struct {
+	base http.RoundTripper
+}
+
+func (t *requestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	id := identify(req.Header)
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		utils.Debug("request %s: %s %s failed: %v", id, req.Method, utils.RedactURL(req.URL), utils.Redact(err.Error()))
+		return nil, &requestIDError{id: id, err: err}
+	}
+	if resp.StatusCode >= 400 {
+		utils.Debug("request %s: %s %s returned %s", id, req.Method, utils.RedactURL(req.URL), resp.Status)
+	}
+	return resp, nil
+}
+
+// identified wraps rt so every request carries a User-Agent and request ID
+func (c *Client) identified(rt http.RoundTripper) http.RoundTripper {
+	return &requestIDTransport{base: rt}
+}
+
+// requestIDError adds the request ID to a transport error, keeping its
+// timeout classification
+type requestIDError struct {
+	id  string
+	err error
+}
+
+func (e *requestIDError) Error() string {
+	return fmt.Sprintf("%v (request id %s)", e.err, e.id)
+}
+
+func (e *requestIDError) Unwrap() error {
+	return e.err
+}
+
+func (e *requestIDError) Timeout() bool {
+	var netErr net.Error
+	return errors.As(e.err, &netErr) && netErr.Timeout()
+}
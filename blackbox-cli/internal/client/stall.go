@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultStallTimeout is how long a connected stream may go without an event
+// or heartbeat before it's reported stalled. blackbox-server sends twice a second.
+const DefaultStallTimeout = 10 * time.Second
+
+// A stalled stream is dropped and reconnected once it has been quiet for
+// this many stall timeouts, in case the connection is half-open
+const stallReconnectAfter = 3
+
+// ErrStreamStalled is why a stream was reconnected after going quiet
+var ErrStreamStalled = errors.New("no events or heartbeats from the server")
+
+// WithStallTimeout sets how long Stream waits for an event or heartbeat before
+// reporting StreamStalled. Zero keeps DefaultStallTimeout; negative turns
+// detection off.
+func WithStallTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		if d != 0 {
+			c.stallTimeout = d
+		}
+	}
+}
+
+// stallWatch times one stream connection. It reports StreamStalled when a
+// connected stream goes quiet for timeout and StreamConnected when it wakes
+// up, and cancels ctx when the quiet lasts stallReconnectAfter timeouts.
+type stallWatch struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	timeout   time.Duration
+	notify    func(StreamState)
+	last      atomic.Int64 // UnixNano of the last event or heartbeat
+	connected atomic.Bool
+	stalled   atomic.Bool
+	expired   atomic.Bool
+	done      chan struct{}
+}
+
+func (c *Client) watchStall(ctx context.Context, notify func(StreamState)) *stallWatch {
+	w := &stallWatch{timeout: c.stallTimeout, notify: notify, done: make(chan struct{})}
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	w.last.Store(time.Now().UnixNano())
+	if w.timeout > 0 {
+		go w.run()
+	}
+	return w
+}
+
+func (w *stallWatch) run() {
+	ticker := time.NewTicker(max(w.timeout/4, 100*time.Millisecond))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		quiet := time.Since(time.Unix(0, w.last.Load()))
+		if quiet >= stallReconnectAfter*w.timeout {
+			w.expired.Store(true)
+			w.cancel()
+			return
+		}
+		if quiet >= w.timeout && w.connected.Load() && w.stalled.CompareAndSwap(false, true) {
+			w.notify(StreamStalled)
+		}
+	}
+}
+
+// connect marks the stream connected; the quiet period starts over
+func (w *stallWatch) connect() {
+	w.last.Store(time.Now().UnixNano())
+	w.connected.Store(true)
+}
+
+// beat records an event or heartbeat
+func (w *stallWatch) beat() {
+	w.last.Store(time.Now().UnixNano())
+	if w.stalled.CompareAndSwap(true, false) {
+		w.notify(StreamConnected)
+	}
+}
+
+// stop ends the watch; the error is ErrStreamStalled if the watch dropped the connection
+func (w *stallWatch) stop(err error) error {
+	close(w.done)
+	w.cancel()
+	if w.expired.Load() {
+		return ErrStreamStalled
+	}
+	return err
+}
@@ -0,0 +1,337 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/features"
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+// StreamState describes the connection status reported to Stream's onStateChange callback
+type StreamState int
+
+const (
+	StreamConnecting StreamState = iota
+	StreamConnected
+	StreamReconnecting
+	StreamClosed
+	StreamStalled
+)
+
+func (s StreamState) String() string {
+	switch s {
+	case StreamConnecting:
+		return "connecting"
+	case StreamConnected:
+		return "connected"
+	case StreamReconnecting:
+		return "reconnecting"
+	case StreamClosed:
+		return "closed"
+	case StreamStalled:
+		return "stalled"
+	}
+	return "unknown"
+}
+
+const (
+	streamBackoffMin = 500 * time.Millisecond
+	streamBackoffMax = 30 * time.Second
+)
+
+// permanentError marks stream failures that reconnecting won't fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Stream delivers snapshots until ctx is cancelled or onSnapshot returns an error.
+// Dropped connections are re-established with exponential backoff; for SSE the last
+// seen event ID is sent as Last-Event-ID so the server can resume. onStateChange may be nil.
+//
+// A connected stream with no event or heartbeat for the stall timeout reports
+// StreamStalled, then StreamConnected when data flows again; a stall that lasts
+// three timeouts is reconnected. StreamStalled is reported from another goroutine.
+func (c *Client) Stream(ctx context.Context, onSnapshot func(*model.Snapshot) error, onStateChange func(StreamState)) error {
+	notify := func(s StreamState) {
+		if onStateChange != nil {
+			onStateChange(s)
+		}
+	}
+	defer notify(StreamClosed)
+
+	transport := c.streamTransport
+	lastEventID := ""
+	backoff := streamBackoffMin
+	notify(StreamConnecting)
+
+	for {
+		if transport == TransportGRPC && !features.Enabled(features.GRPC) {
+			utils.Warn("grpc transport is experimental and not enabled (--enable-experimental or features.%s in config), using SSE", features.GRPC)
+			transport = TransportSSE
+		}
+
+		received := false
+		watch := c.watchStall(ctx, notify)
+		deliver := func(s *model.Snapshot) error {
+			received = true
+			watch.beat()
+			c.noteSchemaVersion(s.SchemaVersion)
+			if err := onSnapshot(s); err != nil {
+				return &permanentError{err}
+			}
+			return nil
+		}
+		onConnected := func() {
+			watch.connect()
+			notify(StreamConnected)
+		}
+
+		var err error
+		switch transport {
+		case TransportWebSocket, TransportAuto:
+			err = c.streamWebSocket(watch.ctx, deliver, onConnected, watch.beat)
+		case TransportGRPC:
+			err = c.streamGRPC(watch.ctx, deliver, onConnected)
+		default:
+			err = c.streamSSE(watch.ctx, &lastEventID, deliver, onConnected, watch.beat)
+		}
+		err = watch.stop(err)
+
+		switch {
+		case transport == TransportWebSocket && errors.Is(err, errWebSocketUnsupported):
+			return err
+		case transport == TransportAuto && errors.Is(err, errWebSocketUnsupported):
+			utils.Debug("WebSocket upgrade not supported by %s, falling back to SSE", c.baseURL)
+			transport = TransportSSE
+			continue
+		case transport == TransportGRPC && errors.Is(err, errGRPCUnavailable):
+			utils.Debug("gRPC streaming not available for %s (%v), falling back to SSE", c.baseURL, err)
+			transport = TransportSSE
+			continue
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var perm *permanentError
+		if errors.As(err, &perm) {
+			return perm.err
+		}
+		if errors.Is(err, ErrCertificatePin) {
+			return err
+		}
+
+		if received {
+			backoff = streamBackoffMin
+		}
+		if err == nil {
+			utils.Debug("stream closed by server, reconnecting in %s", backoff)
+		} else {
+			utils.Debug("stream error: %v, reconnecting in %s", err, backoff)
+		}
+		notify(StreamReconnecting)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, streamBackoffMax)
+	}
+}
+
+// streamSSE reads one SSE connection; beat is called for every line, so
+// comment lines work as heartbeats between events
+func (c *Client) streamSSE(ctx context.Context, lastEventID *string, onSnapshot func(*model.Snapshot) error, onConnected, beat func()) error {
+	streamURL := c.baseURL + "/vram/stream"
+	if strings.HasPrefix(streamURL, "http:/") && !strings.HasPrefix(streamURL, "http://") {
+		streamURL = strings.Replace(streamURL, "http:/", "http://", 1)
+	}
+	if strings.HasPrefix(streamURL, "https:/") && !strings.HasPrefix(streamURL, "https://") {
+		streamURL = strings.Replace(streamURL, "https:/", "https://", 1)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, streamURL, nil)
+	if err != nil {
+		return &permanentError{fmt.Errorf("failed to create request: %w", err)}
+	}
+	c.setHeaders(req)
+
+	// Set SSE-specific headers
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+	req.Header.Set("Connection", "keep-alive")
+	if *lastEventID != "" {
+		req.Header.Set("Last-Event-ID", *lastEventID)
+	}
+
+	resp, err := c.sseClient().Do(req)
+	if err != nil {
+		return requestError(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		err := statusError(resp)
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+			return &permanentError{err}
+		}
+		return err
+	}
+
+	// Verify content type
+	contentType := resp.Header.Get("Content-Type")
+	if !strings.Contains(contentType, "text/event-stream") {
+		return &permanentError{fmt.Errorf("%w: content type %s (expected text/event-stream)", ErrBadSchema, contentType)}
+	}
+	onConnected()
+
+	// The server sends each SSE event as a separate HTTP response
+	// We need to read the raw stream and parse multiple HTTP responses
+	reader := bufio.NewReader(resp.Body)
+	var currentData strings.Builder
+	skipUntilEmptyLine := false
+
+	for {
+		// Check for context cancellation
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		// Read line by line
+		line, err := readLine(reader, maxStreamEventBytes)
+		if err != nil {
+			if tooLarge := tooLargeError(err); tooLarge != nil {
+				return &permanentError{tooLarge}
+			}
+			if err == io.EOF {
+				// Process any remaining data before EOF
+				if currentData.Len() > 0 {
+					data := currentData.String()
+					currentData.Reset()
+					var snap model.Snapshot
+					if json.Unmarshal(upgrade([]byte(data), &snap), &snap) == nil {
+						if err := onSnapshot(&snap); err != nil {
+							return err
+						}
+					}
+				}
+				return nil
+			}
+			return fmt.Errorf("stream read error: %w", err)
+		}
+		beat()
+
+		line = strings.TrimRight(line, "\r\n")
+
+		// Handle HTTP response headers that appear in the stream
+		// The server sends multiple HTTP responses, each starting with headers
+		if strings.HasPrefix(line, "HTTP/") {
+			// New HTTP response - skip headers until empty line
+			skipUntilEmptyLine = true
+			currentData.Reset()
+			continue
+		}
+
+		if skipUntilEmptyLine {
+			if line == "" {
+				// End of headers, start reading SSE data
+				skipUntilEmptyLine = false
+			}
+			continue
+		}
+
+		// Parse SSE format
+		if line == "" {
+			// Empty line indicates end of SSE event
+			if currentData.Len() > 0 {
+				data := currentData.String()
+				currentData.Reset()
+
+				var snap model.Snapshot
+				payload := upgrade([]byte(data), &snap)
+				if err := checkSchema(payload, &snap); err != nil {
+					return &permanentError{err}
+				}
+				if err := json.Unmarshal(payload, &snap); err != nil {
+					// Skip malformed JSON
+					continue
+				}
+
+				if err := onSnapshot(&snap); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		// Handle SSE field lines
+		if strings.HasPrefix(line, "data: ") {
+			// Extract data after "data: " prefix
+			data := strings.TrimSpace(line[6:])
+			if data != "" {
+				currentData.Reset()
+				currentData.WriteString(data)
+			}
+		} else if strings.HasPrefix(line, ":") {
+			// SSE comment - ignore
+			continue
+		} else if strings.HasPrefix(line, "event:") {
+			// SSE metadata - ignore
+			continue
+		} else if strings.HasPrefix(line, "id:") {
+			// Remembered so a reconnect can resume from here
+			*lastEventID = strings.TrimSpace(line[3:])
+			continue
+		}
+		// Ignore any other lines (could be HTTP headers from subsequent responses)
+	}
+}
+
+// sseClient returns the client's dedicated, non-pooling HTTP client for SSE, built once
+func (c *Client) sseClient() *http.Client {
+	c.sseOnce.Do(func() {
+		// Create a completely isolated HTTP client for SSE
+		// The server sends multiple HTTP responses on the same connection (each SSE event is a full HTTP response)
+		// We need to disable connection pooling entirely to prevent "unsolicited response" errors
+		transport := &http.Transport{
+			Proxy:               c.proxy,
+			DialContext:         c.timeouts.dialer().DialContext,
+			TLSHandshakeTimeout: c.timeouts.TLSHandshake,
+			TLSClientConfig:     c.pins.tlsConfig(),
+			DisableKeepAlives:   true, // Disable keep-alive to prevent connection reuse
+			MaxIdleConns:        0,    // No connection pooling
+			MaxIdleConnsPerHost: 0,    // No per-host pooling
+			IdleConnTimeout:     0,    // No timeout
+			DisableCompression:  true, // Disable compression for SSE
+			// Force new connection for each request
+			ForceAttemptHTTP2: false, // Disable HTTP/2 which has different connection handling
+		}
+
+		// Create a dedicated client that won't interfere with other requests
+		streamClient := &http.Client{
+			Timeout:   0, // No timeout for streaming
+			Transport: c.limit(c.identified(c.traced(&traceTransport{base: transport}))),
+			// Don't follow redirects
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+		c.sse = streamClient
+	})
+	return c.sse
+}
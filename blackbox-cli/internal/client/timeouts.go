@@ -0,0 +1,52 @@
+package client
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// Connection phase timeouts used when an endpoint doesn't set its own. They
+// are short so an unreachable host fails in seconds even when the overall
+// request timeout is minutes long, as it is for deploys.
+const (
+	DefaultDialTimeout         = 5 * time.Second
+	DefaultTLSHandshakeTimeout = 5 * time.Second
+)
+
+// Timeouts bound the phases of a request separately from its overall
+// timeout. Zero fields keep the defaults; ResponseHeader defaults to no
+// limit beyond the overall timeout.
+type Timeouts struct {
+	Dial           time.Duration // TCP connect, including to a proxy
+	TLSHandshake   time.Duration
+	ResponseHeader time.Duration // From the request being written to the response headers; not applied to streams
+}
+
+// WithTimeouts sets the dial, TLS handshake and response header timeouts
+func WithTimeouts(t Timeouts) Option {
+	return func(c *Client) {
+		if t.Dial > 0 {
+			c.timeouts.Dial = t.Dial
+		}
+		if t.TLSHandshake > 0 {
+			c.timeouts.TLSHandshake = t.TLSHandshake
+		}
+		if t.ResponseHeader > 0 {
+			c.timeouts.ResponseHeader = t.ResponseHeader
+		}
+	}
+}
+
+func defaultTimeouts() Timeouts {
+	return Timeouts{Dial: DefaultDialTimeout, TLSHandshake: DefaultTLSHandshakeTimeout}
+}
+
+// key distinguishes connection pools that were built with different timeouts
+func (t Timeouts) key() string {
+	return fmt.Sprintf("%s/%s/%s", t.Dial, t.TLSHandshake, t.ResponseHeader)
+}
+
+func (t Timeouts) dialer() *net.Dialer {
+	return &net.Dialer{Timeout: t.Dial, KeepAlive: 30 * time.Second}
+}
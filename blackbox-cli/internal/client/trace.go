@@ -0,0 +1,72 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+const traceBodyLimit = 2048
+
+// traceTransport logs each exchange through utils.Trace with secrets redacted.
+// Bodies are truncated, and event streams are never buffered.
+type traceTransport struct {
+	base http.RoundTripper
+}
+
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !utils.HTTPTraceEnabled() {
+		return t.base.RoundTrip(req)
+	}
+
+	reqBody := ""
+	if req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			reqBody = peekBody(body)
+			body.Close()
+		}
+	}
+	utils.Trace("--> %s %s headers=[%s] body=%q",
+		req.Method, utils.RedactURL(req.URL), utils.RedactHeaders(req.Header), utils.Redact(reqBody))
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		utils.Trace("<-- %s %s failed after %s: %s", req.Method, utils.RedactURL(req.URL), elapsed, utils.Redact(err.Error()))
+		return nil, err
+	}
+
+	respBody := "(stream)"
+	if !strings.Contains(resp.Header.Get("Content-Type"), "text/event-stream") {
+		// Read a prefix for the log and splice it back in front of the rest
+		prefix := make([]byte, traceBodyLimit)
+		n, _ := io.ReadFull(resp.Body, prefix)
+		prefix = prefix[:n]
+		respBody = truncateBody(prefix, n == traceBodyLimit)
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(prefix), resp.Body), resp.Body}
+	}
+	utils.Trace("<-- %s %s %s in %s headers=[%s] body=%q",
+		req.Method, utils.RedactURL(req.URL), resp.Status, elapsed, utils.RedactHeaders(resp.Header), utils.Redact(respBody))
+	return resp, nil
+}
+
+func peekBody(r io.Reader) string {
+	buf := make([]byte, traceBodyLimit)
+	n, _ := io.ReadFull(r, buf)
+	return truncateBody(buf[:n], n == traceBodyLimit)
+}
+
+func truncateBody(b []byte, more bool) string {
+	if more {
+		return string(b) + "...(truncated)"
+	}
+	return string(b)
+}
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+	"github.com/maxdcmn/blackbox-cli/internal/schema"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+var (
+	strictSchema bool
+	schemas      sync.Map // reflect.Type -> *schema.Schema
+)
+
+// SetStrictSchema makes response fields the CLI doesn't know an error; by
+// default they are only logged at debug level, since newer servers add fields
+func SetStrictSchema(strict bool) {
+	strictSchema = strict
+}
+
+func schemaFor(t reflect.Type) *schema.Schema {
+	if s, ok := schemas.Load(t); ok {
+		return s.(*schema.Schema)
+	}
+	s, _ := schemas.LoadOrStore(t, schema.For(t.Name(), reflect.New(t).Elem().Interface()))
+	return s.(*schema.Schema)
+}
+
+// checkSchema validates data against the type v points to, so a server on a
+// different API version fails with the offending JSON paths instead of
+// decoding into zero values. Data that isn't JSON is left to json.Unmarshal.
+// With projected fields, only those top-level fields are required.
+func checkSchema(data []byte, v interface{}, projected ...string) error {
+	t := reflect.TypeOf(v).Elem()
+	problems, err := schema.Validate(schemaFor(t), data)
+	if err != nil {
+		return nil
+	}
+	var fatal, unknown []schema.Problem
+	for _, p := range problems {
+		if len(projected) > 0 && p.Missing && !slices.Contains(projected, strings.TrimPrefix(p.Path, "$.")) {
+			continue
+		}
+		if p.Unknown && !strictSchema {
+			unknown = append(unknown, p)
+		} else {
+			fatal = append(fatal, p)
+		}
+	}
+	if len(unknown) > 0 {
+		utils.Debug("%s: ignoring unknown fields: %s", t.Name(), schema.Summary(unknown, 5))
+	}
+	if len(fatal) > 0 {
+		return fmt.Errorf("%w: %s from the server doesn't match this CLI (versions may differ): %s", ErrBadSchema, t.Name(), schema.Summary(fatal, 3))
+	}
+	return nil
+}
+
+// schemaVersionHeader tells the server which snapshot schema version the CLI
+// reads, so a newer server can answer in it
+const schemaVersionHeader = "X-Blackbox-Schema-Version"
+
+// upgrade rewrites snapshots of an older schema version into the current one
+// before they're checked, so an older server's payloads decode instead of
+// failing the schema check or decoding into an empty dashboard
+func upgrade(data []byte, v interface{}) []byte {
+	return model.Upgrade(data, v)
+}
+
+// noteSchemaVersion warns once per client when the server sends a snapshot
+// schema newer than the CLI reads; its new fields are ignored until the CLI
+// is upgraded
+func (c *Client) noteSchemaVersion(version int) {
+	if version > model.SchemaVersion && !c.newerSchema.Swap(true) {
+		utils.Warn("%s sends snapshot schema version %d, this CLI reads up to %d; upgrade the CLI to see its new fields",
+			c.baseURL, version, model.SchemaVersion)
+	}
+}
+
+// decodeBody reads resp's JSON body into v after checking it against v's
+// schema; projected lists the top-level fields asked for, when not all were
+func decodeBody(ctx context.Context, resp *http.Response, v interface{}, projected ...string) error {
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return decodeError(ctx, resp, err)
+	}
+	data = upgrade(data, v)
+	if err := checkSchema(data, v, projected...); err != nil {
+		if !isSuccess(resp) {
+			return statusError(resp)
+		}
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return decodeError(ctx, resp, err)
+	}
+	return nil
+}
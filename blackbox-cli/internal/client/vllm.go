@@ -0,0 +1,388 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+)
+
+// DefaultVLLMMetricsPath is where vLLM serves Prometheus metrics
+const DefaultVLLMMetricsPath = "/metrics"
+
+const vllmStreamInterval = 2 * time.Second
+
+// VLLMClient talks straight to a vLLM server's Prometheus /metrics endpoint and
+// synthesizes snapshots from it, for hosts without blackbox-server. vLLM doesn't
+// export GPU memory, so VRAM figures are derived from gpuMemoryBytes when it is
+// set: allocated = gpu_memory_utilization * total, and used KV cache =
+// kv_cache_usage_perc * allocated (an upper bound, the pool is smaller).
+// Model management calls return ErrUnsupported.
+type VLLMClient struct {
+	c              *Client
+	gpuMemoryBytes int64
+
+	mu          sync.Mutex
+	prevHits    float64
+	prevQueries float64
+	prevLatency map[string]vllmLatency
+	// generation_tokens_total by model and when it was read, for throughput
+	prevGenerated   map[string]float64
+	prevGeneratedAt time.Time
+}
+
+var _ MetricsClient = (*VLLMClient)(nil)
+
+func NewVLLM(baseURL, metricsPath string, timeout time.Duration, gpuMemoryBytes int64, opts ...Option) *VLLMClient {
+	if metricsPath == "" {
+		metricsPath = DefaultVLLMMetricsPath
+	}
+	return &VLLMClient{c: New(baseURL, metricsPath, timeout, opts...), gpuMemoryBytes: gpuMemoryBytes}
+}
+
+type promSample struct {
+	name   string
+	labels map[string]string
+	value  float64
+}
+
+// parsePrometheusText reads the Prometheus text exposition format, skipping
+// comments and lines it can't parse
+func parsePrometheusText(r io.Reader) ([]promSample, error) {
+	var samples []promSample
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		s := promSample{labels: map[string]string{}}
+		rest := line
+		if i := strings.IndexAny(line, "{ "); i >= 0 && line[i] == '{' {
+			s.name = line[:i]
+			end := strings.LastIndex(line, "}")
+			if end < i {
+				continue
+			}
+			s.labels = parsePromLabels(line[i+1 : end])
+			rest = strings.TrimSpace(line[end+1:])
+		} else if i >= 0 {
+			s.name = line[:i]
+			rest = strings.TrimSpace(line[i:])
+		} else {
+			continue
+		}
+		// Value, optionally followed by a timestamp
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		v, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			continue
+		}
+		s.value = v
+		samples = append(samples, s)
+	}
+	return samples, scanner.Err()
+}
+
+func parsePromLabels(s string) map[string]string {
+	labels := map[string]string{}
+	for len(s) > 0 {
+		eq := strings.Index(s, "=")
+		if eq < 0 || eq+1 >= len(s) || s[eq+1] != '"' {
+			break
+		}
+		key := strings.TrimSpace(strings.TrimLeft(s[:eq], ", "))
+		var val strings.Builder
+		i := eq + 2
+		for ; i < len(s) && s[i] != '"'; i++ {
+			if s[i] == '\\' && i+1 < len(s) {
+				i++
+				if s[i] == 'n' {
+					val.WriteByte('\n')
+					continue
+				}
+			}
+			val.WriteByte(s[i])
+		}
+		labels[key] = val.String()
+		if i+1 > len(s) {
+			break
+		}
+		s = s[i+1:]
+	}
+	return labels
+}
+
+// vllmScrape is what one /metrics scrape tells us, summed over all models served
+type vllmScrape struct {
+	models               []string
+	requestsRunning      float64
+	requestsWaiting      float64
+	kvCacheUsage         float64 // 0-1, averaged over models
+	gpuMemoryUtilization float64 // 0-1, from cache_config_info
+	prefixHits           float64
+	prefixQueries        float64
+	latency              map[string]vllmLatency // By model name
+	generated            map[string]float64     // generation_tokens_total by model name
+	running              map[string]float64     // num_requests_running by model name
+	waiting              map[string]float64     // num_requests_waiting by model name
+	at                   time.Time
+}
+
+// vllmLatency is one model's latency histograms, as their lifetime sums and counts
+type vllmLatency struct {
+	ttftSum, ttftCount float64
+	itlSum, itlCount   float64
+	// time_per_output_token is the older name of the inter-token histogram,
+	// only used when inter_token_latency isn't exported
+	tpotSum, tpotCount float64
+}
+
+// means returns the mean TTFT and inter-token latency over what l counted
+// since prev; over its lifetime when prev is zero or the counters reset
+func (l vllmLatency) means(prev vllmLatency) (ttft, interToken float64) {
+	if l.ttftCount < prev.ttftCount || l.itlCount < prev.itlCount || l.tpotCount < prev.tpotCount {
+		prev = vllmLatency{}
+	}
+	ttft = mean(l.ttftSum-prev.ttftSum, l.ttftCount-prev.ttftCount)
+	if l.itlCount > 0 {
+		return ttft, mean(l.itlSum-prev.itlSum, l.itlCount-prev.itlCount)
+	}
+	return ttft, mean(l.tpotSum-prev.tpotSum, l.tpotCount-prev.tpotCount)
+}
+
+func mean(sum, count float64) float64 {
+	if count <= 0 {
+		return 0
+	}
+	return sum / count
+}
+
+func summarizeVLLM(samples []promSample) vllmScrape {
+	out := vllmScrape{latency: map[string]vllmLatency{}, generated: map[string]float64{},
+		running: map[string]float64{}, waiting: map[string]float64{}, at: time.Now()}
+	seen := map[string]bool{}
+	kvCount := 0
+	for _, s := range samples {
+		if name := s.labels["model_name"]; name != "" && !seen[name] {
+			seen[name] = true
+			out.models = append(out.models, name)
+		}
+		switch s.name {
+		case "vllm:num_requests_running":
+			out.requestsRunning += s.value
+			out.running[s.labels["model_name"]] += s.value
+		case "vllm:num_requests_waiting":
+			out.requestsWaiting += s.value
+			out.waiting[s.labels["model_name"]] += s.value
+		case "vllm:kv_cache_usage_perc", "vllm:gpu_cache_usage_perc":
+			out.kvCacheUsage += s.value
+			kvCount++
+		case "vllm:prefix_cache_hits_total", "vllm:gpu_prefix_cache_hits_total":
+			out.prefixHits += s.value
+		case "vllm:prefix_cache_queries_total", "vllm:gpu_prefix_cache_queries_total":
+			out.prefixQueries += s.value
+		case "vllm:cache_config_info":
+			if v, err := strconv.ParseFloat(s.labels["gpu_memory_utilization"], 64); err == nil {
+				out.gpuMemoryUtilization = v
+			}
+		case "vllm:generation_tokens_total":
+			out.generated[s.labels["model_name"]] += s.value
+		case "vllm:time_to_first_token_seconds_sum", "vllm:time_to_first_token_seconds_count",
+			"vllm:inter_token_latency_seconds_sum", "vllm:inter_token_latency_seconds_count",
+			"vllm:time_per_output_token_seconds_sum", "vllm:time_per_output_token_seconds_count":
+			name := s.labels["model_name"]
+			l := out.latency[name]
+			switch s.name {
+			case "vllm:time_to_first_token_seconds_sum":
+				l.ttftSum += s.value
+			case "vllm:time_to_first_token_seconds_count":
+				l.ttftCount += s.value
+			case "vllm:inter_token_latency_seconds_sum":
+				l.itlSum += s.value
+			case "vllm:inter_token_latency_seconds_count":
+				l.itlCount += s.value
+			case "vllm:time_per_output_token_seconds_sum":
+				l.tpotSum += s.value
+			case "vllm:time_per_output_token_seconds_count":
+				l.tpotCount += s.value
+			}
+			out.latency[name] = l
+		}
+	}
+	if kvCount > 0 {
+		out.kvCacheUsage /= float64(kvCount)
+	}
+	sort.Strings(out.models)
+	return out
+}
+
+func (v *VLLMClient) scrape(ctx context.Context) (vllmScrape, error) {
+	c := v.c
+	ctx, cancel := requestContext(ctx, c.timeout)
+	defer cancel()
+
+	metricsURL := c.baseURL + c.endpoint
+	if strings.HasPrefix(metricsURL, "http:/") && !strings.HasPrefix(metricsURL, "http://") {
+		metricsURL = strings.Replace(metricsURL, "http:/", "http://", 1)
+	}
+	if strings.HasPrefix(metricsURL, "https:/") && !strings.HasPrefix(metricsURL, "https://") {
+		metricsURL = strings.Replace(metricsURL, "https:/", "https://", 1)
+	}
+	if _, err := url.Parse(metricsURL); err != nil {
+		return vllmScrape{}, fmt.Errorf("invalid URL %q: %w", metricsURL, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metricsURL, nil)
+	if err != nil {
+		return vllmScrape{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.setHeaders(req)
+	req.Header.Set("Accept", "text/plain")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return vllmScrape{}, requestError(err)
+	}
+	defer resp.Body.Close()
+
+	if !isSuccess(resp) {
+		return vllmScrape{}, statusError(resp)
+	}
+
+	samples, err := parsePrometheusText(resp.Body)
+	if err != nil {
+		return vllmScrape{}, decodeError(ctx, resp, err)
+	}
+	if len(samples) == 0 {
+		return vllmScrape{}, fmt.Errorf("%w: no Prometheus samples at %s", ErrBadSchema, metricsURL)
+	}
+	return summarizeVLLM(samples), nil
+}
+
+func (v *VLLMClient) snapshotFrom(s vllmScrape) *model.Snapshot {
+	// Hit rate over the interval since the last scrape; lifetime rate on the first one
+	v.mu.Lock()
+	hits, queries := s.prefixHits, s.prefixQueries
+	if v.prevQueries > 0 && s.prefixQueries >= v.prevQueries {
+		hits, queries = s.prefixHits-v.prevHits, s.prefixQueries-v.prevQueries
+	}
+	v.prevHits, v.prevQueries = s.prefixHits, s.prefixQueries
+	// Latency over the same interval, so it follows the load instead of
+	// settling on the lifetime mean; 0 when no request finished in it
+	prevLatency := v.prevLatency
+	v.prevLatency = s.latency
+	// Throughput needs two scrapes; 0 on the first one
+	prevGenerated, elapsed := v.prevGenerated, s.at.Sub(v.prevGeneratedAt).Seconds()
+	v.prevGenerated, v.prevGeneratedAt = s.generated, s.at
+	v.mu.Unlock()
+
+	snap := &model.Snapshot{TotalVRAMBytes: v.gpuMemoryBytes, NumRequestsRunning: s.requestsRunning, NumRequestsWaiting: s.requestsWaiting}
+	if queries > 0 {
+		snap.PrefixCacheHitRate = hits / queries * 100
+	}
+	if v.gpuMemoryBytes > 0 {
+		snap.AllocatedVRAMBytes = int64(s.gpuMemoryUtilization * float64(v.gpuMemoryBytes))
+		snap.UsedKVCacheBytes = int64(s.kvCacheUsage * float64(snap.AllocatedVRAMBytes))
+	}
+	for _, name := range s.models {
+		info := model.ModelInfo{ModelID: name}
+		info.TTFTSeconds, info.InterTokenLatencySeconds = s.latency[name].means(prevLatency[name])
+		if prev, ok := prevGenerated[name]; ok && elapsed > 0 && s.generated[name] >= prev {
+			info.GenerationTokensPerSecond = (s.generated[name] - prev) / elapsed
+		}
+		info.NumRequestsRunning, info.NumRequestsWaiting = s.running[name], s.waiting[name]
+		snap.Models = append(snap.Models, info)
+	}
+	if len(s.models) == 1 {
+		snap.Models[0].AllocatedVRAMBytes = snap.AllocatedVRAMBytes
+		snap.Models[0].UsedKVCacheBytes = snap.UsedKVCacheBytes
+	}
+	return snap
+}
+
+func (v *VLLMClient) Snapshot(ctx context.Context) (*model.Snapshot, error) {
+	s, err := v.scrape(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return v.snapshotFrom(s), nil
+}
+
+// AggregatedSnapshot returns a single scrape as a one-sample aggregate; vLLM keeps no history
+func (v *VLLMClient) AggregatedSnapshot(ctx context.Context, windowSeconds int) (*model.AggregatedSnapshot, error) {
+	s, err := v.scrape(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snap := v.snapshotFrom(s)
+	return &model.AggregatedSnapshot{
+		TotalVRAMBytes:     snap.TotalVRAMBytes,
+		WindowSeconds:      windowSeconds,
+		SampleCount:        1,
+		AllocatedVRAMBytes: singleSample(float64(snap.AllocatedVRAMBytes)),
+		UsedKVCacheBytes:   singleSample(float64(snap.UsedKVCacheBytes)),
+		PrefixCacheHitRate: singleSample(snap.PrefixCacheHitRate),
+		NumRequestsRunning: singleSample(s.requestsRunning),
+		NumRequestsWaiting: singleSample(s.requestsWaiting),
+		Models:             snap.Models,
+	}, nil
+}
+
+// Stream scrapes every couple of seconds; vLLM has no push endpoint
+func (v *VLLMClient) Stream(ctx context.Context, onSnapshot func(*model.Snapshot) error, onStateChange func(StreamState)) error {
+	return pollStream(ctx, vllmStreamInterval, v.Snapshot, onSnapshot, onStateChange)
+}
+
+func (v *VLLMClient) ListModels(ctx context.Context, opts ListModelsOptions) (*ModelsResponse, error) {
+	s, err := v.scrape(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp := &ModelsResponse{Total: len(s.models), Running: len(s.models)}
+	for _, name := range s.models {
+		resp.Models = append(resp.Models, DeployedModel{
+			ModelID:                     name,
+			Running:                     true,
+			ConfiguredMaxGPUUtilization: s.gpuMemoryUtilization,
+		})
+	}
+	return ApplyListOptions(resp, opts), nil
+}
+
+func (v *VLLMClient) DeployModel(ctx context.Context, modelID, hfToken, port string) (*DeployResponse, error) {
+	return nil, fmt.Errorf("deploy: %w", ErrUnsupported)
+}
+
+func (v *VLLMClient) GetDeployStatus(ctx context.Context, jobID string) (*DeployStatus, error) {
+	return nil, fmt.Errorf("deploy: %w", ErrUnsupported)
+}
+
+func (v *VLLMClient) SpindownModel(ctx context.Context, modelID, containerID string) (*SpindownResponse, error) {
+	return nil, fmt.Errorf("spindown: %w", ErrUnsupported)
+}
+
+func (v *VLLMClient) Optimize(ctx context.Context) (*OptimizeResponse, error) {
+	return nil, fmt.Errorf("optimize: %w", ErrUnsupported)
+}
+
+func (v *VLLMClient) Warmup(ctx context.Context, modelID string, port int, opts WarmupOptions) (*WarmupResult, error) {
+	return nil, fmt.Errorf("warm-up: %w", ErrUnsupported)
+}
+
+func (v *VLLMClient) ContainerLogs(ctx context.Context, modelID string, opts LogsOptions) (*LogsResponse, error) {
+	return nil, fmt.Errorf("logs: %w", ErrUnsupported)
+}
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Warm-up defaults: a few tokens are enough to load the weights and capture
+// CUDA graphs, which is what makes a fresh model's first request slow
+const (
+	DefaultWarmupPrompt    = "Hello"
+	DefaultWarmupMaxTokens = 16
+	DefaultWarmupTimeout   = 2 * time.Minute
+	warmupRetryInterval    = 2 * time.Second
+)
+
+// WarmupOptions is the generation request sent to a freshly deployed model
+type WarmupOptions struct {
+	Prompt    string
+	MaxTokens int
+	// Scheme of the model's port; vLLM serves plain http there even when
+	// blackbox-server sits behind TLS, so "" means http
+	Scheme string
+}
+
+// WarmupResult is how the first generation on a model went
+type WarmupResult struct {
+	Latency  time.Duration // Of the request that succeeded
+	Tokens   int           // Completion tokens, when the server reports them
+	Attempts int           // Requests sent, including those made before the model listened
+}
+
+// Warmup sends a small completion request to the model's OpenAI-compatible
+// server on port of the endpoint's host, over opts.Scheme. Until the model accepts it, refused
+// connections and 404/502/503 answers are retried every couple of seconds;
+// ctx bounds the whole warm-up. Endpoint headers aren't sent, since they are
+// meant for blackbox-server.
+func (c *Client) Warmup(ctx context.Context, modelID string, port int, opts WarmupOptions) (*WarmupResult, error) {
+	if port <= 0 {
+		return nil, errors.New("warm-up: the model's port is unknown")
+	}
+	baseURL := c.baseURL
+	if strings.HasPrefix(baseURL, "http:/") && !strings.HasPrefix(baseURL, "http://") {
+		baseURL = strings.Replace(baseURL, "http:/", "http://", 1)
+	}
+	if strings.HasPrefix(baseURL, "https:/") && !strings.HasPrefix(baseURL, "https://") {
+		baseURL = strings.Replace(baseURL, "https:/", "https://", 1)
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil || base.Hostname() == "" {
+		return nil, fmt.Errorf("invalid URL %q", c.baseURL)
+	}
+	scheme := opts.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	target := url.URL{Scheme: scheme, Host: base.Hostname() + ":" + strconv.Itoa(port), Path: "/v1/completions"}
+	if opts.Prompt == "" {
+		opts.Prompt = DefaultWarmupPrompt
+	}
+	if opts.MaxTokens <= 0 {
+		opts.MaxTokens = DefaultWarmupMaxTokens
+	}
+	body, err := json.Marshal(map[string]any{
+		"model":       modelID,
+		"prompt":      opts.Prompt,
+		"max_tokens":  opts.MaxTokens,
+		"temperature": 0,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	res := &WarmupResult{}
+	for {
+		res.Attempts++
+		start := time.Now()
+		tokens, err := c.complete(ctx, target.String(), body)
+		if err == nil {
+			res.Latency, res.Tokens = time.Since(start), tokens
+			return res, nil
+		}
+		if !warmupRetryable(err) || ctx.Err() != nil {
+			return res, fmt.Errorf("warm-up: %w", err)
+		}
+		select {
+		case <-ctx.Done():
+			return res, fmt.Errorf("warm-up: model didn't answer in time: %w", err)
+		case <-time.After(warmupRetryInterval):
+		}
+	}
+}
+
+// complete sends one completion request and returns the completion tokens reported
+func (c *Client) complete(ctx context.Context, rawURL string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, requestError(err)
+	}
+	defer resp.Body.Close()
+	if !isSuccess(resp) {
+		return 0, statusError(resp)
+	}
+	var out struct {
+		Usage struct {
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return 0, decodeError(ctx, resp, err)
+	}
+	return out.Usage.CompletionTokens, nil
+}
+
+// warmupRetryable reports whether err means the model isn't listening yet
+func warmupRetryable(err error) bool {
+	var status *StatusError
+	if errors.As(err, &status) {
+		switch status.StatusCode {
+		case http.StatusNotFound, http.StatusBadGateway, http.StatusServiceUnavailable:
+			return true
+		}
+		return false
+	}
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET)
+}
@@ -0,0 +1,143 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+const (
+	wsPingInterval = 15 * time.Second
+	wsPongWait     = 2 * wsPingInterval
+	wsWriteWait    = 5 * time.Second
+)
+
+// errWebSocketUnsupported is returned when the server refuses the upgrade,
+// letting auto mode fall back to SSE.
+var errWebSocketUnsupported = errors.New("server does not support websocket streaming")
+
+// streamWebSocket reads one WebSocket connection; beat is called for pings
+// from the server, which count as heartbeats (our own pings' pongs don't)
+func (c *Client) streamWebSocket(ctx context.Context, onSnapshot func(*model.Snapshot) error, onConnected, beat func()) error {
+	wsURL := c.baseURL + "/vram/ws"
+	if strings.HasPrefix(wsURL, "http:/") && !strings.HasPrefix(wsURL, "http://") {
+		wsURL = strings.Replace(wsURL, "http:/", "http://", 1)
+	}
+	if strings.HasPrefix(wsURL, "https:/") && !strings.HasPrefix(wsURL, "https://") {
+		wsURL = strings.Replace(wsURL, "https:/", "https://", 1)
+	}
+	if strings.HasPrefix(wsURL, "https://") {
+		wsURL = "wss://" + strings.TrimPrefix(wsURL, "https://")
+	} else if strings.HasPrefix(wsURL, "http://") {
+		wsURL = "ws://" + strings.TrimPrefix(wsURL, "http://")
+	}
+
+	if c.limiter != nil {
+		if err := c.limiter.wait(ctx); err != nil {
+			return err
+		}
+	}
+	dialer := websocket.Dialer{
+		Proxy:            c.proxy,
+		NetDialContext:   c.timeouts.dialer().DialContext,
+		HandshakeTimeout: c.timeout,
+		TLSClientConfig:  c.pins.tlsConfig(),
+	}
+	header := c.header()
+	id := identify(header)
+	conn, resp, err := dialer.DialContext(ctx, wsURL, header)
+	if err != nil {
+		if errors.Is(err, websocket.ErrBadHandshake) && resp != nil {
+			if errors.Is(statusError(resp), ErrUnauthorized) {
+				return &permanentError{statusError(resp)}
+			}
+			return fmt.Errorf("%w (server returned %s)", errWebSocketUnsupported, resp.Status)
+		}
+		return fmt.Errorf("websocket dial failed: %w", &requestIDError{id: id, err: err})
+	}
+	defer conn.Close()
+	conn.SetReadLimit(maxStreamEventBytes)
+	onConnected()
+
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+	conn.SetPingHandler(func(data string) error {
+		beat()
+		conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		err := conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(wsWriteWait))
+		if errors.Is(err, websocket.ErrCloseSent) {
+			return nil
+		}
+		return err
+	})
+
+	// Keepalive pings; the read loop below owns all other reads
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(wsPingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				// Unblock ReadMessage
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(wsWriteWait))
+				conn.Close()
+				return
+			case <-ticker.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+					utils.Debug("websocket ping failed: %v", err)
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		msgType, data, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				return nil
+			}
+			if errors.Is(err, websocket.ErrReadLimit) {
+				return &permanentError{tooLargeError(&http.MaxBytesError{Limit: maxStreamEventBytes})}
+			}
+			return fmt.Errorf("stream read error: %w", err)
+		}
+		// Any frame counts as liveness, not just pongs
+		conn.SetReadDeadline(time.Now().Add(wsPongWait))
+
+		if msgType != websocket.TextMessage && msgType != websocket.BinaryMessage {
+			continue
+		}
+
+		var snap model.Snapshot
+		data = upgrade(data, &snap)
+		if err := checkSchema(data, &snap); err != nil {
+			return &permanentError{err}
+		}
+		if err := json.Unmarshal(data, &snap); err != nil {
+			// Skip malformed JSON
+			continue
+		}
+		if err := onSnapshot(&snap); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+)
+
+// ModelLocation is one deployed model found by FindModels
+type ModelLocation struct {
+	Endpoint string `json:"endpoint"`
+	Host     string `json:"host"`
+	Port     int    `json:"port,omitempty"`
+	ModelID  string `json:"model_id"`
+	Running  bool   `json:"running"`
+}
+
+// FindModels lists the models on every endpoint of m and returns those
+// matching pattern (see MatchModelID) in endpoint order. Endpoints that can't
+// be listed are skipped and reported as "<name>: <error>".
+func FindModels(ctx context.Context, m *Multi, pattern string) ([]ModelLocation, []error) {
+	res := m.ListModels(ctx, ListModelsOptions{})
+	var locs []ModelLocation
+	var failed []error
+	for _, ep := range m.Endpoints() {
+		if err := res.Errors[ep.Name]; err != nil {
+			failed = append(failed, fmt.Errorf("%s: %w", ep.Name, err))
+			continue
+		}
+		host, port := endpointHostPort(ep)
+		for _, model := range res.Models[ep.Name].Models {
+			if !MatchModelID(pattern, model.ModelID) {
+				continue
+			}
+			loc := ModelLocation{Endpoint: ep.Name, Host: host, Port: model.Port, ModelID: model.ModelID, Running: model.Running}
+			// vLLM serves its model on the scraped port itself
+			if loc.Port == 0 && ep.Type == config.EndpointTypeVLLM {
+				loc.Port = port
+			}
+			locs = append(locs, loc)
+		}
+	}
+	return locs, failed
+}
+
+// endpointHostPort is the host models on ep are reachable at, and the base URL's port
+func endpointHostPort(ep config.Endpoint) (string, int) {
+	if ep.Type == config.EndpointTypeLocal {
+		return "localhost", 0
+	}
+	u, err := url.Parse(ep.BaseURL)
+	if err != nil {
+		return ep.BaseURL, 0
+	}
+	port, _ := strconv.Atoi(u.Port())
+	return u.Hostname(), port
+}
@@ -0,0 +1,181 @@
+// Package collector reads GPU memory on this machine without blackbox-server
+package collector
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+)
+
+// ErrNoDriver means nvidia-smi isn't installed or can't talk to the driver
+var ErrNoDriver = errors.New("nvidia-smi not available")
+
+const mib = 1024 * 1024
+
+// GPU is one device as reported by nvidia-smi
+type GPU struct {
+	Index      int
+	UUID       string
+	Name       string
+	TotalBytes int64
+	UsedBytes  int64
+	// Utilization is the SM (compute) utilization in percent; it and the
+	// rest are 0 when not reported
+	Utilization  float64
+	TemperatureC float64
+	PowerWatts   float64
+	PowerLimit   float64 // Enforced power limit in watts
+}
+
+// Process is one compute process holding GPU memory
+type Process struct {
+	PID       int
+	Name      string
+	GPUUUID   string
+	UsedBytes int64
+}
+
+// NvidiaSMI collects snapshots by shelling out to nvidia-smi
+type NvidiaSMI struct {
+	// Path to the binary; empty means look it up on PATH
+	Path string
+}
+
+func (n *NvidiaSMI) run(ctx context.Context, args ...string) ([][]string, error) {
+	path := n.Path
+	if path == "" {
+		var err error
+		if path, err = exec.LookPath("nvidia-smi"); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrNoDriver, err)
+		}
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = strings.TrimSpace(string(out))
+		}
+		return nil, fmt.Errorf("%w: %v: %s", ErrNoDriver, err, msg)
+	}
+	r := csv.NewReader(bytes.NewReader(out))
+	r.TrimLeadingSpace = true
+	r.FieldsPerRecord = -1
+	return r.ReadAll()
+}
+
+// GPUs lists every device with its memory, utilization, temperature and power
+func (n *NvidiaSMI) GPUs(ctx context.Context) ([]GPU, error) {
+	rows, err := n.run(ctx, "--query-gpu=index,uuid,name,memory.total,memory.used,utilization.gpu,temperature.gpu,power.draw,power.limit", "--format=csv,noheader,nounits")
+	if err != nil {
+		return nil, err
+	}
+	var gpus []GPU
+	for _, row := range rows {
+		if len(row) < 5 {
+			continue
+		}
+		g := GPU{UUID: row[1], Name: row[2]}
+		g.Index, _ = strconv.Atoi(row[0])
+		g.TotalBytes = parseMiB(row[3])
+		g.UsedBytes = parseMiB(row[4])
+		if len(row) > 8 {
+			g.Utilization = parseFloat(row[5])
+			g.TemperatureC = parseFloat(row[6])
+			g.PowerWatts = parseFloat(row[7])
+			g.PowerLimit = parseFloat(row[8])
+		}
+		gpus = append(gpus, g)
+	}
+	if len(gpus) == 0 {
+		return nil, fmt.Errorf("%w: no GPUs found", ErrNoDriver)
+	}
+	return gpus, nil
+}
+
+// Processes lists compute processes using GPU memory
+func (n *NvidiaSMI) Processes(ctx context.Context) ([]Process, error) {
+	rows, err := n.run(ctx, "--query-compute-apps=pid,process_name,gpu_uuid,used_memory", "--format=csv,noheader,nounits")
+	if err != nil {
+		return nil, err
+	}
+	var procs []Process
+	for _, row := range rows {
+		if len(row) < 4 {
+			continue
+		}
+		p := Process{Name: row[1], GPUUUID: row[2], UsedBytes: parseMiB(row[3])}
+		p.PID, _ = strconv.Atoi(row[0])
+		procs = append(procs, p)
+	}
+	return procs, nil
+}
+
+// Snapshot sums memory over all GPUs, lists them as its GPUs and each compute
+// process as a model.
+// nvidia-smi knows nothing about KV cache or prefix caching, so those stay zero.
+func (n *NvidiaSMI) Snapshot(ctx context.Context) (*model.Snapshot, error) {
+	gpus, err := n.GPUs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	procs, err := n.Processes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snap := &model.Snapshot{}
+	for _, g := range gpus {
+		snap.TotalVRAMBytes += g.TotalBytes
+		snap.AllocatedVRAMBytes += g.UsedBytes
+		snap.GPUs = append(snap.GPUs, model.GPUStats{
+			Index:              g.Index,
+			Name:               g.Name,
+			TotalVRAMBytes:     g.TotalBytes,
+			AllocatedVRAMBytes: g.UsedBytes,
+			UtilizationPercent: g.Utilization,
+			TemperatureC:       g.TemperatureC,
+			PowerWatts:         g.PowerWatts,
+			PowerLimitWatts:    g.PowerLimit,
+		})
+	}
+	sort.Slice(procs, func(i, j int) bool { return procs[i].UsedBytes > procs[j].UsedBytes })
+	for _, p := range procs {
+		snap.Models = append(snap.Models, model.ModelInfo{
+			ModelID:            fmt.Sprintf("%s (pid %d)", filepath.Base(p.Name), p.PID),
+			AllocatedVRAMBytes: p.UsedBytes,
+		})
+	}
+	return snap, nil
+}
+
+// parseMiB reads a nounits memory field; "[N/A]" and friends count as zero
+func parseMiB(s string) int64 {
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0
+	}
+	return int64(v * mib)
+}
+
+// parseFloat reads a nounits field like utilization or power; "[N/A]" counts as zero
+func parseFloat(s string) float64 {
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultBackups is how many config backups are kept when the config doesn't say
+const DefaultBackups = 10
+
+const backupTimeFormat = "20060102T150405.000000000"
+
+// Backup is a copy of the config file taken before it was changed
+type Backup struct {
+	Path string
+	Time time.Time
+}
+
+// BackupDir holds the timestamped copies written before every Save, next
+// to the config file
+func BackupDir() string {
+	return filepath.Join(filepath.Dir(Path()), "backups")
+}
+
+// backupPrefix starts the names of the config file's backups, e.g. "config-"
+func backupPrefix() string {
+	base := filepath.Base(Path())
+	return strings.TrimSuffix(base, filepath.Ext(base)) + "-"
+}
+
+// Backups lists the saved copies, newest first
+func Backups() ([]Backup, error) {
+	entries, err := os.ReadDir(BackupDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backups: %w", err)
+	}
+	var backups []Backup
+	prefix := backupPrefix()
+	for _, e := range entries {
+		stamp, ok := strings.CutPrefix(e.Name(), prefix)
+		if !ok || e.IsDir() {
+			continue
+		}
+		t, err := time.ParseInLocation(backupTimeFormat, strings.TrimSuffix(stamp, filepath.Ext(stamp)), time.Local)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, Backup{Path: filepath.Join(BackupDir(), e.Name()), Time: t})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].Time.After(backups[j].Time) })
+	return backups, nil
+}
+
+// backup copies the config file as it is on disk before it's overwritten, then
+// drops all but the newest keep copies. keep < 0 turns backups off.
+func backup(keep int) error {
+	if keep < 0 {
+		return nil
+	}
+	if keep == 0 {
+		keep = DefaultBackups
+	}
+	current := Path()
+	data, err := os.ReadFile(current)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	backups, err := Backups()
+	if err != nil {
+		return err
+	}
+	// Saving the same config twice shouldn't push a real change out of the window
+	if len(backups) > 0 {
+		if last, err := os.ReadFile(backups[0].Path); err == nil && bytes.Equal(last, data) {
+			return nil
+		}
+	}
+
+	if err := os.MkdirAll(BackupDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	now := time.Now()
+	path := filepath.Join(BackupDir(), backupPrefix()+now.Format(backupTimeFormat)+filepath.Ext(current))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	backups = append([]Backup{{Path: path, Time: now}}, backups...)
+	for _, b := range backups[min(keep, len(backups)):] {
+		os.Remove(b.Path)
+	}
+	return nil
+}
+
+// Rollback replaces the config with the backup at path. The config being
+// replaced is backed up first, so a rollback can itself be rolled back. A
+// backup in the other format (JSON for a YAML config or the reverse) is
+// converted rather than copied.
+func Rollback(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+	cfg, _, err := parse(path, data)
+	if err != nil {
+		return nil, fmt.Errorf("backup %s is not a valid config: %w", filepath.Base(path), err)
+	}
+	unlock, err := lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	keep := 0
+	if current, err := load(false); err == nil {
+		keep = current.Backups
+	}
+	if err := backup(keep); err != nil {
+		return nil, err
+	}
+	target := Path()
+	if isYAML(path) != isYAML(target) {
+		if data, err = encode(target, cfg); err != nil {
+			return nil, fmt.Errorf("failed to marshal config: %w", err)
+		}
+	}
+	if err := writeFile(target, data); err != nil {
+		return nil, fmt.Errorf("failed to write config: %w", err)
+	}
+	return cfg, nil
+}
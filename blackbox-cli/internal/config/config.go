@@ -1,36 +1,341 @@
 package config
 
 import (
-	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/maxdcmn/blackbox-cli/internal/keyring"
+	"github.com/maxdcmn/blackbox-cli/internal/poller"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
 )
 
 type Endpoint struct {
-	Name     string `json:"name"`
-	BaseURL  string `json:"base_url"`
-	Endpoint string `json:"endpoint"`
-	Timeout  string `json:"timeout"`
+	Name      string            `json:"name"`
+	BaseURL   string            `json:"base_url"`
+	Endpoint  string            `json:"endpoint"`
+	Timeout   string            `json:"timeout"`
+	Transport string            `json:"transport,omitempty"` // Stream transport: sse (default), ws, auto, grpc
+	Proxy     string            `json:"proxy,omitempty"`     // http://, https:// or socks5:// proxy; defaults to HTTP(S)_PROXY env
+	Headers   map[string]string `json:"headers,omitempty"`   // Sent with every request (tenant, tracing, routing)
+
+	// Type is "blackbox" (default), "vllm" to scrape a vLLM /metrics endpoint directly,
+	// or "local" to read this machine's GPUs with nvidia-smi (BaseURL is then unused).
+	// vLLM doesn't report GPU size, so GPUMemoryGB sets it for VRAM figures.
+	Type        string  `json:"type,omitempty"`
+	GPUMemoryGB float64 `json:"gpu_memory_gb,omitempty"`
+
+	// PinSHA256 lists "sha256/<base64>" hashes of public keys, one of which the
+	// server's certificate chain must contain; needs an https base_url
+	PinSHA256 []string `json:"pin_sha256,omitempty"`
+
+	// StallTimeout is how long a stream may go without an event or heartbeat
+	// before it's reported stalled; empty uses the default, "0" turns it off
+	StallTimeout string `json:"stall_timeout,omitempty"`
+
+	// GRPCAddr is the host:port for the grpc transport; defaults to the base URL's host
+	GRPCAddr string `json:"grpc_addr,omitempty"`
+
+	// Connection phase timeouts, so an unreachable host fails fast while Timeout
+	// stays long enough for slow requests like deploys. Empty uses the defaults.
+	DialTimeout           string `json:"dial_timeout,omitempty"`
+	TLSHandshakeTimeout   string `json:"tls_handshake_timeout,omitempty"`
+	ResponseHeaderTimeout string `json:"response_header_timeout,omitempty"`
+
+	// Polling speeds up to PollMin while metrics move or requests queue and
+	// backs off to PollMax while the endpoint is idle. Empty uses the defaults;
+	// setting both to the same value polls at a fixed rate.
+	PollMin string `json:"poll_min,omitempty"`
+	PollMax string `json:"poll_max,omitempty"`
+
+	// Interval is how often this endpoint is polled while its load is steady,
+	// overriding --interval (and the selected endpoint's 5s); HistorySize is
+	// how many points its charts keep. Empty or 0 uses the defaults.
+	Interval    string `json:"interval,omitempty"`
+	HistorySize int    `json:"history_size,omitempty"`
+
+	// KVCacheMerge is how the dashboard totals used KV cache from an aggregated
+	// poll: "sum" of the models (default), the window's "avg", or the "max" of both
+	KVCacheMerge string `json:"kv_cache_merge,omitempty"`
+
+	// HFToken is the Hugging Face token for deploys from the dashboard when
+	// none is typed in; write it as keyring:<name> to keep it in the OS keyring
+	HFToken string `json:"hf_token,omitempty"`
+
+	// After a dashboard deploy succeeds, a short generation loads the weights
+	// and captures CUDA graphs, and its latency is shown. Empty uses the
+	// defaults; warmup_timeout "0" turns the warm-up off. The model's port
+	// is reached over plain http unless warmup_scheme is "https", whatever
+	// the scheme of base_url.
+	WarmupPrompt    string `json:"warmup_prompt,omitempty"`
+	WarmupMaxTokens int    `json:"warmup_max_tokens,omitempty"`
+	WarmupTimeout   string `json:"warmup_timeout,omitempty"`
+	WarmupScheme    string `json:"warmup_scheme,omitempty"`
+
+	// RateLimit caps requests per second to this endpoint; 0 uses the default, negative disables it
+	RateLimit float64 `json:"rate_limit,omitempty"`
+
+	// Description says what the endpoint is (GPU host, team), shown under the
+	// endpoints panel's header while it's hovered and in its preview
+	Description string `json:"description,omitempty"`
+
+	// Who to reach about this host, shown in the dashboard next to its alerts
+	Owner  string `json:"owner,omitempty"`
+	OnCall string `json:"on_call,omitempty"`
+	Notes  string `json:"notes,omitempty"`
+
+	// Tags label the endpoint (env=prod, gpu=h100) for --select and dashboard grouping
+	Tags Tags `json:"tags,omitempty"`
+
+	// Default makes the dashboard start on this endpoint when it has none
+	// remembered from its last run
+	Default bool `json:"default,omitempty"`
+
+	// ReadOnly keeps deploys, spindowns and optimizing off this endpoint, in
+	// the dashboard and for commands whose --url points at it
+	ReadOnly bool `json:"read_only,omitempty"`
 }
 
+// Contact is who to page when the endpoint alerts: on-call, else the owner
+func (ep Endpoint) Contact() string {
+	if ep.OnCall != "" {
+		return ep.OnCall
+	}
+	return ep.Owner
+}
+
+// ReadOnlyAt returns the read_only endpoint, in any profile, at baseURL.
+// Trailing slashes and the case of the scheme and host don't matter.
+func (cfg *Config) ReadOnlyAt(baseURL string) (Endpoint, bool) {
+	for _, ep := range cfg.AllEndpoints() {
+		if ep.ReadOnly && ep.Type != EndpointTypeLocal && sameURL(ep.BaseURL, baseURL) {
+			return ep, true
+		}
+	}
+	return Endpoint{}, false
+}
+
+func sameURL(a, b string) bool {
+	ua, errA := url.Parse(strings.TrimSpace(a))
+	ub, errB := url.Parse(strings.TrimSpace(b))
+	if errA != nil || errB != nil {
+		return strings.TrimRight(a, "/") == strings.TrimRight(b, "/")
+	}
+	return strings.EqualFold(ua.Scheme, ub.Scheme) && strings.EqualFold(ua.Host, ub.Host) &&
+		strings.TrimRight(ua.Path, "/") == strings.TrimRight(ub.Path, "/")
+}
+
+const (
+	EndpointTypeBlackbox = "blackbox"
+	EndpointTypeVLLM     = "vllm"
+	EndpointTypeLocal    = "local"
+)
+
 type Config struct {
-	Endpoints []Endpoint `json:"endpoints"`
+	Version   int                `json:"version"` // Layout of the file, upgraded on load; see CurrentVersion
+	Endpoints []Endpoint         `json:"endpoints"`
+	Alerts    []AlertRule        `json:"alerts,omitempty"`
+	Features  map[string]bool    `json:"features,omitempty"` // Experimental features switched on (or off) by name
+	Telemetry *Telemetry         `json:"telemetry,omitempty"`
+	Backups   int                `json:"backups,omitempty"`  // Copies kept in BackupDir before each change; 0 uses DefaultBackups, negative disables
+	Redact    []string           `json:"redact,omitempty"`   // Values (tenant names, hostnames) masked in logs, traces, shared snapshots and exports
+	Profiles  map[string]Profile `json:"profiles,omitempty"` // Named endpoint sets selected with --profile
+	// AgeIdentity is the age identity file that decrypts "age:" token values;
+	// relative to the config's directory, default age.key there if it exists
+	AgeIdentity string   `json:"age_identity,omitempty"`
+	UI          *UIPrefs `json:"ui,omitempty"` // Dashboard look and keys, also changed from its settings popup
+	// History is where and for how long chart history is kept on disk
+	History *HistoryStore `json:"history,omitempty"`
+
+	profile  string     // Profile whose endpoints are in Endpoints, see SetProfile
+	topLevel []Endpoint // The top-level endpoints while a profile's are in use
+}
+
+// Secrets lists the values logs and exports must never show: the user's
+// redact list and the values of credential headers on every endpoint
+func (cfg *Config) Secrets() []string {
+	secrets := append([]string(nil), cfg.Redact...)
+	for _, ep := range cfg.AllEndpoints() {
+		if !keyring.IsRef(ep.HFToken) && ep.HFToken != "" {
+			secrets = append(secrets, ep.HFToken)
+		}
+		for name, value := range ep.Headers {
+			if utils.SensitiveHeader(name) {
+				secrets = append(secrets, strings.TrimPrefix(strings.TrimPrefix(value, "Bearer "), "Basic "), value)
+			}
+		}
+	}
+	return secrets
+}
+
+// CheckAgeValues decrypts every age: value in the config, so a wrong or
+// missing identity file shows up when the CLI starts instead of as a failed
+// request later. The plaintexts are cached for when the values are used.
+func (cfg *Config) CheckAgeValues() error {
+	for _, ep := range cfg.AllEndpoints() {
+		fields := map[string]string{"hf_token": ep.HFToken}
+		for name, value := range ep.Headers {
+			fields["headers."+name] = value
+		}
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if !keyring.IsAge(fields[name]) {
+				continue
+			}
+			if _, err := keyring.Resolve(fields[name]); err != nil {
+				return fmt.Errorf("endpoint '%s' %s: %w", ep.Name, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Telemetry is off unless Enabled; reports are only sent when Endpoint is set
+type Telemetry struct {
+	Enabled  bool   `json:"enabled"`
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// UIPrefs are the dashboard's display preferences. Empty fields, and values
+// the dashboard doesn't know, use the defaults.
+type UIPrefs struct {
+	Theme      string `json:"theme,omitempty"`       // "dark" (default), "light", "high-contrast", "solarized", "mono" or one of Themes
+	Units      string `json:"units,omitempty"`       // Memory sizes in "gib" (default), "gb", "mib" or "percent" of the VRAM
+	ChartStyle string `json:"chart_style,omitempty"` // "area" (default), "line" or "bars"
+	Layout     string `json:"layout,omitempty"`      // View the dashboard starts in: "dashboard" (default), "grid", "fleet" or "usage"
+	// Keys rebinds dashboard actions by name ("grid": "G"); an action's
+	// default key does nothing once it's rebound
+	Keys map[string]string `json:"keys,omitempty"`
+	// Themes are custom palettes by name, picked with Theme like the built-in ones
+	Themes map[string]Palette `json:"themes,omitempty"`
+}
+
+// Palette is a custom dashboard theme. Colors are ANSI 256 numbers ("214")
+// or hex ("#ffaf00"); the ones left empty are Base's.
+type Palette struct {
+	Base          string `json:"base,omitempty"` // Built-in theme it starts from, default "dark"
+	Focused       string `json:"focused,omitempty"`
+	Unfocused     string `json:"unfocused,omitempty"`
+	Text          string `json:"text,omitempty"`
+	Muted         string `json:"muted,omitempty"`
+	Dim           string `json:"dim,omitempty"`
+	Italic        string `json:"italic,omitempty"`
+	Background    string `json:"background,omitempty"`
+	Orange        string `json:"orange,omitempty"`
+	Yellow        string `json:"yellow,omitempty"`
+	Cyan          string `json:"cyan,omitempty"`
+	Green         string `json:"green,omitempty"`
+	Red           string `json:"red,omitempty"`
+	VRAM          string `json:"vram,omitempty"` // Chart colors
+	Blocks        string `json:"blocks,omitempty"`
+	Fragmentation string `json:"fragmentation,omitempty"`
+	PrefixHitRate string `json:"prefix_hit_rate,omitempty"`
+	Throughput    string `json:"throughput,omitempty"`
+}
+
+// HistoryStore is the dashboard's chart history file. Durations are Go
+// durations, seconds or days ("7d"); empty fields use the defaults.
+type HistoryStore struct {
+	Disabled   bool   `json:"disabled,omitempty"`   // Keep history in memory only, as before
+	Path       string `json:"path,omitempty"`       // Relative to the config's directory, default history.db there
+	Retention  string `json:"retention,omitempty"`  // How long samples are kept, default 7d
+	RawFor     string `json:"raw_for,omitempty"`    // How long every poll is kept before being averaged, default 24h
+	Resolution string `json:"resolution,omitempty"` // What older polls are averaged into, default 5m
+}
+
+// AlertRule fires when Metric compares against Value using Op (">" or "<").
+// Metric is one of the dashboard charts' (e.g. "allocated_vram_gb") or a
+// share of the VRAM ("allocated_vram_percent", "used_kv_cache_percent").
+// Rules with an empty Endpoint apply to every endpoint without its own rule.
+type AlertRule struct {
+	Endpoint string  `json:"endpoint,omitempty"`
+	Metric   string  `json:"metric"`
+	Op       string  `json:"op"`
+	Value    float64 `json:"value"`
 }
 
-var configPath string
+var configPath, pathOverride string
+
+// defaultAgeIdentity is the identity file used when age_identity isn't set
+const defaultAgeIdentity = "age.key"
+
+// DirEnv names the config directory, overriding XDG_CONFIG_HOME
+const DirEnv = "BLACKBOX_CONFIG_DIR"
 
 func init() {
+	configPath = filepath.Join(defaultDir(), "config.json")
+}
+
+// defaultDir is $BLACKBOX_CONFIG_DIR, else blackbox under $XDG_CONFIG_HOME,
+// else ~/.config/blackbox
+func defaultDir() string {
+	if dir := os.Getenv(DirEnv); dir != "" {
+		return dir
+	}
 	home, err := os.UserHomeDir()
 	if err != nil {
 		home = "."
 	}
-	configPath = filepath.Join(home, ".config", "blackbox", "config.json")
+	legacy := filepath.Join(home, ".config", "blackbox")
+	// The spec ignores relative values
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(xdg) {
+		dir := filepath.Join(xdg, "blackbox")
+		// A config set up before XDG_CONFIG_HOME was honoured stays where it is
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			if _, err := os.Stat(legacy); err == nil {
+				return legacy
+			}
+		}
+		return dir
+	}
+	return legacy
 }
 
+// SetPortable keeps the config and local state next to the running binary,
+// for a blackbox carried on a USB stick or baked into a container image
+func SetPortable() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the blackbox binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	configPath = filepath.Join(filepath.Dir(exe), "config.json")
+	return nil
+}
+
+// SetPath makes Load and Save use path instead of the file in Dir; its
+// extension picks JSON or YAML
+func SetPath(path string) {
+	pathOverride = path
+}
+
+// Dir is the directory holding local state and, unless SetPath moved it, the config file
+func Dir() string {
+	return filepath.Dir(configPath)
+}
+
+// Load reads the config from Path; a missing file gives the default local endpoint
 func Load() (*Config, error) {
-	data, err := os.ReadFile(configPath)
+	return load(true)
+}
+
+// load reads the config. A file in an older layout is written back upgraded
+// when saveMigrated is set; Update leaves that to its own save, as it already
+// holds the lock.
+func load(saveMigrated bool) (*Config, error) {
+	path := Path()
+	data, err := os.ReadFile(path)
 	if os.IsNotExist(err) {
+		keyring.SetAgeIdentity((&Config{}).ageIdentityPath(path))
 		return &Config{
 			Endpoints: []Endpoint{
 				{
@@ -46,9 +351,28 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("failed to read config: %w", err)
 	}
 
-	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse config: %w", err)
+	cfg, migrated, err := parse(path, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", filepath.Base(path), err)
+	}
+	if migrated && saveMigrated {
+		// Written back, after a backup of the old file, so the upgrade runs once
+		if err := Save(cfg); err != nil {
+			utils.Warn("config upgraded to version %d but not saved: %v", CurrentVersion, err)
+		} else {
+			utils.Info("upgraded %s to config version %d; the old file is in %s", filepath.Base(path), CurrentVersion, BackupDir())
+		}
+	}
+
+	keyring.SetAgeIdentity(cfg.ageIdentityPath(path))
+
+	if profileName != "" {
+		if err := cfg.useProfile(profileName); err != nil {
+			return nil, err
+		}
 	}
 
 	if len(cfg.Endpoints) == 0 {
@@ -62,20 +386,115 @@ func Load() (*Config, error) {
 		}
 	}
 
-	return &cfg, nil
+	return cfg, nil
+}
+
+// validate rejects endpoint settings that would otherwise be ignored: those of
+// the top-level endpoints, and of each profile's endpoints and defaults
+func (cfg *Config) validate() error {
+	check := func(ep Endpoint, where string) error {
+		if _, err := poller.ParseStrategy(ep.KVCacheMerge); err != nil {
+			return fmt.Errorf("%s: kv_cache_merge: %w", where, err)
+		}
+		if ep.WarmupScheme != "" && ep.WarmupScheme != "http" && ep.WarmupScheme != "https" {
+			return fmt.Errorf("%s: invalid warmup_scheme %q (expected http or https)", where, ep.WarmupScheme)
+		}
+		return nil
+	}
+	for _, ep := range cfg.Endpoints {
+		if err := check(ep, fmt.Sprintf("endpoint '%s'", ep.Name)); err != nil {
+			return err
+		}
+	}
+	for _, name := range cfg.ProfileNames() {
+		p := cfg.Profiles[name]
+		if p.Defaults != nil {
+			if err := check(*p.Defaults, fmt.Sprintf("profile '%s' defaults", name)); err != nil {
+				return err
+			}
+		}
+		for _, ep := range p.Endpoints {
+			if err := check(ep, fmt.Sprintf("profile '%s' endpoint '%s'", name, ep.Name)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ageIdentityPath resolves AgeIdentity against the directory of the config at path
+func (cfg *Config) ageIdentityPath(path string) string {
+	dir := filepath.Dir(path)
+	if cfg.AgeIdentity == "" {
+		def := filepath.Join(dir, defaultAgeIdentity)
+		if _, err := os.Stat(def); err != nil {
+			return ""
+		}
+		return def
+	}
+	p := cfg.AgeIdentity
+	if rest, ok := strings.CutPrefix(p, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			p = filepath.Join(home, rest)
+		}
+	}
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(dir, p)
+	}
+	return p
 }
 
+// Save writes cfg to Path, in YAML if that's the file in use. It replaces
+// whatever other processes saved since cfg was loaded; changes go through
+// Update so they don't.
 func Save(cfg *Config) error {
-	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
-		return fmt.Errorf("failed to create config directory: %w", err)
+	unlock, err := lock()
+	if err != nil {
+		return err
 	}
+	defer unlock()
+	return save(cfg)
+}
 
-	data, err := json.MarshalIndent(cfg, "", "  ")
+// Update reads the config as it is on disk, applies change to it and saves
+// it, all under the config lock, then copies the result into cfg. Two
+// blackbox processes changing the config at once each keep the other's
+// change. Nothing is saved, and cfg stays as it was, if change fails.
+func Update(cfg *Config, change func(cfg *Config) error) error {
+	unlock, err := lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	current, err := load(false)
+	if err != nil {
+		return err
+	}
+	if err := change(current); err != nil {
+		return err
+	}
+	if err := save(current); err != nil {
+		return err
+	}
+	*cfg = *current
+	return nil
+}
+
+// save writes cfg while the caller holds the lock, after backing up the file it replaces
+func save(cfg *Config) error {
+	path := Path()
+	stored := cfg.stored()
+	stored.Version = CurrentVersion
+	data, err := encode(path, stored)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	if err := os.WriteFile(configPath, data, 0644); err != nil {
+	if err := backup(cfg.Backups); err != nil {
+		return err
+	}
+
+	if err := writeFile(path, data); err != nil {
 		return fmt.Errorf("failed to write config: %w", err)
 	}
 
@@ -83,38 +502,121 @@ func Save(cfg *Config) error {
 }
 
 func AddEndpoint(cfg *Config, ep Endpoint) error {
-	for _, e := range cfg.Endpoints {
-		if e.Name == ep.Name {
-			return fmt.Errorf("endpoint with name '%s' already exists", ep.Name)
+	return Update(cfg, func(cfg *Config) error {
+		for _, e := range cfg.Endpoints {
+			if e.Name == ep.Name {
+				return fmt.Errorf("endpoint with name '%s' already exists", ep.Name)
+			}
 		}
-	}
-	cfg.Endpoints = append(cfg.Endpoints, ep)
-	return Save(cfg)
+		cfg.Endpoints = append(cfg.Endpoints, ep)
+		return nil
+	})
 }
 
 func RemoveEndpoint(cfg *Config, name string) error {
-	found := false
-	endpoints := make([]Endpoint, 0, len(cfg.Endpoints))
-	for _, e := range cfg.Endpoints {
-		if e.Name != name {
-			endpoints = append(endpoints, e)
-		} else {
-			found = true
+	return Update(cfg, func(cfg *Config) error {
+		found := false
+		endpoints := make([]Endpoint, 0, len(cfg.Endpoints))
+		for _, e := range cfg.Endpoints {
+			if e.Name != name {
+				endpoints = append(endpoints, e)
+			} else {
+				found = true
+			}
 		}
-	}
-	if !found {
-		return fmt.Errorf("endpoint '%s' not found", name)
-	}
-	cfg.Endpoints = endpoints
-	return Save(cfg)
+		if !found {
+			return fmt.Errorf("endpoint '%s' not found", name)
+		}
+		cfg.Endpoints = endpoints
+		alerts := make([]AlertRule, 0, len(cfg.Alerts))
+		for _, r := range cfg.Alerts {
+			if r.Endpoint != name {
+				alerts = append(alerts, r)
+			}
+		}
+		cfg.Alerts = alerts
+		return nil
+	})
 }
 
 func UpdateEndpoint(cfg *Config, oldName string, newEp Endpoint) error {
-	for i, e := range cfg.Endpoints {
-		if e.Name == oldName {
-			cfg.Endpoints[i] = newEp
-			return Save(cfg)
+	return Update(cfg, func(cfg *Config) error {
+		for i, e := range cfg.Endpoints {
+			if e.Name == oldName {
+				cfg.Endpoints[i] = newEp
+				for j := range cfg.Alerts {
+					if cfg.Alerts[j].Endpoint == oldName {
+						cfg.Alerts[j].Endpoint = newEp.Name
+					}
+				}
+				return nil
+			}
 		}
+		return fmt.Errorf("endpoint '%s' not found", oldName)
+	})
+}
+
+// AlertRuleFor returns the rule for metric on endpoint, preferring an endpoint-specific rule over a global one
+func (cfg *Config) AlertRuleFor(endpoint, metric string) (AlertRule, bool) {
+	var global *AlertRule
+	for i, r := range cfg.Alerts {
+		if r.Metric != metric {
+			continue
+		}
+		if r.Endpoint == endpoint {
+			return r, true
+		}
+		if r.Endpoint == "" && global == nil {
+			global = &cfg.Alerts[i]
+		}
+	}
+	if global != nil {
+		return *global, true
+	}
+	return AlertRule{}, false
+}
+
+// Breached reports whether value trips the rule
+func (r AlertRule) Breached(value float64) bool {
+	if r.Op == "<" {
+		return value < r.Value
+	}
+	return value > r.Value
+}
+
+// SetAlertRule adds rule or replaces the existing rule for the same endpoint and metric
+func SetAlertRule(cfg *Config, rule AlertRule) error {
+	if rule.Op != ">" && rule.Op != "<" {
+		return fmt.Errorf("invalid alert operator '%s'", rule.Op)
 	}
-	return fmt.Errorf("endpoint '%s' not found", oldName)
+	return Update(cfg, func(cfg *Config) error {
+		for i, r := range cfg.Alerts {
+			if r.Endpoint == rule.Endpoint && r.Metric == rule.Metric {
+				cfg.Alerts[i] = rule
+				return nil
+			}
+		}
+		cfg.Alerts = append(cfg.Alerts, rule)
+		return nil
+	})
+}
+
+func RemoveAlertRule(cfg *Config, endpoint, metric string) error {
+	return Update(cfg, func(cfg *Config) error {
+		for i, r := range cfg.Alerts {
+			if r.Endpoint == endpoint && r.Metric == metric {
+				cfg.Alerts = append(cfg.Alerts[:i], cfg.Alerts[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("no alert rule for '%s' on '%s'", metric, endpoint)
+	})
+}
+
+// SetUIPrefs replaces the dashboard preferences and saves the config
+func SetUIPrefs(cfg *Config, prefs UIPrefs) error {
+	return Update(cfg, func(cfg *Config) error {
+		cfg.UI = &prefs
+		return nil
+	})
 }
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlNames are the YAML config files, in order of preference. Either one
+// wins over config.json when both exist.
+var yamlNames = []string{"config.yaml", "config.yml"}
+
+// Path is the config file in use: the one given to SetPath, else
+// config.yaml or config.yml if present, otherwise config.json
+func Path() string {
+	if pathOverride != "" {
+		return pathOverride
+	}
+	for _, name := range yamlNames {
+		p := filepath.Join(Dir(), name)
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+	return configPath
+}
+
+func isYAML(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// decode parses a config in the format given by path's extension into v. YAML
+// is converted through JSON so the json field names are the only ones, and
+// anchors, aliases and merge keys (<<: *defaults) are resolved first.
+func decode(path string, data []byte, v any) error {
+	if !isYAML(path) {
+		return json.Unmarshal(data, v)
+	}
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if doc == nil {
+		return nil
+	}
+	asJSON, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("unsupported YAML value: %w", err)
+	}
+	return json.Unmarshal(asJSON, v)
+}
+
+// encode writes cfg in the format given by path's extension. YAML keeps the
+// JSON field order; comments and anchors in the old file aren't carried over,
+// but the backup taken before every save still has them.
+func encode(path string, cfg *Config) ([]byte, error) {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil || !isYAML(path) {
+		return data, err
+	}
+	// JSON is YAML, so reading it as a node tree keeps the key order
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	blockStyle(&node)
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), enc.Close()
+}
+
+// blockStyle drops the flow style ({...}, [...]) the JSON input was parsed with
+func blockStyle(n *yaml.Node) {
+	n.Style &^= yaml.FlowStyle | yaml.DoubleQuotedStyle
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !unix
+
+package config
+
+import (
+	"errors"
+	"os"
+)
+
+// Without flock changes aren't serialised; the atomic rename still keeps
+// the file whole.
+func tryLock(f *os.File) error {
+	return errors.ErrUnsupported
+}
+
+func unlock(f *os.File) {}
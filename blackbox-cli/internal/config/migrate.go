@@ -0,0 +1,112 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// CurrentVersion is the config layout this CLI reads and writes. Files
+// without a version are version 0.
+const CurrentVersion = 1
+
+// migrations[i] upgrades a decoded config from version i to i+1. Add one here,
+// and bump CurrentVersion, whenever the layout changes in a way older files
+// wouldn't parse under.
+var migrations = []func(doc map[string]any) error{
+	migrateV0,
+}
+
+// durationFields are the endpoint settings holding durations
+var durationFields = []string{
+	"timeout", "stall_timeout", "dial_timeout", "tls_handshake_timeout", "response_header_timeout",
+	"poll_min", "poll_max", "interval", "warmup_timeout",
+}
+
+// migrateV0 turns durations written as bare numbers of seconds ("timeout": 5),
+// which the flags accept but the config never parsed, into strings
+func migrateV0(doc map[string]any) error {
+	eachEndpoint(doc, func(ep map[string]any) {
+		for _, field := range durationFields {
+			if n, ok := ep[field].(float64); ok {
+				ep[field] = strconv.FormatFloat(n, 'f', -1, 64) + "s"
+			}
+		}
+	})
+	return nil
+}
+
+// eachEndpoint calls fn on every endpoint object in doc: the top-level ones,
+// and each profile's and its defaults
+func eachEndpoint(doc map[string]any, fn func(ep map[string]any)) {
+	visit := func(list any) {
+		items, _ := list.([]any)
+		for _, item := range items {
+			if ep, ok := item.(map[string]any); ok {
+				fn(ep)
+			}
+		}
+	}
+	visit(doc["endpoints"])
+	profiles, _ := doc["profiles"].(map[string]any)
+	for _, p := range profiles {
+		p, ok := p.(map[string]any)
+		if !ok {
+			continue
+		}
+		visit(p["endpoints"])
+		if d, ok := p["defaults"].(map[string]any); ok {
+			fn(d)
+		}
+	}
+}
+
+// migrate upgrades doc to CurrentVersion and reports whether it had to. A
+// version newer than CurrentVersion is an error rather than a guess.
+func migrate(doc map[string]any) (bool, error) {
+	version := 0
+	if v, ok := doc["version"]; ok {
+		n, ok := v.(float64)
+		if !ok || n < 0 || n != float64(int(n)) {
+			return false, fmt.Errorf("invalid config version %v", v)
+		}
+		version = int(n)
+	}
+	if version > CurrentVersion {
+		return false, fmt.Errorf("config version %d is newer than this blackbox supports (%d); upgrade blackbox", version, CurrentVersion)
+	}
+	if version == CurrentVersion {
+		return false, nil
+	}
+	for v := version; v < CurrentVersion; v++ {
+		if err := migrations[v](doc); err != nil {
+			return false, fmt.Errorf("failed to upgrade config from version %d: %w", v, err)
+		}
+	}
+	doc["version"] = CurrentVersion
+	return true, nil
+}
+
+// parse decodes a config file of any version, upgrading older layouts;
+// migrated reports whether it did, so the caller can write the result back
+func parse(path string, data []byte) (cfg *Config, migrated bool, err error) {
+	var doc map[string]any
+	if err := decode(path, data, &doc); err != nil {
+		return nil, false, err
+	}
+	cfg = &Config{}
+	if doc == nil {
+		return cfg, false, nil // Empty YAML file
+	}
+	if migrated, err = migrate(doc); err != nil {
+		return nil, false, err
+	}
+	asJSON, err := json.Marshal(doc)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := json.Unmarshal(asJSON, cfg); err != nil {
+		return nil, false, err
+	}
+	return cfg, migrated, nil
+}
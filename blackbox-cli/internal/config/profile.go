@@ -0,0 +1,155 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Profile is a named set of endpoints, e.g. "prod" or "lab", used instead of
+// the top-level endpoints when selected with --profile. Defaults fills in
+// every field an endpoint of the profile leaves empty; headers are merged.
+type Profile struct {
+	Endpoints []Endpoint `json:"endpoints"`
+	Defaults  *Endpoint  `json:"defaults,omitempty"`
+}
+
+var profileName string
+
+// SetProfile makes Load return the named profile's endpoints and Save write
+// them back to it; "" uses the top-level endpoints
+func SetProfile(name string) {
+	profileName = name
+}
+
+// ProfileName is the profile selected with SetProfile
+func ProfileName() string {
+	return profileName
+}
+
+// ProfileNames lists the config's profiles, sorted
+func (cfg *Config) ProfileNames() []string {
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AllEndpoints lists every endpoint in the file: the top-level ones and
+// each profile's, with its defaults applied
+func (cfg *Config) AllEndpoints() []Endpoint {
+	all := cfg.Endpoints
+	if cfg.profile != "" {
+		all = cfg.topLevel
+	}
+	all = append([]Endpoint(nil), all...)
+	for _, name := range cfg.ProfileNames() {
+		p := cfg.Profiles[name]
+		for _, ep := range p.Endpoints {
+			all = append(all, withDefaults(ep, p.Defaults))
+		}
+	}
+	return all
+}
+
+// useProfile swaps the selected profile's endpoints, with defaults applied,
+// in for the top-level ones, which Save puts back
+func (cfg *Config) useProfile(name string) error {
+	p, ok := cfg.Profiles[name]
+	if !ok {
+		if len(cfg.Profiles) == 0 {
+			return fmt.Errorf("unknown profile %q: the config has no profiles", name)
+		}
+		return fmt.Errorf("unknown profile %q (have: %s)", name, strings.Join(cfg.ProfileNames(), ", "))
+	}
+	cfg.profile = name
+	cfg.topLevel = cfg.Endpoints
+	cfg.Endpoints = make([]Endpoint, len(p.Endpoints))
+	for i, ep := range p.Endpoints {
+		cfg.Endpoints[i] = withDefaults(ep, p.Defaults)
+	}
+	return nil
+}
+
+// stored is cfg as written to disk: the profile in use gets the endpoints
+// back without the fields its defaults supply
+func (cfg *Config) stored() *Config {
+	if cfg.profile == "" {
+		return cfg
+	}
+	out := *cfg
+	out.Endpoints = cfg.topLevel
+	if out.Endpoints == nil {
+		out.Endpoints = []Endpoint{}
+	}
+	out.Profiles = make(map[string]Profile, len(cfg.Profiles))
+	for name, p := range cfg.Profiles {
+		out.Profiles[name] = p
+	}
+	p := cfg.Profiles[cfg.profile]
+	p.Endpoints = make([]Endpoint, len(cfg.Endpoints))
+	for i, ep := range cfg.Endpoints {
+		p.Endpoints[i] = withoutDefaults(ep, p.Defaults)
+	}
+	out.Profiles[cfg.profile] = p
+	return &out
+}
+
+// withDefaults copies every field of d that ep leaves empty into ep. The
+// name is never defaulted.
+func withDefaults(ep Endpoint, d *Endpoint) Endpoint {
+	if d == nil {
+		return ep
+	}
+	v, dv := reflect.ValueOf(&ep).Elem(), reflect.ValueOf(d).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		f, df := v.Field(i), dv.Field(i)
+		switch {
+		case v.Type().Field(i).Name == "Name" || df.IsZero():
+		case f.Kind() == reflect.Map:
+			merged := reflect.MakeMap(f.Type())
+			for _, k := range df.MapKeys() {
+				merged.SetMapIndex(k, df.MapIndex(k))
+			}
+			for _, k := range f.MapKeys() {
+				merged.SetMapIndex(k, f.MapIndex(k))
+			}
+			f.Set(merged)
+		case f.IsZero():
+			f.Set(df)
+		}
+	}
+	return ep
+}
+
+// withoutDefaults is the reverse of withDefaults: fields equal to the
+// default are cleared so the default keeps applying after a save
+func withoutDefaults(ep Endpoint, d *Endpoint) Endpoint {
+	if d == nil {
+		return ep
+	}
+	v, dv := reflect.ValueOf(&ep).Elem(), reflect.ValueOf(d).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		f, df := v.Field(i), dv.Field(i)
+		switch {
+		case v.Type().Field(i).Name == "Name" || df.IsZero() || f.IsZero():
+		case f.Kind() == reflect.Map:
+			own := reflect.MakeMap(f.Type())
+			for _, k := range f.MapKeys() {
+				if dval := df.MapIndex(k); !dval.IsValid() || !reflect.DeepEqual(dval.Interface(), f.MapIndex(k).Interface()) {
+					own.SetMapIndex(k, f.MapIndex(k))
+				}
+			}
+			if own.Len() == 0 {
+				own = reflect.Zero(f.Type())
+			}
+			f.Set(own)
+		case reflect.DeepEqual(f.Interface(), df.Interface()):
+			f.Set(reflect.Zero(f.Type()))
+		}
+	}
+	return ep
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const stateFile = "state.json"
+
+// state is what the dashboard remembers between runs. It lives next to the
+// config rather than in it, so switching endpoints doesn't rewrite the config
+// or take a backup.
+type state struct {
+	LastEndpoint map[string]string `json:"last_endpoint,omitempty"` // By config file and profile
+}
+
+func statePath() string {
+	return filepath.Join(Dir(), stateFile)
+}
+
+// stateKey tells apart the endpoint lists of different config files and profiles
+func stateKey() string {
+	if profileName == "" {
+		return Path()
+	}
+	return Path() + "#" + profileName
+}
+
+func loadState() state {
+	var st state
+	if data, err := os.ReadFile(statePath()); err == nil {
+		// An unreadable file is as good as none; the next save replaces it
+		_ = json.Unmarshal(data, &st)
+	}
+	return st
+}
+
+// LastEndpoint is the endpoint the dashboard had selected when it last quit
+// with this config and profile, or "" if there is none
+func LastEndpoint() string {
+	return loadState().LastEndpoint[stateKey()]
+}
+
+// SetLastEndpoint remembers name for LastEndpoint
+func SetLastEndpoint(name string) error {
+	st := loadState()
+	if st.LastEndpoint[stateKey()] == name {
+		return nil
+	}
+	if st.LastEndpoint == nil {
+		st.LastEndpoint = make(map[string]string)
+	}
+	st.LastEndpoint[stateKey()] = name
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(Dir(), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := writeFile(statePath(), data); err != nil {
+		return fmt.Errorf("failed to save %s: %w", stateFile, err)
+	}
+	return nil
+}
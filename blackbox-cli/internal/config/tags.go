@@ -0,0 +1,163 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Tags label an endpoint for --select and for grouping in the dashboard,
+// e.g. {"env": "prod", "gpu": "h100"}. The config may also list them as
+// ["env=prod", "gpu=h100"]; a bare "canary" is the tag canary=true.
+type Tags map[string]string
+
+func (t *Tags) UnmarshalJSON(data []byte) error {
+	var m map[string]string
+	if err := json.Unmarshal(data, &m); err == nil {
+		*t = m
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("tags must be an object or a list of key=value strings")
+	}
+	*t = make(Tags, len(list))
+	for _, s := range list {
+		k, v, ok := strings.Cut(s, "=")
+		if !ok {
+			v = "true"
+		}
+		k = strings.TrimSpace(k)
+		if k == "" {
+			return fmt.Errorf("invalid tag %q", s)
+		}
+		(*t)[k] = strings.TrimSpace(v)
+	}
+	return nil
+}
+
+// Tag is the value of ep's tag key. "name" is the endpoint's name unless a
+// tag of that name is set.
+func (ep Endpoint) Tag(key string) (string, bool) {
+	if v, ok := ep.Tags[key]; ok {
+		return v, true
+	}
+	if key == "name" {
+		return ep.Name, true
+	}
+	return "", false
+}
+
+// TagKeys lists the tag keys used by any of eps, sorted
+func TagKeys(eps []Endpoint) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, ep := range eps {
+		for k := range ep.Tags {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Selector picks endpoints by tag; an endpoint must match every term. The
+// zero Selector matches everything.
+type Selector []selectorTerm
+
+// selectorTerm is one of key=value, key!=value, key (tag set) or !key (tag
+// not set). value may list alternatives: env=prod|staging.
+type selectorTerm struct {
+	key    string
+	values []string // nil: only whether the tag is set counts
+	negate bool
+}
+
+// ParseSelector parses --select expressions. Each may hold several terms
+// separated by commas, e.g. "env=prod,gpu=h100".
+func ParseSelector(exprs ...string) (Selector, error) {
+	var sel Selector
+	for _, expr := range exprs {
+		for _, raw := range strings.Split(expr, ",") {
+			s := strings.TrimSpace(raw)
+			if s == "" {
+				continue
+			}
+			var t selectorTerm
+			if k, v, ok := strings.Cut(s, "!="); ok {
+				t = selectorTerm{key: k, values: strings.Split(v, "|"), negate: true}
+			} else if k, v, ok := strings.Cut(s, "="); ok {
+				t = selectorTerm{key: k, values: strings.Split(v, "|")}
+			} else if k, ok := strings.CutPrefix(s, "!"); ok {
+				t = selectorTerm{key: k, negate: true}
+			} else {
+				t = selectorTerm{key: s}
+			}
+			t.key = strings.TrimSpace(t.key)
+			if t.key == "" {
+				return nil, fmt.Errorf("invalid selector %q: expected tag=value, tag!=value, tag or !tag", s)
+			}
+			for i, v := range t.values {
+				t.values[i] = strings.TrimSpace(v)
+			}
+			sel = append(sel, t)
+		}
+	}
+	return sel, nil
+}
+
+// Matches reports whether ep satisfies every term of s
+func (s Selector) Matches(ep Endpoint) bool {
+	for _, t := range s {
+		v, ok := ep.Tag(t.key)
+		match := ok
+		if ok && t.values != nil {
+			match = false
+			for _, want := range t.values {
+				if v == want {
+					match = true
+					break
+				}
+			}
+		}
+		if match == t.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the endpoints of eps that s matches, in order
+func (s Selector) Filter(eps []Endpoint) []Endpoint {
+	if len(s) == 0 {
+		return eps
+	}
+	out := make([]Endpoint, 0, len(eps))
+	for _, ep := range eps {
+		if s.Matches(ep) {
+			out = append(out, ep)
+		}
+	}
+	return out
+}
+
+func (s Selector) String() string {
+	terms := make([]string, len(s))
+	for i, t := range s {
+		switch {
+		case t.values == nil && t.negate:
+			terms[i] = "!" + t.key
+		case t.values == nil:
+			terms[i] = t.key
+		case t.negate:
+			terms[i] = t.key + "!=" + strings.Join(t.values, "|")
+		default:
+			terms[i] = t.key + "=" + strings.Join(t.values, "|")
+		}
+	}
+	return strings.Join(terms, ",")
+}
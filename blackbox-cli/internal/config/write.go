@@ -0,0 +1,87 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+// lockTimeout is how long a change waits for another blackbox to finish its own
+const lockTimeout = 5 * time.Second
+
+// errLocked means another process holds the config lock
+var errLocked = errors.New("config is locked")
+
+// lock takes the advisory lock on the config file, a .lock file next to it,
+// waiting up to lockTimeout, and returns the func that releases it. Where file
+// locks aren't supported changes go unlocked, and only the writes themselves
+// are atomic.
+func lock() (func(), error) {
+	path := Path()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create config directory: %w", err)
+	}
+	f, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open config lock: %w", err)
+	}
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		err := tryLock(f)
+		switch {
+		case err == nil:
+			return func() {
+				unlock(f)
+				f.Close()
+			}, nil
+		case !errors.Is(err, errLocked):
+			utils.Debug("config lock unavailable, changing %s unlocked: %v", filepath.Base(path), err)
+			f.Close()
+			return func() {}, nil
+		case time.Now().After(deadline):
+			f.Close()
+			return nil, fmt.Errorf("%s is being changed by another blackbox; try again", filepath.Base(path))
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// writeFile replaces the file at path with data atomically: it is written to a
+// temp file in the same directory, synced and renamed over path, so a crash or
+// a concurrent reader never sees half a file. A symlinked file keeps its link,
+// and an existing file keeps its permissions.
+func writeFile(path string, data []byte) error {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Chmod(mode)
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+	}
+	return err
+}
@@ -0,0 +1,163 @@
+package export
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/usage"
+)
+
+// Section is one part of an HTML report: a sheet's rows as tables, split at
+// nil rows, followed by usage calendars
+type Section struct {
+	Sheet     Sheet
+	Calendars []usage.Calendar
+}
+
+// calendarColors shade days by level like GitHub's contribution graph; index 0 is no data
+var calendarColors = [usage.Levels + 1]string{"#ebedf0", "#9be9a8", "#40c463", "#30a14e", "#216e39"}
+
+const (
+	calendarCell  = 11
+	calendarGap   = 2
+	calendarLeft  = 30 // Room for weekday labels
+	calendarTop   = 15 // Room for month labels
+	calendarRight = 90 // Room for weekday averages
+)
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"tables":   tables,
+	"cell":     formatCell,
+	"calendar": calendarSVG,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292f; }
+h1 { font-size: 1.5em; } h2 { font-size: 1.2em; margin-top: 2em; border-bottom: 1px solid #d0d7de; }
+table { border-collapse: collapse; margin: 1em 0; font-size: 0.9em; }
+td { border: 1px solid #d0d7de; padding: 3px 8px; } tr:first-child td { font-weight: 600; }
+td.num { text-align: right; font-variant-numeric: tabular-nums; }
+figure { margin: 1em 0; } figcaption { font-size: 0.9em; color: #57606a; margin-bottom: 4px; }
+svg text { font-size: 9px; fill: #57606a; }
+.muted { color: #57606a; font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="muted">Generated {{.Generated}}</p>
+{{range .Sections}}
+<h2>{{.Sheet.Name}}</h2>
+{{range tables .Sheet.Rows}}<table>
+{{range .}}<tr>{{range .}}{{cell .}}{{end}}</tr>
+{{end}}</table>
+{{end}}{{range .Calendars}}<figure>
+<figcaption>{{.Metric}}</figcaption>
+{{calendar .}}
+</figure>
+{{else}}<p class="muted">No daily usage recorded for this endpoint; the dashboard records it while it runs.</p>
+{{end}}{{end}}
+</body>
+</html>
+`))
+
+// WriteHTML writes a self-contained HTML report, one heading per section
+func WriteHTML(w io.Writer, title string, sections []Section) error {
+	return reportTemplate.Execute(w, struct {
+		Title     string
+		Generated string
+		Sections  []Section
+	}{title, time.Now().Format(time.RFC1123), sections})
+}
+
+// tables splits rows at nil rows, dropping empty tables
+func tables(rows [][]interface{}) [][][]interface{} {
+	var out [][][]interface{}
+	var cur [][]interface{}
+	for _, row := range rows {
+		if row == nil {
+			if len(cur) > 0 {
+				out = append(out, cur)
+			}
+			cur = nil
+			continue
+		}
+		cur = append(cur, row)
+	}
+	if len(cur) > 0 {
+		out = append(out, cur)
+	}
+	return out
+}
+
+func formatCell(v interface{}) template.HTML {
+	var text string
+	num := true
+	switch v := v.(type) {
+	case nil:
+		text = ""
+	case float64:
+		if v == math.Trunc(v) {
+			text = fmt.Sprintf("%.0f", v)
+		} else {
+			text = fmt.Sprintf("%.2f", v)
+		}
+	case int, int64:
+		text = fmt.Sprint(v)
+	case time.Time:
+		text, num = v.Format("2006-01-02 15:04:05"), false
+	default:
+		text, num = fmt.Sprint(v), false
+	}
+	if num {
+		return template.HTML(`<td class="num">` + html.EscapeString(text) + "</td>")
+	}
+	return template.HTML("<td>" + html.EscapeString(text) + "</td>")
+}
+
+// calendarSVG draws cal as an inline SVG, each day titled with its date and value
+func calendarSVG(cal usage.Calendar) template.HTML {
+	step := calendarCell + calendarGap
+	width := calendarLeft + step*len(cal.Weeks) + calendarRight
+	height := calendarTop + step*7
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg width="%d" height="%d" role="img">`, width, height)
+	for w := range cal.Weeks {
+		first := cal.Weeks[w][0].Date
+		if w == 0 || first.Month() != cal.Weeks[w-1][0].Date.Month() {
+			fmt.Fprintf(&b, `<text x="%d" y="10">%s</text>`, calendarLeft+step*w, first.Format("Jan"))
+		}
+	}
+	for d, name := range usage.WeekdayNames {
+		y := calendarTop + step*d
+		if d%2 == 0 {
+			fmt.Fprintf(&b, `<text x="0" y="%d">%s</text>`, y+9, name)
+		}
+		if cal.WeekdayDays[d] > 0 {
+			fmt.Fprintf(&b, `<text x="%d" y="%d">avg %s</text>`, calendarLeft+step*len(cal.Weeks)+4, y+9, html.EscapeString(cal.Metric.Format(cal.WeekdayAvg[d])))
+		}
+	}
+	for w, week := range cal.Weeks {
+		for d, c := range week {
+			if c.After {
+				continue
+			}
+			label := c.Date.Format("Mon 2006-01-02") + ": no data"
+			if c.Has {
+				label = c.Date.Format("Mon 2006-01-02") + ": " + cal.Metric.Format(c.Value)
+			}
+			fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"><title>%s</title></rect>`,
+				calendarLeft+step*w, calendarTop+step*d, calendarCell, calendarCell, calendarColors[c.Level], html.EscapeString(label))
+		}
+	}
+	b.WriteString("</svg>")
+	return template.HTML(b.String())
+}
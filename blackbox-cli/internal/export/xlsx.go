@@ -0,0 +1,202 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sheet is a single worksheet: a name and rows of cells.
+// Cells may be strings, ints, floats, bools, time.Time or nil; nil, NaN and
+// infinite cells are left empty.
+type Sheet struct {
+	Name string
+	Rows [][]interface{}
+}
+
+// WriteXLSX writes a minimal Office Open XML workbook with one worksheet per sheet.
+func WriteXLSX(w io.Writer, sheets []Sheet) error {
+	if len(sheets) == 0 {
+		return fmt.Errorf("workbook needs at least one sheet")
+	}
+
+	zw := zip.NewWriter(w)
+	names := uniqueSheetNames(sheets)
+
+	files := []struct {
+		name string
+		body string
+	}{
+		{"[Content_Types].xml", contentTypesXML(len(sheets))},
+		{"_rels/.rels", rootRelsXML},
+		{"xl/workbook.xml", workbookXML(names)},
+		{"xl/_rels/workbook.xml.rels", workbookRelsXML(len(sheets))},
+		{"xl/styles.xml", stylesXML},
+	}
+	for i, s := range sheets {
+		files = append(files, struct {
+			name string
+			body string
+		}{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), sheetXML(s.Rows)})
+	}
+
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", f.name, err)
+		}
+		if _, err := io.WriteString(fw, f.body); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+	}
+
+	return zw.Close()
+}
+
+// uniqueSheetNames applies Excel's naming rules (max 31 chars, no []:*?/\) and de-duplicates.
+func uniqueSheetNames(sheets []Sheet) []string {
+	seen := make(map[string]bool)
+	names := make([]string, len(sheets))
+	for i, s := range sheets {
+		name := strings.Map(func(r rune) rune {
+			if strings.ContainsRune(`[]:*?/\`, r) {
+				return '_'
+			}
+			return r
+		}, s.Name)
+		if name == "" {
+			name = fmt.Sprintf("Sheet%d", i+1)
+		}
+		name = truncateRunes(name, 31)
+		base := name
+		for n := 2; seen[strings.ToLower(name)]; n++ {
+			suffix := fmt.Sprintf(" (%d)", n)
+			name = truncateRunes(base, 31-len(suffix)) + suffix
+		}
+		seen[strings.ToLower(name)] = true
+		names[i] = name
+	}
+	return names
+}
+
+// truncateRunes cuts s to at most n characters, never inside a multi-byte one
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
+
+func columnName(idx int) string {
+	name := ""
+	for idx >= 0 {
+		name = string(rune('A'+idx%26)) + name
+		idx = idx/26 - 1
+	}
+	return name
+}
+
+func escapeXML(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func sheetXML(rows [][]interface{}) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for r, row := range rows {
+		fmt.Fprintf(&b, `<row r="%d">`, r+1)
+		for c, cell := range row {
+			ref := columnName(c) + strconv.Itoa(r+1)
+			switch v := cell.(type) {
+			case nil:
+				continue
+			case string:
+				fmt.Fprintf(&b, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, escapeXML(v))
+			case bool:
+				val := 0
+				if v {
+					val = 1
+				}
+				fmt.Fprintf(&b, `<c r="%s" t="b"><v>%d</v></c>`, ref, val)
+			case int:
+				fmt.Fprintf(&b, `<c r="%s"><v>%d</v></c>`, ref, v)
+			case int64:
+				fmt.Fprintf(&b, `<c r="%s"><v>%d</v></c>`, ref, v)
+			case float64:
+				// Cells can't hold NaN or infinities, so those stay empty
+				if math.IsNaN(v) || math.IsInf(v, 0) {
+					continue
+				}
+				fmt.Fprintf(&b, `<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(v, 'f', -1, 64))
+			case time.Time:
+				// Stored as text so spreadsheets don't need a date style to read it
+				fmt.Fprintf(&b, `<c r="%s" t="inlineStr"><is><t>%s</t></is></c>`, ref, v.Format(time.RFC3339))
+			default:
+				fmt.Fprintf(&b, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, escapeXML(fmt.Sprint(v)))
+			}
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String()
+}
+
+func contentTypesXML(sheetCount int) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := 1; i <= sheetCount; i++ {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i)
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+func workbookXML(names []string) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, name := range names {
+		fmt.Fprintf(&b, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escapeXML(name), i+1, i+1)
+	}
+	b.WriteString(`</sheets></workbook>`)
+	return b.String()
+}
+
+func workbookRelsXML(sheetCount int) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := 1; i <= sheetCount; i++ {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i, i)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, sheetCount+1)
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+const rootRelsXML = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const stylesXML = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<fonts count="1"><font><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/></cellXfs>` +
+	`</styleSheet>`
@@ -0,0 +1,66 @@
+// Package features gates experimental subsystems so they can ship dark and be
+// switched on per user, from the config's "features" map or --enable-experimental.
+package features
+
+import (
+	"sort"
+	"sync"
+)
+
+const (
+	WebUI = "web-ui" // blackbox serve-ui
+	GRPC  = "grpc"   // gRPC stream transport
+)
+
+// Feature describes one gated subsystem
+type Feature struct {
+	Name        string
+	Description string
+}
+
+// Experimental lists every gated feature; all are off by default
+var Experimental = []Feature{
+	{WebUI, "read-only web dashboard (blackbox serve-ui)"},
+	{GRPC, "gRPC stream transport (transport: grpc)"},
+}
+
+var (
+	mu      sync.RWMutex
+	enabled = map[string]bool{}
+)
+
+// Init sets the enabled set: every experimental feature when experimental is
+// true, then explicit config entries on top, so "name": false in config still
+// opts out under --enable-experimental. Unknown names are ignored.
+func Init(config map[string]bool, experimental bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	enabled = map[string]bool{}
+	for _, f := range Experimental {
+		on := experimental
+		if v, ok := config[f.Name]; ok {
+			on = v
+		}
+		if on {
+			enabled[f.Name] = true
+		}
+	}
+}
+
+func Enabled(name string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return enabled[name]
+}
+
+// EnabledNames returns the enabled features, sorted
+func EnabledNames() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(enabled))
+	for name := range enabled {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
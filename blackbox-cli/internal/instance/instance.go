@@ -0,0 +1,240 @@
+// Package instance coordinates dashboards running at the same time for the
+// same user. Whoever holds the leader lock polls the fleet in the background
+// and publishes each result to a shared cache; the others read the cache
+// instead of polling, so N terminals cost the servers one poller. When the
+// leader exits its lock is released and the next instance to look takes over.
+package instance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/client"
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+const (
+	lockFile  = "leader.lock"
+	cacheFile = "fleet.json"
+)
+
+// Entry is the leader's last poll of one endpoint
+type Entry struct {
+	Snapshot   *model.Snapshot `json:"snapshot,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	ErrorClass string          `json:"error_class,omitempty"`
+	Updated    time.Time       `json:"updated"`
+}
+
+// Err rebuilds the poll error; errors.Is still matches the client's error classes
+func (e Entry) Err() error {
+	if e.Error == "" {
+		return nil
+	}
+	return &cachedError{msg: e.Error, class: errorClasses[e.ErrorClass]}
+}
+
+type cache struct {
+	LeaderPID int              `json:"leader_pid"`
+	Entries   map[string]Entry `json:"entries"`
+}
+
+// Coordinator is one instance's view of the shared lock and cache. A nil
+// *Coordinator is valid and always acts as leader without sharing anything.
+type Coordinator struct {
+	dir string
+
+	mu      sync.Mutex
+	lock    *os.File
+	leader  bool
+	entries map[string]Entry // leader: everything published so far
+
+	readMod   time.Time // follower: mtime of the cache last read
+	readCache cache
+}
+
+// Open prepares coordination in dir (created if needed) and tries to become
+// leader right away
+func Open(dir string) (*Coordinator, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	c := &Coordinator{dir: dir, entries: make(map[string]Entry)}
+	c.TryLead()
+	return c, nil
+}
+
+// DefaultDir is the per-user cache directory shared by all instances
+func DefaultDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "blackbox")
+}
+
+// Key identifies an endpoint across instances whose configs may differ
+func Key(name, baseURL, endpoint string) string {
+	return name + " " + baseURL + endpoint
+}
+
+// TryLead takes the leader lock if it is free and reports whether this
+// instance now leads
+func (c *Coordinator) TryLead() bool {
+	if c == nil {
+		return true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.leader {
+		return true
+	}
+	f, err := os.OpenFile(filepath.Join(c.dir, lockFile), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		utils.Debug("instance: open lock: %v", err)
+		return false
+	}
+	if err := tryLock(f); err != nil {
+		f.Close()
+		return false
+	}
+	f.Truncate(0)
+	f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	c.lock, c.leader = f, true
+	utils.Debug("instance: leading fleet polling (pid %d)", os.Getpid())
+	return true
+}
+
+// Leader reports whether this instance currently polls for everyone
+func (c *Coordinator) Leader() bool {
+	if c == nil {
+		return true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.leader
+}
+
+// LeaderPID reads the pid of the current leader from the lock file, 0 if unknown
+func (c *Coordinator) LeaderPID() int {
+	if c == nil {
+		return os.Getpid()
+	}
+	data, err := os.ReadFile(filepath.Join(c.dir, lockFile))
+	if err != nil {
+		return 0
+	}
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	return pid
+}
+
+// Publish records a poll result; only the leader writes
+func (c *Coordinator) Publish(key string, s *model.Snapshot, pollErr error) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.leader {
+		return
+	}
+	e := Entry{Snapshot: s, Updated: time.Now()}
+	if pollErr != nil {
+		e.Snapshot = nil
+		e.Error = pollErr.Error()
+		e.ErrorClass = errorClass(pollErr)
+	}
+	c.entries[key] = e
+
+	data, err := json.Marshal(cache{LeaderPID: os.Getpid(), Entries: c.entries})
+	if err == nil {
+		tmp := filepath.Join(c.dir, cacheFile+".tmp")
+		if err = os.WriteFile(tmp, data, 0600); err == nil {
+			err = os.Rename(tmp, filepath.Join(c.dir, cacheFile))
+		}
+	}
+	if err != nil {
+		utils.Debug("instance: failed to publish fleet cache: %v", err)
+	}
+}
+
+// Lookup returns the leader's entry for key if it is newer than maxAge. A
+// missing or stale entry means the caller should poll for itself.
+func (c *Coordinator) Lookup(key string, maxAge time.Duration) (Entry, bool) {
+	if c == nil {
+		return Entry{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	path := filepath.Join(c.dir, cacheFile)
+	info, err := os.Stat(path)
+	if err != nil {
+		return Entry{}, false
+	}
+	if !info.ModTime().Equal(c.readMod) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Entry{}, false
+		}
+		var fresh cache
+		if err := json.Unmarshal(data, &fresh); err != nil {
+			utils.Debug("instance: unreadable fleet cache: %v", err)
+			return Entry{}, false
+		}
+		c.readCache, c.readMod = fresh, info.ModTime()
+	}
+	e, ok := c.readCache.Entries[key]
+	if !ok || time.Since(e.Updated) > maxAge {
+		return Entry{}, false
+	}
+	return e, true
+}
+
+// Close gives up leadership so another instance can take over
+func (c *Coordinator) Close() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.lock != nil {
+		unlock(c.lock)
+		c.lock.Close()
+		c.lock, c.leader = nil, false
+	}
+}
+
+// errorClasses lets followers match the leader's errors with errors.Is
+var errorClasses = map[string]error{
+	"timeout":      client.ErrTimeout,
+	"unauthorized": client.ErrUnauthorized,
+	"not_found":    client.ErrNotFound,
+	"bad_schema":   client.ErrBadSchema,
+	"unsupported":  client.ErrUnsupported,
+}
+
+func errorClass(err error) string {
+	for name, target := range errorClasses {
+		if errors.Is(err, target) {
+			return name
+		}
+	}
+	return ""
+}
+
+// cachedError is a poll error relayed from the leader
+type cachedError struct {
+	msg   string
+	class error
+}
+
+func (e *cachedError) Error() string { return e.msg }
+func (e *cachedError) Unwrap() error { return e.class }
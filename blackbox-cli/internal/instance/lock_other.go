@@ -0,0 +1,16 @@
+//go:build !unix
+
+package instance
+
+import (
+	"errors"
+	"os"
+)
+
+// Without flock every instance would lead, so coordination is off: each
+// dashboard polls for itself as before.
+func tryLock(f *os.File) error {
+	return errors.ErrUnsupported
+}
+
+func unlock(f *os.File) {}
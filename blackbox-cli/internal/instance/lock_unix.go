@@ -0,0 +1,18 @@
+//go:build unix
+
+package instance
+
+import (
+	"os"
+	"syscall"
+)
+
+// tryLock takes an exclusive advisory lock without blocking. The kernel drops
+// it when the process exits, so a crashed leader never wedges the others.
+func tryLock(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+}
+
+func unlock(f *os.File) {
+	syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,144 @@
+package keyring
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+const agePrefix = "age:"
+
+// AgeIdentityEnv names the age identity file, overriding the config's age_identity
+const AgeIdentityEnv = "BLACKBOX_AGE_IDENTITY"
+
+// ErrNoIdentity means an age: value was found but no identity file is set
+var ErrNoIdentity = errors.New("no age identity to decrypt with")
+
+var ageIdentity string
+
+// SetAgeIdentity sets the identity file age: values are decrypted with,
+// unless AgeIdentityEnv names another
+func SetAgeIdentity(path string) {
+	mu.Lock()
+	defer mu.Unlock()
+	ageIdentity = path
+}
+
+// AgeIdentity is the identity file age: values are decrypted with; "" when none is set
+func AgeIdentity() string {
+	if path := os.Getenv(AgeIdentityEnv); path != "" {
+		return path
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	return ageIdentity
+}
+
+// IsAge reports whether value is an "age:<base64>" value, encrypted with age
+func IsAge(value string) bool {
+	return strings.HasPrefix(strings.TrimSpace(value), agePrefix)
+}
+
+// decryptAge decrypts an age: value with the identities in AgeIdentity.
+// Plaintexts are cached per value and masked in logs like keyring secrets.
+func decryptAge(value string) (string, error) {
+	path := AgeIdentity()
+	mu.Lock()
+	if secret, ok := cache[value]; ok {
+		mu.Unlock()
+		return secret, nil
+	}
+	mu.Unlock()
+	if path == "" {
+		return "", fmt.Errorf("%w: set age_identity in the config or %s", ErrNoIdentity, AgeIdentityEnv)
+	}
+	identities, err := readIdentities(path)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(strings.TrimSpace(value), agePrefix))
+	if err != nil {
+		return "", fmt.Errorf("age value is not base64: %w", err)
+	}
+	r, err := age.Decrypt(bytes.NewReader(ciphertext), identities...)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt age value with %s: %w", path, err)
+	}
+	plain, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt age value with %s: %w", path, err)
+	}
+	secret := strings.TrimRight(string(plain), "\r\n")
+	utils.AddSecrets(secret, strings.TrimPrefix(strings.TrimPrefix(secret, "Bearer "), "Basic "))
+	mu.Lock()
+	cache[value] = secret
+	mu.Unlock()
+	return secret, nil
+}
+
+// EncryptAge encrypts secret to the age recipients ("age1...") as an age:
+// value to put in the config
+func EncryptAge(secret string, recipients []string) (string, error) {
+	if len(recipients) == 0 {
+		return "", errors.New("no age recipients to encrypt to")
+	}
+	rs := make([]age.Recipient, len(recipients))
+	for i, s := range recipients {
+		r, err := age.ParseX25519Recipient(strings.TrimSpace(s))
+		if err != nil {
+			return "", fmt.Errorf("invalid age recipient %q: %w", s, err)
+		}
+		rs[i] = r
+	}
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, rs...)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.WriteString(w, secret); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return agePrefix + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// AgeRecipients reads the public keys of the identities in the identity file,
+// so values can be encrypted to it without naming a recipient
+func AgeRecipients(path string) ([]string, error) {
+	identities, err := readIdentities(path)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, id := range identities {
+		if x, ok := id.(*age.X25519Identity); ok {
+			out = append(out, x.Recipient().String())
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no X25519 identities in %s", path)
+	}
+	return out, nil
+}
+
+func readIdentities(path string) ([]age.Identity, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read age identity: %w", err)
+	}
+	defer f.Close()
+	identities, err := age.ParseIdentities(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse age identity %s: %w", path, err)
+	}
+	return identities, nil
+}
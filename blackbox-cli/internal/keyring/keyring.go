@@ -0,0 +1,118 @@
+// Package keyring resolves "keyring:<name>" references in the config from the
+// OS credential store (macOS Keychain, Secret Service on Linux, Windows
+// Credential Manager), so tokens don't have to sit in the config file, and
+// decrypts "age:<base64>" values, so they can sit there encrypted.
+package keyring
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+	gokeyring "github.com/zalando/go-keyring"
+)
+
+// Service is the keyring service every secret is stored under
+const Service = "blackbox-cli"
+
+const refPrefix = "keyring:"
+
+// ErrNotFound means a referenced secret isn't in the keyring
+var ErrNotFound = errors.New("secret not found in the OS keyring")
+
+var (
+	mu    sync.Mutex
+	cache = map[string]string{}
+)
+
+// Ref returns the secret name value references, if it is a "keyring:<name>" reference
+func Ref(value string) (string, bool) {
+	name, ok := strings.CutPrefix(strings.TrimSpace(value), refPrefix)
+	return name, ok && name != ""
+}
+
+// IsRef reports whether value is a keyring reference or an age: value, rather than the secret itself
+func IsRef(value string) bool {
+	_, ok := Ref(value)
+	return ok || IsAge(value)
+}
+
+// Resolve returns value itself, or the secret it references or encrypts.
+// Secrets are looked up once per process and masked in logs from then on.
+func Resolve(value string) (string, error) {
+	if IsAge(value) {
+		return decryptAge(value)
+	}
+	name, ok := Ref(value)
+	if !ok {
+		return value, nil
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if secret, ok := cache[name]; ok {
+		return secret, nil
+	}
+	secret, err := gokeyring.Get(Service, name)
+	if errors.Is(err, gokeyring.ErrNotFound) {
+		return "", fmt.Errorf("%w: %q (store it with 'blackbox keyring set %s')", ErrNotFound, name, name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("keyring lookup of %q failed: %w", name, err)
+	}
+	utils.AddSecrets(secret, strings.TrimPrefix(strings.TrimPrefix(secret, "Bearer "), "Basic "))
+	cache[name] = secret
+	return secret, nil
+}
+
+// ResolveMap resolves every value of m, returning m itself when none is a reference
+func ResolveMap(m map[string]string) (map[string]string, error) {
+	var out map[string]string
+	for k, v := range m {
+		if !IsRef(v) {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]string, len(m))
+			for k, v := range m {
+				out[k] = v
+			}
+		}
+		secret, err := Resolve(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+		out[k] = secret
+	}
+	if out == nil {
+		return m, nil
+	}
+	return out, nil
+}
+
+// Set stores secret under name, replacing any previous value
+func Set(name, secret string) error {
+	if err := gokeyring.Set(Service, name, secret); err != nil {
+		return fmt.Errorf("failed to store %q in the keyring: %w", name, err)
+	}
+	mu.Lock()
+	delete(cache, name)
+	mu.Unlock()
+	return nil
+}
+
+// Delete removes name from the keyring
+func Delete(name string) error {
+	err := gokeyring.Delete(Service, name)
+	if errors.Is(err, gokeyring.ErrNotFound) {
+		return fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete %q from the keyring: %w", name, err)
+	}
+	mu.Lock()
+	delete(cache, name)
+	mu.Unlock()
+	return nil
+}
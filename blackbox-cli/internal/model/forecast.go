@@ -0,0 +1,83 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// VRAMSample is one reading of allocated VRAM, as fitted by ForecastVRAM
+type VRAMSample struct {
+	At                 time.Time
+	AllocatedVRAMBytes int64
+}
+
+// Forecast is where allocated VRAM is heading, from a line fitted through
+// recent samples
+type Forecast struct {
+	GrowthBytesPerSecond float64 `json:"growth_bytes_per_second"`   // Slope of the fitted line; negative while VRAM is freed
+	SecondsToFull        float64 `json:"seconds_to_full,omitempty"` // At the current growth; left out when not growing or already full
+	WindowSeconds        float64 `json:"window_seconds"`            // From the oldest sample fitted to the newest
+	Samples              int     `json:"samples"`
+}
+
+// ForecastVRAM fits a least-squares line through samples, oldest first, and
+// projects the newest reading along it to totalBytes. It's false with fewer
+// than two samples, when they're all from the same instant or without a total.
+func ForecastVRAM(samples []VRAMSample, totalBytes int64) (Forecast, bool) {
+	if len(samples) < 2 || totalBytes <= 0 {
+		return Forecast{}, false
+	}
+	first, newest := samples[0], samples[len(samples)-1]
+	n := float64(len(samples))
+	var sumT, sumV float64
+	for _, s := range samples {
+		sumT += s.At.Sub(first.At).Seconds()
+		sumV += float64(s.AllocatedVRAMBytes)
+	}
+	meanT, meanV := sumT/n, sumV/n
+	var cov, variance float64
+	for _, s := range samples {
+		dt := s.At.Sub(first.At).Seconds() - meanT
+		cov += dt * (float64(s.AllocatedVRAMBytes) - meanV)
+		variance += dt * dt
+	}
+	if variance == 0 {
+		return Forecast{}, false
+	}
+
+	f := Forecast{
+		GrowthBytesPerSecond: cov / variance,
+		WindowSeconds:        newest.At.Sub(first.At).Seconds(),
+		Samples:              len(samples),
+	}
+	if remaining := totalBytes - newest.AllocatedVRAMBytes; remaining > 0 && f.GrowthBytesPerSecond > 0 {
+		f.SecondsToFull = float64(remaining) / f.GrowthBytesPerSecond
+	}
+	return f, true
+}
+
+// TimeToFull is SecondsToFull as a duration; 0 when VRAM isn't filling up
+func (f Forecast) TimeToFull() time.Duration {
+	return time.Duration(f.SecondsToFull * float64(time.Second))
+}
+
+// Summary says when VRAM runs out, e.g. "at current growth, VRAM full in ~42m"
+func (f Forecast) Summary() string {
+	if f.SecondsToFull <= 0 {
+		return "VRAM not growing"
+	}
+	return "at current growth, VRAM full in ~" + FormatETA(f.TimeToFull())
+}
+
+// FormatETA rounds d for a forecast: "45s", "42m", "3h10m", "2d4h"
+func FormatETA(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+}
@@ -7,13 +7,103 @@ type Snapshot struct {
 	UsedKVCacheBytes    int64        `json:"used_kv_cache_bytes"`   // Actual used KV cache (num_blocks * block_size * kv_cache_usage_perc)
 	PrefixCacheHitRate  float64      `json:"prefix_cache_hit_rate"` // Prefix cache hit rate (0.0-100.0)
 	Models              []ModelInfo  `json:"models"`                 // Per-model breakdown
+	NumRequestsRunning  float64      `json:"num_requests_running,omitempty"` // Requests being served, summed over models
+	NumRequestsWaiting  float64      `json:"num_requests_waiting,omitempty"` // Requests queued, summed over models
+	GPUs                []GPUStats   `json:"gpus,omitempty"`         // Per-device breakdown; empty when the source only reports totals
+	SchemaVersion       int          `json:"schema_version,omitempty"` // See SchemaVersion; 1 once an older payload is upgraded
+}
+
+// GPUStats is one device of a multi-GPU host
+type GPUStats struct {
+	Index              int     `json:"index"`
+	Name               string  `json:"name"`
+	TotalVRAMBytes     int64   `json:"total_vram_bytes"`
+	AllocatedVRAMBytes int64   `json:"allocated_vram_bytes"`
+	UtilizationPercent float64 `json:"utilization_percent,omitempty"` // SM (compute) utilization (0.0-100.0), when reported
+	TemperatureC       float64 `json:"temperature_c,omitempty"`       // Core temperature, when reported
+	PowerWatts         float64 `json:"power_watts,omitempty"`         // Power draw, when reported
+	PowerLimitWatts    float64 `json:"power_limit_watts,omitempty"`   // Enforced power limit, when reported
+}
+
+// GPUTelemetry sums up the thermals, power and utilization of a snapshot's GPUs
+type GPUTelemetry struct {
+	TemperatureC       float64 // Of the hottest GPU
+	PowerWatts         float64 // Summed over GPUs
+	PowerLimitWatts    float64 // Summed over GPUs
+	UtilizationPercent float64 // Averaged over GPUs
+}
+
+// Telemetry sums up s.GPUs; all zero when no GPU reports any of it
+func (s *Snapshot) Telemetry() GPUTelemetry {
+	var t GPUTelemetry
+	if len(s.GPUs) == 0 {
+		return t
+	}
+	for _, g := range s.GPUs {
+		t.TemperatureC = max(t.TemperatureC, g.TemperatureC)
+		t.PowerWatts += g.PowerWatts
+		t.PowerLimitWatts += g.PowerLimitWatts
+		t.UtilizationPercent += g.UtilizationPercent
+	}
+	t.UtilizationPercent /= float64(len(s.GPUs))
+	return t
+}
+
+// Reported says whether any GPU reported thermals, power or utilization
+func (t GPUTelemetry) Reported() bool {
+	return t.TemperatureC > 0 || t.PowerWatts > 0 || t.UtilizationPercent > 0
 }
 
 type ModelInfo struct {
-	ModelID            string `json:"model_id"`
-	Port               int    `json:"port"`
-	AllocatedVRAMBytes int64  `json:"allocated_vram_bytes"`
-	UsedKVCacheBytes   int64  `json:"used_kv_cache_bytes"`
+	ModelID                   string  `json:"model_id"`
+	Port                      int     `json:"port"`
+	AllocatedVRAMBytes        int64   `json:"allocated_vram_bytes"`
+	UsedKVCacheBytes          int64   `json:"used_kv_cache_bytes"`
+	TTFTSeconds               float64 `json:"ttft_seconds,omitempty"`                 // Mean time to first token, when reported
+	InterTokenLatencySeconds  float64 `json:"inter_token_latency_seconds,omitempty"`  // Mean time between output tokens, when reported
+	GenerationTokensPerSecond float64 `json:"generation_tokens_per_second,omitempty"` // Tokens generated per second over all requests, when reported
+	NumRequestsRunning        float64 `json:"num_requests_running,omitempty"`         // Requests being served, when reported
+	NumRequestsWaiting        float64 `json:"num_requests_waiting,omitempty"`         // Requests queued, when reported
+}
+
+// TokensPerSecond is the decode speed of one request, from the inter-token latency; 0 when not reported
+func (m ModelInfo) TokensPerSecond() float64 {
+	if m.InterTokenLatencySeconds <= 0 {
+		return 0
+	}
+	return 1 / m.InterTokenLatencySeconds
+}
+
+// Throughput sums the generated tokens per second over models
+func (s *Snapshot) Throughput() float64 {
+	var total float64
+	for _, m := range s.Models {
+		total += m.GenerationTokensPerSecond
+	}
+	return total
+}
+
+// Latency averages TTFT and inter-token latency over the models that report
+// them; 0 when none do
+func (s *Snapshot) Latency() (ttftSeconds, interTokenSeconds float64) {
+	var ttftModels, interTokenModels int
+	for _, m := range s.Models {
+		if m.TTFTSeconds > 0 {
+			ttftSeconds += m.TTFTSeconds
+			ttftModels++
+		}
+		if m.InterTokenLatencySeconds > 0 {
+			interTokenSeconds += m.InterTokenLatencySeconds
+			interTokenModels++
+		}
+	}
+	if ttftModels > 0 {
+		ttftSeconds /= float64(ttftModels)
+	}
+	if interTokenModels > 0 {
+		interTokenSeconds /= float64(interTokenModels)
+	}
+	return ttftSeconds, interTokenSeconds
 }
 
 // AggregatedStats represents statistical aggregation over a time window
@@ -26,6 +116,23 @@ type AggregatedStats struct {
 	Count int     `json:"count"`
 }
 
+// Stat returns the statistic called name: "min", "max", "avg", "p95" or "p99"; 0 for any other
+func (a AggregatedStats) Stat(name string) float64 {
+	switch name {
+	case "min":
+		return a.Min
+	case "max":
+		return a.Max
+	case "avg":
+		return a.Avg
+	case "p95":
+		return a.P95
+	case "p99":
+		return a.P99
+	}
+	return 0
+}
+
 // AggregatedSnapshot from blackbox-server /vram/aggregated endpoint
 type AggregatedSnapshot struct {
 	TotalVRAMBytes      int64                    `json:"total_vram_bytes"`
@@ -37,4 +144,6 @@ type AggregatedSnapshot struct {
 	NumRequestsRunning  AggregatedStats          `json:"num_requests_running"`
 	NumRequestsWaiting  AggregatedStats          `json:"num_requests_waiting"`
 	Models              []ModelInfo              `json:"models"`
+	GPUs                []GPUStats               `json:"gpus,omitempty"` // Latest reading per device
+	SchemaVersion       int                      `json:"schema_version,omitempty"`
 }
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+)
+
+// SchemaVersion is the snapshot schema this CLI reads, sent by servers as
+// schema_version. Payloads without one are version 1, from servers that
+// predate it: those name the VRAM totals total_bytes and used_bytes, and
+// leave out the KV cache, prefix cache and models.
+const SchemaVersion = 2
+
+// legacyFields maps version 1 field names to their current ones
+var legacyFields = map[string]string{
+	"total_bytes": "total_vram_bytes",
+	"used_bytes":  "allocated_vram_bytes",
+}
+
+// zeroStats is an AggregatedStats of no samples
+var zeroStats, _ = json.Marshal(AggregatedStats{})
+
+// legacyDefaults fills in, by the type a payload decodes into, the fields the
+// current schema requires that version 1 didn't send. A snapshot's figures
+// are numbers; an aggregated snapshot's are AggregatedStats objects.
+var legacyDefaults = map[reflect.Type]map[string]json.RawMessage{
+	reflect.TypeOf(Snapshot{}): {
+		"used_kv_cache_bytes":   json.RawMessage("0"),
+		"prefix_cache_hit_rate": json.RawMessage("0"),
+		"models":                json.RawMessage("[]"),
+	},
+	reflect.TypeOf(AggregatedSnapshot{}): {
+		"used_kv_cache_bytes":   zeroStats,
+		"prefix_cache_hit_rate": zeroStats,
+		"num_requests_running":  zeroStats,
+		"num_requests_waiting":  zeroStats,
+		"models":                json.RawMessage("[]"),
+	},
+}
+
+// Upgrade rewrites a payload of an older schema version into the current one
+// of v, a *Snapshot or *AggregatedSnapshot, so it validates and decodes like
+// a current payload instead of failing or rendering empty. Payloads of this
+// version or a newer one, data that isn't a JSON object, and other types of
+// v are returned as they are.
+func Upgrade(data []byte, v any) []byte {
+	t := reflect.TypeOf(v)
+	if t == nil || t.Kind() != reflect.Pointer {
+		return data
+	}
+	defaults, ok := legacyDefaults[t.Elem()]
+	if !ok {
+		return data
+	}
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(data, &fields) != nil || fields == nil {
+		return data
+	}
+	version := 1
+	if raw, ok := fields["schema_version"]; ok {
+		v, err := strconv.Atoi(string(raw))
+		if err != nil || v >= SchemaVersion {
+			return data
+		}
+		version = v
+	}
+
+	for from, to := range legacyFields {
+		raw, ok := fields[from]
+		if !ok {
+			continue
+		}
+		delete(fields, from)
+		if _, ok := fields[to]; !ok {
+			fields[to] = raw
+		}
+	}
+	for name, zero := range defaults {
+		if raw, ok := fields[name]; !ok || string(raw) == "null" {
+			fields[name] = zero
+		}
+	}
+	fields["schema_version"] = json.RawMessage(strconv.Itoa(version))
+
+	upgraded, err := json.Marshal(fields)
+	if err != nil {
+		return data
+	}
+	return upgraded
+}
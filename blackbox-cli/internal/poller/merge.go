@@ -0,0 +1,88 @@
+// Package poller turns the server's aggregated responses into the snapshots
+// the dashboard shows, outside any tea.Cmd so the rules can be checked on
+// their own.
+package poller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+// Strategy picks a merged snapshot's used KV cache from the models' figures
+// and the window's aggregate, which disagree while models load or unload
+type Strategy string
+
+const (
+	// SumOfModels adds up the models, so the total matches the per-model
+	// rows; it falls back to the aggregate average when they sum to 0
+	SumOfModels Strategy = "sum"
+	// AggregateAvg uses the average over the window
+	AggregateAvg Strategy = "avg"
+	// Max uses the larger of the models' sum and the average
+	Max Strategy = "max"
+)
+
+// DefaultStrategy is used when none is configured
+const DefaultStrategy = SumOfModels
+
+// Strategies lists every Strategy, for flags and messages
+var Strategies = []Strategy{SumOfModels, AggregateAvg, Max}
+
+// ParseStrategy reads a configured strategy; "" is DefaultStrategy
+func ParseStrategy(s string) (Strategy, error) {
+	if s == "" {
+		return DefaultStrategy, nil
+	}
+	for _, st := range Strategies {
+		if strings.EqualFold(s, string(st)) {
+			return st, nil
+		}
+	}
+	names := make([]string, len(Strategies))
+	for i, st := range Strategies {
+		names[i] = string(st)
+	}
+	return DefaultStrategy, fmt.Errorf("unknown KV cache merge %q (expected %s)", s, strings.Join(names, ", "))
+}
+
+// MergeAggregated converts agg to a regular snapshot using the window's
+// averages, with used KV cache picked by strategy. A nil agg gives the zero snapshot.
+func MergeAggregated(agg *model.AggregatedSnapshot, strategy Strategy) model.Snapshot {
+	if agg == nil {
+		return model.Snapshot{}
+	}
+	var sum int64
+	for _, m := range agg.Models {
+		utils.Debug("Model %s: UsedKVCacheBytes=%d, AllocatedVRAMBytes=%d", m.ModelID, m.UsedKVCacheBytes, m.AllocatedVRAMBytes)
+		sum += m.UsedKVCacheBytes
+	}
+	avg := int64(agg.UsedKVCacheBytes.Avg)
+	utils.Debug("Total from models: %d, Aggregated avg: %d, Sample count: %d, strategy: %s", sum, avg, agg.UsedKVCacheBytes.Count, strategy)
+
+	var usedKV int64
+	switch strategy {
+	case AggregateAvg:
+		usedKV = avg
+	case Max:
+		usedKV = max(sum, avg)
+	default:
+		usedKV = sum
+		if usedKV == 0 {
+			usedKV = avg
+		}
+	}
+	return model.Snapshot{
+		TotalVRAMBytes:     agg.TotalVRAMBytes,
+		AllocatedVRAMBytes: int64(agg.AllocatedVRAMBytes.Avg),
+		UsedKVCacheBytes:   usedKV,
+		PrefixCacheHitRate: agg.PrefixCacheHitRate.Avg,
+		Models:             agg.Models,
+		// Like the dashboard's load: a queue that built up at any point in the window shows
+		NumRequestsRunning: agg.NumRequestsRunning.Avg,
+		NumRequestsWaiting: agg.NumRequestsWaiting.Max,
+		GPUs:               agg.GPUs,
+	}
+}
@@ -0,0 +1,86 @@
+package poller
+
+import (
+	"testing"
+
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+)
+
+func TestMergeAggregated(t *testing.T) {
+	withModels := func(avg float64, kv ...int64) *model.AggregatedSnapshot {
+		agg := &model.AggregatedSnapshot{
+			TotalVRAMBytes:     80 << 30,
+			AllocatedVRAMBytes: model.AggregatedStats{Avg: 40 << 30},
+			UsedKVCacheBytes:   model.AggregatedStats{Avg: avg, Count: 5},
+			PrefixCacheHitRate: model.AggregatedStats{Avg: 62.5},
+			NumRequestsRunning: model.AggregatedStats{Avg: 3, Max: 4},
+			NumRequestsWaiting: model.AggregatedStats{Avg: 1, Max: 6},
+		}
+		for _, b := range kv {
+			agg.Models = append(agg.Models, model.ModelInfo{ModelID: "m", UsedKVCacheBytes: b})
+		}
+		return agg
+	}
+
+	tests := []struct {
+		name     string
+		agg      *model.AggregatedSnapshot
+		strategy Strategy
+		wantKV   int64
+	}{
+		{"sum of models", withModels(900, 100, 200), SumOfModels, 300},
+		{"sum falls back to the average when the models sum to 0", withModels(900, 0, 0), SumOfModels, 900},
+		{"sum falls back to the average without models", withModels(900), SumOfModels, 900},
+		{"unknown strategy sums like the default", withModels(900, 100, 200), Strategy("bogus"), 300},
+		{"avg ignores the models", withModels(900, 100, 200), AggregateAvg, 900},
+		{"avg without samples is 0", withModels(0, 100), AggregateAvg, 0},
+		{"max takes the models' sum when larger", withModels(250, 100, 200), Max, 300},
+		{"max takes the average when larger", withModels(900, 100, 200), Max, 900},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := MergeAggregated(tt.agg, tt.strategy)
+			if s.UsedKVCacheBytes != tt.wantKV {
+				t.Errorf("UsedKVCacheBytes = %d, want %d", s.UsedKVCacheBytes, tt.wantKV)
+			}
+			if s.TotalVRAMBytes != tt.agg.TotalVRAMBytes || s.AllocatedVRAMBytes != 40<<30 {
+				t.Errorf("VRAM = %d of %d, want %d of %d", s.AllocatedVRAMBytes, s.TotalVRAMBytes, int64(40<<30), tt.agg.TotalVRAMBytes)
+			}
+			if s.PrefixCacheHitRate != 62.5 || s.NumRequestsRunning != 3 || s.NumRequestsWaiting != 6 {
+				t.Errorf("hit rate %v, running %v, waiting %v; want 62.5, the average 3 and the peak 6",
+					s.PrefixCacheHitRate, s.NumRequestsRunning, s.NumRequestsWaiting)
+			}
+			if len(s.Models) != len(tt.agg.Models) {
+				t.Errorf("%d models, want %d", len(s.Models), len(tt.agg.Models))
+			}
+		})
+	}
+}
+
+func TestMergeAggregatedNil(t *testing.T) {
+	for _, st := range Strategies {
+		if s := MergeAggregated(nil, st); s.UsedKVCacheBytes != 0 || s.TotalVRAMBytes != 0 || s.Models != nil {
+			t.Errorf("MergeAggregated(nil, %s) = %+v, want the zero snapshot", st, s)
+		}
+	}
+}
+
+func TestParseStrategy(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Strategy
+		wantErr bool
+	}{
+		{"", DefaultStrategy, false},
+		{"sum", SumOfModels, false},
+		{"AVG", AggregateAvg, false},
+		{"max", Max, false},
+		{"median", DefaultStrategy, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseStrategy(tt.in)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseStrategy(%q) = %s, %v; want %s, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,415 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: blackbox.proto
+
+package blackboxpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ModelInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ModelId            string `protobuf:"bytes,1,opt,name=model_id,json=modelId,proto3" json:"model_id,omitempty"`
+	Port               int32  `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	AllocatedVramBytes int64  `protobuf:"varint,3,opt,name=allocated_vram_bytes,json=allocatedVramBytes,proto3" json:"allocated_vram_bytes,omitempty"`
+	UsedKvCacheBytes   int64  `protobuf:"varint,4,opt,name=used_kv_cache_bytes,json=usedKvCacheBytes,proto3" json:"used_kv_cache_bytes,omitempty"`
+}
+
+func (x *ModelInfo) Reset() {
+	*x = ModelInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_blackbox_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ModelInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModelInfo) ProtoMessage() {}
+
+func (x *ModelInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_blackbox_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModelInfo.ProtoReflect.Descriptor instead.
+func (*ModelInfo) Descriptor() ([]byte, []int) {
+	return file_blackbox_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ModelInfo) GetModelId() string {
+	if x != nil {
+		return x.ModelId
+	}
+	return ""
+}
+
+func (x *ModelInfo) GetPort() int32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *ModelInfo) GetAllocatedVramBytes() int64 {
+	if x != nil {
+		return x.AllocatedVramBytes
+	}
+	return 0
+}
+
+func (x *ModelInfo) GetUsedKvCacheBytes() int64 {
+	if x != nil {
+		return x.UsedKvCacheBytes
+	}
+	return 0
+}
+
+type Snapshot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TotalVramBytes     int64        `protobuf:"varint,1,opt,name=total_vram_bytes,json=totalVramBytes,proto3" json:"total_vram_bytes,omitempty"`
+	AllocatedVramBytes int64        `protobuf:"varint,2,opt,name=allocated_vram_bytes,json=allocatedVramBytes,proto3" json:"allocated_vram_bytes,omitempty"`
+	UsedKvCacheBytes   int64        `protobuf:"varint,3,opt,name=used_kv_cache_bytes,json=usedKvCacheBytes,proto3" json:"used_kv_cache_bytes,omitempty"`
+	PrefixCacheHitRate float64      `protobuf:"fixed64,4,opt,name=prefix_cache_hit_rate,json=prefixCacheHitRate,proto3" json:"prefix_cache_hit_rate,omitempty"` // 0.0-100.0
+	Models             []*ModelInfo `protobuf:"bytes,5,rep,name=models,proto3" json:"models,omitempty"`
+}
+
+func (x *Snapshot) Reset() {
+	*x = Snapshot{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_blackbox_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Snapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Snapshot) ProtoMessage() {}
+
+func (x *Snapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_blackbox_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Snapshot.ProtoReflect.Descriptor instead.
+func (*Snapshot) Descriptor() ([]byte, []int) {
+	return file_blackbox_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Snapshot) GetTotalVramBytes() int64 {
+	if x != nil {
+		return x.TotalVramBytes
+	}
+	return 0
+}
+
+func (x *Snapshot) GetAllocatedVramBytes() int64 {
+	if x != nil {
+		return x.AllocatedVramBytes
+	}
+	return 0
+}
+
+func (x *Snapshot) GetUsedKvCacheBytes() int64 {
+	if x != nil {
+		return x.UsedKvCacheBytes
+	}
+	return 0
+}
+
+func (x *Snapshot) GetPrefixCacheHitRate() float64 {
+	if x != nil {
+		return x.PrefixCacheHitRate
+	}
+	return 0
+}
+
+func (x *Snapshot) GetModels() []*ModelInfo {
+	if x != nil {
+		return x.Models
+	}
+	return nil
+}
+
+type SnapshotRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *SnapshotRequest) Reset() {
+	*x = SnapshotRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_blackbox_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotRequest) ProtoMessage() {}
+
+func (x *SnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_blackbox_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotRequest.ProtoReflect.Descriptor instead.
+func (*SnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_blackbox_proto_rawDescGZIP(), []int{2}
+}
+
+type StreamRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Minimum time between snapshots; 0 lets the server pick
+	IntervalMs uint32 `protobuf:"varint,1,opt,name=interval_ms,json=intervalMs,proto3" json:"interval_ms,omitempty"`
+}
+
+func (x *StreamRequest) Reset() {
+	*x = StreamRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_blackbox_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamRequest) ProtoMessage() {}
+
+func (x *StreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_blackbox_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamRequest.ProtoReflect.Descriptor instead.
+func (*StreamRequest) Descriptor() ([]byte, []int) {
+	return file_blackbox_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StreamRequest) GetIntervalMs() uint32 {
+	if x != nil {
+		return x.IntervalMs
+	}
+	return 0
+}
+
+var File_blackbox_proto protoreflect.FileDescriptor
+
+var file_blackbox_proto_rawDesc = []byte{
+	0x0a, 0x0e, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x62, 0x6f, 0x78, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x0b, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x62, 0x6f, 0x78, 0x2e, 0x76, 0x31, 0x22, 0x9b, 0x01,
+	0x0a, 0x09, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x19, 0x0a, 0x08, 0x6d,
+	0x6f, 0x64, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d,
+	0x6f, 0x64, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x30, 0x0a, 0x14, 0x61, 0x6c,
+	0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x76, 0x72, 0x61, 0x6d, 0x5f, 0x62, 0x79, 0x74,
+	0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x12, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61,
+	0x74, 0x65, 0x64, 0x56, 0x72, 0x61, 0x6d, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x2d, 0x0a, 0x13,
+	0x75, 0x73, 0x65, 0x64, 0x5f, 0x6b, 0x76, 0x5f, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x62, 0x79,
+	0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x75, 0x73, 0x65, 0x64, 0x4b,
+	0x76, 0x43, 0x61, 0x63, 0x68, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0xf8, 0x01, 0x0a, 0x08,
+	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x28, 0x0a, 0x10, 0x74, 0x6f, 0x74, 0x61,
+	0x6c, 0x5f, 0x76, 0x72, 0x61, 0x6d, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0e, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x56, 0x72, 0x61, 0x6d, 0x42, 0x79, 0x74,
+	0x65, 0x73, 0x12, 0x30, 0x0a, 0x14, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x64, 0x5f,
+	0x76, 0x72, 0x61, 0x6d, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x12, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x64, 0x56, 0x72, 0x61, 0x6d, 0x42,
+	0x79, 0x74, 0x65, 0x73, 0x12, 0x2d, 0x0a, 0x13, 0x75, 0x73, 0x65, 0x64, 0x5f, 0x6b, 0x76, 0x5f,
+	0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x10, 0x75, 0x73, 0x65, 0x64, 0x4b, 0x76, 0x43, 0x61, 0x63, 0x68, 0x65, 0x42, 0x79,
+	0x74, 0x65, 0x73, 0x12, 0x31, 0x0a, 0x15, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x5f, 0x63, 0x61,
+	0x63, 0x68, 0x65, 0x5f, 0x68, 0x69, 0x74, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x12, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x43, 0x61, 0x63, 0x68, 0x65, 0x48,
+	0x69, 0x74, 0x52, 0x61, 0x74, 0x65, 0x12, 0x2e, 0x0a, 0x06, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73,
+	0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x62, 0x6f,
+	0x78, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x06,
+	0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x22, 0x11, 0x0a, 0x0f, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x30, 0x0a, 0x0d, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e,
+	0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0a, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x4d, 0x73, 0x32, 0x92, 0x01, 0x0a, 0x04,
+	0x56, 0x52, 0x41, 0x4d, 0x12, 0x42, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x53, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x12, 0x1c, 0x2e, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x62, 0x6f, 0x78, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x15, 0x2e, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x62, 0x6f, 0x78, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x46, 0x0a, 0x0f, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x12, 0x1a, 0x2e, 0x62, 0x6c,
+	0x61, 0x63, 0x6b, 0x62, 0x6f, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x62,
+	0x6f, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x30, 0x01,
+	0x42, 0x3b, 0x5a, 0x39, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d,
+	0x61, 0x78, 0x64, 0x63, 0x6d, 0x6e, 0x2f, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x62, 0x6f, 0x78, 0x2d,
+	0x63, 0x6c, 0x69, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x3b, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x62, 0x6f, 0x78, 0x70, 0x62, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_blackbox_proto_rawDescOnce sync.Once
+	file_blackbox_proto_rawDescData = file_blackbox_proto_rawDesc
+)
+
+func file_blackbox_proto_rawDescGZIP() []byte {
+	file_blackbox_proto_rawDescOnce.Do(func() {
+		file_blackbox_proto_rawDescData = protoimpl.X.CompressGZIP(file_blackbox_proto_rawDescData)
+	})
+	return file_blackbox_proto_rawDescData
+}
+
+var file_blackbox_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_blackbox_proto_goTypes = []any{
+	(*ModelInfo)(nil),       // 0: blackbox.v1.ModelInfo
+	(*Snapshot)(nil),        // 1: blackbox.v1.Snapshot
+	(*SnapshotRequest)(nil), // 2: blackbox.v1.SnapshotRequest
+	(*StreamRequest)(nil),   // 3: blackbox.v1.StreamRequest
+}
+var file_blackbox_proto_depIdxs = []int32{
+	0, // 0: blackbox.v1.Snapshot.models:type_name -> blackbox.v1.ModelInfo
+	2, // 1: blackbox.v1.VRAM.GetSnapshot:input_type -> blackbox.v1.SnapshotRequest
+	3, // 2: blackbox.v1.VRAM.StreamSnapshots:input_type -> blackbox.v1.StreamRequest
+	1, // 3: blackbox.v1.VRAM.GetSnapshot:output_type -> blackbox.v1.Snapshot
+	1, // 4: blackbox.v1.VRAM.StreamSnapshots:output_type -> blackbox.v1.Snapshot
+	3, // [3:5] is the sub-list for method output_type
+	1, // [1:3] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_blackbox_proto_init() }
+func file_blackbox_proto_init() {
+	if File_blackbox_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_blackbox_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*ModelInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_blackbox_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*Snapshot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_blackbox_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*SnapshotRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_blackbox_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*StreamRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_blackbox_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_blackbox_proto_goTypes,
+		DependencyIndexes: file_blackbox_proto_depIdxs,
+		MessageInfos:      file_blackbox_proto_msgTypes,
+	}.Build()
+	File_blackbox_proto = out.File
+	file_blackbox_proto_rawDesc = nil
+	file_blackbox_proto_goTypes = nil
+	file_blackbox_proto_depIdxs = nil
+}
@@ -0,0 +1,169 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: blackbox.proto
+
+package blackboxpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	VRAM_GetSnapshot_FullMethodName     = "/blackbox.v1.VRAM/GetSnapshot"
+	VRAM_StreamSnapshots_FullMethodName = "/blackbox.v1.VRAM/StreamSnapshots"
+)
+
+// VRAMClient is the client API for VRAM service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// gRPC surface of blackbox-server for high-frequency monitoring. Field names
+// and units match the JSON returned by /vram.
+type VRAMClient interface {
+	GetSnapshot(ctx context.Context, in *SnapshotRequest, opts ...grpc.CallOption) (*Snapshot, error)
+	StreamSnapshots(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Snapshot], error)
+}
+
+type vRAMClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVRAMClient(cc grpc.ClientConnInterface) VRAMClient {
+	return &vRAMClient{cc}
+}
+
+func (c *vRAMClient) GetSnapshot(ctx context.Context, in *SnapshotRequest, opts ...grpc.CallOption) (*Snapshot, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Snapshot)
+	err := c.cc.Invoke(ctx, VRAM_GetSnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vRAMClient) StreamSnapshots(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Snapshot], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &VRAM_ServiceDesc.Streams[0], VRAM_StreamSnapshots_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamRequest, Snapshot]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VRAM_StreamSnapshotsClient = grpc.ServerStreamingClient[Snapshot]
+
+// VRAMServer is the server API for VRAM service.
+// All implementations must embed UnimplementedVRAMServer
+// for forward compatibility.
+//
+// gRPC surface of blackbox-server for high-frequency monitoring. Field names
+// and units match the JSON returned by /vram.
+type VRAMServer interface {
+	GetSnapshot(context.Context, *SnapshotRequest) (*Snapshot, error)
+	StreamSnapshots(*StreamRequest, grpc.ServerStreamingServer[Snapshot]) error
+	mustEmbedUnimplementedVRAMServer()
+}
+
+// UnimplementedVRAMServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedVRAMServer struct{}
+
+func (UnimplementedVRAMServer) GetSnapshot(context.Context, *SnapshotRequest) (*Snapshot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSnapshot not implemented")
+}
+func (UnimplementedVRAMServer) StreamSnapshots(*StreamRequest, grpc.ServerStreamingServer[Snapshot]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamSnapshots not implemented")
+}
+func (UnimplementedVRAMServer) mustEmbedUnimplementedVRAMServer() {}
+func (UnimplementedVRAMServer) testEmbeddedByValue()              {}
+
+// UnsafeVRAMServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to VRAMServer will
+// result in compilation errors.
+type UnsafeVRAMServer interface {
+	mustEmbedUnimplementedVRAMServer()
+}
+
+func RegisterVRAMServer(s grpc.ServiceRegistrar, srv VRAMServer) {
+	// If the following call pancis, it indicates UnimplementedVRAMServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&VRAM_ServiceDesc, srv)
+}
+
+func _VRAM_GetSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VRAMServer).GetSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VRAM_GetSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VRAMServer).GetSnapshot(ctx, req.(*SnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VRAM_StreamSnapshots_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(VRAMServer).StreamSnapshots(m, &grpc.GenericServerStream[StreamRequest, Snapshot]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VRAM_StreamSnapshotsServer = grpc.ServerStreamingServer[Snapshot]
+
+// VRAM_ServiceDesc is the grpc.ServiceDesc for VRAM service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var VRAM_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "blackbox.v1.VRAM",
+	HandlerType: (*VRAMServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetSnapshot",
+			Handler:    _VRAM_GetSnapshot_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamSnapshots",
+			Handler:       _VRAM_StreamSnapshots_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "blackbox.proto",
+}
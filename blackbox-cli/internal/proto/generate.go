@@ -0,0 +1,5 @@
+// Package blackboxpb holds the generated gRPC client for blackbox-server.
+// Regenerate after editing blackbox.proto (protoc-gen-go v1.34.2, protoc-gen-go-grpc v1.5.1).
+package blackboxpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative blackbox.proto
@@ -0,0 +1,129 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is the subset of JSON Schema needed to describe the CLI's data structures
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	// Nullable also allows null, which is how encoding/json writes a nil slice or map
+	Nullable bool `json:"-"`
+}
+
+// MarshalJSON writes a Nullable schema's type as [Type, "null"]
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	type plain Schema
+	if !s.Nullable {
+		return json.Marshal((*plain)(s))
+	}
+	return json.Marshal(struct {
+		Type []string `json:"type"`
+		*plain
+	}{[]string{s.Type, "null"}, (*plain)(s)})
+}
+
+// For builds a JSON Schema document for the type of v
+func For(title string, v interface{}) *Schema {
+	s := reflectType(reflect.TypeOf(v))
+	// The CLI never prints a whole document as null
+	s.Nullable = false
+	s.Schema = draft
+	s.ID = "https://github.com/maxdcmn/blackbox/schemas/" + title + ".json"
+	s.Title = title
+	return s
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func reflectType(t reflect.Type) *Schema {
+	t = indirect(t)
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice:
+		return &Schema{Type: "array", Items: reflectType(t.Elem()), Nullable: true}
+	case reflect.Array:
+		return &Schema{Type: "array", Items: reflectType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: reflectType(t.Elem()), Nullable: true}
+	case reflect.Struct:
+		s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, omitempty, skip := jsonName(f)
+			if skip {
+				continue
+			}
+			// Like encoding/json, an embedded struct without a name of its own
+			// contributes its fields to the outer object
+			tagged := strings.Split(f.Tag.Get("json"), ",")[0] != ""
+			if f.Anonymous && !tagged && indirect(f.Type).Kind() == reflect.Struct {
+				inner := reflectType(f.Type)
+				for k, v := range inner.Properties {
+					s.Properties[k] = v
+				}
+				s.Required = append(s.Required, inner.Required...)
+				continue
+			}
+			s.Properties[name] = reflectType(f.Type)
+			if !omitempty {
+				s.Required = append(s.Required, name)
+			}
+		}
+		return s
+	}
+	return &Schema{}
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func jsonName(f reflect.StructField) (name string, omitempty, skip bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = f.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty, false
+}
@@ -0,0 +1,143 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Problem is one way a document departs from its schema, located by a JSON
+// path such as $.models[2].port. Unknown fields are reported separately
+// because newer servers may add them harmlessly, and missing ones because a
+// response projected to some fields leaves the rest out.
+type Problem struct {
+	Path    string
+	Message string
+	Unknown bool
+	Missing bool
+}
+
+func (p Problem) String() string {
+	return p.Path + ": " + p.Message
+}
+
+// Validate checks data against s: missing required fields, type mismatches and
+// fields s doesn't describe. It returns an error only when data isn't JSON.
+// null is accepted where s is Nullable, and wherever an object is expected, as
+// encoding/json decodes it into the zero value.
+func Validate(s *Schema, data []byte) ([]Problem, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	var problems []Problem
+	validate(s, v, "$", &problems)
+	return problems, nil
+}
+
+func validate(s *Schema, v interface{}, path string, problems *[]Problem) {
+	if s == nil || s.Type == "" || v == nil && s.Nullable {
+		return
+	}
+	mismatch := func() {
+		*problems = append(*problems, Problem{Path: path, Message: fmt.Sprintf("expected %s, got %s", s.Type, describe(v))})
+	}
+	switch s.Type {
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			mismatch()
+		}
+	case "string":
+		if _, ok := v.(string); !ok {
+			mismatch()
+		}
+	case "number":
+		if _, ok := v.(json.Number); !ok {
+			mismatch()
+		}
+	case "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			mismatch()
+		} else if _, err := n.Int64(); err != nil {
+			mismatch()
+		}
+	case "array":
+		items, ok := v.([]interface{})
+		if !ok {
+			mismatch()
+			return
+		}
+		for i, item := range items {
+			validate(s.Items, item, fmt.Sprintf("%s[%d]", path, i), problems)
+		}
+	case "object":
+		if v == nil {
+			return
+		}
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			mismatch()
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				*problems = append(*problems, Problem{Path: path + "." + name, Message: "required field missing", Missing: true})
+			}
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := path + "." + k
+			if prop, ok := s.Properties[k]; ok {
+				validate(prop, obj[k], child, problems)
+			} else if s.AdditionalProperties != nil {
+				validate(s.AdditionalProperties, obj[k], child, problems)
+			} else if s.Properties != nil {
+				*problems = append(*problems, Problem{Path: child, Message: "unknown field", Unknown: true})
+			}
+		}
+	}
+}
+
+// describe names the JSON type of v for error messages, with a short excerpt
+func describe(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return fmt.Sprintf("boolean %t", v)
+	case json.Number:
+		return "number " + v.String()
+	case string:
+		if len(v) > 20 {
+			v = v[:20] + "..."
+		}
+		return fmt.Sprintf("string %q", v)
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// Summary joins the first few problems into one line
+func Summary(problems []Problem, limit int) string {
+	parts := make([]string, 0, limit)
+	for i, p := range problems {
+		if i == limit {
+			parts = append(parts, fmt.Sprintf("and %d more", len(problems)-limit))
+			break
+		}
+		parts = append(parts, p.String())
+	}
+	return strings.Join(parts, "; ")
+}
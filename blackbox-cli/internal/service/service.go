@@ -0,0 +1,157 @@
+// Package service renders systemd units and launchd plists that run a
+// long-lived blackbox command (exporter, serve-ui) in the background
+package service
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Unit formats
+const (
+	FormatSystemd = "systemd"
+	FormatLaunchd = "launchd"
+)
+
+// DefaultFormat is launchd on macOS and systemd everywhere else
+func DefaultFormat() string {
+	if runtime.GOOS == "darwin" {
+		return FormatLaunchd
+	}
+	return FormatSystemd
+}
+
+// Spec describes one service: the blackbox binary, the command line after
+// it, and who it runs as
+type Spec struct {
+	Name string // mode, e.g. "exporter"; the unit is blackbox-<Name>
+	Exec string // absolute path to the blackbox binary
+	Args []string
+	User string // systemd only; launchd agents run as the installing user
+	Home string // HOME for the service, where blackbox finds its config
+	Env  map[string]string
+}
+
+// UnitName is the file name of the unit in the given format
+func (s Spec) UnitName(format string) string {
+	if format == FormatLaunchd {
+		return "com.blackbox." + s.Name + ".plist"
+	}
+	return "blackbox-" + s.Name + ".service"
+}
+
+// InstallPath is where the unit goes: the system unit directory for
+// systemd, the user's LaunchAgents for launchd
+func (s Spec) InstallPath(format string) string {
+	if format == FormatLaunchd {
+		return filepath.Join(s.Home, "Library", "LaunchAgents", s.UnitName(format))
+	}
+	return filepath.Join("/etc/systemd/system", s.UnitName(format))
+}
+
+// Render returns the unit file contents
+func (s Spec) Render(format string) (string, error) {
+	switch format {
+	case FormatSystemd:
+		return s.systemd(), nil
+	case FormatLaunchd:
+		return s.launchd(), nil
+	}
+	return "", fmt.Errorf("unknown service format %q (expected %s or %s)", format, FormatSystemd, FormatLaunchd)
+}
+
+func (s Spec) env() []string {
+	keys := make([]string, 0, len(s.Env))
+	for k := range s.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s Spec) systemd() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[Unit]\nDescription=blackbox %s\nWants=network-online.target\nAfter=network-online.target\n\n", s.Name)
+	b.WriteString("[Service]\nType=simple\n")
+	if s.User != "" {
+		fmt.Fprintf(&b, "User=%s\n", s.User)
+	}
+	if s.Home != "" {
+		fmt.Fprintf(&b, "Environment=%s\n", systemdQuote("HOME="+s.Home))
+	}
+	for _, k := range s.env() {
+		fmt.Fprintf(&b, "Environment=%s\n", systemdQuote(k+"="+s.Env[k]))
+	}
+	words := []string{systemdQuote(s.Exec)}
+	for _, a := range s.Args {
+		words = append(words, systemdQuote(a))
+	}
+	fmt.Fprintf(&b, "ExecStart=%s\n", strings.Join(words, " "))
+	b.WriteString("Restart=on-failure\nRestartSec=5\n\n")
+	b.WriteString("[Install]\nWantedBy=multi-user.target\n")
+	return b.String()
+}
+
+// systemdQuote escapes a word for ExecStart/Environment: % specifiers and $
+// expansion are doubled, and words with spaces or quotes are double-quoted
+func systemdQuote(s string) string {
+	s = strings.ReplaceAll(s, "%", "%%")
+	s = strings.ReplaceAll(s, "$", "$$")
+	if !strings.ContainsAny(s, " \t\"'\\;") {
+		return s
+	}
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+func (s Spec) launchd() string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+`)
+	fmt.Fprintf(&b, "\t<key>Label</key>\n\t<string>%s</string>\n", xmlEscape(strings.TrimSuffix(s.UnitName(FormatLaunchd), ".plist")))
+	b.WriteString("\t<key>ProgramArguments</key>\n\t<array>\n")
+	for _, a := range append([]string{s.Exec}, s.Args...) {
+		fmt.Fprintf(&b, "\t\t<string>%s</string>\n", xmlEscape(a))
+	}
+	b.WriteString("\t</array>\n")
+	if len(s.Env) > 0 {
+		b.WriteString("\t<key>EnvironmentVariables</key>\n\t<dict>\n")
+		for _, k := range s.env() {
+			fmt.Fprintf(&b, "\t\t<key>%s</key>\n\t\t<string>%s</string>\n", xmlEscape(k), xmlEscape(s.Env[k]))
+		}
+		b.WriteString("\t</dict>\n")
+	}
+	b.WriteString("\t<key>RunAtLoad</key>\n\t<true/>\n")
+	b.WriteString("\t<key>KeepAlive</key>\n\t<dict>\n\t\t<key>SuccessfulExit</key>\n\t\t<false/>\n\t</dict>\n")
+	if s.Home != "" {
+		logPath := filepath.Join(s.Home, "Library", "Logs", "blackbox-"+s.Name+".log")
+		fmt.Fprintf(&b, "\t<key>StandardOutPath</key>\n\t<string>%s</string>\n", xmlEscape(logPath))
+		fmt.Fprintf(&b, "\t<key>StandardErrorPath</key>\n\t<string>%s</string>\n", xmlEscape(logPath))
+	}
+	b.WriteString("</dict>\n</plist>\n")
+	return b.String()
+}
+
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// NextSteps returns the commands that load and start an installed unit
+func (s Spec) NextSteps(format string) []string {
+	if format == FormatLaunchd {
+		return []string{"launchctl load -w " + s.InstallPath(format)}
+	}
+	unit := strings.TrimSuffix(s.UnitName(format), ".service")
+	return []string{"sudo systemctl daemon-reload", "sudo systemctl enable --now " + unit, "journalctl -u " + unit + " -f"}
+}
@@ -0,0 +1,210 @@
+// Package share packs a snapshot and its recent history into a compact text
+// blob that can be attached to tickets or posted as a gist, and reads it back.
+package share
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+const (
+	// Prefix marks a blob so it can be pasted anywhere and still be recognised
+	Prefix  = "bbx1:"
+	version = 1
+
+	gistAPI = "https://api.github.com/gists"
+
+	// maxBundleBytes caps a decompressed bundle and a gist API response; a
+	// real bundle with a full history is well under 1 MiB
+	maxBundleBytes = 16 << 20
+)
+
+// Bundle is everything a shared view needs. Call Redacted before encoding.
+type Bundle struct {
+	Version    int             `json:"version"`
+	Endpoint   string          `json:"endpoint"`
+	BaseURL    string          `json:"base_url"`
+	CapturedAt time.Time       `json:"captured_at"`
+	Snapshot   *model.Snapshot `json:"snapshot"`
+	History    []Sample        `json:"history"`
+}
+
+type Sample struct {
+	Time               time.Time `json:"time"`
+	AllocatedVRAMBytes int64     `json:"allocated_vram_bytes"`
+	UsedKVCacheBytes   int64     `json:"used_kv_cache_bytes"`
+	PrefixCacheHitRate float64   `json:"prefix_cache_hit_rate"`
+	RequestsRunning    float64   `json:"num_requests_running,omitempty"`
+	RequestsWaiting    float64   `json:"num_requests_waiting,omitempty"`
+	GPUTemperatureC    float64   `json:"gpu_temperature_c,omitempty"`
+	GPUPowerWatts      float64   `json:"gpu_power_w,omitempty"`
+	GPUUtilization     float64   `json:"gpu_utilization_percent,omitempty"`
+	TTFTSeconds        float64   `json:"ttft_seconds,omitempty"`
+	InterTokenSeconds  float64   `json:"inter_token_latency_seconds,omitempty"`
+	Throughput         float64   `json:"generation_tokens_per_second,omitempty"`
+}
+
+// Redacted returns a copy with credentials and registered secrets masked in
+// the endpoint name, base URL and model IDs; the metrics are left as they are
+func (b *Bundle) Redacted() *Bundle {
+	out := *b
+	out.Endpoint = utils.Redact(b.Endpoint)
+	out.BaseURL = utils.RedactRawURL(b.BaseURL)
+	if b.Snapshot != nil {
+		snap := *b.Snapshot
+		snap.Models = make([]model.ModelInfo, len(b.Snapshot.Models))
+		for i, m := range b.Snapshot.Models {
+			m.ModelID = utils.Redact(m.ModelID)
+			snap.Models[i] = m
+		}
+		out.Snapshot = &snap
+	}
+	return &out
+}
+
+// Encode returns the bundle as Prefix + base64url(gzip(json))
+func Encode(b *Bundle) (string, error) {
+	b.Version = version
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(b); err != nil {
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+	return Prefix + base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func Decode(blob string) (*Bundle, error) {
+	blob = strings.TrimSpace(blob)
+	if !strings.HasPrefix(blob, Prefix) {
+		return nil, fmt.Errorf("not a blackbox share blob (expected %q prefix)", Prefix)
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(blob, Prefix))
+	if err != nil {
+		return nil, fmt.Errorf("corrupt share blob: %w", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("corrupt share blob: %w", err)
+	}
+	defer zr.Close()
+	var b Bundle
+	if err := json.NewDecoder(http.MaxBytesReader(nil, zr, maxBundleBytes)).Decode(&b); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, fmt.Errorf("share blob expands to more than %d MiB", maxBundleBytes>>20)
+		}
+		return nil, fmt.Errorf("corrupt share blob: %w", err)
+	}
+	if b.Version > version {
+		return nil, fmt.Errorf("share blob version %d is newer than this blackbox supports (%d)", b.Version, version)
+	}
+	if b.Snapshot == nil {
+		return nil, fmt.Errorf("share blob has no snapshot")
+	}
+	return &b, nil
+}
+
+// Load reads a bundle from a file, a gist URL or a pasted blob
+func Load(ctx context.Context, src string) (*Bundle, error) {
+	switch {
+	case strings.HasPrefix(src, Prefix):
+		return Decode(src)
+	case strings.HasPrefix(src, "https://gist.github.com/"):
+		blob, err := fetchGist(ctx, src)
+		if err != nil {
+			return nil, err
+		}
+		return Decode(blob)
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(string(data))
+}
+
+// PublishGist uploads blob as a secret gist and returns its URL
+func PublishGist(ctx context.Context, token, filename, description, blob string) (string, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"description": description,
+		"public":      false,
+		"files":       map[string]map[string]string{filename: {"content": blob}},
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, gistAPI, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("gist upload failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("gist upload failed: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	var out struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("gist upload: failed to decode response: %w", err)
+	}
+	return out.HTMLURL, nil
+}
+
+func fetchGist(ctx context.Context, gistURL string) (string, error) {
+	id := gistURL[strings.LastIndex(strings.TrimRight(gistURL, "/"), "/")+1:]
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gistAPI+"/"+strings.TrimRight(id, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("gist fetch failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("gist fetch failed: %s", resp.Status)
+	}
+	var out struct {
+		Files map[string]struct {
+			Content string `json:"content"`
+		} `json:"files"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(nil, resp.Body, maxBundleBytes)).Decode(&out); err != nil {
+		return "", fmt.Errorf("gist fetch: failed to decode response: %w", err)
+	}
+	for _, f := range out.Files {
+		if strings.HasPrefix(strings.TrimSpace(f.Content), Prefix) {
+			return f.Content, nil
+		}
+	}
+	return "", fmt.Errorf("gist has no blackbox share blob")
+}
@@ -0,0 +1,250 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+// ErrBackpressure is returned by Publish when the delivery queue is full and the
+// sink is configured to block but the caller's context expired first.
+var ErrBackpressure = errors.New("sink queue full")
+
+// EnvelopeVersion is bumped whenever Envelope's fields change incompatibly
+const EnvelopeVersion = 1
+
+// Envelope wraps a snapshot with routing metadata. Field names and types are kept
+// flat and explicit so the record maps 1:1 onto an Avro/Protobuf schema later.
+type Envelope struct {
+	SchemaVersion int             `json:"schema_version"`
+	Endpoint      string          `json:"endpoint"`
+	Timestamp     time.Time       `json:"timestamp"`
+	Snapshot      *model.Snapshot `json:"snapshot"`
+}
+
+type message struct {
+	key   string
+	value []byte
+}
+
+// partialError is a flush that failed after handing the first sent messages
+// to the broker; the batcher retries only the rest
+type partialError struct {
+	sent int
+	err  error
+}
+
+func (e *partialError) Error() string { return e.err.Error() }
+func (e *partialError) Unwrap() error { return e.err }
+
+type batchOptions struct {
+	format    string // json (bare snapshot) or envelope
+	batchSize int
+	linger    time.Duration
+	queueSize int
+	onFull    string // block or drop
+	retries   int
+}
+
+func parseBatchOptions(q url.Values) (batchOptions, error) {
+	opts := batchOptions{
+		format:    "envelope",
+		batchSize: 50,
+		linger:    time.Second,
+		queueSize: 1000,
+		onFull:    "block",
+		retries:   3,
+	}
+	if v := q.Get("format"); v != "" {
+		format, err := parseFormat(v)
+		if err != nil {
+			return opts, err
+		}
+		opts.format = format
+	}
+	for _, p := range []struct {
+		name string
+		dst  *int
+	}{{"batch", &opts.batchSize}, {"queue", &opts.queueSize}, {"retries", &opts.retries}} {
+		if v := q.Get(p.name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 || (n == 0 && p.name != "retries") {
+				return opts, fmt.Errorf("invalid %s %q", p.name, v)
+			}
+			*p.dst = n
+		}
+	}
+	if v := q.Get("linger"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return opts, fmt.Errorf("invalid linger %q: %w", v, err)
+		}
+		if d <= 0 {
+			return opts, fmt.Errorf("invalid linger %q (must be positive)", v)
+		}
+		opts.linger = d
+	}
+	if v := q.Get("on_full"); v != "" {
+		if v != "block" && v != "drop" {
+			return opts, fmt.Errorf("invalid on_full %q (expected block or drop)", v)
+		}
+		opts.onFull = v
+	}
+	return opts, nil
+}
+
+// batcher queues encoded messages and hands them to flush in batches from a
+// single goroutine, so a slow broker pushes back on Publish instead of growing memory.
+type batcher struct {
+	name    string
+	opts    batchOptions
+	flush   func(ctx context.Context, batch []message) error
+	queue   chan message
+	dropped int
+	mu      sync.Mutex
+	done    chan struct{}
+}
+
+func newBatcher(name string, opts batchOptions, flush func(ctx context.Context, batch []message) error) *batcher {
+	b := &batcher{
+		name:  name,
+		opts:  opts,
+		flush: flush,
+		queue: make(chan message, opts.queueSize),
+		done:  make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+type timestampKey struct{}
+
+// WithTimestamp makes snapshots published with the returned context carry t
+// as their envelope timestamp instead of the time they are published, e.g.
+// the wall-clock boundary an aligned sample belongs to
+func WithTimestamp(ctx context.Context, t time.Time) context.Context {
+	return context.WithValue(ctx, timestampKey{}, t)
+}
+
+func timestampFrom(ctx context.Context) time.Time {
+	if t, ok := ctx.Value(timestampKey{}).(time.Time); ok {
+		return t.UTC()
+	}
+	return time.Now().UTC()
+}
+
+// parseFormat reads a sink's format option: json (bare snapshot) or envelope
+func parseFormat(v string) (string, error) {
+	if v != "json" && v != "envelope" {
+		return "", fmt.Errorf("invalid format %q (expected json or envelope)", v)
+	}
+	return v, nil
+}
+
+// encode renders snap in format, wrapped in an Envelope stamped at unless
+// format is json
+func encode(format, endpoint string, snap *model.Snapshot, at time.Time) ([]byte, error) {
+	if format == "json" {
+		return json.Marshal(snap)
+	}
+	return json.Marshal(Envelope{
+		SchemaVersion: EnvelopeVersion,
+		Endpoint:      endpoint,
+		Timestamp:     at,
+		Snapshot:      snap,
+	})
+}
+
+func (b *batcher) enqueue(ctx context.Context, key string, endpoint string, snap *model.Snapshot) error {
+	value, err := encode(b.opts.format, endpoint, snap, timestampFrom(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	msg := message{key: key, value: value}
+
+	if b.opts.onFull == "drop" {
+		select {
+		case b.queue <- msg:
+		default:
+			b.mu.Lock()
+			b.dropped++
+			dropped := b.dropped
+			b.mu.Unlock()
+			utils.Warn("%s sink queue full, dropped snapshot for %s (%d dropped so far)", b.name, endpoint, dropped)
+		}
+		return nil
+	}
+
+	select {
+	case b.queue <- msg:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %v", ErrBackpressure, ctx.Err())
+	}
+}
+
+func (b *batcher) run() {
+	defer close(b.done)
+	ticker := time.NewTicker(b.opts.linger)
+	defer ticker.Stop()
+
+	batch := make([]message, 0, b.opts.batchSize)
+	send := func() {
+		if len(batch) == 0 {
+			return
+		}
+		backoff := 200 * time.Millisecond
+		pending := batch
+		for attempt := 0; ; attempt++ {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			err := b.flush(ctx, pending)
+			cancel()
+			if err == nil {
+				break
+			}
+			var partial *partialError
+			if errors.As(err, &partial) {
+				pending = pending[partial.sent:]
+			}
+			if attempt >= b.opts.retries {
+				utils.Error("%s sink dropped batch of %d after %d attempts: %v", b.name, len(pending), attempt+1, err)
+				break
+			}
+			utils.Warn("%s sink delivery failed (attempt %d): %v", b.name, attempt+1, err)
+			// Blocking here is the backpressure: the queue fills and Publish waits
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case msg, ok := <-b.queue:
+			if !ok {
+				send()
+				return
+			}
+			batch = append(batch, msg)
+			if len(batch) >= b.opts.batchSize {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		}
+	}
+}
+
+// close flushes anything still queued and stops the worker
+func (b *batcher) close() {
+	close(b.queue)
+	<-b.done
+}
@@ -0,0 +1,63 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+	"github.com/segmentio/kafka-go"
+)
+
+// kafkaSink writes one record per snapshot to a topic, keyed by endpoint name
+// so all samples from a host land in the same partition in order.
+type kafkaSink struct {
+	writer *kafka.Writer
+	batch  *batcher
+}
+
+// newKafka accepts kafka://broker1:9092,broker2:9092/topic?format=envelope&batch=50&linger=1s&queue=1000&on_full=block
+func newKafka(u *url.URL) (*kafkaSink, error) {
+	opts, err := parseBatchOptions(u.Query())
+	if err != nil {
+		return nil, fmt.Errorf("kafka sink: %w", err)
+	}
+	topic := strings.TrimPrefix(u.Path, "/")
+	if topic == "" {
+		topic = "blackbox.snapshots"
+	}
+	brokers := strings.Split(u.Host, ",")
+
+	s := &kafkaSink{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireOne,
+			// Batching is done by our batcher; write each batch immediately
+			BatchSize:    opts.batchSize,
+			BatchTimeout: 10 * time.Millisecond,
+		},
+	}
+	s.batch = newBatcher("kafka", opts, s.flush)
+	return s, nil
+}
+
+func (s *kafkaSink) Publish(ctx context.Context, endpoint string, snap *model.Snapshot) error {
+	return s.batch.enqueue(ctx, endpoint, endpoint, snap)
+}
+
+func (s *kafkaSink) flush(ctx context.Context, batch []message) error {
+	msgs := make([]kafka.Message, len(batch))
+	for i, m := range batch {
+		msgs[i] = kafka.Message{Key: []byte(m.key), Value: m.value}
+	}
+	return s.writer.WriteMessages(ctx, msgs...)
+}
+
+func (s *kafkaSink) Close() error {
+	s.batch.close()
+	return s.writer.Close()
+}
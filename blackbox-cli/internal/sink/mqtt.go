@@ -0,0 +1,144 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+)
+
+// mqttSink publishes each snapshot to <prefix>/<endpoint>/snapshot, in an
+// Envelope like the other sinks unless format is json, and each model to
+// <prefix>/<endpoint>/models/<model_id>.
+type mqttSink struct {
+	client mqtt.Client
+	prefix string
+	format string
+	qos    byte
+	retain bool
+}
+
+// mqttDefaultPorts are the broker ports for each of paho's schemes
+var mqttDefaultPorts = map[string]string{"tcp": "1883", "ssl": "8883", "ws": "80", "wss": "443"}
+
+// newMQTT accepts mqtt://[user:pass@]host:port?prefix=blackbox&format=envelope&qos=0&retain=false&client_id=...
+func newMQTT(u *url.URL) (*mqttSink, error) {
+	q := u.Query()
+
+	scheme := u.Scheme
+	switch scheme {
+	case "mqtt":
+		scheme = "tcp"
+	case "mqtts":
+		scheme = "ssl"
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host += ":" + mqttDefaultPorts[scheme]
+	}
+
+	s := &mqttSink{prefix: q.Get("prefix"), format: "envelope"}
+	if s.prefix == "" {
+		s.prefix = "blackbox"
+	}
+	if v := q.Get("format"); v != "" {
+		format, err := parseFormat(v)
+		if err != nil {
+			return nil, err
+		}
+		s.format = format
+	}
+	if v := q.Get("qos"); v != "" {
+		qos, err := strconv.Atoi(v)
+		if err != nil || qos < 0 || qos > 2 {
+			return nil, fmt.Errorf("invalid mqtt qos %q (expected 0, 1 or 2)", v)
+		}
+		s.qos = byte(qos)
+	}
+	if v := q.Get("retain"); v != "" {
+		retain, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mqtt retain %q: %w", v, err)
+		}
+		s.retain = retain
+	}
+
+	clientID := q.Get("client_id")
+	if clientID == "" {
+		hostname, _ := os.Hostname()
+		clientID = fmt.Sprintf("blackbox-%s-%d", hostname, os.Getpid())
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(scheme + "://" + host + u.EscapedPath()).
+		SetClientID(clientID).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectTimeout(10 * time.Second)
+	if u.User != nil {
+		opts.SetUsername(u.User.Username())
+		if pw, ok := u.User.Password(); ok {
+			opts.SetPassword(pw)
+		}
+	}
+
+	s.client = mqtt.NewClient(opts)
+	tok := s.client.Connect()
+	// With ConnectRetry the client keeps trying in the background, so give up on it
+	if !tok.WaitTimeout(10 * time.Second) {
+		s.client.Disconnect(0)
+		return nil, fmt.Errorf("mqtt connect to %s timed out", host)
+	}
+	if err := tok.Error(); err != nil {
+		s.client.Disconnect(0)
+		return nil, fmt.Errorf("mqtt connect to %s failed: %w", host, err)
+	}
+	return s, nil
+}
+
+func (s *mqttSink) Publish(ctx context.Context, endpoint string, snap *model.Snapshot) error {
+	base := s.prefix + "/" + topicSegment(endpoint)
+
+	payload, err := encode(s.format, endpoint, snap, timestampFrom(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	if err := s.publish(ctx, base+"/snapshot", payload); err != nil {
+		return err
+	}
+
+	for _, m := range snap.Models {
+		payload, err := json.Marshal(m)
+		if err != nil {
+			return fmt.Errorf("failed to marshal model: %w", err)
+		}
+		if err := s.publish(ctx, base+"/models/"+topicSegment(m.ModelID), payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *mqttSink) publish(ctx context.Context, topic string, payload []byte) error {
+	tok := s.client.Publish(topic, s.qos, s.retain, payload)
+	select {
+	case <-tok.Done():
+		if err := tok.Error(); err != nil {
+			return fmt.Errorf("mqtt publish to %s failed: %w", topic, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *mqttSink) Close() error {
+	s.client.Disconnect(250)
+	return nil
+}
@@ -0,0 +1,71 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+	"github.com/nats-io/nats.go"
+)
+
+// natsSink publishes to <prefix>.<endpoint>.snapshot. Delivery is at least
+// once: a batch whose final round-trip to the server fails is sent again in
+// full, so subscribers may see a snapshot twice.
+type natsSink struct {
+	conn   *nats.Conn
+	prefix string
+	batch  *batcher
+}
+
+// newNATS accepts nats://[user:pass@]host:4222?prefix=blackbox&format=envelope&batch=50&linger=1s&queue=1000&on_full=block
+func newNATS(u *url.URL) (*natsSink, error) {
+	q := u.Query()
+	opts, err := parseBatchOptions(q)
+	if err != nil {
+		return nil, fmt.Errorf("nats sink: %w", err)
+	}
+
+	server := *u
+	server.RawQuery = ""
+	if server.Scheme == "nats+tls" {
+		server.Scheme = "tls"
+	}
+	conn, err := nats.Connect(server.String(),
+		nats.Name("blackbox-exporter"),
+		nats.MaxReconnects(-1),
+		nats.Timeout(10*time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("nats connect to %s failed: %w", u.Host, err)
+	}
+
+	s := &natsSink{conn: conn, prefix: q.Get("prefix")}
+	if s.prefix == "" {
+		s.prefix = "blackbox"
+	}
+	s.batch = newBatcher("nats", opts, s.flush)
+	return s, nil
+}
+
+func (s *natsSink) Publish(ctx context.Context, endpoint string, snap *model.Snapshot) error {
+	return s.batch.enqueue(ctx, s.prefix+"."+topicSegment(endpoint)+".snapshot", endpoint, snap)
+}
+
+func (s *natsSink) flush(ctx context.Context, batch []message) error {
+	for i, m := range batch {
+		if err := s.conn.Publish(m.key, m.value); err != nil {
+			return &partialError{sent: i, err: err}
+		}
+	}
+	// Round-trip to the server so failures surface here rather than silently
+	// in the buffer. Which messages got through isn't known then, so the
+	// retry sends them all again.
+	return s.conn.FlushWithContext(ctx)
+}
+
+func (s *natsSink) Close() error {
+	s.batch.close()
+	s.conn.Close()
+	return nil
+}
@@ -0,0 +1,47 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+)
+
+// Sink receives snapshots from the exporter and forwards them elsewhere
+type Sink interface {
+	Publish(ctx context.Context, endpoint string, snap *model.Snapshot) error
+	Close() error
+}
+
+// Open creates a sink from a URL; the scheme selects the implementation
+// and query parameters carry sink-specific options.
+func Open(rawURL string) (Sink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sink URL %q: %w", rawURL, err)
+	}
+
+	switch u.Scheme {
+	case "mqtt", "mqtts", "tcp", "ssl", "ws", "wss":
+		return newMQTT(u)
+	case "nats", "nats+tls":
+		return newNATS(u)
+	case "kafka":
+		return newKafka(u)
+	default:
+		return nil, fmt.Errorf("unsupported sink scheme %q", u.Scheme)
+	}
+}
+
+// topicSegment makes an endpoint or model ID safe to use as a single topic/subject level
+func topicSegment(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '+', '#', '.', '*', '>', ' ':
+			return '_'
+		}
+		return r
+	}, s)
+}
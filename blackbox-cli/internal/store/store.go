@@ -0,0 +1,352 @@
+// Package store keeps the dashboard's chart history in a SQLite file, so the
+// charts reach further back than what fits in memory and survive restarts.
+// Polls are kept as they came for a while, then averaged into coarser
+// buckets, and dropped once they're older than the retention.
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+	"github.com/maxdcmn/blackbox-cli/internal/share"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+const (
+	fileName = "history.db"
+
+	// DefaultRetention is how long samples are kept
+	DefaultRetention = 7 * 24 * time.Hour
+	// DefaultRawFor is how long every poll is kept before it's averaged
+	DefaultRawFor = 24 * time.Hour
+	// DefaultResolution is the bucket older polls are averaged into
+	DefaultResolution = 5 * time.Minute
+
+	// compactInterval is how often old samples are averaged and dropped
+	compactInterval = time.Hour
+	// queueSize is how many samples can wait for the writer; more are dropped
+	queueSize = 256
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS samples (
+	endpoint TEXT NOT NULL,
+	at       INTEGER NOT NULL, -- Unix milliseconds
+	bucket   INTEGER NOT NULL, -- Seconds averaged into the row; 0 for a poll as it came
+	data     TEXT NOT NULL     -- share.Sample as JSON
+);
+CREATE INDEX IF NOT EXISTS samples_endpoint_at ON samples (endpoint, at);
+`
+
+// Options are how long samples are kept and how they're thinned out; zero
+// fields use the defaults
+type Options struct {
+	Retention  time.Duration
+	RawFor     time.Duration
+	Resolution time.Duration
+}
+
+// OptionsFrom reads the config's history section. Durations that don't
+// parse are warned about and use the defaults.
+func OptionsFrom(h *config.HistoryStore) Options {
+	var o Options
+	if h == nil {
+		return o.withDefaults()
+	}
+	for _, f := range []struct {
+		name  string
+		value string
+		into  *time.Duration
+	}{
+		{"retention", h.Retention, &o.Retention},
+		{"raw_for", h.RawFor, &o.RawFor},
+		{"resolution", h.Resolution, &o.Resolution},
+	} {
+		if f.value == "" {
+			continue
+		}
+		d, err := utils.ParseDuration(f.value)
+		if err != nil {
+			utils.Warn("history.%s: %v, using the default", f.name, err)
+			continue
+		}
+		*f.into = d
+	}
+	return o.withDefaults()
+}
+
+func (o Options) withDefaults() Options {
+	if o.Retention <= 0 {
+		o.Retention = DefaultRetention
+	}
+	if o.RawFor <= 0 {
+		o.RawFor = DefaultRawFor
+	}
+	if o.Resolution <= 0 {
+		o.Resolution = DefaultResolution
+	}
+	return o
+}
+
+type write struct {
+	endpoint string
+	sample   share.Sample
+}
+
+// Store is the history file. Append hands samples to a writer goroutine, so
+// a slow disk never holds up the caller; Close waits for it to finish.
+type Store struct {
+	db   *sql.DB
+	opts Options
+
+	writes    chan write
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Path is where the config's history section keeps the history: its path,
+// relative to the config's directory, else history.db there
+func Path(h *config.HistoryStore) string {
+	if h == nil || h.Path == "" {
+		return filepath.Join(config.Dir(), fileName)
+	}
+	if filepath.IsAbs(h.Path) {
+		return h.Path
+	}
+	return filepath.Join(config.Dir(), h.Path)
+}
+
+// Open opens (or creates) the history at path and starts its writer
+func Open(path string, opts Options) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create config directory: %w", err)
+	}
+	// WAL and a busy timeout let several dashboards share one file
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	s := &Store{
+		db:     db,
+		opts:   opts.withDefaults(),
+		writes: make(chan write, queueSize),
+		done:   make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+// Append queues a sample of endpoint for writing. When the writer has fallen
+// behind by queueSize samples the sample is dropped rather than waited on.
+func (s *Store) Append(endpoint string, sample share.Sample) {
+	select {
+	case s.writes <- write{endpoint: endpoint, sample: sample}:
+	default:
+		utils.Debug("history: writer behind, dropped a sample of %s", endpoint)
+	}
+}
+
+// Load returns endpoint's latest samples, at most limit of them, oldest first
+func (s *Store) Load(endpoint string, limit int) ([]share.Sample, error) {
+	rows, err := s.db.Query(`SELECT data FROM samples WHERE endpoint = ? AND at >= ? ORDER BY at DESC LIMIT ?`,
+		endpoint, time.Now().Add(-s.opts.Retention).UnixMilli(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	defer rows.Close()
+	var out []share.Sample
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read history: %w", err)
+		}
+		var sample share.Sample
+		if err := json.Unmarshal([]byte(data), &sample); err != nil {
+			continue
+		}
+		out = append(out, sample)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out, nil
+}
+
+// Close writes the samples still queued and closes the file
+func (s *Store) Close() error {
+	s.closeOnce.Do(func() { close(s.writes) })
+	<-s.done
+	return s.db.Close()
+}
+
+// run writes queued samples, a batch per transaction, and compacts the
+// history on start and every compactInterval
+func (s *Store) run() {
+	defer close(s.done)
+	s.compact()
+	ticker := time.NewTicker(compactInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case w, ok := <-s.writes:
+			if !ok {
+				return
+			}
+			batch := []write{w}
+		drain:
+			for len(batch) < queueSize {
+				select {
+				case w, ok := <-s.writes:
+					if !ok {
+						break drain
+					}
+					batch = append(batch, w)
+				default:
+					break drain
+				}
+			}
+			if err := s.insert(batch); err != nil {
+				utils.Warn("failed to save history: %v", err)
+			}
+		case <-ticker.C:
+			s.compact()
+		}
+	}
+}
+
+func (s *Store) insert(batch []write) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, w := range batch {
+		data, err := json.Marshal(w.sample)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`INSERT INTO samples (endpoint, at, bucket, data) VALUES (?, ?, 0, ?)`,
+			w.endpoint, w.sample.Time.UnixMilli(), string(data)); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// compact drops samples past the retention and averages polls older than
+// RawFor into one row per Resolution
+func (s *Store) compact() {
+	if err := s.downsample(); err != nil {
+		utils.Warn("failed to compact history: %v", err)
+	}
+}
+
+func (s *Store) downsample() error {
+	now := time.Now()
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`DELETE FROM samples WHERE at < ?`, now.Add(-s.opts.Retention).UnixMilli()); err != nil {
+		return err
+	}
+
+	cutoff := now.Add(-s.opts.RawFor).UnixMilli()
+	rows, err := tx.Query(`SELECT endpoint, at, data FROM samples WHERE bucket = 0 AND at < ? ORDER BY endpoint, at`, cutoff)
+	if err != nil {
+		return err
+	}
+	type key struct {
+		endpoint string
+		start    int64
+	}
+	buckets := map[key][]share.Sample{}
+	var order []key
+	resolution := s.opts.Resolution.Milliseconds()
+	for rows.Next() {
+		var endpoint, data string
+		var at int64
+		if err := rows.Scan(&endpoint, &at, &data); err != nil {
+			rows.Close()
+			return err
+		}
+		var sample share.Sample
+		if err := json.Unmarshal([]byte(data), &sample); err != nil {
+			continue
+		}
+		k := key{endpoint, at - at%resolution}
+		if _, ok := buckets[k]; !ok {
+			order = append(order, k)
+		}
+		buckets[k] = append(buckets[k], sample)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(order) == 0 {
+		return tx.Commit()
+	}
+
+	if _, err := tx.Exec(`DELETE FROM samples WHERE bucket = 0 AND at < ?`, cutoff); err != nil {
+		return err
+	}
+	for _, k := range order {
+		avg := average(buckets[k])
+		avg.Time = time.UnixMilli(k.start)
+		data, err := json.Marshal(avg)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`INSERT INTO samples (endpoint, at, bucket, data) VALUES (?, ?, ?, ?)`,
+			k.endpoint, k.start, int64(s.opts.Resolution.Seconds()), string(data)); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// average is the mean of every numeric field of samples, so fields added to
+// share.Sample are averaged without touching this
+func average(samples []share.Sample) share.Sample {
+	var out share.Sample
+	sum := reflect.ValueOf(&out).Elem()
+	for _, sample := range samples {
+		v := reflect.ValueOf(sample)
+		for i := 0; i < v.NumField(); i++ {
+			switch f := sum.Field(i); f.Kind() {
+			case reflect.Float64:
+				f.SetFloat(f.Float() + v.Field(i).Float())
+			case reflect.Int64:
+				f.SetInt(f.Int() + v.Field(i).Int())
+			}
+		}
+	}
+	n := len(samples)
+	for i := 0; i < sum.NumField(); i++ {
+		switch f := sum.Field(i); f.Kind() {
+		case reflect.Float64:
+			f.SetFloat(f.Float() / float64(n))
+		case reflect.Int64:
+			f.SetInt(f.Int() / int64(n))
+		}
+	}
+	return out
+}
@@ -0,0 +1,249 @@
+// Package telemetry keeps strictly opt-in, anonymous usage counts: which
+// commands run, which dashboard features are used and which classes of error
+// occur. It never records endpoint names, URLs, model IDs or metrics. Nothing
+// is recorded unless the user ran 'blackbox telemetry enable', and nothing is
+// sent unless a collector endpoint is configured.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/client"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+const (
+	schemaVersion = 1
+	sendInterval  = 24 * time.Hour
+	sendTimeout   = 2 * time.Second
+	stateFile     = "telemetry.json"
+)
+
+// Description is printed by 'blackbox telemetry' so users see what is collected
+const Description = `Telemetry is opt-in and off by default. When enabled, blackbox counts:
+  - commands run (e.g. "blackbox stat"), without arguments or flag values
+  - dashboard features used (e.g. grid, carousel, share), by name only
+  - error classes (timeout, unauthorized, not_found, bad_schema, unsupported, other)
+along with a random install ID, the blackbox version, OS and architecture.
+Endpoint names, URLs, headers, model IDs and metrics are never recorded.
+Counts stay in the config directory and are sent at most once a day, only
+when a collector endpoint is configured. 'blackbox telemetry preview' prints
+exactly what the next report would contain.`
+
+// Payload is exactly what gets sent
+type Payload struct {
+	Schema      int            `json:"schema"`
+	InstallID   string         `json:"install_id"`
+	Version     string         `json:"version"`
+	OS          string         `json:"os"`
+	Arch        string         `json:"arch"`
+	PeriodStart time.Time      `json:"period_start"`
+	Commands    map[string]int `json:"commands"`
+	TUIFeatures map[string]int `json:"tui_features"`
+	Errors      map[string]int `json:"errors"`
+}
+
+type state struct {
+	Payload
+	LastSent time.Time `json:"last_sent"`
+}
+
+var (
+	mu       sync.Mutex
+	enabled  bool
+	endpoint string
+	version  string
+	path     string
+	st       *state
+	dirty    bool
+)
+
+// Init loads the local counters when telemetry is enabled; otherwise every
+// recording call is a no-op
+func Init(on bool, collector, dir, ver string) {
+	mu.Lock()
+	defer mu.Unlock()
+	enabled, endpoint, version = on, collector, ver
+	path = filepath.Join(dir, stateFile)
+	st = nil
+	if !enabled {
+		return
+	}
+	st = loadState(path)
+	st.Version = ver
+}
+
+func loadState(p string) *state {
+	s := &state{}
+	if data, err := os.ReadFile(p); err == nil {
+		if err := json.Unmarshal(data, s); err != nil {
+			utils.Debug("telemetry: discarding unreadable %s: %v", p, err)
+			s = &state{}
+		}
+	}
+	if s.InstallID == "" {
+		s.InstallID = newInstallID()
+	}
+	s.reset(s.PeriodStart)
+	s.Schema, s.OS, s.Arch = schemaVersion, runtime.GOOS, runtime.GOARCH
+	return s
+}
+
+// reset makes sure the maps exist, starting a new period when start is zero
+func (s *state) reset(start time.Time) {
+	if start.IsZero() {
+		s.PeriodStart = time.Now().UTC().Truncate(time.Hour)
+	}
+	if s.Commands == nil {
+		s.Commands = map[string]int{}
+	}
+	if s.TUIFeatures == nil {
+		s.TUIFeatures = map[string]int{}
+	}
+	if s.Errors == nil {
+		s.Errors = map[string]int{}
+	}
+}
+
+func newInstallID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func count(m func(*state) map[string]int, key string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if st == nil {
+		return
+	}
+	m(st)[key]++
+	dirty = true
+}
+
+// Command counts a command by its path ("blackbox models")
+func Command(path string) {
+	count(func(s *state) map[string]int { return s.Commands }, path)
+}
+
+// Feature counts use of a named dashboard feature
+func Feature(name string) {
+	count(func(s *state) map[string]int { return s.TUIFeatures }, name)
+}
+
+// Error counts the class of err, never its message
+func Error(err error) {
+	if err != nil {
+		count(func(s *state) map[string]int { return s.Errors }, ErrorClass(err))
+	}
+}
+
+func ErrorClass(err error) string {
+	switch {
+	case errors.Is(err, client.ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, client.ErrUnauthorized):
+		return "unauthorized"
+	case errors.Is(err, client.ErrNotFound):
+		return "not_found"
+	case errors.Is(err, client.ErrBadSchema):
+		return "bad_schema"
+	case errors.Is(err, client.ErrUnsupported):
+		return "unsupported"
+	}
+	return "other"
+}
+
+// Preview returns the next report as indented JSON. With telemetry off it
+// shows the shape of a report with empty counts.
+func Preview() ([]byte, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	p := Payload{Schema: schemaVersion, InstallID: "(assigned on enable)", Version: version, OS: runtime.GOOS, Arch: runtime.GOARCH,
+		PeriodStart: time.Now().UTC().Truncate(time.Hour), Commands: map[string]int{}, TUIFeatures: map[string]int{}, Errors: map[string]int{}}
+	if st != nil {
+		p = st.Payload
+	}
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// Flush saves the counters and, at most once per sendInterval, sends them to
+// the configured collector. Failures are only logged.
+func Flush(ctx context.Context) {
+	mu.Lock()
+	defer mu.Unlock()
+	if st == nil {
+		return
+	}
+	if endpoint != "" && time.Since(st.LastSent) >= sendInterval && len(st.Commands)+len(st.TUIFeatures)+len(st.Errors) > 0 {
+		if err := send(ctx, st.Payload); err != nil {
+			utils.Debug("telemetry: send failed: %v", err)
+		} else {
+			st.LastSent = time.Now()
+			st.Commands, st.TUIFeatures, st.Errors = nil, nil, nil
+			st.reset(time.Time{})
+			dirty = true
+		}
+	}
+	if !dirty {
+		return
+	}
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+	}
+	if err == nil {
+		err = os.WriteFile(path, data, 0600)
+	}
+	if err != nil {
+		utils.Debug("telemetry: failed to save %s: %v", path, err)
+		return
+	}
+	dirty = false
+}
+
+func send(ctx context.Context, p Payload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("collector returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Forget deletes the local counters and install ID
+func Forget() error {
+	mu.Lock()
+	defer mu.Unlock()
+	st = nil
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package topology
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Formats lists what Write can produce
+var Formats = []string{"mermaid", "dot"}
+
+// Write renders f in format, one of Formats
+func Write(w io.Writer, f *Fleet, format string) error {
+	switch format {
+	case "mermaid":
+		return WriteMermaid(w, f)
+	case "dot":
+		return WriteDOT(w, f)
+	}
+	return fmt.Errorf("unknown format %q (expected %s)", format, strings.Join(Formats, " or "))
+}
+
+// WriteMermaid writes f as a Mermaid flowchart, one subgraph per endpoint.
+// Unreachable endpoints and stopped models are drawn dashed.
+func WriteMermaid(w io.Writer, f *Fleet) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintln(b, "flowchart LR")
+	var dashed []string
+	for i, ep := range f.Endpoints {
+		id := "ep" + strconv.Itoa(i)
+		fmt.Fprintf(b, "  subgraph %s[%s]\n", id, mermaidText([]string{ep.Name, ep.Address}))
+		if ep.Error != "" {
+			fmt.Fprintf(b, "    %s_err[%s]\n", id, mermaidText([]string{"unreachable"}))
+			dashed = append(dashed, id+"_err")
+		} else {
+			gpu := id + "_gpu"
+			fmt.Fprintf(b, "    %s[%s]\n", gpu, mermaidText(gpuLabel(ep.GPU)))
+			for j, m := range ep.Models {
+				mid := id + "_m" + strconv.Itoa(j)
+				fmt.Fprintf(b, "    %s(%s)\n", mid, mermaidText(modelLabel(m)))
+				if label := shareLabel(m, ep.GPU); label != "" {
+					fmt.Fprintf(b, "    %s -->|%s| %s\n", gpu, mermaidText([]string{label}), mid)
+				} else {
+					fmt.Fprintf(b, "    %s --> %s\n", gpu, mid)
+				}
+				if !m.Running {
+					dashed = append(dashed, mid)
+				}
+			}
+		}
+		fmt.Fprintln(b, "  end")
+	}
+	if len(dashed) > 0 {
+		fmt.Fprintln(b, "  classDef off stroke-dasharray: 5 5,color:#888")
+		fmt.Fprintf(b, "  class %s off\n", strings.Join(dashed, ","))
+	}
+	return b.Flush()
+}
+
+// WriteDOT writes f as a Graphviz digraph, one cluster per endpoint.
+// Unreachable endpoints and stopped models are drawn dashed.
+func WriteDOT(w io.Writer, f *Fleet) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintln(b, "digraph blackbox {")
+	fmt.Fprintln(b, "  rankdir=LR;")
+	fmt.Fprintln(b, `  node [shape=box, fontname="Helvetica"];`)
+	fmt.Fprintln(b, `  edge [fontname="Helvetica", fontsize=10];`)
+	for i, ep := range f.Endpoints {
+		id := "ep" + strconv.Itoa(i)
+		fmt.Fprintf(b, "  subgraph cluster_%s {\n", id)
+		fmt.Fprintf(b, "    label=%s;\n", dotText([]string{ep.Name, ep.Address}))
+		if ep.Error != "" {
+			fmt.Fprintf(b, "    %s_err [label=%s, style=dashed];\n", id, dotText([]string{"unreachable"}))
+		} else {
+			gpu := id + "_gpu"
+			fmt.Fprintf(b, "    %s [label=%s, shape=box3d];\n", gpu, dotText(gpuLabel(ep.GPU)))
+			for j, m := range ep.Models {
+				mid := id + "_m" + strconv.Itoa(j)
+				attrs := "label=" + dotText(modelLabel(m)) + ", style=rounded"
+				if !m.Running {
+					attrs = "label=" + dotText(modelLabel(m)) + `, style="rounded,dashed"`
+				}
+				fmt.Fprintf(b, "    %s [%s];\n", mid, attrs)
+				if label := shareLabel(m, ep.GPU); label != "" {
+					fmt.Fprintf(b, "    %s -> %s [label=%s];\n", gpu, mid, dotText([]string{label}))
+				} else {
+					fmt.Fprintf(b, "    %s -> %s;\n", gpu, mid)
+				}
+			}
+		}
+		fmt.Fprintln(b, "  }")
+	}
+	fmt.Fprintln(b, "}")
+	return b.Flush()
+}
+
+// mermaidText quotes lines as a Mermaid label; quotes become entity codes
+// since Mermaid has no escape character
+func mermaidText(lines []string) string {
+	for i, l := range lines {
+		lines[i] = strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;", "\n", " ").Replace(l)
+	}
+	return `"` + strings.Join(lines, "<br/>") + `"`
+}
+
+// dotText quotes lines as a DOT string with centred line breaks
+func dotText(lines []string) string {
+	for i, l := range lines {
+		lines[i] = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ").Replace(l)
+	}
+	return `"` + strings.Join(lines, `\n`) + `"`
+}
+
+func formatGB(bytes int64) string {
+	return strconv.FormatFloat(float64(bytes)/gib, 'f', 1, 64) + " GB"
+}
+
+func formatPercent(p float64) string {
+	return strconv.FormatFloat(p, 'f', 0, 64) + "%"
+}
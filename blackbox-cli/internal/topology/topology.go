@@ -0,0 +1,187 @@
+// Package topology builds a diagram of the fleet — endpoints, their GPUs and
+// the models deployed on them — and writes it as Graphviz DOT or Mermaid.
+package topology
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/maxdcmn/blackbox-cli/internal/client"
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+const gib = 1024 * 1024 * 1024
+
+// Fleet is what the diagram shows, in config order
+type Fleet struct {
+	Endpoints []Endpoint
+}
+
+// Endpoint is one configured endpoint. Error is set when neither its
+// snapshot nor its model list could be fetched; the diagram then only says
+// it's unreachable.
+type Endpoint struct {
+	Name    string
+	Address string
+	Error   string
+	GPU     GPU
+	Models  []Model
+}
+
+// GPU is the endpoint's VRAM as a whole; the snapshot doesn't split it by device
+type GPU struct {
+	Types              []string // From the model list, when the server reports them
+	TotalVRAMBytes     int64
+	AllocatedVRAMBytes int64
+}
+
+// Model is a deployed model and its share of the endpoint's VRAM
+type Model struct {
+	ModelID   string
+	Port      int
+	Running   bool
+	VRAMBytes int64
+}
+
+// Share is the model's VRAM as a percentage of the endpoint's, or -1 if unknown
+func (m Model) Share(g GPU) float64 {
+	if g.TotalVRAMBytes <= 0 || m.VRAMBytes <= 0 {
+		return -1
+	}
+	return float64(m.VRAMBytes) / float64(g.TotalVRAMBytes) * 100
+}
+
+// Build combines each endpoint's snapshot (VRAM per model) with its model
+// list (ports, status, GPU type). Names, addresses and model IDs are redacted
+// like other exports.
+func Build(endpoints []config.Endpoint, snaps client.SnapshotResults, models client.ModelsResults) *Fleet {
+	f := &Fleet{}
+	for _, ep := range endpoints {
+		e := Endpoint{Name: utils.Redact(ep.Name), Address: address(ep)}
+		snap := snaps.Snapshots[ep.Name]
+		list := models.Models[ep.Name]
+		if snap == nil && list == nil {
+			err := snaps.Errors[ep.Name]
+			if err == nil {
+				err = models.Errors[ep.Name]
+			}
+			if err != nil {
+				e.Error = utils.Redact(err.Error())
+			}
+			f.Endpoints = append(f.Endpoints, e)
+			continue
+		}
+		if snap != nil {
+			e.GPU.TotalVRAMBytes = snap.TotalVRAMBytes
+			e.GPU.AllocatedVRAMBytes = snap.AllocatedVRAMBytes
+		}
+		e.Models, e.GPU.Types = mergeModels(snap, list)
+		f.Endpoints = append(f.Endpoints, e)
+	}
+	return f
+}
+
+// mergeModels lists the models of the model list, with VRAM from the
+// snapshot, then any the snapshot has that the list lacks
+func mergeModels(snap *model.Snapshot, list *client.ModelsResponse) ([]Model, []string) {
+	var infos []model.ModelInfo
+	if snap != nil {
+		infos = snap.Models
+	}
+	used := make([]bool, len(infos))
+	vram := func(id string, port int) int64 {
+		for i, info := range infos {
+			if used[i] || info.ModelID != id || (port > 0 && info.Port > 0 && port != info.Port) {
+				continue
+			}
+			used[i] = true
+			return info.AllocatedVRAMBytes
+		}
+		return 0
+	}
+
+	var out []Model
+	types := map[string]bool{}
+	if list != nil {
+		for _, dm := range list.Models {
+			out = append(out, Model{
+				ModelID:   utils.Redact(dm.ModelID),
+				Port:      dm.Port,
+				Running:   dm.Running,
+				VRAMBytes: vram(dm.ModelID, dm.Port),
+			})
+			if dm.GPUType != "" {
+				types[dm.GPUType] = true
+			}
+		}
+	}
+	for i, info := range infos {
+		if !used[i] {
+			out = append(out, Model{ModelID: utils.Redact(info.ModelID), Port: info.Port, Running: true, VRAMBytes: info.AllocatedVRAMBytes})
+		}
+	}
+
+	var typeList []string
+	for t := range types {
+		typeList = append(typeList, t)
+	}
+	sort.Strings(typeList)
+	return out, typeList
+}
+
+// address is the endpoint's host and port without credentials or path
+func address(ep config.Endpoint) string {
+	if ep.Type == config.EndpointTypeLocal {
+		return "localhost"
+	}
+	u, err := url.Parse(ep.BaseURL)
+	if err != nil || u.Host == "" {
+		return utils.RedactRawURL(ep.BaseURL)
+	}
+	return utils.Redact(u.Host)
+}
+
+// gpuLabel is the GPU type and how much of its VRAM is allocated
+func gpuLabel(g GPU) []string {
+	name := "GPU"
+	if len(g.Types) > 0 {
+		name = strings.Join(g.Types, ", ")
+	}
+	lines := []string{name}
+	if g.TotalVRAMBytes > 0 {
+		lines = append(lines, formatGB(g.TotalVRAMBytes)+" VRAM, "+formatPercent(float64(g.AllocatedVRAMBytes)/float64(g.TotalVRAMBytes)*100)+" allocated")
+	}
+	return lines
+}
+
+// modelLabel is the model's ID and port, and whether it's stopped
+func modelLabel(m Model) []string {
+	lines := []string{m.ModelID}
+	var detail []string
+	if m.Port > 0 {
+		detail = append(detail, ":"+strconv.Itoa(m.Port))
+	}
+	if !m.Running {
+		detail = append(detail, "stopped")
+	}
+	if len(detail) > 0 {
+		lines = append(lines, strings.Join(detail, " "))
+	}
+	return lines
+}
+
+// shareLabel labels the GPU-to-model edge, e.g. "24.0 GB (30%)"
+func shareLabel(m Model, g GPU) string {
+	if m.VRAMBytes <= 0 {
+		return ""
+	}
+	label := formatGB(m.VRAMBytes)
+	if share := m.Share(g); share >= 0 {
+		label += " (" + formatPercent(share) + ")"
+	}
+	return label
+}
@@ -0,0 +1,113 @@
+// Package tracing exports OpenTelemetry spans for CLI-originated requests so
+// they can be lined up with blackbox-server traces. Until Init is called with
+// an endpoint every span is a no-op.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const (
+	tracerName      = "github.com/maxdcmn/blackbox-cli"
+	shutdownTimeout = 3 * time.Second
+)
+
+var (
+	provider *sdktrace.TracerProvider
+	command  trace.Span = noop.Span{}
+)
+
+// Init starts exporting spans over OTLP/HTTP to endpoint, a collector URL such
+// as http://localhost:4318 (/v1/traces is added when the path is empty)
+func Init(endpoint, version string) error {
+	if endpoint == "" {
+		return nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid --otel-endpoint %q: expected a URL like http://localhost:4318", endpoint)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = "/v1/traces"
+	}
+	exporter, err := otlptracehttp.New(context.Background(), otlptracehttp.WithEndpointURL(u.String()))
+	if err != nil {
+		return fmt.Errorf("invalid --otel-endpoint %q: %w", endpoint, err)
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName("blackbox-cli"),
+		semconv.ServiceVersion(version),
+	))
+	if err != nil {
+		return err
+	}
+	provider = sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return nil
+}
+
+// Enabled reports whether spans are being exported
+func Enabled() bool {
+	return provider != nil
+}
+
+// Tracer is the CLI's tracer; a no-op until Init
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// StartCommand opens the root span for one CLI invocation
+func StartCommand(path string) {
+	_, command = Tracer().Start(context.Background(), path, trace.WithAttributes(attribute.String("blackbox.command", path)))
+}
+
+// EndCommand closes the command span, recording err
+func EndCommand(err error) {
+	End(command, err)
+}
+
+// Parent returns ctx, or ctx under the command span when it carries none, so
+// requests made with a bare context.Background() still join the command's trace
+func Parent(ctx context.Context) context.Context {
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	return trace.ContextWithSpan(ctx, command)
+}
+
+// End closes span, recording err with secrets redacted
+func End(span trace.Span, err error) {
+	if err != nil {
+		msg := utils.Redact(err.Error())
+		span.RecordError(errors.New(msg))
+		span.SetStatus(codes.Error, msg)
+	}
+	span.End()
+}
+
+// Shutdown flushes buffered spans; call it once before exiting
+func Shutdown() {
+	if provider == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	provider.Shutdown(ctx)
+}
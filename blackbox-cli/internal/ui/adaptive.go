@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+// Polling bounds for endpoints without poll_min/poll_max
+const (
+	defaultPollMin = time.Second
+	defaultPollMax = 30 * time.Second
+)
+
+// Changes between two polls that count as activity: allocated VRAM or KV
+// cache moving by this share of total VRAM, or the prefix cache hit rate by
+// this many points
+const (
+	activeVRAMShare  = 0.02
+	activeHitRatePts = 5.0
+)
+
+// Why the cadence is at its current delay, shown in the preview card
+const (
+	pollStateUnknown = "" // No previous poll to compare with, or the last one failed
+	pollStateBusy    = "busy"
+	pollStateSteady  = "steady"
+	pollStateIdle    = "idle"
+)
+
+// pollLoad is the request load at poll time, for sources that report it
+type pollLoad struct {
+	running float64
+	waiting float64
+}
+
+// pollCadence picks the delay before an endpoint's next poll: min while its
+// metrics move or requests queue, base while it serves a steady load, and
+// doubling up to max while nothing changes. Errors go back to base so a
+// failing endpoint is neither hammered nor forgotten.
+type pollCadence struct {
+	min, base, max time.Duration
+	cur            time.Duration
+	state          string
+	prev           *model.Snapshot
+}
+
+func newPollCadence(ep config.Endpoint, base time.Duration) *pollCadence {
+	lo, hi := pollBounds(ep)
+	base = max(lo, min(base, hi))
+	return &pollCadence{min: lo, base: base, max: hi, cur: base}
+}
+
+// pollBounds reads ep's poll_min and poll_max; unset or invalid ones keep the
+// defaults, widened to take in ep's interval
+func pollBounds(ep config.Endpoint) (time.Duration, time.Duration) {
+	lo, hi := defaultPollMin, defaultPollMax
+	if d, ok := endpointInterval(ep); ok {
+		lo, hi = min(lo, d), max(hi, d)
+	}
+	if d, err := utils.ParseDuration(ep.PollMin); err == nil && d > 0 {
+		lo = d
+	}
+	if d, err := utils.ParseDuration(ep.PollMax); err == nil && d > 0 {
+		hi = d
+	}
+	return lo, max(lo, hi)
+}
+
+// endpointInterval reads ep's interval; false when unset or invalid
+func endpointInterval(ep config.Endpoint) (time.Duration, bool) {
+	d, err := utils.ParseDuration(ep.Interval)
+	return d, err == nil && d > 0
+}
+
+// pollInterval is ep's base poll interval: its own interval, else fallback
+func pollInterval(ep config.Endpoint, fallback time.Duration) time.Duration {
+	if d, ok := endpointInterval(ep); ok {
+		return d
+	}
+	return fallback
+}
+
+// setBase moves the base delay to d, kept within the poll bounds, and polls
+// at it from the next poll on. It returns the base in use.
+func (c *pollCadence) setBase(d time.Duration) time.Duration {
+	c.base = max(c.min, min(d, c.max))
+	c.cur = c.base
+	return c.base
+}
+
+// next records a poll's result and returns the delay before the next poll.
+// load is nil when the source doesn't report requests.
+func (c *pollCadence) next(s *model.Snapshot, load *pollLoad, err error) time.Duration {
+	switch {
+	case err != nil || s == nil || c.prev == nil:
+		c.cur, c.state = c.base, pollStateUnknown
+	case (load != nil && load.waiting > 0) || snapshotMoved(c.prev, s):
+		c.cur, c.state = c.min, pollStateBusy
+	case load != nil && load.running > 0:
+		c.cur, c.state = c.base, pollStateSteady
+	default:
+		c.cur, c.state = min(max(2*c.cur, c.base), c.max), pollStateIdle
+	}
+	if err == nil && s != nil {
+		c.prev = s
+	}
+	return c.cur
+}
+
+// snapshotMoved reports whether s differs from prev enough to poll faster
+func snapshotMoved(prev, s *model.Snapshot) bool {
+	total := float64(max(s.TotalVRAMBytes, 1))
+	moved := func(a, b int64) bool {
+		return float64(max(a-b, b-a))/total > activeVRAMShare
+	}
+	hitRate := s.PrefixCacheHitRate - prev.PrefixCacheHitRate
+	return moved(s.AllocatedVRAMBytes, prev.AllocatedVRAMBytes) ||
+		moved(s.UsedKVCacheBytes, prev.UsedKVCacheBytes) ||
+		max(hitRate, -hitRate) > activeHitRatePts ||
+		len(s.Models) != len(prev.Models)
+}
+
+// scalePollInterval multiplies the selected endpoint's base poll interval by
+// factor, within its poll bounds. It lasts until the dashboard quits; the
+// config's interval is left alone.
+func (m *DashboardModel) scalePollInterval(factor float64) {
+	if m.cadence == nil || m.selected >= len(m.endpoints) {
+		return
+	}
+	c := m.cadence
+	base := c.setBase(time.Duration(float64(c.base) * factor).Round(100 * time.Millisecond))
+	if m.pollOverrides == nil {
+		m.pollOverrides = make(map[string]time.Duration)
+	}
+	m.pollOverrides[m.endpoints[m.selected].Name] = base
+	m.configMessage = fmt.Sprintf("polling every %s from the next poll (%s-%s)", base, c.min, c.max)
+}
+
+// pollStatus is the status bar's note of the selected endpoint's poll delay,
+// and why it's off the base interval
+func (m *DashboardModel) pollStatus() string {
+	c := m.cadence
+	if c == nil || m.viewing != nil {
+		return ""
+	}
+	text := "⏱ " + c.cur.String()
+	if c.cur != c.base && c.state != pollStateUnknown {
+		text += " " + c.state
+	}
+	return text
+}
@@ -0,0 +1,195 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+)
+
+const (
+	// maxAlertEvents is how many breaches the alerts list keeps across endpoints
+	maxAlertEvents = 200
+	// alertFlashInterval is how often a new breach's chart and grid tile flash
+	alertFlashInterval = 500 * time.Millisecond
+)
+
+// alertEvent is an alert rule starting to breach on an endpoint
+type alertEvent struct {
+	at       time.Time
+	endpoint string
+	chart    string // Title of the chart the rule is drawn on
+	rule     config.AlertRule
+	value    float64   // What tripped the rule, in its units
+	cleared  time.Time // When it stopped breaching; zero while it still does
+}
+
+type flashMsg struct{}
+
+// checkAlerts records the rules of endpoint that s starts or stops breaching
+// and rings the terminal bell for new ones. A fleet poll leaves out latency
+// and GPU figures, so with fleet set their rules stay as they were.
+func (m *DashboardModel) checkAlerts(endpoint string, s *model.Snapshot, fleet bool) {
+	if m.breaching == nil {
+		m.breaching = make(map[string]bool)
+	}
+	ring := false
+	for _, c := range dataCharts {
+		if fleet && c.needs != "" {
+			continue
+		}
+		for _, metric := range c.alertMetrics() {
+			key := endpoint + "\x00" + metric
+			rule, ok := m.config.AlertRuleFor(endpoint, metric)
+			value := snapshotMetric(s, metric)
+			breached := ok && rule.Breached(value)
+			if breached == m.breaching[key] {
+				continue
+			}
+			if !breached {
+				delete(m.breaching, key)
+				m.clearAlert(endpoint, metric)
+				continue
+			}
+			m.breaching[key] = true
+			m.alertEvents = append(m.alertEvents, alertEvent{at: time.Now(), endpoint: endpoint, chart: c.title, rule: rule, value: value})
+			ring = true
+		}
+	}
+	if len(m.alertEvents) > maxAlertEvents {
+		m.alertEvents = m.alertEvents[len(m.alertEvents)-maxAlertEvents:]
+	}
+	if ring {
+		// BEL goes straight to the terminal, like the clipboard's OSC 52
+		fmt.Fprint(os.Stdout, "\a")
+	}
+}
+
+// clearAlert marks endpoint's breach of metric over
+func (m *DashboardModel) clearAlert(endpoint, metric string) {
+	for i := len(m.alertEvents) - 1; i >= 0; i-- {
+		e := &m.alertEvents[i]
+		if e.endpoint == endpoint && e.rule.Metric == metric && e.cleared.IsZero() {
+			e.cleared = time.Now()
+			return
+		}
+	}
+}
+
+// alertFlashing reports whether endpoint has a breach, on the chart titled
+// chart unless that's "", that started since the alerts list was last opened
+// and still holds
+func (m *DashboardModel) alertFlashing(endpoint, chart string) bool {
+	for i := len(m.alertEvents) - 1; i >= 0; i-- {
+		e := m.alertEvents[i]
+		if !e.at.After(m.alertsSeen) {
+			break
+		}
+		if e.cleared.IsZero() && (endpoint == "" || e.endpoint == endpoint) && (chart == "" || e.chart == chart) {
+			return true
+		}
+	}
+	return false
+}
+
+// startFlashing starts the flash loop when a breach wants flashing and it
+// isn't running yet
+func (m *DashboardModel) startFlashing() tea.Cmd {
+	if m.flashing || !m.alertFlashing("", "") {
+		return nil
+	}
+	m.flashing = true
+	return tea.Tick(alertFlashInterval, func(time.Time) tea.Msg { return flashMsg{} })
+}
+
+// updateFlash turns the flash over, and stops once nothing is left to flash
+func (m *DashboardModel) updateFlash() tea.Cmd {
+	if !m.alertFlashing("", "") {
+		m.flashing, m.flashOn = false, false
+		return nil
+	}
+	m.flashOn = !m.flashOn
+	return tea.Tick(alertFlashInterval, func(time.Time) tea.Msg { return flashMsg{} })
+}
+
+// flashRed reports whether endpoint's grid tile, or its chart titled chart,
+// is in the red half of a flash
+func (m *DashboardModel) flashRed(endpoint, chart string) bool {
+	return m.flashOn && m.alertFlashing(endpoint, chart)
+}
+
+// openAlerts shows the alerts list; breaches seen there stop flashing
+func (m *DashboardModel) openAlerts() {
+	m.showingAlerts = true
+	m.alertsScroll = 0
+	m.alertsSeen = time.Now()
+}
+
+func (m *DashboardModel) updateAlertsMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case m.keys.key("quit"), "ctrl+c":
+		return m.quit()
+	case m.keys.key("alerts"), "esc":
+		m.showingAlerts = false
+	case "j", "down":
+		if m.alertsScroll < len(m.alertEvents)-1 {
+			m.alertsScroll++
+		}
+	case "k", "up":
+		if m.alertsScroll > 0 {
+			m.alertsScroll--
+		}
+	}
+	return m, nil
+}
+
+// renderAlerts lists the breaches of this run, newest first, with the rule
+// each tripped and whether it still holds
+func (m *DashboardModel) renderAlerts(width, height int) string {
+	width, height = ensureMin(width, height, 40, 8)
+	if len(m.alertEvents) == 0 {
+		return m.renderEmptyState(width, height, "No alerts yet\n\nSet thresholds with 't' on a chart or in the config's alerts list\n\nPress 'A' to go back", colorUnfocused)
+	}
+
+	var b strings.Builder
+	breaching := 0
+	for _, e := range m.alertEvents {
+		if e.cleared.IsZero() {
+			breaching++
+		}
+	}
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(colorText))
+	b.WriteString(title.Render("Alerts") + styleColor(colorMuted).Render(fmt.Sprintf("  %d this run", len(m.alertEvents))))
+	if breaching > 0 {
+		b.WriteString("  " + styleColor(colorRed).Bold(true).Render(fmt.Sprintf("%d breaching", breaching)))
+	}
+	b.WriteString("\n\n")
+
+	const timeWidth, endpointWidth, chartWidth, ruleWidth = 10, 20, 24, 46
+	contentWidth := width - 4
+	visible := max(1, height-6)
+	start := min(m.alertsScroll, max(0, len(m.alertEvents)-visible))
+	for i := start; i < len(m.alertEvents) && i < start+visible; i++ {
+		e := m.alertEvents[len(m.alertEvents)-1-i]
+		status := styleColor(colorRed).Bold(true).Render("▲ breaching")
+		if !e.cleared.IsZero() {
+			status = styleColor(colorGreen).Render("cleared after " + e.cleared.Sub(e.at).Truncate(time.Second).String())
+		}
+		rule := fmt.Sprintf("%-*s", ruleWidth, fmt.Sprintf("%s %s %g, at %.1f", e.rule.Metric, e.rule.Op, e.rule.Value, e.value))
+		line := styleColor(colorMuted).Render(fmt.Sprintf("%-*s", timeWidth, e.at.Local().Format("15:04:05"))) +
+			lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(colorText)).Render(fmt.Sprintf("%-*s", endpointWidth, truncateString(e.endpoint, endpointWidth-1))) +
+			fmt.Sprintf("%-*s", chartWidth, e.chart) +
+			styleColor(colorMuted).Render(rule) + status
+		b.WriteString(lipgloss.NewStyle().MaxWidth(contentWidth).Render(line) + "\n")
+	}
+	if len(m.alertEvents) > visible {
+		b.WriteString(styleColor(colorMuted).Render(fmt.Sprintf("\n[%d-%d of %d]", start+1, min(start+visible, len(m.alertEvents)), len(m.alertEvents))))
+	}
+
+	return lipgloss.NewStyle().Width(width).Height(height).Padding(1, 2).Render(b.String())
+}
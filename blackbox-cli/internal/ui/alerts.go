@@ -0,0 +1,251 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+)
+
+type chartDef struct {
+	title     string
+	metric    string // alert rule metric key, in the chart's units
+	pctMetric string // alert rule metric key for the chart's share of the VRAM, if it has one
+	unit      string
+	step      float64
+	needs     string // "latency" or "gpu": only drawn when the endpoint reports those, see visibleCharts
+}
+
+// dataCharts lists the data panel charts in render order
+var dataCharts = []chartDef{
+	{title: "Allocated VRAM", metric: "allocated_vram_gb", pctMetric: "allocated_vram_percent", unit: "GB", step: 0.5},
+	{title: "Used KV Cache", metric: "used_kv_cache_gb", pctMetric: "used_kv_cache_percent", unit: "GB", step: 0.5},
+	{title: "Prefix Cache Hit Rate", metric: "prefix_cache_hit_rate", unit: "%", step: 1},
+	{title: "Throughput", metric: "generation_tokens_per_second", unit: "tok/s", step: 10},
+	{title: "Latency", metric: "ttft_ms", unit: "ms", step: 10, needs: "latency"},
+	{title: "GPU Temperature", metric: "gpu_temperature_c", unit: "°C", step: 1, needs: "gpu"},
+	{title: "GPU Power", metric: "gpu_power_w", unit: "W", step: 10, needs: "gpu"},
+	{title: "SM Utilization", metric: "gpu_utilization_percent", unit: "%", step: 1, needs: "gpu"},
+}
+
+// visibleCharts are the dataCharts the data panel draws: Latency once the
+// models have reported it, the GPU charts when the GPUs report them and fit
+func (m *DashboardModel) visibleCharts() []chartDef {
+	if m.chartModel != "" {
+		return modelCharts
+	}
+	var charts, gpu []chartDef
+	for _, c := range dataCharts {
+		switch c.needs {
+		case "":
+			charts = append(charts, c)
+		case "latency":
+			if m.hasLatency() {
+				charts = append(charts, c)
+			}
+		case "gpu":
+			gpu = append(gpu, c)
+		}
+	}
+	if m.gpuChartsFit(len(charts) + len(gpu)) {
+		charts = append(charts, gpu...)
+	}
+	return charts
+}
+
+// selectedChartDef is the chart selected with j/k in the data panel
+func (m *DashboardModel) selectedChartDef() chartDef {
+	charts := m.visibleCharts()
+	return charts[min(m.selectedChart, len(charts)-1)]
+}
+
+const thresholdRune = '┄'
+
+func chartByTitle(title string) (chartDef, bool) {
+	for _, c := range dataCharts {
+		if c.title == title {
+			return c, true
+		}
+	}
+	return chartDef{}, false
+}
+
+// chartCurrentValue returns the latest value of a chart in its own units
+func (m *DashboardModel) chartCurrentValue(c chartDef) float64 {
+	if m.last == nil {
+		return 0
+	}
+	return snapshotMetric(m.last, c.metric)
+}
+
+// snapshotMetric extracts an alert metric from a snapshot, in the metric's units
+func snapshotMetric(s *model.Snapshot, metric string) float64 {
+	switch metric {
+	case "allocated_vram_gb":
+		return float64(s.AllocatedVRAMBytes) / gbDivisor
+	case "used_kv_cache_gb":
+		return float64(s.UsedKVCacheBytes) / gbDivisor
+	case "allocated_vram_percent":
+		return vramPercent(s.AllocatedVRAMBytes, s)
+	case "used_kv_cache_percent":
+		return vramPercent(s.UsedKVCacheBytes, s)
+	case "prefix_cache_hit_rate":
+		return s.PrefixCacheHitRate
+	case "gpu_temperature_c":
+		return s.Telemetry().TemperatureC
+	case "gpu_power_w":
+		return s.Telemetry().PowerWatts
+	case "gpu_utilization_percent":
+		return s.Telemetry().UtilizationPercent
+	case "generation_tokens_per_second":
+		return s.Throughput()
+	case "ttft_ms":
+		ttft, _ := s.Latency()
+		return ttft * 1000
+	}
+	return 0
+}
+
+// vramPercent is bytes as a share of the snapshot's VRAM, 0-100
+func vramPercent(bytes int64, s *model.Snapshot) float64 {
+	if s.TotalVRAMBytes <= 0 {
+		return 0
+	}
+	return float64(bytes) / float64(s.TotalVRAMBytes) * 100
+}
+
+// alertMetrics are the alert rule metrics drawn on c
+func (c chartDef) alertMetrics() []string {
+	if c.pctMetric == "" {
+		return []string{c.metric}
+	}
+	return []string{c.metric, c.pctMetric}
+}
+
+// breachedAlerts returns the charts one of whose alert rules the snapshot currently trips
+func (m *DashboardModel) breachedAlerts(endpoint string, s *model.Snapshot) []chartDef {
+	var out []chartDef
+	if s == nil {
+		return out
+	}
+	for _, c := range dataCharts {
+		for _, metric := range c.alertMetrics() {
+			if rule, ok := m.config.AlertRuleFor(endpoint, metric); ok && rule.Breached(snapshotMetric(s, metric)) {
+				out = append(out, c)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// alertSummary names the breached charts and who to contact, e.g.
+// "Allocated VRAM, Used KV Cache: page @gpu-oncall"
+func alertSummary(breached []chartDef, ep config.Endpoint) string {
+	titles := make([]string, len(breached))
+	for i, c := range breached {
+		titles[i] = c.title
+	}
+	s := strings.Join(titles, ", ")
+	if contact := ep.Contact(); contact != "" {
+		s += ": page " + contact
+	}
+	return s
+}
+
+// chartThreshold returns the threshold line to draw on a chart, in the units
+// it's drawn in: the value being edited while the picker is open, otherwise
+// the configured rule.
+func (m *DashboardModel) chartThreshold(title string) (float64, bool) {
+	c, ok := chartByTitle(title)
+	if !ok {
+		return 0, false
+	}
+	if m.thresholdEditing && m.selectedChartDef().metric == c.metric {
+		return m.chartValue(c, m.thresholdValue), true
+	}
+	if len(m.endpoints) == 0 || m.selected >= len(m.endpoints) {
+		return 0, false
+	}
+	rule, ok := m.config.AlertRuleFor(m.endpoints[m.selected].Name, c.metric)
+	return m.chartValue(c, rule.Value), ok
+}
+
+// chartValue converts v from c's alert rule units to those c is drawn in
+func (m *DashboardModel) chartValue(c chartDef, v float64) float64 {
+	if c.unit != "GB" {
+		return v
+	}
+	return m.memValue(int64(v*gbDivisor), m.totalVRAM())
+}
+
+func (m *DashboardModel) startThresholdEdit() {
+	c := m.selectedChartDef()
+	m.thresholdEditing = true
+	m.thresholdMessage = ""
+	m.thresholdOp = ">"
+	m.thresholdValue = math.Round(m.chartCurrentValue(c)/c.step) * c.step
+	if rule, ok := m.config.AlertRuleFor(m.endpoints[m.selected].Name, c.metric); ok {
+		m.thresholdOp = rule.Op
+		m.thresholdValue = rule.Value
+	}
+}
+
+func (m *DashboardModel) updateThresholdMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	c := m.selectedChartDef()
+	endpoint := m.endpoints[m.selected].Name
+
+	switch msg.String() {
+	case "esc":
+		m.thresholdEditing = false
+	case "k", "up", "+":
+		m.thresholdValue += c.step
+	case "j", "down", "-":
+		m.thresholdValue = math.Max(0, m.thresholdValue-c.step)
+	case "K", "pgup":
+		m.thresholdValue += c.step * 10
+	case "J", "pgdown":
+		m.thresholdValue = math.Max(0, m.thresholdValue-c.step*10)
+	case "o":
+		if m.thresholdOp == ">" {
+			m.thresholdOp = "<"
+		} else {
+			m.thresholdOp = ">"
+		}
+	case "x", "delete":
+		if _, ok := m.config.AlertRuleFor(endpoint, c.metric); ok {
+			if err := config.RemoveAlertRule(m.config, endpoint, c.metric); err != nil {
+				m.thresholdMessage = err.Error()
+				return m, nil
+			}
+		}
+		m.thresholdEditing = false
+	case "enter":
+		rule := config.AlertRule{Endpoint: endpoint, Metric: c.metric, Op: m.thresholdOp, Value: m.thresholdValue}
+		if err := config.SetAlertRule(m.config, rule); err != nil {
+			m.thresholdMessage = err.Error()
+			return m, nil
+		}
+		m.thresholdEditing = false
+	}
+	return m, nil
+}
+
+func (m *DashboardModel) renderThresholdBar(width int) string {
+	c := m.selectedChartDef()
+	prompt := fmt.Sprintf("Alert %s when %s %s %s %s",
+		styleColor(colorText).Bold(true).Render(m.endpoints[m.selected].Name),
+		c.title,
+		m.thresholdOp,
+		styleColor(colorRed).Bold(true).Render(fmt.Sprintf("%.1f", m.thresholdValue)),
+		c.unit)
+	keys := styleColor(colorItalic).Render("k/j: ±" + fmt.Sprint(c.step) + "  K/J: ±" + fmt.Sprint(c.step*10) + "  o: flip  Enter: save  x: remove  Esc: cancel")
+	content := prompt + "  " + keys
+	if m.thresholdMessage != "" {
+		content = prompt + "  " + styleColor(colorRed).Render("✗ "+m.thresholdMessage)
+	}
+	return statusBarStyle.Width(width).Height(1).Render(content)
+}
@@ -0,0 +1,161 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// anomalyWindow is how many of the preceding poll-to-poll changes a change is compared against
+	anomalyWindow = 30
+	// anomalyMinPolls is the fewest preceding changes before one can stand out
+	anomalyMinPolls = 10
+	// anomalyZ is how many standard deviations off the recent changes a jump is
+	anomalyZ = 4.0
+	// anomalyMinJumpGB keeps a flat series' first wobble from counting as a jump
+	anomalyMinJumpGB = 0.25
+	// leakPolls is how many polls in a row the KV cache must grow, with no
+	// more requests running than before, to count as a leak
+	leakPolls = 20
+	// anomalyHold is how long the status bar keeps the latest anomaly up
+	anomalyHold = 5 * time.Minute
+	// maxAnomalies is how many anomalies are kept across endpoints
+	maxAnomalies = 50
+)
+
+const anomalyRune = '◆'
+
+// anomaly is one jump or leak found in an endpoint's history
+type anomaly struct {
+	at       time.Time
+	endpoint string
+	chart    string // Title of the chart it's marked on
+	kind     string // "jump" or "leak"
+	detail   string // e.g. "+12.50 GB"
+}
+
+// anomalyCharts are the charts checked for anomalies, with the series they draw
+var anomalyCharts = []struct {
+	title string
+	value func(DataPoint) float64
+}{
+	{"Allocated VRAM", func(dp DataPoint) float64 { return float64(dp.AllocatedVRAMBytes) / gbDivisor }},
+	{"Used KV Cache", func(dp DataPoint) float64 { return float64(dp.UsedKVCacheBytes) / gbDivisor }},
+}
+
+// jumps flags the values that moved off the previous one by more than
+// anomalyZ standard deviations of the recent changes
+func jumps(values []float64) []bool {
+	flags := make([]bool, len(values))
+	deltas := make([]float64, len(values))
+	for i := 1; i < len(values); i++ {
+		deltas[i] = values[i] - values[i-1]
+	}
+	for i := anomalyMinPolls + 1; i < len(values); i++ {
+		window := deltas[max(1, i-anomalyWindow):i]
+		var mean, variance float64
+		for _, d := range window {
+			mean += d
+		}
+		mean /= float64(len(window))
+		for _, d := range window {
+			variance += (d - mean) * (d - mean)
+		}
+		std := math.Sqrt(variance / float64(len(window)))
+		change := math.Abs(deltas[i] - mean)
+		flags[i] = change >= anomalyMinJumpGB && change > anomalyZ*std
+	}
+	return flags
+}
+
+// leaks flags the values that end a run of leakPolls polls of KV cache
+// growth while the running requests didn't grow
+func leaks(kvCache, running []float64) []bool {
+	flags := make([]bool, len(kvCache))
+	grown := 0
+	for i := 1; i < len(kvCache); i++ {
+		if kvCache[i] > kvCache[i-1] {
+			grown++
+		} else {
+			grown = 0
+		}
+		flags[i] = grown >= leakPolls && running[i] <= running[i-leakPolls]
+	}
+	return flags
+}
+
+// anomalyFlags marks the points of the chart called title that are part of
+// an anomaly, one per history point; nil for charts that aren't checked
+func (m *DashboardModel) anomalyFlags(title string) []bool {
+	for _, c := range anomalyCharts {
+		if c.title != title {
+			continue
+		}
+		values := m.getHistory(c.value)
+		flags := jumps(values)
+		if title == "Used KV Cache" {
+			running := m.getHistory(func(dp DataPoint) float64 { return dp.RequestsRunning })
+			for i, leaking := range leaks(values, running) {
+				flags[i] = flags[i] || leaking
+			}
+		}
+		return flags
+	}
+	return nil
+}
+
+// detectAnomalies raises an anomaly for each chart whose newest point starts
+// a jump or a leak
+func (m *DashboardModel) detectAnomalies() {
+	n := len(m.history)
+	if n < 2 {
+		return
+	}
+	endpoint := m.historyKey
+	newest, previous := m.history[n-1], m.history[n-2]
+	for _, c := range anomalyCharts {
+		if flags := jumps(m.getHistory(c.value)); flags[n-1] && !flags[n-2] {
+			m.raiseAnomaly(anomaly{at: newest.Time, endpoint: endpoint, chart: c.title, kind: "jump",
+				detail: m.signedMem(int64((c.value(newest)-c.value(previous))*gbDivisor), m.totalVRAM())})
+		}
+	}
+	running := m.getHistory(func(dp DataPoint) float64 { return dp.RequestsRunning })
+	kvCache := m.getHistory(func(dp DataPoint) float64 { return float64(dp.UsedKVCacheBytes) })
+	if flags := leaks(kvCache, running); flags[n-1] && !flags[n-2] {
+		start := m.history[n-1-leakPolls]
+		m.raiseAnomaly(anomaly{at: newest.Time, endpoint: endpoint, chart: "Used KV Cache", kind: "leak",
+			detail: fmt.Sprintf("%s over %d polls", m.signedMem(newest.UsedKVCacheBytes-start.UsedKVCacheBytes, m.totalVRAM()), leakPolls)})
+	}
+}
+
+func (m *DashboardModel) raiseAnomaly(a anomaly) {
+	m.anomalies = append(m.anomalies, a)
+	if len(m.anomalies) > maxAnomalies {
+		m.anomalies = m.anomalies[len(m.anomalies)-maxAnomalies:]
+	}
+}
+
+// latestAnomaly is the selected endpoint's newest anomaly of the last anomalyHold
+func (m *DashboardModel) latestAnomaly() (anomaly, bool) {
+	if m.selected >= len(m.endpoints) {
+		return anomaly{}, false
+	}
+	name := m.endpoints[m.selected].Name
+	for i := len(m.anomalies) - 1; i >= 0; i-- {
+		a := m.anomalies[i]
+		if time.Since(a.at) > anomalyHold {
+			break
+		}
+		if a.endpoint == name {
+			return a, true
+		}
+	}
+	return anomaly{}, false
+}
+
+// anomalySummary describes a for the status bar, e.g.
+// "Used KV Cache leak +3.20 GiB over 20 polls, 12s ago"
+func anomalySummary(a anomaly) string {
+	return fmt.Sprintf("%s %s %s, %s ago", a.chart, a.kind, a.detail, time.Since(a.at).Truncate(time.Second))
+}
@@ -3,6 +3,7 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 )
@@ -12,11 +13,33 @@ type point struct {
 	val  float64
 }
 
+// axisLabelWidth is the width of the value axis' labels, left of each chart
+const axisLabelWidth = 5
+
 func (m *DashboardModel) renderSparklineChart(values []float64, width, height int, color lipgloss.Color, fixedMax float64, title string) string {
 	if len(values) < 2 {
 		return ""
 	}
 	width, height = ensureMin(width, height, 20, 6)
+	chartWidth := max(10, width-axisLabelWidth-1)
+	chartHeight := max(4, height)
+	gridHeight := max(3, chartHeight-1)
+
+	// Zoomed in or panned back, only part of the history is drawn
+	m.lastChartWidth = chartWidth
+	flags := m.anomalyFlags(title)
+	times := m.chartTimes()
+	start, end := m.chartWindow(len(values), chartWidth)
+	if len(flags) == len(values) {
+		flags = flags[start:end]
+	}
+	if len(times) == len(values) {
+		times = times[start:end]
+	} else {
+		times = nil
+	}
+	newest := end == len(values)
+	values = values[start:end]
 
 	maxVal := fixedMax
 	if fixedMax <= 0 {
@@ -39,19 +62,24 @@ func (m *DashboardModel) renderSparklineChart(values []float64, width, height in
 		minVal = 0
 	}
 
+	threshold, hasThreshold := m.chartThreshold(title)
+	if hasThreshold && threshold > maxVal {
+		// Keep the threshold line on the chart instead of pinning it to the top
+		maxVal = threshold * 1.05
+	}
+
 	if maxVal <= minVal {
 		maxVal = minVal + 1
 	}
 
-	chartWidth := max(10, width)
-	chartHeight := max(4, height)
-	gridHeight := max(3, chartHeight-1)
-
 	displayCount := min(len(values), chartWidth-2)
 	if displayCount < 2 {
 		displayCount = min(len(values), 2)
 	}
 	displayValues := values[len(values)-displayCount:]
+	if times != nil {
+		times = times[len(times)-displayCount:]
+	}
 
 	grid := make([][]rune, gridHeight)
 	for i := range grid {
@@ -73,27 +101,172 @@ func (m *DashboardModel) renderSparklineChart(values []float64, width, height in
 	}
 	grid[gridHeight-1][0] = '└'
 
+	thresholdRow := -1
+	if hasThreshold {
+		thresholdRow = gridHeight - 2 - int(normalizeValue(threshold, minVal, maxVal)*float64(gridHeight-2))
+		thresholdRow = max(0, min(thresholdRow, gridHeight-2))
+	}
+
 	if len(displayValues) > 1 {
 		points := m.calculateChartPoints(displayValues, chartWidth, gridHeight, minVal, maxVal)
-		m.drawChartArea(grid, points, chartWidth, gridHeight)
-		m.drawChartLine(grid, points)
+		switch m.prefs.ChartStyle {
+		case "bars":
+			m.drawChartBars(grid, points, gridHeight)
+		case "area":
+			m.drawChartArea(grid, points, chartWidth, gridHeight)
+		}
+		// Threshold goes over the area fill but under the data line
+		if thresholdRow >= 0 {
+			for j := 1; j < chartWidth; j++ {
+				grid[thresholdRow][j] = thresholdRune
+			}
+		}
+		if m.prefs.ChartStyle != "bars" {
+			m.drawChartLine(grid, points)
+		}
 		m.highlightCurrentPoint(grid, points, chartWidth, gridHeight)
+		if len(flags) == len(values) {
+			for i, anomalous := range flags[len(flags)-displayCount:] {
+				if anomalous {
+					grid[points[i].y][points[i].x] = anomalyRune
+				}
+			}
+		}
 	}
 
 	var b strings.Builder
 	colorStyle := lipgloss.NewStyle().Foreground(color)
+	axisStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(colorMuted))
+	yLabels := valueAxisLabels(minVal, maxVal, gridHeight, m.chartUnit(title))
 
-	if chartHeight > 0 {
-		b.WriteString(strings.Repeat(" ", chartWidth) + "\n")
+	// The threshold line and anomalous points get their own colors
+	markStyles := map[rune]lipgloss.Style{
+		thresholdRune: lipgloss.NewStyle().Foreground(lipgloss.Color(colorRed)),
+		anomalyRune:   lipgloss.NewStyle().Foreground(lipgloss.Color(colorOrange)).Bold(true),
 	}
-
 	for i := 0; i < gridHeight && i < len(grid); i++ {
-		b.WriteString(colorStyle.Render(string(grid[i])) + "\n")
+		b.WriteString(axisStyle.Render(fmt.Sprintf("%*s ", axisLabelWidth, yLabels[i])))
+		if i != thresholdRow && !strings.ContainsRune(string(grid[i]), anomalyRune) {
+			b.WriteString(colorStyle.Render(string(grid[i])) + "\n")
+			continue
+		}
+		// Render the row in runs so the data line keeps its own color
+		var run []rune
+		var mark rune
+		flush := func() {
+			if len(run) == 0 {
+				return
+			}
+			if style, ok := markStyles[mark]; ok {
+				b.WriteString(style.Render(string(run)))
+			} else {
+				b.WriteString(colorStyle.Render(string(run)))
+			}
+			run = run[:0]
+		}
+		for _, r := range grid[i] {
+			kind := rune(0)
+			if _, ok := markStyles[r]; ok {
+				kind = r
+			}
+			if kind != mark {
+				flush()
+				mark = kind
+			}
+			run = append(run, r)
+		}
+		flush()
+		b.WriteString("\n")
 	}
+	b.WriteString(axisStyle.Render(strings.Repeat(" ", axisLabelWidth+1)+timeAxisLabels(times, chartWidth, newest)) + "\n")
 
 	return b.String()
 }
 
+// chartTimes are the times of the points the data panel charts: the charted
+// model's samples, else the totals' history
+func (m *DashboardModel) chartTimes() []time.Time {
+	if m.chartModel != "" {
+		ring := m.modelHistory[m.chartModel]
+		if ring == nil {
+			return nil
+		}
+		samples := ring.list()
+		times := make([]time.Time, len(samples))
+		for i, s := range samples {
+			times[i] = s.Time
+		}
+		return times
+	}
+	times := make([]time.Time, len(m.history))
+	for i, dp := range m.history {
+		times[i] = dp.Time
+	}
+	return times
+}
+
+// chartUnit is the unit the chart titled title is drawn in, "" when it has none
+func (m *DashboardModel) chartUnit(title string) string {
+	for _, charts := range [][]chartDef{dataCharts, modelCharts} {
+		for _, c := range charts {
+			if c.title != title {
+				continue
+			}
+			// Memory is drawn in the units picked with u, not the alert rules' GB
+			if c.unit == "GB" {
+				return m.memUnitFor(m.totalVRAM()).label
+			}
+			return c.unit
+		}
+	}
+	return ""
+}
+
+// valueAxisLabels labels the top, middle and bottom data rows of a chart
+// gridHeight rows high with the values they stand for; the axis row and the
+// rows between get none
+func valueAxisLabels(minVal, maxVal float64, gridHeight int, unit string) []string {
+	labels := make([]string, gridHeight)
+	bottom := gridHeight - 2
+	if bottom <= 0 {
+		labels[0] = formatAxisValue(maxVal, unit)
+		return labels
+	}
+	for _, row := range []int{0, bottom / 2, bottom} {
+		labels[row] = formatAxisValue(minVal+(maxVal-minVal)*float64(bottom-row)/float64(bottom), unit)
+	}
+	return labels
+}
+
+// timeAxisLabels puts how long ago the oldest, middle and newest points
+// shown were polled under a chart width wide, e.g. "-5m    -2m   now". The
+// newest reads "now" unless the chart is panned back. Without times the line
+// is blank.
+func timeAxisLabels(times []time.Time, width int, newest bool) string {
+	line := []rune(strings.Repeat(" ", width))
+	if len(times) < 2 {
+		return string(line)
+	}
+	now := time.Now()
+	last := formatAgo(now.Sub(times[len(times)-1]))
+	if newest {
+		last = "now"
+	}
+	first := formatAgo(now.Sub(times[0]))
+	right := max(0, width-len([]rune(last)))
+	copy(line[right:], []rune(last))
+	// A label that reads the same as the one right of it would only repeat it
+	if len([]rune(first))+1 < right && first != last {
+		copy(line[1:], []rune(first))
+	}
+	mid := formatAgo(now.Sub(times[len(times)/2]))
+	midStart := width/2 - len([]rune(mid))/2
+	if midStart > len([]rune(first))+2 && midStart+len([]rune(mid))+1 < right && mid != last {
+		copy(line[midStart:], []rune(mid))
+	}
+	return string(line)
+}
+
 func (m *DashboardModel) calculateChartPoints(values []float64, width, height int, minVal, maxVal float64) []point {
 	points := make([]point, len(values))
 	for i, val := range values {
@@ -136,6 +309,22 @@ func (m *DashboardModel) drawChartArea(grid [][]rune, points []point, width, hei
 	}
 }
 
+// drawChartBars draws a bar from the axis up to each point, as wide as the
+// gap to the next one, for the "bars" chart style
+func (m *DashboardModel) drawChartBars(grid [][]rune, points []point, height int) {
+	for i, p := range points {
+		right := p.x
+		if i < len(points)-1 {
+			right = max(p.x, points[i+1].x-1)
+		}
+		for x := p.x; x <= right; x++ {
+			for y := p.y; y < height-1; y++ {
+				grid[y][x] = '█'
+			}
+		}
+	}
+}
+
 func (m *DashboardModel) drawChartLine(grid [][]rune, points []point) {
 	for i := 0; i < len(points)-1; i++ {
 		p1, p2 := points[i], points[i+1]
@@ -209,28 +398,60 @@ func (m *DashboardModel) formatMetricValues(title string, val1, val2, val3 int)
 			styleColor(colorItalic).Render(fmt.Sprintf("Total: %d", total)))
 	case "Fragmentation":
 		return styleColor(getPercentColor(float64(val1))).Render(fmt.Sprintf("%.2f%%", float64(val1)))
-	case "Allocated VRAM":
+	case "Allocated VRAM", "Model VRAM":
 		// Show allocated/total with percentage
 		// val1 = allocated MB, val2 = total MB
+		allocated, total := int64(val1)*1024*1024, int64(val2)*1024*1024
 		if val2 <= 0 {
 			// If total is not available, just show allocated
-			gb1 := float64(val1) / 1024.0
-			return styleColor(colorOrange).Render(fmt.Sprintf("%.2f GB", gb1))
+			return styleColor(colorOrange).Render(m.mem(allocated, m.totalVRAM()))
 		}
 		percent := (float64(val1) / float64(val2)) * 100.0
-		gb1 := float64(val1) / 1024.0
-		gb2 := float64(val2) / 1024.0
-		return fmt.Sprintf("%s / %s %s",
-			styleColor(colorOrange).Render(fmt.Sprintf("%.2f", gb1)),
-			styleColor(colorItalic).Render(fmt.Sprintf("%.2f GB", gb2)),
+		used, of := m.memOf(allocated, total)
+		if m.memUnitFor(total).percent() {
+			return styleColor(getPercentColor(percent)).Render(used) + styleColor(colorItalic).Render(of)
+		}
+		return fmt.Sprintf("%s%s %s",
+			styleColor(colorOrange).Render(used),
+			styleColor(colorItalic).Render(of),
 			styleColor(getPercentColor(percent)).Render(fmt.Sprintf("(%.1f%%)", percent)))
-	case "Used KV Cache":
-		// Show in GB, no percentage calculation needed
-		gb := float64(val1) / 1024.0
-		return styleColor(colorGreen).Render(fmt.Sprintf("%.2f GB", gb))
+	case "Used KV Cache", "Model KV Cache":
+		// No percentage calculation needed
+		return styleColor(colorGreen).Render(m.mem(int64(val1)*1024*1024, m.totalVRAM()))
 	case "Prefix Cache Hit Rate":
 		// Show as percentage
 		return styleColor(getPercentColor(float64(val1))).Render(fmt.Sprintf("%.1f%%", float64(val1)))
+	case "GPU Temperature":
+		// val1 = hottest GPU in °C
+		return styleColor(getTemperatureColor(float64(val1))).Render(fmt.Sprintf("%d°C", val1))
+	case "GPU Power":
+		// val1 = draw, val2 = limit, both in W summed over GPUs
+		if val2 <= 0 {
+			return styleColor(colorCyan).Render(fmt.Sprintf("%d W", val1))
+		}
+		percent := (float64(val1) / float64(val2)) * 100.0
+		return fmt.Sprintf("%s / %s %s",
+			styleColor(getPercentColor(percent)).Render(fmt.Sprintf("%d", val1)),
+			styleColor(colorItalic).Render(fmt.Sprintf("%d W", val2)),
+			styleColor(getPercentColor(percent)).Render(fmt.Sprintf("(%.1f%%)", percent)))
+	case "SM Utilization":
+		return styleColor(colorCyan).Render(fmt.Sprintf("%d%%", val1))
+	case "Throughput", "Model Throughput":
+		// val1 = generated tokens/sec (over all models, or the charted one's), val2 = the most seen
+		return fmt.Sprintf("%s %s", styleColor(colorCyan).Render(fmt.Sprintf("%d tok/s", val1)),
+			styleColor(colorItalic).Render(fmt.Sprintf("peak %d", val2)))
+	case "Latency", "Model Latency":
+		// val1 = TTFT in ms, val2 = inter-token latency in µs; 0 when no request finished
+		ttft, interToken := "--", "--"
+		if val1 > 0 {
+			ttft = fmt.Sprintf("%d ms", val1)
+		}
+		if val2 > 0 {
+			interToken = fmt.Sprintf("%.1f ms (%.1f tok/s)", float64(val2)/1000, 1e6/float64(val2))
+		}
+		return fmt.Sprintf("%s %s  %s %s",
+			styleColor(colorItalic).Render("TTFT"), styleColor(colorYellow).Render(ttft),
+			styleColor(colorItalic).Render("ITL"), styleColor(colorYellow).Render(interToken))
 	default:
 		percent := 0.0
 		if val2 > 0 {
@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/muesli/termenv"
+)
+
+// clipboardTimeout bounds a copy tool, which runs on the UI goroutine
+const clipboardTimeout = time.Second
+
+// clipboardTools are the copy commands tried in order, each reading the text
+// on stdin, for the desktop blackbox runs on
+func clipboardTools() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		return [][]string{{"clip.exe"}}
+	}
+	var tools [][]string
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		tools = append(tools, []string{"wl-copy"})
+	}
+	if os.Getenv("DISPLAY") != "" {
+		tools = append(tools, []string{"xclip", "-selection", "clipboard"}, []string{"xsel", "--clipboard", "--input"})
+	}
+	return tools
+}
+
+// copyToClipboard puts text on the clipboard with the desktop's copy tool.
+// Over SSH, or without a tool, it's sent to the terminal as an OSC 52 escape
+// instead, which most terminals put on the clipboard of the machine they run
+// on. It says which way it went: "clipboard" or "terminal".
+func copyToClipboard(text string) (string, error) {
+	if os.Getenv("SSH_TTY") == "" && os.Getenv("SSH_CONNECTION") == "" {
+		var errs []error
+		for _, tool := range clipboardTools() {
+			if _, err := exec.LookPath(tool[0]); err != nil {
+				continue
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), clipboardTimeout)
+			cmd := exec.CommandContext(ctx, tool[0], tool[1:]...)
+			cmd.Stdin = strings.NewReader(text)
+			err := cmd.Run()
+			cancel()
+			if err == nil {
+				return "clipboard", nil
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", tool[0], err))
+		}
+		if len(errs) > 0 {
+			return "", errors.Join(errs...)
+		}
+	}
+	termenv.Copy(text)
+	return "terminal", nil
+}
+
+// yankTarget is what y copies: the highlighted endpoint's URL with the
+// endpoints panel focused, the charted (or first) model's ID with Properties
+// focused, and the selected chart's value with the charts focused
+func (m *DashboardModel) yankTarget() (what, text string) {
+	switch m.focusedPanel {
+	case 0:
+		if m.hovered < len(m.endpoints) {
+			ep := m.endpoints[m.hovered]
+			return ep.Name + " URL", ep.BaseURL
+		}
+	case 1:
+		if m.chartModel != "" {
+			return "model ID", m.chartModel
+		}
+		if m.last != nil && len(m.last.Models) > 0 {
+			return "model ID", m.last.Models[0].ModelID
+		}
+	case 2:
+		if m.last != nil {
+			c := m.selectedChartDef()
+			return c.title, m.chartValueText(c)
+		}
+	}
+	return "", ""
+}
+
+// chartValueText is the newest value of chart c with its unit, e.g. "12.50 GiB"
+func (m *DashboardModel) chartValueText(c chartDef) string {
+	if m.chartModel != "" {
+		ring := m.modelHistory[m.chartModel]
+		if ring == nil {
+			return ""
+		}
+		s := ring.newest()
+		switch c.title {
+		case "Model VRAM":
+			return m.mem(s.AllocatedVRAMBytes, m.totalVRAM())
+		case "Model KV Cache":
+			return m.mem(s.UsedKVCacheBytes, m.totalVRAM())
+		case "Model Throughput":
+			return fmt.Sprintf("%.1f tok/s", s.Throughput)
+		case "Model Latency":
+			return fmt.Sprintf("%.0f ms", s.TTFTSeconds*1000)
+		}
+		return ""
+	}
+	v := m.chartCurrentValue(c)
+	if c.unit == "GB" {
+		return m.mem(int64(v*gbDivisor), m.totalVRAM())
+	}
+	if c.unit == "%" || c.unit == "°C" {
+		return fmt.Sprintf("%.1f%s", v, c.unit)
+	}
+	return fmt.Sprintf("%.1f %s", v, c.unit)
+}
+
+// yank copies text to the clipboard and says so in the status bar
+func (m *DashboardModel) yank(what, text string) {
+	if text == "" {
+		m.shareMessage = "✗ nothing to copy here"
+		return
+	}
+	via, err := copyToClipboard(text)
+	if err != nil {
+		m.shareMessage = "✗ copy failed: " + err.Error()
+		return
+	}
+	m.shareMessage = fmt.Sprintf("✓ copied %s: %s", what, truncateString(text, 40))
+	if via == "terminal" {
+		m.shareMessage += " (via the terminal)"
+	}
+}
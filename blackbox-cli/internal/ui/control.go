@@ -0,0 +1,188 @@
+package ui
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+const controlReplyTimeout = 5 * time.Second
+
+// controlMsg is one command from the control socket; the reply goes back on reply
+type controlMsg struct {
+	args  []string
+	reply chan string
+}
+
+// ControlSample is one history point as written by the export command
+type ControlSample struct {
+	Time               time.Time `json:"time"`
+	AllocatedVRAMBytes int64     `json:"allocated_vram_bytes"`
+	UsedKVCacheBytes   int64     `json:"used_kv_cache_bytes"`
+	PrefixCacheHitRate float64   `json:"prefix_cache_hit_rate"`
+	RequestsRunning    float64   `json:"num_requests_running,omitempty"`
+	RequestsWaiting    float64   `json:"num_requests_waiting,omitempty"`
+	GPUTemperatureC    float64   `json:"gpu_temperature_c,omitempty"`
+	GPUPowerWatts      float64   `json:"gpu_power_w,omitempty"`
+	GPUUtilization     float64   `json:"gpu_utilization_percent,omitempty"`
+	TTFTSeconds        float64   `json:"ttft_seconds,omitempty"`
+	InterTokenSeconds  float64   `json:"inter_token_latency_seconds,omitempty"`
+	Throughput         float64   `json:"generation_tokens_per_second,omitempty"`
+}
+
+// DefaultControlSocket is where the dashboard listens when --control is given without a path
+func DefaultControlSocket() string {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, fmt.Sprintf("blackbox-%d.sock", os.Getuid()))
+}
+
+// ServeControl accepts one-line commands on a unix socket at path and forwards
+// them to the running program, writing back a single reply per connection:
+//
+//	switch <endpoint>   select an endpoint by name
+//	pause | resume      freeze or unfreeze the data panel and charts
+//	toggle-pause
+//	export [file]       current endpoint's history as JSON, to file or the reply
+//	export-models [file] its history per model as a JSON object keyed by model ID
+//	status              selected endpoint, pause state, history length and fleet polling role
+//
+// Replies start with "ok" or "error:". The returned func closes the listener
+// and removes the socket.
+func ServeControl(p *tea.Program, path string) (func(), error) {
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("control socket %s is in use by another dashboard", path)
+	}
+	os.Remove(path) // stale socket from a dashboard that didn't exit cleanly
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	os.Chmod(path, 0600)
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				if !errors.Is(err, net.ErrClosed) {
+					utils.Warn("control socket: %v", err)
+				}
+				return
+			}
+			go handleControlConn(p, conn)
+		}
+	}()
+	return func() {
+		ln.Close()
+		os.Remove(path)
+	}, nil
+}
+
+func handleControlConn(p *tea.Program, conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(2 * controlReplyTimeout))
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil && line == "" {
+		return
+	}
+	args := strings.Fields(line)
+	if len(args) == 0 {
+		fmt.Fprintln(conn, "error: empty command")
+		return
+	}
+	utils.Debug("control: %s", strings.Join(args, " "))
+
+	msg := controlMsg{args: args, reply: make(chan string, 1)}
+	p.Send(msg)
+	select {
+	case reply := <-msg.reply:
+		fmt.Fprintln(conn, reply)
+	case <-time.After(controlReplyTimeout):
+		fmt.Fprintln(conn, "error: dashboard did not respond")
+	}
+}
+
+func (m *DashboardModel) updateControl(msg controlMsg) (tea.Model, tea.Cmd) {
+	reply, cmd := m.runControl(msg.args)
+	msg.reply <- reply
+	return m, cmd
+}
+
+func (m *DashboardModel) runControl(args []string) (string, tea.Cmd) {
+	switch args[0] {
+	case "switch":
+		if len(args) != 2 {
+			return "error: usage: switch <endpoint>", nil
+		}
+		for i, ep := range m.endpoints {
+			if ep.Name == args[1] {
+				if i == m.selected {
+					return "ok already on " + ep.Name, nil
+				}
+				m.selectEndpoint(i)
+				return "ok switched to " + ep.Name, startPolling(m.ctx, m.client, m.selected, m.fetchSequence)
+			}
+		}
+		return fmt.Sprintf("error: no endpoint named %q", args[1]), nil
+	case "pause":
+		m.paused = true
+		return "ok paused", nil
+	case "resume":
+		m.paused = false
+		return "ok resumed", nil
+	case "toggle-pause":
+		m.paused = !m.paused
+		if m.paused {
+			return "ok paused", nil
+		}
+		return "ok resumed", nil
+	case "export":
+		samples := make([]ControlSample, len(m.history))
+		for i, dp := range m.history {
+			samples[i] = ControlSample(dp)
+		}
+		return exportJSON(samples, args, fmt.Sprintf("%d samples", len(samples)))
+	case "export-models":
+		series := m.modelSeries()
+		return exportJSON(series, args, fmt.Sprintf("%d models", len(series)))
+	case "status":
+		name := ""
+		if m.selected < len(m.endpoints) {
+			name = m.endpoints[m.selected].Name
+		}
+		fleet := "follower"
+		if m.coord.Leader() {
+			fleet = "leader"
+		}
+		return fmt.Sprintf("ok endpoint=%s paused=%t samples=%d fleet=%s", name, m.paused, len(m.history), fleet), nil
+	}
+	return fmt.Sprintf("error: unknown command %q (switch, pause, resume, toggle-pause, export, export-models, status)", args[0]), nil
+}
+
+// exportJSON replies with v as JSON, or writes it to the file in args[1] and
+// replies with what was written
+func exportJSON(v any, args []string, what string) (string, tea.Cmd) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "error: " + err.Error(), nil
+	}
+	if len(args) < 2 {
+		return "ok " + string(data), nil
+	}
+	if err := os.WriteFile(args[1], data, 0644); err != nil {
+		return "error: " + err.Error(), nil
+	}
+	return fmt.Sprintf("ok wrote %s to %s", what, args[1]), nil
+}
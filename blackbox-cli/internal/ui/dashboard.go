@@ -2,11 +2,18 @@ package ui
 
 import (
 	"context"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/maxdcmn/blackbox-cli/internal/client"
 	"github.com/maxdcmn/blackbox-cli/internal/config"
+	"github.com/maxdcmn/blackbox-cli/internal/instance"
 	"github.com/maxdcmn/blackbox-cli/internal/model"
+	"github.com/maxdcmn/blackbox-cli/internal/share"
+	"github.com/maxdcmn/blackbox-cli/internal/store"
+	"github.com/maxdcmn/blackbox-cli/internal/telemetry"
+	"github.com/maxdcmn/blackbox-cli/internal/usage"
 	"github.com/maxdcmn/blackbox-cli/internal/utils"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -18,13 +25,22 @@ type DataPoint struct {
 	AllocatedVRAMBytes int64
 	UsedKVCacheBytes   int64
 	PrefixCacheHitRate float64
+	RequestsRunning    float64
+	RequestsWaiting    float64
+	GPUTemperatureC    float64
+	GPUPowerWatts      float64
+	GPUUtilization     float64
+	TTFTSeconds        float64
+	InterTokenSeconds  float64
+	Throughput         float64
 }
 
 type DashboardModel struct {
 	config                  *config.Config
 	endpoints               []config.Endpoint
 	selected                int
-	client                  *client.Client
+	hovered                 int
+	client                  client.MetricsClient
 	interval                time.Duration
 	timeout                 time.Duration
 	width                   int
@@ -33,6 +49,9 @@ type DashboardModel struct {
 	lastErr                 error
 	loaded                  bool
 	history                 []DataPoint
+	historyLimit            int                   // Points history keeps, from the selected endpoint's history_size
+	modelHistory            map[string]*modelRing // The selected endpoint's samples per model, by ModelID
+	chartModel              string                // Model the data panel charts instead of the totals; "" for the totals
 	quitting                bool
 	creating                bool
 	editing                 bool
@@ -46,14 +65,27 @@ type DashboardModel struct {
 	newURL                  string
 	newEp                   string
 	newTO                   string
+	newDesc                 string
 	editOldName             string
+	probing                 bool            // The endpoint being created is being checked
+	probeFailed             bool            // probedEp didn't answer; Enter saves it anyway
+	probedEp                config.Endpoint // Form values last checked
+	probeMessage            string
 	deployModelID           string
 	deployHFToken           string
 	deployPort              string
 	deployMessage           string
 	deploySuccess           bool
+	deployJobID             string // job being polled; empty once it finishes
+	deployStatus            *client.DeployStatus
+	deployClient            client.MetricsClient
+	deployPollFailures      int
+	deployWarming           string // model being warmed up after its deploy; empty when none
 	modelsList              *client.ModelsResponse
 	modelsErr               error
+	modelsIter              *client.ModelIterator // listing the popup is showing; pages for any other are dropped
+	modelsLoading           bool
+	modelsMoreErr           error // a page after the first failed; the models so far stay shown
 	selectedModel           int
 	spindownMessage         string
 	spindownSuccess         bool
@@ -61,7 +93,7 @@ type DashboardModel struct {
 	optimizeMessage         string
 	optimizeSuccess         bool
 	optimizeRestartedModels []string
-	cursorPos               [4]int
+	cursorPos               [5]int
 	metricsScroll           int
 	endpointsScroll         int
 	modelsScroll            int
@@ -71,16 +103,106 @@ type DashboardModel struct {
 	maxBlocksSeen           float64
 	maxFragSeen             float64
 	maxPrefixHitRateSeen    float64
+	maxThroughputSeen       float64
+	selectedChart           int
+	thresholdEditing        bool
+	thresholdValue          float64
+	thresholdOp             string
+	thresholdMessage        string
+	fleet                   map[string]*fleetStatus
+	fleetGen                map[string]int
+	historyCache            *historyCache
+	historyKey              string
+	smoothingAlpha          float64
+	smoothedCharts          map[string]bool
+	chartZoom               int  // Times the charts' time range was halved with +
+	chartPan                int  // Points the charts end before the newest, moved with h/l
+	scrubbing               bool // Timeline (P): every panel shows the poll chartPan back, see scrubSnapshot
+	lastChartWidth          int
+	clientFactory           func(config.Endpoint, time.Duration) client.MetricsClient
+	showingGrid             bool
+	gridPanels              bool // The grid shows the fleet view's panels (F) instead of tiles (g)
+	clients                 map[string]cachedClient
+	kiosk                   bool
+	cycling                 bool
+	cycleDwell              time.Duration
+	cycleGen                int
+	lastKeyAt               time.Time
+	paused                  bool
+	shareMessage            string
+	configMessage           string // Outcome of the last reload of the config file
+	viewing                 *share.Bundle
+	coord                   *instance.Coordinator
+	searching               bool
+	searchQuery             string
+	searchRan               string // query whose results are shown or pending
+	searchPending           bool
+	searchResults           []client.ModelLocation
+	searchErrs              []error
+	searchSelected          int
+
+	cadence       *pollCadence             // Pace of the selected endpoint's polls
+	pollOverrides map[string]time.Duration // Base poll intervals set with [ and ] this run, by endpoint name
+
+	selector  config.Selector // Endpoints of the config shown, from --select
+	groupBy   string          // Tag key the endpoints panel is grouped by; "" for none
+	collapsed map[string]bool // Groups showing only their header, by tag value
+
+	usage         *usage.Store // Daily peaks; nil records nothing
+	store         *store.Store // Chart history on disk; nil keeps it in memory only
+	usageSaved    time.Time
+	showingUsage  bool
+	showingLogs   bool
+	logs          logPane
+	showingQueue  bool
+	queueScroll   int
+	showingAlerts bool
+	alertsScroll  int
+	usageMetric   usage.Metric
+	usageEndpoint int // Endpoint the usage calendar shows
+
+	lastAgg        *model.AggregatedSnapshot // Last poll's aggregates as they came, for the stat view
+	statView       int                       // Index into statViews, cycled with `a`
+	lastComplete   *model.AggregatedSnapshot // Last poll with both stats and models, to fill partial ones from
+	lastCompleteAt time.Time
+	partial        partialData     // Parts of the snapshot shown that came from lastComplete
+	anomalies      []anomaly       // Jumps and leaks found in the charts, oldest first
+	alertEvents    []alertEvent    // Alert rules that started breaching this run, oldest first
+	breaching      map[string]bool // Rules breached as of the last poll, by endpoint and metric
+	alertsSeen     time.Time       // When the alerts list was last opened; older breaches don't flash
+	flashing       bool            // The flash loop is running
+	flashOn        bool            // Flashing charts and tiles are red
+
+	prefs           config.UIPrefs // The config's ui section, with defaults filled in
+	theme           string         // Theme in use: prefs.Theme unless --theme chose one
+	themeFlag       string
+	units           memUnit
+	keys            keyMap
+	settingsActive  bool
+	settingsField   int
+	settingsMessage string
+
+	// ctx is cancelled when the dashboard quits, stopping every request in flight
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 func NewDashboard(cfg *config.Config, interval, timeout time.Duration) *DashboardModel {
 	m := &DashboardModel{
-		config:    cfg,
-		endpoints: cfg.Endpoints,
-		interval:  interval,
-		timeout:   timeout,
-		history:   make([]DataPoint, 0, maxHistorySize),
+		config:       cfg,
+		endpoints:    cfg.Endpoints,
+		interval:     interval,
+		timeout:      timeout,
+		history:      make([]DataPoint, 0, maxHistorySize),
+		historyLimit: maxHistorySize,
+		modelHistory: make(map[string]*modelRing),
+
+		historyCache:   newHistoryCache(historyCacheSize),
+		smoothingAlpha: defaultSmoothingAlpha,
+		smoothedCharts: make(map[string]bool),
 	}
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	m.applyPrefs(cfg.UI)
 	if len(m.endpoints) > 0 {
 		m.selectEndpoint(0)
 	}
@@ -92,24 +214,145 @@ func (m *DashboardModel) selectEndpoint(idx int) {
 		return
 	}
 	m.selected = idx
+	m.hovered = idx
 	ep := m.endpoints[idx]
-	timeout, err := time.ParseDuration(ep.Timeout)
-	if err != nil || timeout == 0 {
-		// Fallback to model's timeout if endpoint timeout is invalid or zero
-		timeout = m.timeout
-		if timeout == 0 {
-			timeout = 10 * time.Second // Final fallback
-		}
+	m.client = m.endpointClient(ep)
+	if m.historyKey != "" {
+		m.historyCache.put(m.historyKey, m.history, m.modelHistory, m.last)
 	}
-	m.client = client.New(ep.BaseURL, ep.Endpoint, timeout)
 	m.loaded = false
 	m.last = nil
 	m.lastErr = nil
-	m.history = make([]DataPoint, 0, maxHistorySize)
+	m.lastAgg = nil
+	m.lastComplete = nil
+	m.partial = partialData{}
+	m.historyLimit = m.historySize(ep)
+	m.history = make([]DataPoint, 0, m.historyLimit)
+	m.modelHistory = make(map[string]*modelRing)
+	m.chartModel = ""
+	m.chartPan = 0
+	m.scrubbing = false
+	// Restore what we had for this endpoint; fresh polls append to it
+	if cached, ok := m.historyCache.get(ep.Name); ok {
+		m.history = cached.history
+		if len(m.history) > m.historyLimit {
+			m.history = m.history[len(m.history)-m.historyLimit:]
+		}
+		m.last = cached.last
+		m.loaded = cached.last != nil
+		// A changed history_size only applies to rings started from now on
+		m.modelHistory = cached.models
+	} else {
+		m.loadStoredHistory(ep.Name)
+	}
+	m.historyKey = ep.Name
+	base := pollInterval(ep, mainPollInterval)
+	if d, ok := m.pollOverrides[ep.Name]; ok {
+		base = d
+	}
+	m.cadence = newPollCadence(ep, base)
 	m.metricsScroll = 0
 	m.fetchSequence++
 }
 
+// SetClientFactory replaces how clients are built for endpoints, e.g. with a
+// clienttest.Fake. Call it before Init.
+func (m *DashboardModel) SetClientFactory(f func(config.Endpoint, time.Duration) client.MetricsClient) {
+	m.clientFactory = f
+	m.clients = nil
+	if len(m.endpoints) > 0 {
+		m.selectEndpoint(m.selected)
+	}
+}
+
+// SetContext makes every request the dashboard sends stop when ctx is done,
+// as well as when the dashboard quits. Call it before Init.
+func (m *DashboardModel) SetContext(ctx context.Context) {
+	m.cancel()
+	m.ctx, m.cancel = context.WithCancel(ctx)
+}
+
+// quit cancels requests still in flight so the program exits right away.
+// The selected endpoint is remembered for the next start, except in kiosk
+// mode where the selection just rotates.
+func (m *DashboardModel) quit() (tea.Model, tea.Cmd) {
+	m.quitting = true
+	m.cancel()
+	if m.usage != nil && m.usage.Dirty() {
+		if err := m.usage.Save(); err != nil {
+			utils.Warn("failed to save usage: %v", err)
+		}
+	}
+	if !m.kiosk && m.selected < len(m.endpoints) {
+		if err := config.SetLastEndpoint(m.endpoints[m.selected].Name); err != nil {
+			utils.Debug("failed to remember the selected endpoint: %v", err)
+		}
+	}
+	return m, tea.Quit
+}
+
+// RestoreSelection selects the endpoint the dashboard had selected when it
+// last quit, else the first one marked "default", else the first one. Call it before Init.
+func (m *DashboardModel) RestoreSelection() {
+	idx := -1
+	if last := config.LastEndpoint(); last != "" {
+		for i, ep := range m.endpoints {
+			if ep.Name == last {
+				idx = i
+				break
+			}
+		}
+	}
+	for i, ep := range m.endpoints {
+		if idx < 0 && ep.Default {
+			idx = i
+		}
+	}
+	if idx > 0 {
+		m.selectEndpoint(idx)
+	}
+}
+
+// SetCoordinator shares background fleet polling with other running
+// dashboards; without one every dashboard polls for itself. Call it before Init.
+func (m *DashboardModel) SetCoordinator(c *instance.Coordinator) {
+	m.coord = c
+}
+
+type cachedClient struct {
+	ep config.Endpoint
+	c  client.MetricsClient
+}
+
+// endpointClient returns the endpoint's client, reusing it across polls and
+// actions until the endpoint's settings change. Callers bound each request with
+// a context deadline; the endpoint timeout is only the fallback.
+func (m *DashboardModel) endpointClient(ep config.Endpoint) client.MetricsClient {
+	if cached, ok := m.clients[ep.Name]; ok && reflect.DeepEqual(cached.ep, ep) {
+		return cached.c
+	}
+	var c client.MetricsClient
+	if m.clientFactory != nil {
+		c = m.clientFactory(ep, m.endpointTimeout(ep))
+	} else {
+		c = client.FromEndpoint(ep, m.endpointTimeout(ep))
+	}
+	if m.clients == nil {
+		m.clients = make(map[string]cachedClient)
+	}
+	m.clients[ep.Name] = cachedClient{ep: ep, c: c}
+	return c
+}
+
+func (m *DashboardModel) endpointTimeout(ep config.Endpoint) time.Duration {
+	// Fallback to model's timeout if endpoint timeout is invalid or zero
+	timeout := m.timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second // Final fallback
+	}
+	return client.EndpointTimeout(ep, timeout)
+}
+
 type tickMsg time.Time
 type snapMsg struct {
 	s          *model.Snapshot
@@ -118,99 +361,95 @@ type snapMsg struct {
 	fetchSeq   int
 }
 type streamMsg struct {
-	s          *model.Snapshot
+	agg        *model.AggregatedSnapshot // Converted on arrival, with the endpoint's settings
 	err        error
 	endpointID int
 }
 
+// mainPollInterval is how often the selected endpoint is polled while it
+// serves a steady load; its cadence moves between its poll bounds
+const mainPollInterval = 5 * time.Second
+
 func (m *DashboardModel) Init() tea.Cmd {
+	m.startLayout()
 	if m.client == nil {
 		return nil
 	}
 	m.fetchSequence++
-	return startPolling(m.client, m.selected, m.fetchSequence)
+	cmds := []tea.Cmd{startPolling(m.ctx, m.client, m.selected, m.fetchSequence), m.syncFleet()}
+	if m.cycling {
+		cmds = append(cmds, m.scheduleCycle())
+	}
+	return tea.Batch(cmds...)
 }
 
 func tick(d time.Duration) tea.Cmd {
 	return tea.Tick(d, func(t time.Time) tea.Msg { return tickMsg(t) })
 }
 
-func fetchSnapshot(c *client.Client, timeout time.Duration, endpointID int, fetchSeq int) tea.Cmd {
+func fetchSnapshot(ctx context.Context, c client.MetricsClient, timeout time.Duration, endpointID int, fetchSeq int) tea.Cmd {
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		ctx, cancel := context.WithTimeout(ctx, timeout)
 		defer cancel()
 		s, err := c.Snapshot(ctx)
 		return snapMsg{s: s, err: err, endpointID: endpointID, fetchSeq: fetchSeq}
 	}
 }
 
-func startPolling(c *client.Client, endpointID int, fetchSeq int) tea.Cmd {
+func startPolling(ctx context.Context, c client.MetricsClient, endpointID int, fetchSeq int) tea.Cmd {
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+		ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
 		defer cancel()
 		aggSnap, err := c.AggregatedSnapshot(ctx, 5)
 		if err != nil {
-			return streamMsg{s: nil, err: err, endpointID: endpointID}
-		}
-		// Convert aggregated snapshot to regular snapshot using average values
-		// Calculate total used KV cache from models to ensure consistency
-		var totalUsedKV int64
-		for _, m := range aggSnap.Models {
-			utils.Debug("Model %s: UsedKVCacheBytes=%d, AllocatedVRAMBytes=%d", m.ModelID, m.UsedKVCacheBytes, m.AllocatedVRAMBytes)
-			totalUsedKV += m.UsedKVCacheBytes
-		}
-		utils.Debug("Total from models: %d, Aggregated avg: %.2f, Sample count: %d", totalUsedKV, aggSnap.UsedKVCacheBytes.Avg, aggSnap.UsedKVCacheBytes.Count)
-		// Use the calculated total from models, or fallback to aggregated avg if sum is 0
-		if totalUsedKV == 0 {
-			totalUsedKV = int64(aggSnap.UsedKVCacheBytes.Avg)
-			utils.Debug("Using aggregated avg as fallback: %d", totalUsedKV)
+			return streamMsg{err: err, endpointID: endpointID}
 		}
-		s := &model.Snapshot{
-			TotalVRAMBytes:     aggSnap.TotalVRAMBytes,
-			AllocatedVRAMBytes:  int64(aggSnap.AllocatedVRAMBytes.Avg),
-			UsedKVCacheBytes:    totalUsedKV,
-			PrefixCacheHitRate:  aggSnap.PrefixCacheHitRate.Avg,
-			Models:              aggSnap.Models,
-		}
-		utils.Debug("Final snapshot: UsedKVCacheBytes=%d, Models count=%d", s.UsedKVCacheBytes, len(s.Models))
-		return streamMsg{s: s, err: nil, endpointID: endpointID}
+		return streamMsg{agg: aggSnap, endpointID: endpointID}
 	}
 }
 
-func scheduleNextPoll(c *client.Client, endpointID int) tea.Cmd {
-	return tea.Tick(5*time.Second, func(time.Time) tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+func scheduleNextPoll(ctx context.Context, c client.MetricsClient, endpointID int, delay time.Duration) tea.Cmd {
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
 		defer cancel()
 		aggSnap, err := c.AggregatedSnapshot(ctx, 5)
 		if err != nil {
-			return streamMsg{s: nil, err: err, endpointID: endpointID}
-		}
-		// Convert aggregated snapshot to regular snapshot using average values
-		// Calculate total used KV cache from models to ensure consistency
-		var totalUsedKV int64
-		for _, m := range aggSnap.Models {
-			utils.Debug("Model %s: UsedKVCacheBytes=%d, AllocatedVRAMBytes=%d", m.ModelID, m.UsedKVCacheBytes, m.AllocatedVRAMBytes)
-			totalUsedKV += m.UsedKVCacheBytes
-		}
-		utils.Debug("Total from models: %d, Aggregated avg: %.2f, Sample count: %d", totalUsedKV, aggSnap.UsedKVCacheBytes.Avg, aggSnap.UsedKVCacheBytes.Count)
-		// Use the calculated total from models, or fallback to aggregated avg if sum is 0
-		if totalUsedKV == 0 {
-			totalUsedKV = int64(aggSnap.UsedKVCacheBytes.Avg)
-			utils.Debug("Using aggregated avg as fallback: %d", totalUsedKV)
-		}
-		s := &model.Snapshot{
-			TotalVRAMBytes:     aggSnap.TotalVRAMBytes,
-			AllocatedVRAMBytes:  int64(aggSnap.AllocatedVRAMBytes.Avg),
-			UsedKVCacheBytes:    totalUsedKV,
-			PrefixCacheHitRate:  aggSnap.PrefixCacheHitRate.Avg,
-			Models:              aggSnap.Models,
-		}
-		utils.Debug("Final snapshot: UsedKVCacheBytes=%d, Models count=%d", s.UsedKVCacheBytes, len(s.Models))
-		return streamMsg{s: s, err: nil, endpointID: endpointID}
+			return streamMsg{err: err, endpointID: endpointID}
+		}
+		return streamMsg{agg: aggSnap, endpointID: endpointID}
 	})
 }
 
 func (m *DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Handled ahead of the popups so the fleet poller keeps running under them
+	if msg, ok := msg.(fleetMsg); ok {
+		return m, m.updateFleet(msg)
+	}
+	if msg, ok := msg.(cycleMsg); ok {
+		return m.updateCycle(msg)
+	}
+	if msg, ok := msg.(controlMsg); ok {
+		return m.updateControl(msg)
+	}
+	if msg, ok := msg.(shareMsg); ok {
+		m.updateShare(msg)
+		return m, nil
+	}
+	if msg, ok := msg.(configReloadMsg); ok {
+		return m, m.applyConfig(msg)
+	}
+	if msg, ok := msg.(logsMsg); ok {
+		return m, m.updateLogs(msg)
+	}
+	if key, ok := msg.(tea.KeyMsg); ok {
+		if m.kiosk {
+			return m.updateKioskKey(key)
+		}
+		m.lastKeyAt = time.Now()
+		m.shareMessage = ""
+		m.configMessage = ""
+	}
+
 	if m.helpActive {
 		if _, ok := msg.(tea.KeyMsg); ok {
 			m.helpActive = false
@@ -236,6 +475,45 @@ func (m *DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if m.optimizing {
 		return m.updateOptimizeMode(msg)
 	}
+	if m.searching {
+		return m.updateSearchMode(msg)
+	}
+	if m.thresholdEditing {
+		// Only keys go to the picker so the chart keeps updating underneath it
+		if key, ok := msg.(tea.KeyMsg); ok {
+			return m.updateThresholdMode(key)
+		}
+	}
+	if m.settingsActive {
+		if key, ok := msg.(tea.KeyMsg); ok {
+			return m.updateSettingsMode(key)
+		}
+	}
+	if m.showingGrid {
+		if key, ok := msg.(tea.KeyMsg); ok {
+			return m.updateGridMode(key)
+		}
+	}
+	if m.showingUsage {
+		if key, ok := msg.(tea.KeyMsg); ok {
+			return m.updateUsageMode(key)
+		}
+	}
+	if m.showingLogs {
+		if key, ok := msg.(tea.KeyMsg); ok {
+			return m.updateLogsMode(key)
+		}
+	}
+	if m.showingQueue {
+		if key, ok := msg.(tea.KeyMsg); ok {
+			return m.updateQueueMode(key)
+		}
+	}
+	if m.showingAlerts {
+		if key, ok := msg.(tea.KeyMsg); ok {
+			return m.updateAlertsMode(key)
+		}
+	}
 
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
@@ -246,28 +524,46 @@ func (m *DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// No longer used with SSE, but keeping for compatibility
 		return m, nil
 
+	case flashMsg:
+		return m, m.updateFlash()
+
 	case snapMsg:
 		if msg.endpointID != m.selected || msg.fetchSeq != m.fetchSequence {
 			return m, nil
 		}
+		if m.paused {
+			return m, nil
+		}
 		m.loaded = true
 		m.lastErr = msg.err
 		if msg.err == nil && msg.s != nil {
 			m.updateHistory(msg.s)
 		}
-		return m, nil
+		return m, m.startFlashing()
 
 	case streamMsg:
 		if msg.endpointID != m.selected {
 			return m, nil
 		}
-		m.loaded = true
-		m.lastErr = msg.err
-		if msg.err == nil && msg.s != nil {
-			m.updateHistory(msg.s)
+		var s *model.Snapshot
+		var load *pollLoad
+		if msg.err == nil && msg.agg != nil {
+			s, load = m.fromAggregated(msg.agg)
+		}
+		// While paused polls keep running but the panels stay frozen
+		if !m.paused {
+			m.loaded = true
+			m.lastErr = msg.err
+			if s != nil {
+				m.lastAgg = msg.agg
+				m.updateHistory(s)
+			}
+		}
+		next := tea.Batch(scheduleNextPoll(m.ctx, m.client, m.selected, m.cadence.next(s, load, msg.err)), m.startFlashing())
+		if msg.err == nil && m.selected < len(m.endpoints) {
+			return m, tea.Batch(next, m.recordUsage(m.endpoints[m.selected].Name, s, load))
 		}
-		// Schedule next poll in 5 seconds
-		return m, scheduleNextPoll(m.client, m.selected)
+		return m, next
 
 	case tea.KeyMsg:
 		return m.handleKey(msg)
@@ -278,47 +574,260 @@ func (m *DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m *DashboardModel) updateHistory(s *model.Snapshot) {
 	m.last = s
-	m.history = append(m.history, DataPoint{
+	// The GPU charts go away on an endpoint that doesn't report them
+	m.selectedChart = min(m.selectedChart, len(m.visibleCharts())-1)
+	t := s.Telemetry()
+	ttft, interToken := s.Latency()
+	dp := DataPoint{
 		Time:               time.Now(),
 		AllocatedVRAMBytes: s.AllocatedVRAMBytes,
 		UsedKVCacheBytes:   s.UsedKVCacheBytes,
 		PrefixCacheHitRate: s.PrefixCacheHitRate,
-	})
-	if len(m.history) > maxHistorySize {
-		m.history = m.history[1:]
+		RequestsRunning:    s.NumRequestsRunning,
+		RequestsWaiting:    s.NumRequestsWaiting,
+		GPUTemperatureC:    t.TemperatureC,
+		GPUPowerWatts:      t.PowerWatts,
+		GPUUtilization:     t.UtilizationPercent,
+		TTFTSeconds:        ttft,
+		InterTokenSeconds:  interToken,
+		Throughput:         s.Throughput(),
+	}
+	m.history = append(m.history, dp)
+	if len(m.history) > m.historyLimit {
+		m.history = m.history[len(m.history)-m.historyLimit:]
+	}
+	m.trackMax(dp)
+	m.storeHistory(dp)
+	m.recordModels(s, dp.Time)
+	m.detectAnomalies()
+	if m.selected < len(m.endpoints) {
+		m.checkAlerts(m.endpoints[m.selected].Name, s, false)
+	}
+	// A panned chart stays on the points it shows
+	if m.chartPan > 0 {
+		m.chartPan++
 	}
+}
 
-	// Track max values for scaling charts
-	allocatedGB := float64(s.AllocatedVRAMBytes) / (1024 * 1024 * 1024)
+// trackMax records the largest values seen for scaling charts
+func (m *DashboardModel) trackMax(dp DataPoint) {
+	allocatedGB := float64(dp.AllocatedVRAMBytes) / (1024 * 1024 * 1024)
 	if allocatedGB > m.maxVRAMSeen {
 		m.maxVRAMSeen = allocatedGB
 	}
 
-	usedKVCacheGB := float64(s.UsedKVCacheBytes) / (1024 * 1024 * 1024)
+	usedKVCacheGB := float64(dp.UsedKVCacheBytes) / (1024 * 1024 * 1024)
 	if usedKVCacheGB > m.maxBlocksSeen {
 		m.maxBlocksSeen = usedKVCacheGB
 	}
 
-	if s.PrefixCacheHitRate > m.maxPrefixHitRateSeen {
-		m.maxPrefixHitRateSeen = s.PrefixCacheHitRate
+	if dp.PrefixCacheHitRate > m.maxPrefixHitRateSeen {
+		m.maxPrefixHitRateSeen = dp.PrefixCacheHitRate
+	}
+
+	if dp.Throughput > m.maxThroughputSeen {
+		m.maxThroughputSeen = dp.Throughput
 	}
 }
 
+// telemetryFeatures names the dashboard features counted by opt-in telemetry
+var telemetryFeatures = map[string]string{
+	"?": "help",
+	",": "settings",
+	"t": "threshold",
+	"C": "carousel",
+	"p": "pause",
+	"x": "share",
+	"X": "export",
+	"y": "yank",
+	"g": "grid",
+	"F": "fleet",
+	"S": "smoothing",
+	"a": "stat_view",
+	"n": "add_endpoint",
+	"e": "edit_endpoint",
+	"d": "remove_endpoint",
+	"r": "refresh",
+	"D": "deploy",
+	"m": "models",
+	"s": "spindown",
+	"o": "optimize",
+	"/": "search",
+	"T": "group_endpoints",
+	"H": "usage_calendar",
+	"L": "logs",
+	"Q": "queue",
+	"[": "poll_interval",
+	"]": "poll_interval",
+	"u": "units",
+	"+": "chart_zoom",
+	"h": "chart_pan",
+	"P": "timeline",
+	"A": "alerts",
+}
+
 func (m *DashboardModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	key := msg.String()
-	if m.creating || m.editing || m.deploying || m.helpActive || m.showingModels || m.spindowning || m.optimizing {
+	key := m.keys.resolve(msg.String())
+	if m.creating || m.editing || m.deploying || m.helpActive || m.showingModels || m.spindowning || m.optimizing || m.searching {
+		return m, nil
+	}
+	if f, ok := telemetryFeatures[key]; ok {
+		telemetry.Feature(f)
+	}
+	if m.readOnlyBlocked(key) {
+		return m, nil
+	}
+	if m.scrubbing && m.scrubKey(key) {
 		return m, nil
 	}
 
 	switch key {
 	case "q", "ctrl+c":
-		m.quitting = true
-		return m, tea.Quit
+		return m.quit()
 	case "?":
 		m.helpActive = !m.helpActive
 		return m, nil
+	case ",":
+		// Theme, units, chart style and start view, saved to the config
+		m.startSettings()
+		return m, nil
 	case "tab":
-		m.focusedPanel = (m.focusedPanel + 1) % 2
+		m.focusedPanel = (m.focusedPanel + 1) % 3
+		return m, nil
+	case "esc":
+		// Back from a model's charts to the totals
+		if m.chartModel != "" {
+			m.chartModel = ""
+			m.selectedChart = 0
+		}
+		return m, nil
+	case "t":
+		// Set alert threshold for the selected chart; a model's charts take none
+		if m.focusedPanel == 2 && m.chartModel == "" && m.last != nil && len(m.endpoints) > 0 && m.selected < len(m.endpoints) {
+			m.startThresholdEdit()
+		}
+		return m, nil
+	case "enter":
+		if m.focusedPanel == 0 && m.hoveringCollapsed() {
+			m.toggleGroup()
+			return m, nil
+		}
+		if m.focusedPanel == 0 && m.hovered != m.selected && m.hovered < len(m.endpoints) {
+			m.selectEndpoint(m.hovered)
+			return m, startPolling(m.ctx, m.client, m.selected, m.fetchSequence)
+		}
+		return m, nil
+	case "C":
+		// Carousel: rotate through endpoints every dwell
+		m.lastKeyAt = time.Time{}
+		return m, m.toggleCarousel()
+	case "p":
+		m.paused = !m.paused
+		return m, nil
+	case "x":
+		// Share: save snapshot + history as a blob (and a gist with GITHUB_TOKEN)
+		return m, m.shareSnapshot()
+	case "X":
+		// Export: snapshot + history as JSON, history as CSV and xlsx, for evidence.
+		// On X rather than x, which share already had.
+		m.exportSnapshot()
+		return m, nil
+	case "y":
+		// Yank the focused panel's endpoint URL, model ID or chart value
+		m.yank(m.yankTarget())
+		return m, nil
+	case "/":
+		// Find a model across all endpoints
+		if len(m.endpoints) > 0 {
+			m.startSearch()
+		}
+		return m, nil
+	case "T":
+		// Group the endpoints panel by the next tag key
+		return m, m.cycleGroupBy()
+	case "z":
+		// Collapse or expand the highlighted endpoint group
+		if m.focusedPanel == 0 {
+			m.toggleGroup()
+		}
+		return m, nil
+	case "g":
+		// Fleet overview grid
+		m.showingGrid, m.gridPanels = true, false
+		m.hovered = m.selected
+		return m, nil
+	case "F":
+		// Fleet view: a panel per endpoint with its VRAM and KV cache
+		m.showingGrid, m.gridPanels = true, true
+		m.hovered = m.selected
+		return m, nil
+	case "+":
+		// Zoom the charts in on the newest (or panned to) points
+		m.zoomCharts(1)
+		return m, nil
+	case "-":
+		m.zoomCharts(-1)
+		m.panCharts(0)
+		return m, nil
+	case "h":
+		// Pan the charts back through history
+		m.panCharts(1)
+		return m, nil
+	case "l":
+		m.panCharts(-1)
+		return m, nil
+	case "P":
+		// Timeline: scrub every panel back through the history
+		m.startTimeline()
+		return m, nil
+	case "H":
+		// Usage calendar of daily peaks
+		m.showingUsage = true
+		m.usageEndpoint = m.selected
+		return m, nil
+	case "Q":
+		// Running and waiting requests per model
+		m.showingQueue = true
+		m.queueScroll = 0
+		return m, nil
+	case "A":
+		// Breaches of the alert thresholds this run
+		m.openAlerts()
+		return m, nil
+	case "L":
+		// Follow the charted (or first) model's container log
+		if m.client != nil {
+			return m, m.startLogs("")
+		}
+		return m, nil
+	case "[":
+		// Poll the selected endpoint twice as often, down to its poll_min
+		m.scalePollInterval(0.5)
+		return m, nil
+	case "]":
+		m.scalePollInterval(2)
+		return m, nil
+	case "u":
+		// GiB, GB, MiB or percent of the VRAM in every panel and chart
+		saved := m.stepSetting(unitsSetting, 1)
+		m.configMessage = "memory in " + m.units.label
+		if m.units.percent() {
+			m.configMessage += " of VRAM"
+		}
+		if strings.HasPrefix(saved, "✗") {
+			m.configMessage = saved
+		}
+		return m, nil
+	case "a":
+		// Window average, p95, p99 or max in the Properties panel and chart values
+		m.cycleStatView()
+		return m, nil
+	case "S":
+		// Toggle EMA smoothing for the selected chart
+		if m.focusedPanel == 2 {
+			title := m.selectedChartDef().title
+			m.smoothedCharts[title] = !m.smoothedCharts[title]
+		}
 		return m, nil
 	case "j", "down":
 		return m.handleDown()
@@ -326,38 +835,45 @@ func (m *DashboardModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleUp()
 	case "n":
 		m.creating = true
+		m.probing = false
+		m.probeFailed = false
+		m.probeMessage = ""
 		m.newName = ""
 		m.newURL = "http://127.0.0.1:6767"
 		m.newEp = "/vram"
 		m.newTO = "10s"
+		m.newDesc = ""
 		m.inputField = 0
-		m.cursorPos = [4]int{0, len(m.newURL), len(m.newEp), len(m.newTO)}
+		m.cursorPos = [5]int{0, len(m.newURL), len(m.newEp), len(m.newTO), 0}
 		return m, nil
 	case "e":
 		if len(m.endpoints) > 0 && m.selected < len(m.endpoints) {
 			ep := m.endpoints[m.selected]
 			m.editing = true
+			m.probeMessage = ""
 			m.editOldName = ep.Name
 			m.newName = ep.Name
 			m.newURL = ep.BaseURL
 			m.newEp = ep.Endpoint
 			m.newTO = ep.Timeout
+			m.newDesc = ep.Description
 			m.inputField = 0
-			m.cursorPos = [4]int{len(m.newName), len(m.newURL), len(m.newEp), len(m.newTO)}
+			m.cursorPos = [5]int{len(m.newName), len(m.newURL), len(m.newEp), len(m.newTO), len(m.newDesc)}
 			return m, nil
 		}
 	case "d":
 		if len(m.endpoints) > 0 && m.selected < len(m.endpoints) {
 			name := m.endpoints[m.selected].Name
 			if err := config.RemoveEndpoint(m.config, name); err == nil {
-				m.endpoints = m.config.Endpoints
+				m.setEndpoints(m.config.Endpoints)
 				if m.selected >= len(m.endpoints) {
 					m.selected = len(m.endpoints) - 1
 				}
 				if len(m.endpoints) > 0 {
 					m.selectEndpoint(m.selected)
-					return m, startPolling(m.client, m.selected, m.fetchSequence)
+					return m, tea.Batch(startPolling(m.ctx, m.client, m.selected, m.fetchSequence), m.syncFleet())
 				}
+				m.syncFleet()
 				m.client = nil
 			}
 		}
@@ -366,7 +882,7 @@ func (m *DashboardModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.loaded = false
 			m.lastErr = nil
 			m.fetchSequence++
-			return m, startPolling(m.client, m.selected, m.fetchSequence)
+			return m, startPolling(m.ctx, m.client, m.selected, m.fetchSequence)
 		}
 	case "D":
 		// Deploy model - only if we have an endpoint selected
@@ -377,36 +893,35 @@ func (m *DashboardModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.deployPort = ""
 			m.deployMessage = ""
 			m.deploySuccess = false
+			m.deployJobID = ""
+			m.deployStatus = nil
+			m.deployWarming = ""
 			m.inputField = 0
-			m.cursorPos = [4]int{0, 0, 0, 0}
+			m.cursorPos = [5]int{}
 			return m, nil
 		}
 	case "m":
 		// Show models list
 		if m.client != nil && len(m.endpoints) > 0 && m.selected < len(m.endpoints) {
 			m.showingModels = true
-			m.modelsList = nil
-			m.modelsErr = nil
 			m.selectedModel = 0
 			m.modelsScroll = 0
 			ep := m.endpoints[m.selected]
-			modelsClient := client.New(ep.BaseURL, ep.Endpoint, m.timeout)
-			return m, fetchModels(modelsClient, m.timeout)
+			modelsClient := m.endpointClient(ep)
+			return m, m.fetchModels(modelsClient)
 		}
 	case "s":
 		// Spindown model - show models list first
 		if m.client != nil && len(m.endpoints) > 0 && m.selected < len(m.endpoints) {
 			m.spindowning = true
-			m.modelsList = nil
-			m.modelsErr = nil
 			m.selectedModel = 0
 			m.modelsScroll = 0
 			m.spindownMessage = ""
 			m.spindownSuccess = false
 			m.spindownInFlight = false
 			ep := m.endpoints[m.selected]
-			modelsClient := client.New(ep.BaseURL, ep.Endpoint, m.timeout)
-			return m, fetchModels(modelsClient, m.timeout)
+			modelsClient := m.endpointClient(ep)
+			return m, m.fetchModels(modelsClient)
 		}
 	case "o":
 		// Optimize models
@@ -415,20 +930,51 @@ func (m *DashboardModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.optimizeMessage = ""
 			m.optimizeSuccess = false
 			ep := m.endpoints[m.selected]
-			optimizeClient := client.New(ep.BaseURL, ep.Endpoint, m.timeout)
-			return m, optimizeModels(optimizeClient, m.timeout)
+			optimizeClient := m.endpointClient(ep)
+			return m, optimizeModels(m.ctx, optimizeClient, m.timeout)
 		}
 	}
 	return m, nil
 }
 
+// readOnlyBlocked reports whether key would deploy to, spin down or optimize
+// the selected endpoint while it's read_only, and says so in the status bar
+func (m *DashboardModel) readOnlyBlocked(key string) bool {
+	switch key {
+	case "D", "s", "o":
+	default:
+		return false
+	}
+	if !m.selectedReadOnly() {
+		return false
+	}
+	m.configMessage = "✗ " + m.endpoints[m.selected].Name + " is read-only"
+	return true
+}
+
+func (m *DashboardModel) selectedReadOnly() bool {
+	return m.selected >= 0 && m.selected < len(m.endpoints) && m.endpoints[m.selected].ReadOnly
+}
+
 func (m *DashboardModel) handleDown() (tea.Model, tea.Cmd) {
 	if m.focusedPanel == 1 {
 		if m.last != nil {
-			// Calculate total rows: 2 base rows + per-model rows (2 per model)
+			// Calculate total rows: 2 base rows + per-model rows (2 per model, plus latency, throughput and requests) + per-GPU rows (2 per GPU)
 			baseRows := 2
 			modelRows := len(m.last.Models) * 2
-			totalRows := baseRows + modelRows
+			for _, model := range m.last.Models {
+				if model.TTFTSeconds > 0 || model.InterTokenLatencySeconds > 0 {
+					modelRows++
+				}
+				if model.GenerationTokensPerSecond > 0 {
+					modelRows++
+				}
+				if model.NumRequestsRunning > 0 || model.NumRequestsWaiting > 0 {
+					modelRows++
+				}
+			}
+			gpuRows := len(m.last.GPUs) * 2
+			totalRows := baseRows + modelRows + gpuRows
 			sizes := calculateContainerSizes(m.width, m.height)
 			maxVisibleRows := sizes.MetricsGrid.Height - 2
 			if totalRows > maxVisibleRows && m.metricsScroll < totalRows-maxVisibleRows {
@@ -436,9 +982,13 @@ func (m *DashboardModel) handleDown() (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 		}
-	} else if m.focusedPanel == 0 && m.selected < len(m.endpoints)-1 {
-		m.selectEndpoint(m.selected + 1)
-		return m, startPolling(m.client, m.selected, m.fetchSequence)
+	} else if m.focusedPanel == 2 {
+		if m.selectedChart < len(m.visibleCharts())-1 {
+			m.selectedChart++
+		}
+	} else if m.focusedPanel == 0 {
+		// Only preview; Enter switches
+		m.moveHover(1)
 	}
 	return m, nil
 }
@@ -449,9 +999,12 @@ func (m *DashboardModel) handleUp() (tea.Model, tea.Cmd) {
 			m.metricsScroll--
 			return m, nil
 		}
-	} else if m.focusedPanel == 0 && m.selected > 0 {
-		m.selectEndpoint(m.selected - 1)
-		return m, startPolling(m.client, m.selected, m.fetchSequence)
+	} else if m.focusedPanel == 2 {
+		if m.selectedChart > 0 {
+			m.selectedChart--
+		}
+	} else if m.focusedPanel == 0 {
+		m.moveHover(-1)
 	}
 	return m, nil
 }
@@ -476,12 +1029,72 @@ func (m *DashboardModel) View() string {
 	if m.optimizing {
 		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.renderOptimizeMode())
 	}
+	if m.searching {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.renderSearchMode())
+	}
+	if m.settingsActive {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.renderSettingsMode())
+	}
 
 	sizes := calculateContainerSizes(m.width, m.height)
+	if m.showingGrid {
+		grid := m.renderGrid(m.width, m.height-sizes.StatusBar.Height)
+		keys := styleColor(colorItalic).Render("h/j/k/l: move  Enter: open  g: back  q: quit")
+		if m.gridPanels {
+			keys = styleColor(colorItalic).Render("h/j/k/l: move  Enter: open  F: back  g: tiles  q: quit")
+		}
+		bar := statusBarStyle.Width(sizes.StatusBar.Width).Height(1).Render(keys)
+		if m.kiosk {
+			bar = m.renderKioskBar(sizes.StatusBar.Width)
+		}
+		return lipgloss.JoinVertical(lipgloss.Left, grid, bar)
+	}
+	if m.showingUsage {
+		calendar := m.renderUsage(m.width, m.height-sizes.StatusBar.Height)
+		keys := styleColor(colorItalic).Render("Tab: VRAM/requests  j/k: endpoint  H: back  q: quit")
+		bar := statusBarStyle.Width(sizes.StatusBar.Width).Height(1).Render(keys)
+		return lipgloss.JoinVertical(lipgloss.Left, calendar, bar)
+	}
+	if m.showingQueue {
+		queue := m.renderQueue(m.width, m.height-sizes.StatusBar.Height)
+		keys := styleColor(colorItalic).Render("j/k: scroll  Q: back  q: quit")
+		bar := statusBarStyle.Width(sizes.StatusBar.Width).Height(1).Render(keys)
+		return lipgloss.JoinVertical(lipgloss.Left, queue, bar)
+	}
+	if m.showingAlerts {
+		alerts := m.renderAlerts(m.width, m.height-sizes.StatusBar.Height)
+		keys := styleColor(colorItalic).Render("j/k: scroll  A: back  q: quit")
+		bar := statusBarStyle.Width(sizes.StatusBar.Width).Height(1).Render(keys)
+		return lipgloss.JoinVertical(lipgloss.Left, alerts, bar)
+	}
+	if m.showingLogs {
+		logs := m.renderLogs(m.width, m.height-sizes.StatusBar.Height)
+		keys := styleColor(colorItalic).Render("f: follow/pause  j/k: scroll  G: newest  Tab: next model  L: back  q: quit")
+		bar := statusBarStyle.Width(sizes.StatusBar.Width).Height(1).Render(keys)
+		return lipgloss.JoinVertical(lipgloss.Left, logs, bar)
+	}
+	if m.scrubbing {
+		// Polls carry on into m.last underneath; only the drawing goes back
+		live := m.last
+		m.last = m.scrubSnapshot()
+		defer func() { m.last = live }()
+	}
 	endpointsPanel := m.renderEndpointsPanel(sizes.Endpoints.Width, sizes.Endpoints.Height, m.focusedPanel == 0)
 	metricsGrid := m.renderMetricsGrid(sizes.MetricsGrid.Width, sizes.MetricsGrid.Height, m.focusedPanel == 1)
-	dataPanel := m.renderDataPanel(sizes.Data.Width, sizes.Data.Height, false)
+	if m.focusedPanel == 0 && m.hovered != m.selected && m.hovered < len(m.endpoints) {
+		metricsGrid = m.renderPreviewCard(sizes.MetricsGrid.Width, sizes.MetricsGrid.Height)
+	}
+	dataPanel := m.renderDataPanel(sizes.Data.Width, sizes.Data.Height, m.focusedPanel == 2)
 	statusBar := m.renderStatusBar(sizes.StatusBar.Width, sizes.StatusBar.Height, m.focusedPanel == 0)
+	if m.thresholdEditing {
+		statusBar = m.renderThresholdBar(sizes.StatusBar.Width)
+	}
+	if m.scrubbing {
+		statusBar = m.renderTimelineBar(sizes.StatusBar.Width)
+	}
+	if m.kiosk {
+		statusBar = m.renderKioskBar(sizes.StatusBar.Width)
+	}
 
 	leftSide := lipgloss.JoinVertical(lipgloss.Left, endpointsPanel, metricsGrid)
 	separator := lipgloss.NewStyle().Foreground(lipgloss.Color(colorDim)).Render("│")
@@ -491,18 +1104,46 @@ func (m *DashboardModel) View() string {
 	if m.helpActive {
 		helpText := `Keyboard Shortcuts
 ?:        - Show this help
+,         - Settings (theme, units, charts)
 q, ctrl+c - Quit
 Tab       - Switch between panels
 j, k      - Navigate/scroll in focused panel
+Enter     - Switch to highlighted endpoint
+t         - Set alert threshold (charts panel)
+S         - Toggle smoothing (charts panel)
++, -      - Zoom charts in/out
+h, l      - Pan charts back/forward through history
+P         - Timeline: scrub all panels back through history (←/→)
+a         - Cycle avg/p95/p99/max of the poll window
+[, ]      - Halve/double the poll interval
+u         - Cycle memory units (GiB, GB, MiB, % of VRAM)
 n         - Create new endpoint
 e         - Edit selected endpoint
 d         - Delete selected endpoint
 D         - Deploy model
-m         - List models
+m         - List models (Enter charts one, Esc back)
 s         - Spindown model
 o         - Optimize models
+/         - Find a model on any endpoint
 r         - Refresh data
-Press any key to close`
+g         - Fleet overview grid
+F         - Fleet view (VRAM and KV cache per endpoint)
+H         - Usage calendar (daily peaks)
+L         - Follow a model's container log
+Q         - Request queue per model
+A         - Alerts list (threshold breaches this run)
+T         - Group endpoints by tag (cycles keys)
+z         - Collapse/expand endpoint group
+C         - Toggle endpoint carousel
+p         - Pause/resume data updates
+x         - Share snapshot (.bbx file, gist with GITHUB_TOKEN)
+X         - Export snapshot and history (.json, .csv, .xlsx;
+            on X because x already shares)
+y         - Copy the endpoint URL, model ID or chart value in focus`
+		if rebound := m.reboundKeys(); len(rebound) > 0 {
+			helpText += "\n\nRebound in ui.keys\n" + strings.Join(rebound, "\n")
+		}
+		helpText += "\nPress any key to close"
 		popup := popupStyle.Width(50).Render(helpText)
 		popup = lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, popup)
 		return lipgloss.JoinVertical(lipgloss.Left, content, popup)
@@ -511,6 +1152,13 @@ Press any key to close`
 	return content
 }
 
+// SetSmoothingAlpha sets the EMA weight of the newest sample for smoothed charts (0 < alpha <= 1)
+func (m *DashboardModel) SetSmoothingAlpha(alpha float64) {
+	if alpha > 0 && alpha <= 1 {
+		m.smoothingAlpha = alpha
+	}
+}
+
 func (m *DashboardModel) getHistory(extractor func(DataPoint) float64) []float64 {
 	values := make([]float64, len(m.history))
 	for i, dp := range m.history {
@@ -521,13 +1169,13 @@ func (m *DashboardModel) getHistory(extractor func(DataPoint) float64) []float64
 
 func (m *DashboardModel) getVRAMHistory() []float64 {
 	return m.getHistory(func(dp DataPoint) float64 {
-		return float64(dp.AllocatedVRAMBytes) / (1024 * 1024 * 1024) // Convert to GB
+		return m.memValue(dp.AllocatedVRAMBytes, m.totalVRAM())
 	})
 }
 
 func (m *DashboardModel) getBlocksHistory() []float64 {
 	return m.getHistory(func(dp DataPoint) float64 {
-		return float64(dp.UsedKVCacheBytes) / (1024 * 1024 * 1024) // Convert to GB
+		return m.memValue(dp.UsedKVCacheBytes, m.totalVRAM())
 	})
 }
 
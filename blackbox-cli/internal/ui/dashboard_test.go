@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/maxdcmn/blackbox-cli/internal/client"
+	"github.com/maxdcmn/blackbox-cli/internal/client/clienttest"
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+)
+
+// newFakeDashboard is a dashboard on one endpoint whose client is fake
+func newFakeDashboard(t *testing.T, fake *clienttest.Fake) *DashboardModel {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	cfg := &config.Config{
+		Endpoints: []config.Endpoint{{Name: "gpu-1", BaseURL: "http://gpu-1:6767", Endpoint: "/vram", Timeout: "2s"}},
+		History:   &config.HistoryStore{Disabled: true},
+	}
+	m := NewDashboard(cfg, time.Second, 2*time.Second)
+	m.SetClientFactory(func(config.Endpoint, time.Duration) client.MetricsClient { return fake })
+	m.Update(tea.WindowSizeMsg{Width: 160, Height: 48})
+	return m
+}
+
+// poll runs one poll of the selected endpoint and feeds its result back
+func poll(m *DashboardModel) {
+	m.Update(startPolling(m.ctx, m.client, m.selected, m.fetchSequence)())
+}
+
+func TestDashboardPoll(t *testing.T) {
+	fake := &clienttest.Fake{Aggregated: &model.AggregatedSnapshot{
+		TotalVRAMBytes:     80 << 30,
+		AllocatedVRAMBytes: model.AggregatedStats{Avg: 60 << 30},
+		UsedKVCacheBytes:   model.AggregatedStats{Avg: 20 << 30, Count: 5},
+		PrefixCacheHitRate: model.AggregatedStats{Avg: 42},
+		Models:             []model.ModelInfo{{ModelID: "Qwen/Qwen2.5-7B-Instruct", Port: 8000}},
+	}}
+	m := newFakeDashboard(t, fake)
+	if cmd := m.Init(); cmd == nil {
+		t.Fatal("Init returned no command with a client set")
+	}
+
+	poll(m)
+	if got := fake.CallCount("AggregatedSnapshot"); got != 1 {
+		t.Fatalf("AggregatedSnapshot called %d times, want 1", got)
+	}
+	if !m.loaded || m.lastErr != nil {
+		t.Fatalf("loaded %t, error %v; want loaded without an error", m.loaded, m.lastErr)
+	}
+	if m.last == nil || m.last.AllocatedVRAMBytes != 60<<30 || m.last.TotalVRAMBytes != 80<<30 {
+		t.Fatalf("last snapshot %+v, want 60 of 80 GiB allocated", m.last)
+	}
+	if len(m.history) != 1 {
+		t.Errorf("%d history points, want 1", len(m.history))
+	}
+	if view := m.View(); strings.Contains(view, "Error:") {
+		t.Errorf("view shows an error after a good poll:\n%s", view)
+	}
+
+	fake.Err = errors.New("connection refused")
+	poll(m)
+	if !errors.Is(m.lastErr, fake.Err) {
+		t.Fatalf("lastErr = %v, want %v", m.lastErr, fake.Err)
+	}
+	if m.last == nil || len(m.history) != 1 {
+		t.Errorf("a failed poll dropped the last snapshot or added a point (%d points)", len(m.history))
+	}
+}
+
+func TestDashboardPollError(t *testing.T) {
+	fake := &clienttest.Fake{Err: errors.New("connection refused")}
+	m := newFakeDashboard(t, fake)
+	m.Init()
+
+	poll(m)
+	if m.last != nil {
+		t.Fatalf("last snapshot %+v after a failed first poll, want none", m.last)
+	}
+	view := m.View()
+	if !strings.Contains(view, "Error:") || !strings.Contains(view, "connection refused") {
+		t.Errorf("view doesn't show the poll error:\n%s", view)
+	}
+
+	fake.Err = nil
+	poll(m)
+	if m.lastErr != nil || m.last == nil {
+		t.Errorf("after a good poll: last %v, error %v; want a snapshot and no error", m.last, m.lastErr)
+	}
+	if view := m.View(); strings.Contains(view, "connection refused") {
+		t.Errorf("view still shows the old error:\n%s", view)
+	}
+}
@@ -2,12 +2,14 @@ package ui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/maxdcmn/blackbox-cli/internal/client"
+	"github.com/maxdcmn/blackbox-cli/internal/keyring"
 )
 
 func (m *DashboardModel) renderDeployMode() string {
@@ -16,6 +18,9 @@ func (m *DashboardModel) renderDeployMode() string {
 
 	fields := []*string{&m.deployModelID, &m.deployHFToken, &m.deployPort}
 	labels := []string{"Model ID: ", "HF Token (optional): ", "Port (optional): "}
+	if m.selected < len(m.endpoints) && m.endpoints[m.selected].HFToken != "" {
+		labels[1] = "HF Token (default from config): "
+	}
 
 	maxLabelWidth := 0
 	for _, label := range labels {
@@ -54,6 +59,11 @@ func (m *DashboardModel) renderDeployMode() string {
 		b.WriteString("\n")
 	}
 
+	if m.deployStatus != nil {
+		b.WriteString("\n")
+		b.WriteString(m.renderDeployProgress())
+	}
+
 	if m.deployMessage != "" {
 		b.WriteString("\n")
 		if m.deploySuccess {
@@ -63,34 +73,120 @@ func (m *DashboardModel) renderDeployMode() string {
 		}
 		b.WriteString("\n")
 	}
+	if m.deployWarming != "" {
+		b.WriteString(styleColor(colorYellow).Render("● Warming up..."))
+		b.WriteString("\n")
+	}
 
 	b.WriteString("\nTab: next field  Enter: deploy  Esc: cancel")
 	return popupStyle.Width(70).Render(b.String())
 }
 
+// renderDeployProgress lists the stages of the polled deploy job, marking
+// finished ones, the current one and, if the job failed, where it stopped
+func (m *DashboardModel) renderDeployProgress() string {
+	status := m.deployStatus
+	current := -1 // queued: no stage started yet
+	for i, stage := range deployStages {
+		if stage.state == status.State {
+			current = i
+		}
+	}
+	finished := m.deployJobID == ""
+
+	var b strings.Builder
+	if current < 0 && !finished {
+		b.WriteString(styleColor(colorMuted).Render("… Queued (job " + status.JobID + ")"))
+		b.WriteString("\n")
+	}
+	for i, stage := range deployStages {
+		done := i < current || (i == current && stage.state == client.DeployStateServing)
+		switch {
+		case done:
+			b.WriteString(styleColor(colorGreen).Render("✓ " + stage.label))
+		case i == current && finished:
+			b.WriteString(styleColor(colorRed).Render("✗ " + stage.label))
+		case i == current:
+			label := stage.label + "..."
+			if status.Progress > 0 {
+				label += fmt.Sprintf(" %.0f%%", status.Progress*100)
+			}
+			b.WriteString(styleColor(colorYellow).Render("● " + label))
+		default:
+			b.WriteString(styleColor(colorDim).Render("○ " + stage.label))
+		}
+		b.WriteString("\n")
+	}
+	if status.Message != "" && !finished {
+		b.WriteString(styleColor(colorMuted).Render(status.Message))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+const (
+	deployPollInterval    = time.Second
+	maxDeployPollFailures = 3
+)
+
+// deployStages are the job states shown as progress steps, in order
+var deployStages = []struct{ state, label string }{
+	{client.DeployStatePulling, "Pulling image"},
+	{client.DeployStateLoading, "Loading weights"},
+	{client.DeployStateServing, "Serving"},
+}
+
 type deployMsg struct {
 	success bool
 	message string
 	port    int
+	jobID   string
 }
 
-func deployModel(c *client.Client, timeout time.Duration, modelID, hfToken, port string) tea.Cmd {
+type deployStatusMsg struct {
+	jobID  string
+	status *client.DeployStatus
+	err    error
+}
+
+// pollDeployStatus fetches the job's state after delay
+func pollDeployStatus(ctx context.Context, c client.MetricsClient, timeout time.Duration, jobID string, delay time.Duration) tea.Cmd {
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		status, err := c.GetDeployStatus(ctx, jobID)
+		if err == nil && status == nil {
+			err = fmt.Errorf("deploy job %s: %w: empty status", jobID, client.ErrBadSchema)
+		}
+		return deployStatusMsg{jobID: jobID, status: status, err: err}
+	})
+}
+
+func deployModel(ctx context.Context, c client.MetricsClient, timeout time.Duration, modelID, hfToken, port string) tea.Cmd {
 	return func() tea.Msg {
+		// A keyring:<name> token is looked up here, off the UI loop
+		hfToken, err := keyring.Resolve(hfToken)
+		if err != nil {
+			return deployMsg{success: false, message: "HF token: " + errorText(err)}
+		}
 		// Use short timeout - just enough to send request and get initial response
 		shortTimeout := 3 * time.Second
 		if shortTimeout > timeout {
 			shortTimeout = timeout
 		}
-		ctx, cancel := context.WithTimeout(context.Background(), shortTimeout)
+		ctx, cancel := context.WithTimeout(ctx, shortTimeout)
 		defer cancel()
 
 		resp, err := c.DeployModel(ctx, modelID, hfToken, port)
 		if err != nil {
-			// If timeout or network error, assume deployment started
+			// Servers without async deploy block until the model is up
 			if ctx.Err() == context.DeadlineExceeded {
-				return deployMsg{success: true, message: "I hope it's being deployed! (request sent, check status with 'm')"}
+				return deployMsg{success: true, message: fmt.Sprintf("Request sent; no reply within %s, so progress is unknown (check status with 'm')", shortTimeout)}
 			}
-			return deployMsg{success: false, message: err.Error()}
+			return deployMsg{success: false, message: errorText(err)}
+		}
+		if resp.JobID != "" && resp.Success {
+			return deployMsg{success: true, jobID: resp.JobID}
 		}
 
 		msg := "Deployment " + resp.Message
@@ -106,28 +202,51 @@ func (m *DashboardModel) updateDeployMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case deployMsg:
 		m.deployMessage = msg.message
 		m.deploySuccess = msg.success
+		if msg.jobID != "" {
+			m.deployJobID = msg.jobID
+			m.deployStatus = &client.DeployStatus{JobID: msg.jobID, State: client.DeployStateQueued}
+			m.deployPollFailures = 0
+			return m, pollDeployStatus(m.ctx, m.deployClient, m.timeout, msg.jobID, deployPollInterval)
+		}
 		if msg.success {
 			// Refresh data after successful deploy
 			m.fetchSequence++
-			return m, fetchSnapshot(m.client, m.timeout, m.selected, m.fetchSequence)
+			return m, tea.Batch(fetchSnapshot(m.ctx, m.client, m.timeout, m.selected, m.fetchSequence), m.startWarmup(msg.port))
 		}
 		return m, nil
 
+	case deployStatusMsg:
+		return m.updateDeployStatus(msg)
+
+	case warmupMsg:
+		return m.updateWarmup(msg)
+
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "esc":
+			// The job keeps running on the server; only the polling stops
 			m.deploying = false
 			m.deployMessage = ""
 			m.deploySuccess = false
+			m.deployJobID = ""
+			m.deployStatus = nil
+			m.deployWarming = ""
 			return m, nil
 		case "enter":
-			if m.deployModelID == "" {
+			if m.deployModelID == "" || m.deployJobID != "" {
 				return m, nil
 			}
 			// Deploy the model
 			ep := m.endpoints[m.selected]
-			deployClient := client.New(ep.BaseURL, ep.Endpoint, m.timeout)
-			return m, deployModel(deployClient, m.timeout, m.deployModelID, m.deployHFToken, m.deployPort)
+			m.deployClient = m.endpointClient(ep)
+			m.deployStatus = nil
+			m.deployMessage = ""
+			m.deployWarming = ""
+			hfToken := m.deployHFToken
+			if hfToken == "" {
+				hfToken = ep.HFToken
+			}
+			return m, deployModel(m.ctx, m.deployClient, m.timeout, m.deployModelID, hfToken, m.deployPort)
 		case "tab":
 			m.ensureDeployCursorInBounds()
 			m.inputField = (m.inputField + 1) % 3
@@ -184,6 +303,47 @@ func (m *DashboardModel) updateDeployMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m *DashboardModel) updateDeployStatus(msg deployStatusMsg) (tea.Model, tea.Cmd) {
+	if msg.jobID != m.deployJobID {
+		return m, nil // popup was closed or a newer job started
+	}
+	if msg.err != nil {
+		m.deployPollFailures++
+		if errors.Is(msg.err, client.ErrNotFound) || m.deployPollFailures >= maxDeployPollFailures {
+			m.deployJobID = ""
+			m.deploySuccess = false
+			m.deployMessage = "Lost track of deploy job: " + errorText(msg.err)
+			return m, nil
+		}
+		return m, pollDeployStatus(m.ctx, m.deployClient, m.timeout, msg.jobID, deployPollInterval)
+	}
+	m.deployPollFailures = 0
+
+	switch msg.status.State {
+	case client.DeployStateServing:
+		m.deployStatus = msg.status
+		m.deployJobID = ""
+		m.deploySuccess = true
+		m.deployMessage = "Model is serving"
+		if msg.status.Port > 0 {
+			m.deployMessage += fmt.Sprintf(" (port: %d)", msg.status.Port)
+		}
+		m.fetchSequence++
+		return m, tea.Batch(fetchSnapshot(m.ctx, m.client, m.timeout, m.selected, m.fetchSequence), m.startWarmup(msg.status.Port))
+	case client.DeployStateFailed:
+		// Keep the last stage so the progress list shows where it stopped
+		m.deployJobID = ""
+		m.deploySuccess = false
+		m.deployMessage = "Deployment failed"
+		if msg.status.Message != "" {
+			m.deployMessage += ": " + msg.status.Message
+		}
+		return m, nil
+	}
+	m.deployStatus = msg.status
+	return m, pollDeployStatus(m.ctx, m.deployClient, m.timeout, msg.jobID, deployPollInterval)
+}
+
 func (m *DashboardModel) getDeployFieldValue() *string {
 	fields := []*string{&m.deployModelID, &m.deployHFToken, &m.deployPort}
 	if m.inputField >= 0 && m.inputField < len(fields) {
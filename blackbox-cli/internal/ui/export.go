@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/export"
+	"github.com/maxdcmn/blackbox-cli/internal/share"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+// historyColumns are the CSV and xlsx exports' columns, named as in the JSON
+var historyColumns = []string{
+	"time", "allocated_vram_bytes", "used_kv_cache_bytes", "prefix_cache_hit_rate",
+	"num_requests_running", "num_requests_waiting", "gpu_temperature_c", "gpu_power_w",
+	"gpu_utilization_percent", "ttft_seconds", "inter_token_latency_seconds", "generation_tokens_per_second",
+}
+
+// exportSnapshot writes the selected endpoint's snapshot and history to a
+// .json file in the working directory, and the history alone to a .csv and
+// an .xlsx next to it for spreadsheets. Scrubbed back, the snapshot is the poll scrubbed to.
+func (m *DashboardModel) exportSnapshot() {
+	if m.selected >= len(m.endpoints) || m.last == nil {
+		m.shareMessage = "✗ nothing to export yet"
+		return
+	}
+	b := m.bundle()
+	if m.scrubbing {
+		b.Snapshot = m.scrubSnapshot()
+		b.CapturedAt = m.scrubPoint().Time
+	}
+	b = b.Redacted()
+	stem := fileStem(b)
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err == nil {
+		err = os.WriteFile(stem+".json", append(data, '\n'), 0644)
+	}
+	if err == nil {
+		err = writeHistoryCSV(stem+".csv", b.History)
+	}
+	if err == nil {
+		err = writeHistoryXLSX(stem+".xlsx", b.Endpoint, b.History)
+	}
+	if err != nil {
+		m.shareMessage = "✗ export failed: " + err.Error()
+		return
+	}
+	m.shareMessage = "✓ exported " + stem + ".json, .csv and .xlsx"
+	utils.Info("exported %s with %d samples to %s.json, .csv and .xlsx", b.Endpoint, len(b.History), stem)
+}
+
+func writeHistoryCSV(path string, history []share.Sample) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	w.Write(historyColumns)
+	num := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	for _, s := range history {
+		w.Write([]string{
+			s.Time.Format(time.RFC3339), strconv.FormatInt(s.AllocatedVRAMBytes, 10), strconv.FormatInt(s.UsedKVCacheBytes, 10),
+			num(s.PrefixCacheHitRate), num(s.RequestsRunning), num(s.RequestsWaiting), num(s.GPUTemperatureC),
+			num(s.GPUPowerWatts), num(s.GPUUtilization), num(s.TTFTSeconds), num(s.InterTokenSeconds), num(s.Throughput),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeHistoryXLSX writes history as one sheet named after the endpoint,
+// with the CSV's columns and numbers as numbers
+func writeHistoryXLSX(path, endpoint string, history []share.Sample) error {
+	header := make([]interface{}, len(historyColumns))
+	for i, c := range historyColumns {
+		header[i] = c
+	}
+	rows := [][]interface{}{header}
+	for _, s := range history {
+		rows = append(rows, []interface{}{
+			s.Time, s.AllocatedVRAMBytes, s.UsedKVCacheBytes,
+			s.PrefixCacheHitRate, s.RequestsRunning, s.RequestsWaiting, s.GPUTemperatureC,
+			s.GPUPowerWatts, s.GPUUtilization, s.TTFTSeconds, s.InterTokenSeconds, s.Throughput,
+		})
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := export.WriteXLSX(f, []export.Sheet{{Name: endpoint, Rows: rows}}); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,329 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/maxdcmn/blackbox-cli/internal/client"
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+	"github.com/maxdcmn/blackbox-cli/internal/instance"
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+)
+
+const (
+	fleetHistorySize   = 20
+	sparklineWidth     = 8
+	availabilityWindow = 2 * time.Hour
+	// forecastHorizon is the furthest out a VRAM forecast is shown; beyond it
+	// the trend of a few polls is noise
+	forecastHorizon = 24 * time.Hour
+)
+
+var sparkRunes = []rune("▁▂▃▄▅▆▇█")
+
+// fleetFields are the snapshot fields tiles, sparklines, the preview card and
+// alert badges read; servers that support projections send only these
+var fleetFields = []string{"total_vram_bytes", "allocated_vram_bytes", "used_kv_cache_bytes", "prefix_cache_hit_rate", "models"}
+
+// fleetStatus is what the background poller last saw for one endpoint
+type fleetStatus struct {
+	vramPercent []float64
+	allocated   []model.VRAMSample // The same polls' allocated VRAM, for the forecast
+	last        *model.Snapshot
+	lastErr     error
+	updated     time.Time
+	polls       []pollOutcome
+	shared      bool // last result came from another dashboard's poller
+	cadence     *pollCadence
+}
+
+type pollOutcome struct {
+	at time.Time
+	ok bool
+}
+
+type fleetMsg struct {
+	name   string
+	gen    int
+	s      *model.Snapshot
+	err    error
+	at     time.Time
+	shared bool
+}
+
+// syncFleet starts a poll loop for every endpoint that doesn't have one yet.
+// Loops for removed or renamed endpoints stop on their next message.
+func (m *DashboardModel) syncFleet() tea.Cmd {
+	if m.fleet == nil {
+		m.fleet = make(map[string]*fleetStatus)
+		m.fleetGen = make(map[string]int)
+	}
+	var cmds []tea.Cmd
+	for _, ep := range m.endpoints {
+		if _, ok := m.fleet[ep.Name]; ok {
+			continue
+		}
+		m.fleet[ep.Name] = &fleetStatus{cadence: newPollCadence(ep, pollInterval(ep, m.fleetInterval()))}
+		m.fleetGen[ep.Name]++
+		cmds = append(cmds, m.pollFleet(ep, m.fleetGen[ep.Name], 0))
+	}
+	for name := range m.fleet {
+		if _, ok := m.endpointByName(name); !ok {
+			delete(m.fleet, name)
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *DashboardModel) fleetInterval() time.Duration {
+	if m.interval <= 0 {
+		return 5 * time.Second
+	}
+	return m.interval
+}
+
+// pollFleet fetches one endpoint after delay. When another dashboard leads,
+// its cached result is used unless it is missing or stale, and a follower
+// takes over polling as soon as the leader's lock is free.
+func (m *DashboardModel) pollFleet(ep config.Endpoint, gen int, delay time.Duration) tea.Cmd {
+	c, coord, root := m.endpointClient(ep), m.coord, m.ctx
+	timeout := m.endpointTimeout(ep)
+	// The leader polls an idle endpoint as slowly as poll_max
+	_, slowest := pollBounds(ep)
+	maxAge := max(3*pollInterval(ep, m.fleetInterval()), 2*slowest) + timeout
+	key := instance.Key(ep.Name, ep.BaseURL, ep.Endpoint)
+	name := ep.Name
+	fetch := func() tea.Msg {
+		if !coord.TryLead() {
+			if e, ok := coord.Lookup(key, maxAge); ok {
+				return fleetMsg{name: name, gen: gen, s: e.Snapshot, err: e.Err(), at: e.Updated, shared: true}
+			}
+		}
+		ctx, cancel := context.WithTimeout(client.WithFields(root, fleetFields...), timeout)
+		defer cancel()
+		s, err := c.Snapshot(ctx)
+		coord.Publish(key, s, err)
+		return fleetMsg{name: name, gen: gen, s: s, err: err, at: time.Now()}
+	}
+	if delay == 0 {
+		return fetch
+	}
+	return tea.Tick(delay, func(time.Time) tea.Msg { return fetch() })
+}
+
+func (m *DashboardModel) updateFleet(msg fleetMsg) tea.Cmd {
+	ep, ok := m.endpointByName(msg.name)
+	st := m.fleet[msg.name]
+	if !ok || st == nil || m.fleetGen[msg.name] != msg.gen {
+		return nil
+	}
+	if msg.shared && !msg.at.After(st.updated) {
+		// The leader hasn't polled since we last looked
+		return m.pollFleet(ep, msg.gen, pollInterval(ep, m.fleetInterval()))
+	}
+	// Followers check the leader's cache at the base interval; it picks the pace
+	next := pollInterval(ep, m.fleetInterval())
+	if !msg.shared {
+		next = st.cadence.next(msg.s, nil, msg.err)
+	}
+	st.updated = msg.at
+	st.shared = msg.shared
+	st.lastErr = msg.err
+	st.polls = append(st.polls, pollOutcome{at: st.updated, ok: msg.err == nil})
+	for len(st.polls) > 0 && st.updated.Sub(st.polls[0].at) > availabilityWindow {
+		st.polls = st.polls[1:]
+	}
+	if msg.err == nil && msg.s != nil {
+		st.last = msg.s
+		pct := 0.0
+		if msg.s.TotalVRAMBytes > 0 {
+			pct = float64(msg.s.AllocatedVRAMBytes) / float64(msg.s.TotalVRAMBytes) * 100
+		}
+		st.vramPercent = append(st.vramPercent, pct)
+		if len(st.vramPercent) > fleetHistorySize {
+			st.vramPercent = st.vramPercent[1:]
+		}
+		st.allocated = append(st.allocated, model.VRAMSample{At: msg.at, AllocatedVRAMBytes: msg.s.AllocatedVRAMBytes})
+		if len(st.allocated) > fleetHistorySize {
+			st.allocated = st.allocated[1:]
+		}
+		// The selected endpoint's own polls check its alerts, with every figure
+		if m.selected >= len(m.endpoints) || m.endpoints[m.selected].Name != msg.name {
+			m.checkAlerts(msg.name, msg.s, true)
+		}
+		return tea.Batch(m.pollFleet(ep, msg.gen, next), m.recordUsage(msg.name, msg.s, nil), m.startFlashing())
+	}
+
+	return m.pollFleet(ep, msg.gen, next)
+}
+
+// vramForecast fits the endpoint's recent polls; false unless its VRAM fills
+// up within forecastHorizon at the current growth
+func (st *fleetStatus) vramForecast() (model.Forecast, bool) {
+	if st == nil || st.last == nil {
+		return model.Forecast{}, false
+	}
+	f, ok := model.ForecastVRAM(st.allocated, st.last.TotalVRAMBytes)
+	if !ok || f.SecondsToFull <= 0 || f.TimeToFull() > forecastHorizon {
+		return model.Forecast{}, false
+	}
+	return f, true
+}
+
+// getForecastColor colors a time to full VRAM by how soon it is
+func getForecastColor(d time.Duration) string {
+	if d < 15*time.Minute {
+		return colorRed
+	} else if d < time.Hour {
+		return colorOrange
+	}
+	return colorYellow
+}
+
+func (m *DashboardModel) endpointByName(name string) (config.Endpoint, bool) {
+	for _, ep := range m.endpoints {
+		if ep.Name == name {
+			return ep, true
+		}
+	}
+	return config.Endpoint{}, false
+}
+
+// renderSparkline draws the last width VRAM% samples on a fixed 0-100 scale
+func renderSparkline(values []float64, width int) string {
+	return renderScaledSparkline(values, width, 100)
+}
+
+// renderScaledSparkline draws the last width samples on a 0-top scale
+func renderScaledSparkline(values []float64, width int, top float64) string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", width-len(values)))
+	for _, v := range values {
+		idx := int(normalizeValue(v, 0, top) * float64(len(sparkRunes)-1))
+		b.WriteRune(sparkRunes[idx])
+	}
+	return b.String()
+}
+
+// renderPreviewCard summarises the hovered endpoint from fleet data without switching to it
+func (m *DashboardModel) renderPreviewCard(width, height int) string {
+	width, height = ensureMin(width, height, 20, 5)
+	ep := m.endpoints[m.hovered]
+	st := m.fleet[ep.Name]
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(colorText)).Bold(true)
+	contentWidth := max(1, width-4)
+
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(colorText)).Render("Preview: "+truncateString(ep.Name, contentWidth-9)) + "\n\n")
+	b.WriteString(fmt.Sprintf("%s %s\n", labelStyle.Render("URL:"), truncateString(ep.BaseURL+ep.Endpoint, contentWidth-5)))
+	if ep.Description != "" {
+		b.WriteString(styleColor(colorItalic).Italic(true).Render(truncateString(ep.Description, contentWidth)) + "\n")
+	}
+	if ep.Owner != "" {
+		b.WriteString(fmt.Sprintf("%s %s\n", labelStyle.Render("Owner:"), truncateString(ep.Owner, contentWidth-7)))
+	}
+	if ep.OnCall != "" {
+		b.WriteString(fmt.Sprintf("%s %s\n", labelStyle.Render("On-call:"), truncateString(ep.OnCall, contentWidth-9)))
+	}
+	if ep.Notes != "" {
+		b.WriteString(styleColor(colorMuted).Render(truncateString(ep.Notes, contentWidth)) + "\n")
+	}
+
+	switch {
+	case st == nil || st.updated.IsZero():
+		b.WriteString(fmt.Sprintf("%s %s\n", labelStyle.Render("Health:"), styleColor(colorMuted).Render("waiting for first poll")))
+	case errors.Is(st.lastErr, client.ErrUnauthorized):
+		b.WriteString(fmt.Sprintf("%s %s\n", labelStyle.Render("Health:"), styleColor(colorRed).Render("✗ auth required")))
+	case st.lastErr != nil:
+		b.WriteString(fmt.Sprintf("%s %s\n", labelStyle.Render("Health:"), styleColor(colorRed).Render("✗ unreachable")))
+	default:
+		b.WriteString(fmt.Sprintf("%s %s\n", labelStyle.Render("Health:"), styleColor(colorGreen).Render("✓ ok")))
+	}
+	if st != nil && st.last != nil {
+		pct := 0.0
+		if len(st.vramPercent) > 0 {
+			pct = st.vramPercent[len(st.vramPercent)-1]
+		}
+		allocated, total := m.memOf(st.last.AllocatedVRAMBytes, st.last.TotalVRAMBytes)
+		b.WriteString(fmt.Sprintf("%s %s%s (%s)\n", labelStyle.Render("Allocated VRAM:"),
+			allocated, total,
+			styleColor(getPercentColor(pct)).Render(fmt.Sprintf("%.1f%%", pct))))
+		b.WriteString(fmt.Sprintf("%s %d\n", labelStyle.Render("Models:"), len(st.last.Models)))
+		if f, ok := st.vramForecast(); ok {
+			b.WriteString(fmt.Sprintf("%s %s\n", labelStyle.Render("Forecast:"), styleColor(getForecastColor(f.TimeToFull())).Render(truncateString(f.Summary(), contentWidth-10))))
+		}
+		if breached := m.breachedAlerts(ep.Name, st.last); len(breached) > 0 {
+			b.WriteString(fmt.Sprintf("%s %s\n", labelStyle.Render("Alerting:"), styleColor(colorRed).Bold(true).Render(truncateString(alertSummary(breached, ep), contentWidth-10))))
+		}
+	}
+	if st != nil && !st.updated.IsZero() {
+		updated := time.Since(st.updated).Truncate(time.Second).String() + " ago"
+		if st.shared {
+			updated += styleColor(colorMuted).Render(" (polled by another dashboard)")
+		}
+		b.WriteString(fmt.Sprintf("%s %s\n", labelStyle.Render("Updated:"), updated))
+		if c := st.cadence; !st.shared && c.state != pollStateUnknown {
+			b.WriteString(fmt.Sprintf("%s every %s (%s, %s-%s)\n", labelStyle.Render("Polling:"), c.cur, c.state, c.min, c.max))
+		}
+		strip, pct := renderAvailability(st.polls, time.Now(), min(contentWidth, 60))
+		b.WriteString(fmt.Sprintf("%s %.1f%% over the last %gh\n%s\n", labelStyle.Render("Availability:"), pct, availabilityWindow.Hours(), strip))
+	}
+	if st != nil && st.lastErr != nil {
+		b.WriteString(fmt.Sprintf("%s %s\n", labelStyle.Render("Last error:"), styleColor(colorRed).Render(truncateString(errorText(st.lastErr), contentWidth-12))))
+	}
+	b.WriteString("\n" + styleColor(colorItalic).Render("Enter: switch to this endpoint"))
+
+	m.fillToHeight(&b, b.String(), width, height, colorBg)
+	return borderStyle(width, height, false).Render(b.String())
+}
+
+// renderAvailability draws the poll log over availabilityWindow, one cell per time
+// slice: green when every poll in it succeeded, red when any failed, and a dim gap
+// where no poll landed (dashboard not running or polls stalled). The percentage
+// is successful polls over all polls.
+func renderAvailability(polls []pollOutcome, now time.Time, width int) (string, float64) {
+	width = max(1, width)
+	slice := availabilityWindow / time.Duration(width)
+	start := now.Add(-availabilityWindow)
+
+	// 0 = no data, 1 = all ok, 2 = failure seen
+	cells := make([]int, width)
+	okCount := 0
+	for _, p := range polls {
+		if p.ok {
+			okCount++
+		}
+		idx := max(0, min(int(p.at.Sub(start)/slice), width-1))
+		if !p.ok {
+			cells[idx] = 2
+		} else if cells[idx] == 0 {
+			cells[idx] = 1
+		}
+	}
+
+	var b strings.Builder
+	for _, c := range cells {
+		switch c {
+		case 0:
+			b.WriteString(styleColor(colorDim).Render("·"))
+		case 1:
+			b.WriteString(styleColor(colorGreen).Render("█"))
+		case 2:
+			b.WriteString(styleColor(colorRed).Render("█"))
+		}
+	}
+
+	pct := 0.0
+	if len(polls) > 0 {
+		pct = float64(okCount) / float64(len(polls)) * 100
+	}
+	return b.String(), pct
+}
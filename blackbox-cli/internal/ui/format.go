@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// memUnit is how memory sizes are written in the panels and charts
+type memUnit struct {
+	divisor float64 // Bytes in one unit; 0 writes sizes as a percent of their total
+	label   string
+	format  string
+}
+
+var memUnits = map[string]memUnit{
+	"gib":     {divisor: gbDivisor, label: "GiB", format: "%.2f"},
+	"gb":      {divisor: 1e9, label: "GB", format: "%.2f"},
+	"mib":     {divisor: 1024 * 1024, label: "MiB", format: "%.0f"},
+	"percent": {label: "%", format: "%.1f"},
+}
+
+func (u memUnit) percent() bool {
+	return u.divisor == 0
+}
+
+// memUnitFor is the unit a size out of total is written in: the one in use,
+// or GiB for a percent of a total that isn't known
+func (m *DashboardModel) memUnitFor(total int64) memUnit {
+	if m.units.percent() && total <= 0 {
+		return memUnits["gib"]
+	}
+	return m.units
+}
+
+// memValue is bytes out of total in the units in use, e.g. what charts plot
+func (m *DashboardModel) memValue(bytes, total int64) float64 {
+	u := m.memUnitFor(total)
+	if u.percent() {
+		return float64(bytes) / float64(total) * 100
+	}
+	return float64(bytes) / u.divisor
+}
+
+// mem writes bytes out of total in the units in use, e.g. "12.50 GiB" or "15.6%"
+func (m *DashboardModel) mem(bytes, total int64) string {
+	u := m.memUnitFor(total)
+	value := fmt.Sprintf(u.format, m.memValue(bytes, total))
+	if u.percent() {
+		return value + "%"
+	}
+	return value + " " + u.label
+}
+
+// memOf writes used out of total in two parts, "12.50" and " / 80.00 GiB",
+// so they can be styled apart. As a percent, it's "15.6%" of " of 80.00 GiB".
+func (m *DashboardModel) memOf(used, total int64) (string, string) {
+	u := m.memUnitFor(total)
+	if u.percent() {
+		return m.mem(used, total), " of " + fmt.Sprintf("%.2f GiB", float64(total)/gbDivisor)
+	}
+	return fmt.Sprintf(u.format, m.memValue(used, total)), " / " + m.mem(total, total)
+}
+
+// signedMem writes a change of bytes out of total with its sign, e.g. "+1.20 GiB"
+func (m *DashboardModel) signedMem(bytes, total int64) string {
+	sign := "+"
+	if bytes < 0 {
+		sign, bytes = "-", -bytes
+	}
+	return sign + m.mem(bytes, total)
+}
+
+// memChartMax is the top of a memory chart: 100% of the total, else the
+// most seen, at least 100 GiB
+func (m *DashboardModel) memChartMax(seenGiB float64, total int64) float64 {
+	if m.memUnitFor(total).percent() {
+		return 100
+	}
+	return m.memValue(int64(maxFloat(100, seenGiB)*gbDivisor), total)
+}
+
+// totalVRAM is the selected endpoint's VRAM, what memory is a percent of; 0
+// before the first poll
+func (m *DashboardModel) totalVRAM() int64 {
+	if m.last == nil {
+		return 0
+	}
+	return m.last.TotalVRAMBytes
+}
+
+// formatAxisValue writes v in at most axisLabelWidth characters, with a short
+// form of its unit
+func formatAxisValue(v float64, unit string) string {
+	number := func(v float64) string {
+		if v != 0 && math.Abs(v) < 10 {
+			return strconv.FormatFloat(v, 'f', 1, 64)
+		}
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+	switch unit {
+	case "GiB", "GB":
+		return number(v) + "G"
+	case "%":
+		return strconv.FormatFloat(v, 'f', 0, 64) + "%"
+	case "ms":
+		if v >= 1000 {
+			return number(v/1000) + "s"
+		}
+		return strconv.FormatFloat(v, 'f', 0, 64) + "ms"
+	case "°C":
+		return strconv.FormatFloat(v, 'f', 0, 64) + "°"
+	case "W":
+		return strconv.FormatFloat(v, 'f', 0, 64) + "W"
+	}
+	if v >= 1000 {
+		return number(v/1000) + "k"
+	}
+	return number(v)
+}
+
+// formatAgo writes d as a negative offset in its largest whole unit, e.g. "-90s" or "-2m"
+func formatAgo(d time.Duration) string {
+	switch {
+	case d < 2*time.Minute:
+		return fmt.Sprintf("-%ds", int(d.Seconds()))
+	case d < 2*time.Hour:
+		return fmt.Sprintf("-%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("-%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("-%dd", int(d.Hours()/24))
+}
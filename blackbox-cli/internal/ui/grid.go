@@ -0,0 +1,277 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+)
+
+const (
+	gridTileWidth  = 26 // including border
+	gridTileHeight = 5  // including border
+
+	// The fleet view's (F) panels, also including border
+	fleetPanelWidth  = 36
+	fleetPanelHeight = 7
+)
+
+// gridTileSize is the size of the grid's tiles, or of the fleet view's panels
+func (m *DashboardModel) gridTileSize() (width, height int) {
+	if m.gridPanels {
+		return fleetPanelWidth, fleetPanelHeight
+	}
+	return gridTileWidth, gridTileHeight
+}
+
+func (m *DashboardModel) gridColumns() int {
+	width, _ := m.gridTileSize()
+	return max(1, m.width/width)
+}
+
+func (m *DashboardModel) updateGridMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	cols := m.gridColumns()
+	switch msg.String() {
+	case m.keys.key("quit"), "ctrl+c":
+		return m.quit()
+	case m.keys.key("grid"), m.keys.key("fleet"):
+		// The other layout's key switches to it, the open one's closes it
+		if panels := msg.String() == m.keys.key("fleet"); panels != m.gridPanels {
+			m.gridPanels = panels
+			return m, nil
+		}
+		m.showingGrid = false
+		m.hovered = m.selected
+	case "esc":
+		m.showingGrid = false
+		m.hovered = m.selected
+	case "l", "right":
+		if m.hovered < len(m.endpoints)-1 {
+			m.hovered++
+		}
+	case "h", "left":
+		if m.hovered > 0 {
+			m.hovered--
+		}
+	case "j", "down":
+		if m.hovered+cols < len(m.endpoints) {
+			m.hovered += cols
+		}
+	case "k", "up":
+		if m.hovered-cols >= 0 {
+			m.hovered -= cols
+		}
+	case "enter":
+		m.showingGrid = false
+		if m.hovered != m.selected && m.hovered < len(m.endpoints) {
+			m.selectEndpoint(m.hovered)
+			return m, startPolling(m.ctx, m.client, m.selected, m.fetchSequence)
+		}
+	}
+	return m, nil
+}
+
+// renderGrid lays out every endpoint as a compact tile, or a panel in the
+// fleet view, scrolled so the cursor stays visible
+func (m *DashboardModel) renderGrid(width, height int) string {
+	if len(m.endpoints) == 0 {
+		return m.renderEmptyState(width, height, "No endpoints configured\n\nPress 'g' to go back and 'n' to create one", colorUnfocused)
+	}
+	cols := m.gridColumns()
+	_, tileHeight := m.gridTileSize()
+	visibleRows := max(1, (height-1)/tileHeight)
+	cursorRow := m.hovered / cols
+	firstRow := max(0, cursorRow-visibleRows+1)
+
+	alerting := 0
+	var throughput float64
+	for _, ep := range m.endpoints {
+		st := m.fleet[ep.Name]
+		if st == nil || st.last == nil {
+			continue
+		}
+		if len(m.breachedAlerts(ep.Name, st.last)) > 0 {
+			alerting++
+		}
+		throughput += st.last.Throughput()
+	}
+	header := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(colorGreen)).Render("Fleet") +
+		styleColor(colorMuted).Render(fmt.Sprintf("  %d endpoints", len(m.endpoints)))
+	if throughput > 0 {
+		header += "  " + styleColor(colorCyan).Render(fmt.Sprintf("%.0f tok/s", throughput))
+	}
+	if alerting > 0 {
+		header += "  " + styleColor(colorRed).Bold(true).Render(fmt.Sprintf("%d alerting", alerting))
+	}
+
+	var rows []string
+	for r := firstRow; r < firstRow+visibleRows; r++ {
+		var tiles []string
+		for c := 0; c < cols; c++ {
+			idx := r*cols + c
+			if idx >= len(m.endpoints) {
+				break
+			}
+			if m.gridPanels {
+				tiles = append(tiles, m.renderFleetPanel(idx))
+			} else {
+				tiles = append(tiles, m.renderGridTile(idx))
+			}
+		}
+		if len(tiles) == 0 {
+			break
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, tiles...))
+	}
+
+	body := lipgloss.JoinVertical(lipgloss.Left, append([]string{header}, rows...)...)
+	return lipgloss.NewStyle().Width(width).Height(height).Render(body)
+}
+
+func (m *DashboardModel) renderGridTile(idx int) string {
+	ep := m.endpoints[idx]
+	st := m.fleet[ep.Name]
+	inner := gridTileWidth - 4
+
+	name := truncateString(ep.Name, inner-3)
+	badge := ""
+	var gauge, detail string
+	switch {
+	case st == nil || st.updated.IsZero():
+		gauge = styleColor(colorDim).Render(strings.Repeat("░", inner-6))
+		detail = styleColor(colorMuted).Render("waiting...")
+	case st.lastErr != nil && st.last == nil:
+		gauge = styleColor(colorDim).Render(strings.Repeat("░", inner-6))
+		detail = styleColor(colorRed).Render(truncateString(errorText(st.lastErr), inner))
+		badge = styleColor(colorRed).Bold(true).Render("✗")
+	default:
+		pct := 0.0
+		if st.last.TotalVRAMBytes > 0 {
+			pct = float64(st.last.AllocatedVRAMBytes) / float64(st.last.TotalVRAMBytes) * 100
+		}
+		barWidth := inner - 6
+		filled := int(normalizeValue(pct, 0, 100) * float64(barWidth))
+		gauge = styleColor(getPercentColor(pct)).Render(strings.Repeat("█", filled)) +
+			styleColor(colorDim).Render(strings.Repeat("░", barWidth-filled)) +
+			fmt.Sprintf(" %4.0f%%", pct)
+		detail = styleColor(colorMuted).Render(fmt.Sprintf("%d models", len(st.last.Models)))
+		if f, ok := st.vramForecast(); ok {
+			detail += styleColor(getForecastColor(f.TimeToFull())).Render("  full ~" + model.FormatETA(f.TimeToFull()))
+		}
+		if st.lastErr != nil {
+			detail += styleColor(colorRed).Render("  stale")
+			badge = styleColor(colorRed).Bold(true).Render("✗")
+		}
+		if len(m.breachedAlerts(ep.Name, st.last)) > 0 {
+			badge = styleColor(colorRed).Bold(true).Render("▲")
+			// Whoever looks at the wall should know who to call
+			if contact := ep.Contact(); contact != "" {
+				detail = styleColor(colorRed).Render(truncateString("page "+contact, inner))
+			}
+		}
+	}
+
+	nameStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(colorText))
+	if idx == m.selected {
+		name = "● " + truncateString(ep.Name, inner-5)
+	}
+	title := nameStyle.Render(name)
+	title += strings.Repeat(" ", max(1, inner-lipgloss.Width(title)-lipgloss.Width(badge))) + badge
+
+	borderColor := colorDim
+	if idx == m.hovered {
+		borderColor = colorFocused
+	}
+	if m.flashRed(ep.Name, "") {
+		borderColor = colorRed
+	}
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(borderColor)).
+		Padding(0, 1).
+		Width(gridTileWidth - 2).
+		Render(title + "\n" + gauge + "\n" + detail)
+}
+
+// renderFleetPanel draws an endpoint as a fleet view panel: allocated VRAM and
+// KV cache gauges, the VRAM trend, and a border colored by its health
+func (m *DashboardModel) renderFleetPanel(idx int) string {
+	ep := m.endpoints[idx]
+	st := m.fleet[ep.Name]
+	inner := fleetPanelWidth - 4
+	barWidth := inner - 12 // Less the label and the percentage
+
+	gauge := func(label string, pct float64) string {
+		filled := int(normalizeValue(pct, 0, 100) * float64(barWidth))
+		return styleColor(colorMuted).Render(label) +
+			styleColor(getPercentColor(pct)).Render(strings.Repeat("█", filled)) +
+			styleColor(colorDim).Render(strings.Repeat("░", barWidth-filled)) +
+			fmt.Sprintf(" %4.0f%%", pct)
+	}
+
+	status, statusColor := "waiting", colorDim
+	var lines []string
+	switch {
+	case st == nil || st.updated.IsZero():
+		lines = []string{gauge("VRAM  ", 0), gauge("KV    ", 0), "", styleColor(colorMuted).Render("waiting for first poll")}
+	case st.lastErr != nil && st.last == nil:
+		status, statusColor = "✗ down", colorRed
+		lines = []string{gauge("VRAM  ", 0), gauge("KV    ", 0), "", styleColor(colorRed).Render(truncateString(errorText(st.lastErr), inner))}
+	default:
+		status, statusColor = "● ok", colorGreen
+		s := st.last
+		vramPct, kvPct := 0.0, 0.0
+		if s.TotalVRAMBytes > 0 {
+			vramPct = float64(s.AllocatedVRAMBytes) / float64(s.TotalVRAMBytes) * 100
+		}
+		// KV cache lives in the allocated VRAM, so it's shown as a share of that
+		if s.AllocatedVRAMBytes > 0 {
+			kvPct = float64(s.UsedKVCacheBytes) / float64(s.AllocatedVRAMBytes) * 100
+		}
+		trend := styleColor(getPercentColor(vramPct)).Render(renderScaledSparkline(st.vramPercent, fleetHistorySize, 100)) +
+			styleColor(colorMuted).Render(fmt.Sprintf("  %d models", len(s.Models)))
+		allocated, total := m.memOf(s.AllocatedVRAMBytes, s.TotalVRAMBytes)
+		detail := styleColor(colorMuted).Render(fmt.Sprintf("%s%s · KV %s",
+			allocated, total, m.mem(s.UsedKVCacheBytes, s.TotalVRAMBytes)))
+		if f, ok := st.vramForecast(); ok {
+			detail = styleColor(getForecastColor(f.TimeToFull())).Render("VRAM full in ~" + model.FormatETA(f.TimeToFull()))
+		}
+		if st.lastErr != nil {
+			status, statusColor = "stale", colorYellow
+			detail = styleColor(colorRed).Render(truncateString(errorText(st.lastErr), inner))
+		}
+		if breached := m.breachedAlerts(ep.Name, s); len(breached) > 0 {
+			status, statusColor = "▲ alerting", colorRed
+			detail = styleColor(colorRed).Render(truncateString(alertSummary(breached, ep), inner))
+		}
+		lines = []string{gauge("VRAM  ", vramPct), gauge("KV    ", kvPct), trend, detail}
+	}
+
+	name := ep.Name
+	if idx == m.selected {
+		name = "● " + name
+	}
+	badge := styleColor(statusColor).Bold(true).Render(status)
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(colorText)).Render(truncateString(name, inner-lipgloss.Width(badge)-1))
+	title += strings.Repeat(" ", max(1, inner-lipgloss.Width(title)-lipgloss.Width(badge))) + badge
+	for i, line := range lines {
+		lines[i] = lipgloss.NewStyle().MaxWidth(inner).Render(line)
+	}
+
+	border := lipgloss.RoundedBorder()
+	if idx == m.hovered {
+		border = lipgloss.ThickBorder()
+	}
+	// A new breach flashes the red border
+	if m.alertFlashing(ep.Name, "") && !m.flashOn {
+		statusColor = colorDim
+	}
+	return lipgloss.NewStyle().
+		Border(border).
+		BorderForeground(lipgloss.Color(statusColor)).
+		Padding(0, 1).
+		Width(fleetPanelWidth - 2).
+		Render(title + "\n" + strings.Join(lines, "\n"))
+}
@@ -0,0 +1,223 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+)
+
+// endpointRow is a line of the endpoints panel: an endpoint, or the header of
+// a tag group when the panel is grouped
+type endpointRow struct {
+	idx    int    // Endpoint shown, or the group's first endpoint for a header
+	header bool   // Group header; its endpoints follow unless the group is collapsed
+	group  string // Tag value of the group; "" for endpoints without the tag
+	count  int    // Endpoints in the group, for headers
+}
+
+// SetSelector hides the endpoints sel doesn't match. Call it before Init.
+func (m *DashboardModel) SetSelector(sel config.Selector) {
+	m.selector = sel
+	m.setEndpoints(m.config.Endpoints)
+	if len(m.endpoints) > 0 {
+		m.selectEndpoint(0)
+	}
+}
+
+// SetGroupBy groups the endpoints panel by the tag key; "" lists endpoints
+// in config order. Call it before Init.
+func (m *DashboardModel) SetGroupBy(key string) {
+	m.groupBy = key
+	m.setEndpoints(m.config.Endpoints)
+	if len(m.endpoints) > 0 {
+		m.selectEndpoint(0)
+	}
+}
+
+// setEndpoints shows the endpoints of eps the selector matches, ordered by
+// group so each group's endpoints are adjacent
+func (m *DashboardModel) setEndpoints(eps []config.Endpoint) {
+	eps = m.selector.Filter(eps)
+	if m.groupBy != "" {
+		eps = append([]config.Endpoint(nil), eps...)
+		sort.SliceStable(eps, func(i, j int) bool {
+			gi, gj := m.groupOf(eps[i]), m.groupOf(eps[j])
+			// Endpoints without the tag go last
+			if (gi == "") != (gj == "") {
+				return gj == ""
+			}
+			return gi < gj
+		})
+	}
+	m.endpoints = eps
+}
+
+func (m *DashboardModel) groupOf(ep config.Endpoint) string {
+	v, _ := ep.Tag(m.groupBy)
+	return v
+}
+
+// endpointRows lays out the endpoints panel. Collapsed groups show only their header.
+func (m *DashboardModel) endpointRows() []endpointRow {
+	rows := make([]endpointRow, 0, len(m.endpoints))
+	for i, ep := range m.endpoints {
+		if m.groupBy == "" {
+			rows = append(rows, endpointRow{idx: i})
+			continue
+		}
+		g := m.groupOf(ep)
+		if i == 0 || g != m.groupOf(m.endpoints[i-1]) {
+			rows = append(rows, endpointRow{idx: i, header: true, group: g})
+		}
+		rows[lastHeader(rows)].count++
+		if !m.collapsed[g] {
+			rows = append(rows, endpointRow{idx: i, group: g})
+		}
+	}
+	return rows
+}
+
+func lastHeader(rows []endpointRow) int {
+	for i := len(rows) - 1; i >= 0; i-- {
+		if rows[i].header {
+			return i
+		}
+	}
+	return -1
+}
+
+// hoverable reports whether the cursor can rest on row: any endpoint, and the
+// header of a collapsed group, which stands for the whole group
+func (m *DashboardModel) hoverable(row endpointRow) bool {
+	return !row.header || m.collapsed[row.group]
+}
+
+// hoveredRow is the row of rows the cursor is on
+func (m *DashboardModel) hoveredRow(rows []endpointRow) int {
+	for i, row := range rows {
+		if m.hoverable(row) && row.idx == m.hovered {
+			return i
+		}
+	}
+	// Inside a collapsed group: its header
+	for i, row := range rows {
+		if row.header && m.collapsed[row.group] && row.group == m.groupOf(m.endpoints[m.hovered]) {
+			return i
+		}
+	}
+	return 0
+}
+
+// moveHover moves the cursor by delta hoverable rows, skipping the insides of collapsed groups
+func (m *DashboardModel) moveHover(delta int) {
+	if len(m.endpoints) == 0 {
+		return
+	}
+	rows := m.endpointRows()
+	i := m.hoveredRow(rows)
+	for step := delta; step != 0; {
+		dir := 1
+		if step < 0 {
+			dir = -1
+		}
+		j := i + dir
+		for j >= 0 && j < len(rows) && !m.hoverable(rows[j]) {
+			j += dir
+		}
+		if j < 0 || j >= len(rows) {
+			break
+		}
+		i = j
+		step -= dir
+	}
+	m.hovered = rows[i].idx
+}
+
+// toggleGroup collapses or expands the group under the cursor
+func (m *DashboardModel) toggleGroup() {
+	if m.groupBy == "" || m.hovered >= len(m.endpoints) {
+		return
+	}
+	g := m.groupOf(m.endpoints[m.hovered])
+	if m.collapsed == nil {
+		m.collapsed = make(map[string]bool)
+	}
+	m.collapsed[g] = !m.collapsed[g]
+	if m.collapsed[g] {
+		// Rest on the header, which stands for the group's first endpoint
+		for i, ep := range m.endpoints {
+			if m.groupOf(ep) == g {
+				m.hovered = i
+				break
+			}
+		}
+	}
+}
+
+// hoveringCollapsed reports whether the cursor is on a collapsed group's header
+func (m *DashboardModel) hoveringCollapsed() bool {
+	return m.groupBy != "" && m.hovered < len(m.endpoints) && m.collapsed[m.groupOf(m.endpoints[m.hovered])]
+}
+
+// cycleGroupBy switches grouping to the next tag key in use, then back to none
+func (m *DashboardModel) cycleGroupBy() tea.Cmd {
+	keys := config.TagKeys(m.config.Endpoints)
+	next := ""
+	if len(keys) > 0 {
+		next = keys[0]
+		for i, k := range keys {
+			if k == m.groupBy {
+				next = ""
+				if i+1 < len(keys) {
+					next = keys[i+1]
+				}
+				break
+			}
+		}
+	}
+	m.groupBy = next
+	m.collapsed = nil
+	return m.regroup()
+}
+
+// regroup reorders the endpoints for the current grouping, keeping the
+// selection and the cursor on the same endpoints. The selected endpoint's
+// polls are keyed by its index, so they restart when it moves.
+func (m *DashboardModel) regroup() tea.Cmd {
+	if len(m.endpoints) == 0 {
+		return nil
+	}
+	selected, hovered := m.endpoints[m.selected].Name, ""
+	if m.hovered < len(m.endpoints) {
+		hovered = m.endpoints[m.hovered].Name
+	}
+	m.setEndpoints(m.config.Endpoints)
+	for i, ep := range m.endpoints {
+		if ep.Name == hovered {
+			m.hovered = i
+		}
+	}
+	for i, ep := range m.endpoints {
+		if ep.Name == selected && i != m.selected {
+			m.selected = i
+			m.fetchSequence++
+			return startPolling(m.ctx, m.client, m.selected, m.fetchSequence)
+		}
+	}
+	return nil
+}
+
+// groupHeader is the text of a group header row
+func (m *DashboardModel) groupHeader(row endpointRow) string {
+	arrow := "▾"
+	if m.collapsed[row.group] {
+		arrow = "▸"
+	}
+	label := m.groupBy + "=" + row.group
+	if row.group == "" {
+		label = "no " + m.groupBy
+	}
+	return fmt.Sprintf("%s %s (%d)", arrow, label, row.count)
+}
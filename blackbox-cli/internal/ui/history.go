@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"container/list"
+
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+	"github.com/maxdcmn/blackbox-cli/internal/share"
+	"github.com/maxdcmn/blackbox-cli/internal/store"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+const (
+	historyCacheSize = 8
+	// storedHistorySize is how many chart points are kept when the history
+	// store fills them back in: enough for the widest chart
+	storedHistorySize = 500
+)
+
+// historySize is how many chart points ep keeps: its history_size, else
+// storedHistorySize with a history store and maxHistorySize without
+func (m *DashboardModel) historySize(ep config.Endpoint) int {
+	if ep.HistorySize > 0 {
+		return ep.HistorySize
+	}
+	if m.store != nil {
+		return storedHistorySize
+	}
+	return maxHistorySize
+}
+
+// SetHistoryStore makes the dashboard save every poll of the selected
+// endpoint into s and start each endpoint's charts from what s has kept, so
+// they survive restarts. Without one history is kept in memory only.
+func (m *DashboardModel) SetHistoryStore(s *store.Store) {
+	m.store = s
+	if m.selected < len(m.endpoints) && len(m.history) == 0 {
+		m.historyLimit = m.historySize(m.endpoints[m.selected])
+		m.loadStoredHistory(m.endpoints[m.selected].Name)
+	}
+}
+
+// loadStoredHistory starts the charts of name from the history store
+func (m *DashboardModel) loadStoredHistory(name string) {
+	if m.store == nil {
+		return
+	}
+	samples, err := m.store.Load(name, m.historyLimit)
+	if err != nil {
+		utils.Warn("%v", err)
+		return
+	}
+	for _, s := range samples {
+		dp := DataPoint(s)
+		m.history = append(m.history, dp)
+		m.trackMax(dp)
+	}
+}
+
+// storeHistory queues dp for the history store; the store writes it off the UI goroutine
+func (m *DashboardModel) storeHistory(dp DataPoint) {
+	if m.store != nil && m.historyKey != "" {
+		m.store.Append(m.historyKey, share.Sample(dp))
+	}
+}
+
+type cachedHistory struct {
+	name    string
+	history []DataPoint
+	models  map[string]*modelRing
+	last    *model.Snapshot
+}
+
+// historyCache keeps chart history for recently viewed endpoints so switching
+// back doesn't start from an empty chart. Least recently used entries are evicted.
+type historyCache struct {
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+func newHistoryCache(capacity int) *historyCache {
+	return &historyCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+func (c *historyCache) put(name string, history []DataPoint, models map[string]*modelRing, last *model.Snapshot) {
+	if el, ok := c.entries[name]; ok {
+		el.Value = &cachedHistory{name: name, history: history, models: models, last: last}
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[name] = c.order.PushFront(&cachedHistory{name: name, history: history, models: models, last: last})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedHistory).name)
+	}
+}
+
+func (c *historyCache) get(name string) (*cachedHistory, bool) {
+	el, ok := c.entries[name]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*cachedHistory), true
+}
@@ -1,12 +1,35 @@
 package ui
 
 import (
+	"context"
+	"reflect"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/maxdcmn/blackbox-cli/internal/client"
 	"github.com/maxdcmn/blackbox-cli/internal/config"
 )
 
+// probeMsg is the check of an endpoint about to be created
+type probeMsg struct {
+	ep    config.Endpoint
+	probe *client.Probe
+	err   error
+}
+
+// probeEndpoint checks that ep answers with a valid snapshot before it's saved
+func (m *DashboardModel) probeEndpoint(ep config.Endpoint) tea.Cmd {
+	c, ctx, timeout := m.endpointClient(ep), m.ctx, m.endpointTimeout(ep)
+	return func() tea.Msg {
+		// The optional APIs get a few seconds on top of the snapshot
+		ctx, cancel := context.WithTimeout(ctx, timeout+5*time.Second)
+		defer cancel()
+		p, err := client.ProbeEndpoint(ctx, c)
+		return probeMsg{ep: ep, probe: p, err: err}
+	}
+}
+
 func (m *DashboardModel) renderInputMode(isCreate bool) string {
 	var b strings.Builder
 	if isCreate {
@@ -15,8 +38,8 @@ func (m *DashboardModel) renderInputMode(isCreate bool) string {
 		b.WriteString("Edit Endpoint\n\n")
 	}
 
-	fields := []*string{&m.newName, &m.newURL, &m.newEp, &m.newTO}
-	labels := []string{"Name: ", "Base URL: ", "Endpoint: ", "Timeout: "}
+	fields := []*string{&m.newName, &m.newURL, &m.newEp, &m.newTO, &m.newDesc}
+	labels := []string{"Name: ", "Base URL: ", "Endpoint: ", "Timeout: ", "Description: "}
 
 	maxLabelWidth := 0
 	for _, label := range labels {
@@ -55,54 +78,62 @@ func (m *DashboardModel) renderInputMode(isCreate bool) string {
 		b.WriteString("\n")
 	}
 
-	b.WriteString("\nTab: next field  Enter: save  Esc: cancel")
+	switch {
+	case m.probing:
+		b.WriteString("\n" + styleColor(colorMuted).Render("Checking "+m.newURL+m.newEp+"..."))
+	case m.probeMessage != "":
+		b.WriteString("\n" + styleColor(colorRed).Render(m.probeMessage))
+	}
+	if isCreate && m.probeFailed {
+		b.WriteString("\n\nTab: next field  Enter: save anyway  Esc: cancel")
+	} else {
+		b.WriteString("\nTab: next field  Enter: save  Esc: cancel")
+	}
 	return popupStyle.Width(60).Render(b.String())
 }
 
 func (m *DashboardModel) updateInputMode(msg tea.Msg, isCreate bool) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case probeMsg:
+		if !m.probing || !reflect.DeepEqual(msg.ep, m.formEndpoint(isCreate)) {
+			return m, nil
+		}
+		m.probing = false
+		if msg.err != nil {
+			// Shown in the form; Enter again saves it as it is
+			m.probeFailed = true
+			m.probeMessage = "✗ " + errorText(msg.err)
+			return m, nil
+		}
+		cmd := m.saveEndpoint(msg.ep, isCreate)
+		if !m.creating {
+			m.configMessage = "✓ " + msg.ep.Name + ": " + msg.probe.Summary()
+		}
+		return m, cmd
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "esc":
 			m.creating = false
 			m.editing = false
+			m.probing = false
 			return m, nil
 		case "enter":
-			if m.newName == "" {
+			if m.newName == "" || m.probing {
 				return m, nil
 			}
-			ep := config.Endpoint{
-				Name:     m.newName,
-				BaseURL:  m.newURL,
-				Endpoint: m.newEp,
-				Timeout:  m.newTO,
-			}
-			var err error
-			if isCreate {
-				err = config.AddEndpoint(m.config, ep)
-			} else {
-				err = config.UpdateEndpoint(m.config, m.editOldName, ep)
-			}
-			if err == nil {
-				m.endpoints = m.config.Endpoints
-				if isCreate {
-					m.selected = len(m.endpoints) - 1
-				} else {
-					for i, e := range m.endpoints {
-						if e.Name == ep.Name {
-							m.selected = i
-							break
-						}
-					}
-				}
-				m.selectEndpoint(m.selected)
-				m.creating = false
-				m.editing = false
-				return m, fetchSnapshot(m.client, m.timeout, m.selected, m.fetchSequence)
+			ep := m.formEndpoint(isCreate)
+			if isCreate && !(m.probeFailed && reflect.DeepEqual(ep, m.probedEp)) {
+				// Catch typos in the URL before the endpoint is saved
+				m.probing = true
+				m.probeFailed = false
+				m.probeMessage = ""
+				m.probedEp = ep
+				return m, m.probeEndpoint(ep)
 			}
+			return m, m.saveEndpoint(ep, isCreate)
 		case "tab":
 			m.ensureCursorInBounds()
-			m.inputField = (m.inputField + 1) % 4
+			m.inputField = (m.inputField + 1) % 5
 			m.ensureCursorInBounds()
 			return m, nil
 		case "left":
@@ -156,8 +187,58 @@ func (m *DashboardModel) updateInputMode(msg tea.Msg, isCreate bool) (tea.Model,
 	return m, nil
 }
 
+// formEndpoint is the endpoint the create or edit form describes. Editing
+// keeps the settings the form doesn't show (transport etc.).
+func (m *DashboardModel) formEndpoint(isCreate bool) config.Endpoint {
+	var ep config.Endpoint
+	if !isCreate {
+		for _, e := range m.endpoints {
+			if e.Name == m.editOldName {
+				ep = e
+				break
+			}
+		}
+	}
+	ep.Name = m.newName
+	ep.BaseURL = m.newURL
+	ep.Endpoint = m.newEp
+	ep.Timeout = m.newTO
+	ep.Description = strings.TrimSpace(m.newDesc)
+	return ep
+}
+
+// saveEndpoint adds or updates ep in the config and selects it
+func (m *DashboardModel) saveEndpoint(ep config.Endpoint, isCreate bool) tea.Cmd {
+	var err error
+	if isCreate {
+		err = config.AddEndpoint(m.config, ep)
+	} else {
+		err = config.UpdateEndpoint(m.config, m.editOldName, ep)
+	}
+	if err != nil {
+		m.probeMessage = "✗ " + err.Error()
+		return nil
+	}
+	m.setEndpoints(m.config.Endpoints)
+	m.creating = false
+	m.editing = false
+	if len(m.endpoints) == 0 {
+		// Saved, but --select hides it
+		return m.syncFleet()
+	}
+	m.selected = min(m.selected, len(m.endpoints)-1)
+	for i, e := range m.endpoints {
+		if e.Name == ep.Name {
+			m.selected = i
+			break
+		}
+	}
+	m.selectEndpoint(m.selected)
+	return tea.Batch(fetchSnapshot(m.ctx, m.client, m.timeout, m.selected, m.fetchSequence), m.syncFleet())
+}
+
 func (m *DashboardModel) getFieldValue() *string {
-	fields := []*string{&m.newName, &m.newURL, &m.newEp, &m.newTO}
+	fields := []*string{&m.newName, &m.newURL, &m.newEp, &m.newTO, &m.newDesc}
 	if m.inputField >= 0 && m.inputField < len(fields) {
 		return fields[m.inputField]
 	}
@@ -165,7 +246,7 @@ func (m *DashboardModel) getFieldValue() *string {
 }
 
 func (m *DashboardModel) ensureCursorInBounds() {
-	fields := []*string{&m.newName, &m.newURL, &m.newEp, &m.newTO}
+	fields := []*string{&m.newName, &m.newURL, &m.newEp, &m.newTO, &m.newDesc}
 	if m.inputField >= 0 && m.inputField < len(fields) {
 		fieldLen := len(*fields[m.inputField])
 		if m.cursorPos[m.inputField] < 0 {
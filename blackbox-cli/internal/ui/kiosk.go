@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const defaultCycleDwell = 15 * time.Second
+
+type cycleMsg struct {
+	gen int
+}
+
+// SetKiosk puts the dashboard in read-only display mode: keys other than ctrl+c
+// are ignored and the view either rotates through endpoints every dwell or,
+// with grid set, stays on the fleet grid.
+func (m *DashboardModel) SetKiosk(dwell time.Duration, grid bool) {
+	m.kiosk = true
+	m.showingGrid = grid
+	if dwell <= 0 {
+		dwell = defaultCycleDwell
+	}
+	m.cycleDwell = dwell
+	m.cycling = !grid
+}
+
+// SetCycleDwell sets how long the carousel (C) and kiosk mode stay on each endpoint
+func (m *DashboardModel) SetCycleDwell(dwell time.Duration) {
+	if dwell > 0 {
+		m.cycleDwell = dwell
+	}
+}
+
+func (m *DashboardModel) toggleCarousel() tea.Cmd {
+	m.cycling = !m.cycling
+	if !m.cycling {
+		m.cycleGen++ // drop the pending tick
+		return nil
+	}
+	if m.cycleDwell <= 0 {
+		m.cycleDwell = defaultCycleDwell
+	}
+	return m.scheduleCycle()
+}
+
+func (m *DashboardModel) scheduleCycle() tea.Cmd {
+	m.cycleGen++
+	gen := m.cycleGen
+	return tea.Tick(m.cycleDwell, func(time.Time) tea.Msg { return cycleMsg{gen: gen} })
+}
+
+func (m *DashboardModel) updateCycle(msg cycleMsg) (tea.Model, tea.Cmd) {
+	if !m.cycling || msg.gen != m.cycleGen || len(m.endpoints) < 2 {
+		return m, nil
+	}
+	// Any keypress pauses rotation for a full dwell, and it never moves under a popup
+	busy := m.creating || m.editing || m.deploying || m.showingModels || m.spindowning ||
+		m.optimizing || m.thresholdEditing || m.helpActive || m.showingGrid || m.showingUsage || m.showingLogs || m.showingQueue || m.showingAlerts || m.settingsActive
+	if busy || time.Since(m.lastKeyAt) < m.cycleDwell {
+		return m, m.scheduleCycle()
+	}
+	m.selectEndpoint((m.selected + 1) % len(m.endpoints))
+	return m, tea.Batch(startPolling(m.ctx, m.client, m.selected, m.fetchSequence), m.scheduleCycle())
+}
+
+func (m *DashboardModel) updateKioskKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	if key == "ctrl+c" || (m.viewing != nil && (key == "q" || key == "esc")) {
+		return m.quit()
+	}
+	// Stray keypresses on a wall display must never change or close anything
+	return m, nil
+}
+
+func (m *DashboardModel) renderKioskBar(width int) string {
+	text := "blackbox · read-only"
+	if b := m.viewing; b != nil {
+		text = fmt.Sprintf("blackbox · shared snapshot of %s (%s) captured %s · q: quit",
+			b.Endpoint, b.BaseURL, b.CapturedAt.Local().Format("2006-01-02 15:04:05 MST"))
+		return statusBarStyle.Width(width).Height(1).Render(styleColor(colorItalic).Render(text))
+	}
+	if len(m.endpoints) > 0 && !m.showingGrid && m.selected < len(m.endpoints) {
+		text += fmt.Sprintf(" · %s (%d/%d)", m.endpoints[m.selected].Name, m.selected+1, len(m.endpoints))
+		if m.cycling && len(m.endpoints) > 1 {
+			text += fmt.Sprintf(" · rotating every %s", m.cycleDwell)
+		}
+	}
+	return statusBarStyle.Width(width).Height(1).Render(styleColor(colorItalic).Render(text))
+}
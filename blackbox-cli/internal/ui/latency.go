@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+)
+
+// hasLatency says whether the models reported TTFT or inter-token latency in
+// any sample of the history, so the chart doesn't come and go with idle
+// intervals that report none
+func (m *DashboardModel) hasLatency() bool {
+	for _, dp := range m.history {
+		if dp.TTFTSeconds > 0 || dp.InterTokenSeconds > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// renderLatencyChart draws TTFT over time, with the latest inter-token
+// latency and the decode speed it gives next to it
+func (m *DashboardModel) renderLatencyChart(boxHeight, width int) string {
+	ttft, interToken := m.last.Latency()
+	history := m.getHistory(func(dp DataPoint) float64 { return dp.TTFTSeconds * 1000 })
+	return m.renderMetricContent("Latency", boxHeight, width, int(ttft*1000), int(interToken*1e6), 0,
+		history, lipgloss.Color(colorYellow), 0)
+}
+
+// latencyText sums up a model's latency, e.g. "TTFT 182 ms, 41.2 tok/s"; "" when it reports none
+func latencyText(info model.ModelInfo) string {
+	var parts []string
+	if info.TTFTSeconds > 0 {
+		parts = append(parts, fmt.Sprintf("TTFT %.0f ms", info.TTFTSeconds*1000))
+	}
+	if info.InterTokenLatencySeconds > 0 {
+		parts = append(parts, fmt.Sprintf("%.1f tok/s", info.TokensPerSecond()))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// modelLatencyRow is a model's latency line in the Properties panel; "" when it reports none
+func modelLatencyRow(info model.ModelInfo, labelStyle lipgloss.Style) string {
+	text := latencyText(info)
+	if text == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s %s", labelStyle.Render("    Latency:"), styleColor(colorYellow).Render(text))
+}
+
+// modelLatencySuffix is the latency the last snapshot has for modelID, for
+// the models popup's lines; "" when it has none
+func (m *DashboardModel) modelLatencySuffix(modelID string) string {
+	if m.last == nil {
+		return ""
+	}
+	for _, info := range m.last.Models {
+		if info.ModelID == modelID {
+			if text := latencyText(info); text != "" {
+				return "  " + styleColor(colorYellow).Render(text)
+			}
+		}
+	}
+	return ""
+}
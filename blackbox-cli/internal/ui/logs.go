@@ -0,0 +1,314 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/maxdcmn/blackbox-cli/internal/client"
+)
+
+const (
+	// logsPollInterval is how often a followed log asks for new lines
+	logsPollInterval = 2 * time.Second
+	// maxLogLines is how many lines the log pane keeps; older ones are dropped
+	maxLogLines = 2000
+	// logGapMarker stands in for lines a follow-up fetch was too short to hold
+	logGapMarker = "··· lines skipped: more were written between polls than the server returns at once ···"
+)
+
+// logSeverity matches the level vLLM and Python's logging put at the start of a line
+var logSeverity = regexp.MustCompile(`^\s*(?:\(\w+ pid=\d+\)\s*)?(CRITICAL|FATAL|ERROR|WARNING|WARN|INFO|DEBUG)\b`)
+
+type logsMsg struct {
+	gen  int
+	resp *client.LogsResponse
+	err  error
+}
+
+// logPane is the log of one model's container (L), kept while the pane is open
+type logPane struct {
+	modelID   string
+	container string
+	client    client.MetricsClient
+	lines     []client.LogLine
+	err       error
+	loaded    bool
+	following bool // New lines are fetched and the pane sticks to the newest
+	scroll    int  // Lines the view ends before the newest
+	gen       int  // Bumped on open, close and model switch so stale fetches are dropped
+}
+
+// logsModelIDs lists the models Tab moves the pane through: those in the
+// latest snapshot
+func (m *DashboardModel) logsModelIDs() []string {
+	var ids []string
+	if m.last != nil {
+		for _, model := range m.last.Models {
+			ids = append(ids, model.ModelID)
+		}
+	}
+	return ids
+}
+
+// startLogs opens the log pane on modelID, or the charted model, else the
+// selected endpoint's first model
+func (m *DashboardModel) startLogs(modelID string) tea.Cmd {
+	if m.selected >= len(m.endpoints) {
+		return nil
+	}
+	if modelID == "" {
+		modelID = m.chartModel
+	}
+	if ids := m.logsModelIDs(); modelID == "" && len(ids) > 0 {
+		modelID = ids[0]
+	}
+	m.showingLogs = true
+	m.logs.client = m.endpointClient(m.endpoints[m.selected])
+	return m.openLogs(modelID)
+}
+
+// openLogs switches the pane to modelID's log, reading its latest lines
+func (m *DashboardModel) openLogs(modelID string) tea.Cmd {
+	m.logs.gen++
+	m.logs.modelID = modelID
+	m.logs.container = ""
+	m.logs.lines = nil
+	m.logs.err = nil
+	m.logs.loaded = false
+	m.logs.following = true
+	m.logs.scroll = 0
+	if modelID == "" {
+		return nil
+	}
+	return fetchLogs(m.ctx, m.logs.client, m.timeout, m.logs.gen, modelID, client.LogsOptions{}, 0)
+}
+
+func (m *DashboardModel) closeLogs() {
+	m.showingLogs = false
+	m.logs.gen++
+	m.logs.lines = nil
+}
+
+// fetchLogs reads modelID's log after delay; opts.Since set follows it from there
+func fetchLogs(ctx context.Context, c client.MetricsClient, timeout time.Duration, gen int, modelID string, opts client.LogsOptions, delay time.Duration) tea.Cmd {
+	fetch := func() tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		resp, err := c.ContainerLogs(ctx, modelID, opts)
+		return logsMsg{gen: gen, resp: resp, err: err}
+	}
+	if delay <= 0 {
+		return fetch
+	}
+	return tea.Tick(delay, func(time.Time) tea.Msg { return fetch() })
+}
+
+// updateLogs adds a fetch's lines and, while following, schedules the next one
+func (m *DashboardModel) updateLogs(msg logsMsg) tea.Cmd {
+	if !m.showingLogs || msg.gen != m.logs.gen {
+		return nil
+	}
+	m.logs.loaded = true
+	m.logs.err = msg.err
+	if msg.err == nil && msg.resp != nil {
+		m.logs.container = msg.resp.ContainerName
+		m.appendLogLines(msg.resp.Lines)
+	}
+	// Other endpoint types will never have logs
+	if errors.Is(msg.err, client.ErrUnsupported) || !m.logs.following {
+		return nil
+	}
+	return m.followLogs(logsPollInterval)
+}
+
+// followLogs asks for the lines since the newest one held, as many as the
+// server gives, so a chatty container loses as few as possible between polls
+func (m *DashboardModel) followLogs(delay time.Duration) tea.Cmd {
+	opts := client.LogsOptions{Since: m.newestLogTime()}
+	if !opts.Since.IsZero() {
+		opts.Tail = client.MaxLogTail
+	}
+	return fetchLogs(m.ctx, m.logs.client, m.timeout, m.logs.gen, m.logs.modelID, opts, delay)
+}
+
+// newestLogTime is the timestamp of the newest line held that has one
+func (m *DashboardModel) newestLogTime() time.Time {
+	for i := len(m.logs.lines) - 1; i >= 0; i-- {
+		if !m.logs.lines[i].Time.IsZero() {
+			return m.logs.lines[i].Time
+		}
+	}
+	return time.Time{}
+}
+
+// appendLogLines adds the lines newer than those held. since is inclusive, so
+// the newest line held comes back with every fetch. Without timestamps to go
+// by, each fetch is the latest tail and replaces the lines. A follow-up fetch
+// that comes back full means the server cut it to its last lines, and a
+// marker shows where lines went missing.
+func (m *DashboardModel) appendLogLines(lines []client.LogLine) {
+	newest := m.newestLogTime()
+	if newest.IsZero() {
+		m.logs.lines = nil
+	}
+	gap := !newest.IsZero() && len(lines) >= client.MaxLogTail
+	for len(lines) > 0 && !newest.IsZero() && !lines[0].Time.After(newest) {
+		lines = lines[1:]
+	}
+	if gap {
+		lines = append([]client.LogLine{{Text: logGapMarker}}, lines...)
+	}
+	m.logs.lines = append(m.logs.lines, lines...)
+	if len(m.logs.lines) > maxLogLines {
+		m.logs.lines = m.logs.lines[len(m.logs.lines)-maxLogLines:]
+	}
+	// A paused pane stays on the lines it shows
+	if !m.logs.following && m.logs.scroll > 0 {
+		m.logs.scroll = min(m.logs.scroll+len(lines), len(m.logs.lines)-1)
+	}
+}
+
+func (m *DashboardModel) updateLogsMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case m.keys.key("quit"), "ctrl+c":
+		return m.quit()
+	case m.keys.key("logs"), "esc":
+		m.closeLogs()
+	case "f", " ":
+		// Follow or pause; following again picks up from the newest line held
+		m.logs.following = !m.logs.following
+		m.logs.gen++
+		if m.logs.following {
+			m.logs.scroll = 0
+			return m, m.followLogs(0)
+		}
+	case "tab":
+		// Next model of the endpoint
+		ids := m.logsModelIDs()
+		if len(ids) == 0 {
+			return m, nil
+		}
+		next := 0
+		for i, id := range ids {
+			if id == m.logs.modelID {
+				next = (i + 1) % len(ids)
+			}
+		}
+		return m, m.openLogs(ids[next])
+	case "k", "up":
+		m.scrollLogs(1)
+	case "j", "down":
+		m.scrollLogs(-1)
+	case "pgup":
+		m.scrollLogs(m.logsPageSize())
+	case "pgdown":
+		m.scrollLogs(-m.logsPageSize())
+	case "G", "end":
+		m.logs.scroll = 0
+	}
+	return m, nil
+}
+
+// scrollLogs moves the view by n lines toward older ones; scrolling back
+// pauses following so new lines don't move what is being read
+func (m *DashboardModel) scrollLogs(n int) {
+	m.logs.scroll = max(0, min(m.logs.scroll+n, len(m.logs.lines)-1))
+	if m.logs.scroll > 0 && m.logs.following {
+		m.logs.following = false
+		m.logs.gen++
+	}
+}
+
+func (m *DashboardModel) logsPageSize() int {
+	sizes := calculateContainerSizes(m.width, m.height)
+	return max(1, m.height-sizes.StatusBar.Height-5)
+}
+
+// logLineColor colors a line by its severity; lines without one are plain
+func logLineColor(text string) string {
+	match := logSeverity.FindStringSubmatch(text)
+	switch {
+	case strings.HasPrefix(text, "Traceback"):
+		return colorRed
+	case match == nil:
+		return colorText
+	}
+	switch match[1] {
+	case "CRITICAL", "FATAL", "ERROR":
+		return colorRed
+	case "WARNING", "WARN":
+		return colorOrange
+	case "DEBUG":
+		return colorMuted
+	}
+	return colorText
+}
+
+// renderLogs draws the newest lines of the model's container log that fit,
+// or the ones scrolled back to
+func (m *DashboardModel) renderLogs(width, height int) string {
+	width, height = ensureMin(width, height, 40, 8)
+	if m.logs.modelID == "" {
+		return m.renderEmptyState(width, height, "No models on this endpoint\n\nPress 'L' to go back", colorUnfocused)
+	}
+
+	var b strings.Builder
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(colorText))
+	b.WriteString(title.Render("Logs: " + truncateString(m.logs.modelID, width-40)))
+	if m.logs.container != "" {
+		b.WriteString(styleColor(colorMuted).Render(" · " + m.logs.container))
+	}
+	if m.logs.following {
+		b.WriteString("  " + styleColor(colorGreen).Render("● following"))
+	} else {
+		b.WriteString("  " + styleColor(colorYellow).Bold(true).Render("⏸ paused"))
+	}
+	b.WriteString("\n\n")
+
+	rows := height - 4
+	textWidth := width - 4
+	switch {
+	case !m.logs.loaded:
+		b.WriteString(styleColor(colorMuted).Render("Loading..."))
+	case m.logs.err != nil && len(m.logs.lines) == 0:
+		b.WriteString(styleColor(colorRed).Render("✗ " + logsErrorText(m.logs.err)))
+	case len(m.logs.lines) == 0:
+		b.WriteString(styleColor(colorMuted).Render("No log lines yet"))
+	default:
+		if m.logs.err != nil {
+			// Keep what was read; the next fetch may still work
+			b.WriteString(styleColor(colorRed).Render(truncateString("✗ "+logsErrorText(m.logs.err), textWidth)) + "\n")
+			rows--
+		}
+		end := len(m.logs.lines) - m.logs.scroll
+		start := max(0, end-rows)
+		for _, line := range m.logs.lines[start:end] {
+			stamp := "         "
+			if !line.Time.IsZero() {
+				stamp = line.Time.Local().Format("15:04:05") + " "
+			}
+			text := truncateString(strings.ReplaceAll(line.Text, "\t", "    "), textWidth-len(stamp))
+			b.WriteString(styleColor(colorDim).Render(stamp) + styleColor(logLineColor(line.Text)).Render(text) + "\n")
+		}
+		if m.logs.scroll > 0 {
+			b.WriteString(styleColor(colorMuted).Render(fmt.Sprintf("%d newer lines below", m.logs.scroll)))
+		}
+	}
+
+	return lipgloss.NewStyle().Width(width).Height(height).MaxHeight(height).Padding(1, 2).Render(b.String())
+}
+
+// logsErrorText is errorText, except a 404 is a model without a container
+// rather than a wrong endpoint path
+func logsErrorText(err error) string {
+	if errors.Is(err, client.ErrNotFound) {
+		return err.Error()
+	}
+	return errorText(err)
+}
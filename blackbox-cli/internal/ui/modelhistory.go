@@ -0,0 +1,167 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+)
+
+// ModelSample is one poll of one model, as kept per model and written by export-models
+type ModelSample struct {
+	Time               time.Time `json:"time"`
+	AllocatedVRAMBytes int64     `json:"allocated_vram_bytes"`
+	UsedKVCacheBytes   int64     `json:"used_kv_cache_bytes"`
+	TTFTSeconds        float64   `json:"ttft_seconds,omitempty"`
+	InterTokenSeconds  float64   `json:"inter_token_latency_seconds,omitempty"`
+	Throughput         float64   `json:"generation_tokens_per_second,omitempty"`
+	RequestsRunning    float64   `json:"num_requests_running,omitempty"`
+	RequestsWaiting    float64   `json:"num_requests_waiting,omitempty"`
+}
+
+// modelRing keeps a model's latest samples, overwriting the oldest once it
+// holds capacity of them
+type modelRing struct {
+	samples  []ModelSample
+	start    int // Index of the oldest sample once full
+	capacity int
+}
+
+func newModelRing(capacity int) *modelRing {
+	return &modelRing{samples: make([]ModelSample, 0, capacity), capacity: capacity}
+}
+
+func (r *modelRing) add(s ModelSample) {
+	if len(r.samples) < r.capacity {
+		r.samples = append(r.samples, s)
+		return
+	}
+	r.samples[r.start] = s
+	r.start = (r.start + 1) % r.capacity
+}
+
+// list returns the samples oldest first
+func (r *modelRing) list() []ModelSample {
+	out := make([]ModelSample, 0, len(r.samples))
+	out = append(out, r.samples[r.start:]...)
+	return append(out, r.samples[:r.start]...)
+}
+
+func (r *modelRing) newest() ModelSample {
+	if len(r.samples) < r.capacity {
+		return r.samples[len(r.samples)-1]
+	}
+	return r.samples[(r.start+r.capacity-1)%r.capacity]
+}
+
+// modelCharts are the data panel charts of the model picked with Enter in
+// the models popup. They take smoothing but no alert thresholds.
+var modelCharts = []chartDef{
+	{title: "Model VRAM", unit: "GB"},
+	{title: "Model KV Cache", unit: "GB"},
+	{title: "Model Throughput", unit: "tok/s"},
+	{title: "Model Latency", unit: "ms"},
+}
+
+// recordModels adds a sample per model in s to its ring. Rings of models
+// gone from the snapshots are dropped once their newest sample has scrolled
+// off the totals' history too.
+func (m *DashboardModel) recordModels(s *model.Snapshot, at time.Time) {
+	for _, info := range s.Models {
+		ring := m.modelHistory[info.ModelID]
+		if ring == nil {
+			ring = newModelRing(m.historyLimit)
+			m.modelHistory[info.ModelID] = ring
+		}
+		ring.add(ModelSample{
+			Time:               at,
+			AllocatedVRAMBytes: info.AllocatedVRAMBytes,
+			UsedKVCacheBytes:   info.UsedKVCacheBytes,
+			TTFTSeconds:        info.TTFTSeconds,
+			InterTokenSeconds:  info.InterTokenLatencySeconds,
+			Throughput:         info.GenerationTokensPerSecond,
+			RequestsRunning:    info.NumRequestsRunning,
+			RequestsWaiting:    info.NumRequestsWaiting,
+		})
+	}
+	if len(m.history) == 0 {
+		return
+	}
+	oldest := m.history[0].Time
+	for id, ring := range m.modelHistory {
+		if ring.newest().Time.Before(oldest) {
+			delete(m.modelHistory, id)
+			if m.chartModel == id {
+				m.chartModel = ""
+				m.selectedChart = 0
+			}
+		}
+	}
+}
+
+// modelSeries is every model's samples, oldest first, for export-models
+func (m *DashboardModel) modelSeries() map[string][]ModelSample {
+	out := make(map[string][]ModelSample, len(m.modelHistory))
+	for id, ring := range m.modelHistory {
+		out[id] = ring.list()
+	}
+	return out
+}
+
+// chartModelID picks the model to chart; false when it has no samples yet
+func (m *DashboardModel) chartModelID(id string) bool {
+	if _, ok := m.modelHistory[id]; !ok {
+		return false
+	}
+	m.chartModel = id
+	m.selectedChart = 0
+	m.chartPan = 0
+	return true
+}
+
+// getModelHistory extracts one value from each of the charted model's samples
+func (m *DashboardModel) getModelHistory(extractor func(ModelSample) float64) []float64 {
+	ring := m.modelHistory[m.chartModel]
+	if ring == nil {
+		return nil
+	}
+	samples := ring.list()
+	values := make([]float64, len(samples))
+	for i, s := range samples {
+		values[i] = extractor(s)
+	}
+	return values
+}
+
+// renderModelCharts draws the charted model's trend, in modelCharts order
+func (m *DashboardModel) renderModelCharts(boxHeight, width int) []string {
+	ring := m.modelHistory[m.chartModel]
+	latest := ring.newest()
+
+	vram := m.getModelHistory(func(s ModelSample) float64 { return m.memValue(s.AllocatedVRAMBytes, m.totalVRAM()) })
+	vramContent := m.renderMetricContent("Model VRAM", boxHeight, width, int(latest.AllocatedVRAMBytes/(1024*1024)),
+		int(m.last.TotalVRAMBytes/(1024*1024)), 0, vram, vramColor, maxFloat(1.0, findMax(vram)))
+
+	kvCache := m.getModelHistory(func(s ModelSample) float64 { return m.memValue(s.UsedKVCacheBytes, m.totalVRAM()) })
+	kvCacheContent := m.renderMetricContent("Model KV Cache", boxHeight, width, int(latest.UsedKVCacheBytes/(1024*1024)),
+		0, 0, kvCache, blocksColor, maxFloat(1.0, findMax(kvCache)))
+
+	throughput := m.getModelHistory(func(s ModelSample) float64 { return s.Throughput })
+	throughputContent := m.renderMetricContent("Model Throughput", boxHeight, width, int(latest.Throughput),
+		int(findMax(throughput)), 0, throughput, throughputColor, maxFloat(10.0, findMax(throughput)))
+
+	ttft := m.getModelHistory(func(s ModelSample) float64 { return s.TTFTSeconds * 1000 })
+	latencyContent := m.renderMetricContent("Model Latency", boxHeight, width, int(latest.TTFTSeconds*1000),
+		int(latest.InterTokenSeconds*1e6), 0, ttft, lipgloss.Color(colorYellow), 0)
+
+	return []string{vramContent, kvCacheContent, throughputContent, latencyContent}
+}
+
+// renderModelLine names the charted model under its charts, in place of the requests line
+func (m *DashboardModel) renderModelLine(width int) string {
+	line := lipgloss.NewStyle().Foreground(lipgloss.Color(colorText)).Bold(true).Render("Model") + "  " +
+		styleColor(colorCyan).Render(m.chartModel) +
+		styleColor(colorMuted).Render(fmt.Sprintf("  %d samples · Esc: back to totals", len(m.modelHistory[m.chartModel].samples)))
+	return lipgloss.NewStyle().Background(lipgloss.Color(colorBg)).Width(max(0, width-2)).MaxWidth(max(0, width-2)).Render(line)
+}
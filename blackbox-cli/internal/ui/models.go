@@ -10,9 +10,14 @@ import (
 	"github.com/maxdcmn/blackbox-cli/internal/client"
 )
 
+// modelsMsg is one page for the models popup; the popup shows each page as it
+// arrives and asks for the next while more is set
 type modelsMsg struct {
-	models *client.ModelsResponse
-	err    error
+	it    *client.ModelIterator
+	page  *client.ModelsResponse
+	added bool // page holds models not shown yet
+	more  bool
+	err   error
 }
 
 type spindownMsg struct {
@@ -26,34 +31,90 @@ type optimizeMsg struct {
 	restartedModels []string
 }
 
-func fetchModels(c *client.Client, timeout time.Duration) tea.Cmd {
+// fetchModels starts listing models a page at a time
+func (m *DashboardModel) fetchModels(c client.MetricsClient) tea.Cmd {
+	m.modelsList = nil
+	m.modelsErr = nil
+	m.modelsMoreErr = nil
+	m.modelsIter = client.NewModelIterator(c, client.ListModelsOptions{})
+	m.modelsLoading = true
+	return fetchModelsPage(m.ctx, m.modelsIter, m.timeout)
+}
+
+// fetchModelsPage fetches the iterator's next page under its own timeout, so
+// endpoints with hundreds of containers don't time out as a whole
+func fetchModelsPage(ctx context.Context, it *client.ModelIterator, timeout time.Duration) tea.Cmd {
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		ctx, cancel := context.WithTimeout(ctx, timeout)
 		defer cancel()
-		models, err := c.ListModels(ctx)
-		return modelsMsg{models: models, err: err}
+		added := it.NextPage(ctx)
+		return modelsMsg{it: it, page: it.Page(), added: added, more: !it.Done(), err: it.Err()}
+	}
+}
+
+// addModelsPage appends a page to the popup's list and fetches the next one.
+// Pages from a popup that was closed or reopened since are dropped.
+func (m *DashboardModel) addModelsPage(msg modelsMsg) tea.Cmd {
+	if msg.it != m.modelsIter {
+		return nil
+	}
+	m.modelsLoading = msg.more
+	if msg.err != nil {
+		m.modelsLoading = false
+		if m.modelsList == nil {
+			m.modelsErr = msg.err
+		} else {
+			m.modelsMoreErr = msg.err
+		}
+		return nil
+	}
+	if m.modelsList == nil {
+		m.modelsList = &client.ModelsResponse{Models: []client.DeployedModel{}}
+	}
+	if msg.page != nil {
+		m.modelsList.Total, m.modelsList.Running, m.modelsList.MaxAllowed = msg.page.Total, msg.page.Running, msg.page.MaxAllowed
+		if msg.added {
+			m.modelsList.Models = append(m.modelsList.Models, msg.page.Models...)
+		}
+	}
+	if msg.more {
+		return fetchModelsPage(m.ctx, msg.it, m.timeout)
+	}
+	return nil
+}
+
+// modelsProgress is the footer while pages are still arriving or after one failed
+func (m *DashboardModel) modelsProgress() string {
+	switch {
+	case m.modelsMoreErr != nil:
+		return "\n" + styleColor(colorRed).Render("✗ stopped loading: "+errorText(m.modelsMoreErr))
+	case m.modelsLoading:
+		return fmt.Sprintf("\nLoading... %d of %d", len(m.modelsList.Models), m.modelsList.Total)
+	case m.modelsIter != nil && m.modelsIter.Truncated():
+		return fmt.Sprintf("\nShowing the first %d pages only", client.MaxModelPages)
 	}
+	return ""
 }
 
-func spindownModel(c *client.Client, timeout time.Duration, modelID string) tea.Cmd {
+func spindownModel(ctx context.Context, c client.MetricsClient, timeout time.Duration, modelID string) tea.Cmd {
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		ctx, cancel := context.WithTimeout(ctx, timeout)
 		defer cancel()
 		resp, err := c.SpindownModel(ctx, modelID, "")
 		if err != nil {
-			return spindownMsg{success: false, message: err.Error()}
+			return spindownMsg{success: false, message: errorText(err)}
 		}
 		return spindownMsg{success: resp.Success, message: resp.Message}
 	}
 }
 
-func optimizeModels(c *client.Client, timeout time.Duration) tea.Cmd {
+func optimizeModels(ctx context.Context, c client.MetricsClient, timeout time.Duration) tea.Cmd {
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), timeout*5)
+		ctx, cancel := context.WithTimeout(ctx, timeout*5)
 		defer cancel()
 		resp, err := c.Optimize(ctx)
 		if err != nil {
-			return optimizeMsg{success: false, message: err.Error()}
+			return optimizeMsg{success: false, message: errorText(err)}
 		}
 		return optimizeMsg{
 			success:        resp.Success,
@@ -68,7 +129,7 @@ func (m *DashboardModel) renderModelsMode() string {
 	b.WriteString("Deployed Models\n\n")
 
 	if m.modelsErr != nil {
-		b.WriteString(styleColor(colorRed).Render("✗ Error: " + m.modelsErr.Error()))
+		b.WriteString(styleColor(colorRed).Render("✗ Error: " + errorText(m.modelsErr)))
 		// Try to show models from snapshot as fallback
 		if m.last != nil && len(m.last.Models) > 0 {
 			b.WriteString("\n\nShowing models from VRAM tracking:\n\n")
@@ -134,6 +195,9 @@ func (m *DashboardModel) renderModelsMode() string {
 		}
 
 		line := fmt.Sprintf("%s %s (port: %d)", styleColor(statusColor).Render(status), model.ModelID, model.Port)
+		if model.Running {
+			line += m.modelLatencySuffix(model.ModelID)
+		}
 		if selected {
 			line = activeFieldStyle.Render("> " + line)
 		} else {
@@ -145,8 +209,12 @@ func (m *DashboardModel) renderModelsMode() string {
 	if len(m.modelsList.Models) > maxVisible {
 		b.WriteString(fmt.Sprintf("\n[%d-%d of %d]", start+1, end, len(m.modelsList.Models)))
 	}
+	b.WriteString(m.modelsProgress())
 
-	b.WriteString("\n\nj/k: navigate  Esc: close")
+	b.WriteString("\n\nj/k: navigate  Enter: chart model  L: logs  y: copy ID  Esc: close")
+	if m.shareMessage != "" {
+		b.WriteString("\n" + styleColor(colorCyan).Render(m.shareMessage))
+	}
 	return popupStyle.Width(80).Height(20).Render(b.String())
 }
 
@@ -155,7 +223,7 @@ func (m *DashboardModel) renderSpindownMode() string {
 	b.WriteString("Spindown Model\n\n")
 
 	if m.modelsErr != nil {
-		b.WriteString(styleColor(colorRed).Render("✗ Error: " + m.modelsErr.Error()))
+		b.WriteString(styleColor(colorRed).Render("✗ Error: " + errorText(m.modelsErr)))
 		b.WriteString("\n\nPress Esc to close")
 		return popupStyle.Width(80).Render(b.String())
 	}
@@ -234,6 +302,7 @@ func (m *DashboardModel) renderSpindownMode() string {
 	if len(m.modelsList.Models) > maxVisible {
 		b.WriteString(fmt.Sprintf("\n[%d-%d of %d]", start+1, end, len(m.modelsList.Models)))
 	}
+	b.WriteString(m.modelsProgress())
 
 	if m.spindownInFlight && m.spindownMessage == "" {
 		b.WriteString("\n\n")
@@ -279,9 +348,7 @@ func (m *DashboardModel) renderOptimizeMode() string {
 func (m *DashboardModel) updateModelsMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case modelsMsg:
-		m.modelsList = msg.models
-		m.modelsErr = msg.err
-		return m, nil
+		return m, m.addModelsPage(msg)
 
 	case tea.KeyMsg:
 		switch msg.String() {
@@ -289,6 +356,7 @@ func (m *DashboardModel) updateModelsMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.showingModels = false
 			m.modelsList = nil
 			m.modelsErr = nil
+			m.modelsIter = nil
 			return m, nil
 		case "j", "down":
 			if m.modelsList != nil && m.selectedModel < len(m.modelsList.Models)-1 {
@@ -306,8 +374,37 @@ func (m *DashboardModel) updateModelsMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 			return m, nil
+		case "enter":
+			// Chart the selected model's trend instead of the totals
+			if m.modelsList != nil && m.selectedModel < len(m.modelsList.Models) && m.chartModelID(m.modelsList.Models[m.selectedModel].ModelID) {
+				m.showingModels = false
+				m.modelsList = nil
+				m.modelsErr = nil
+				m.modelsIter = nil
+			}
+			return m, nil
+		case "L":
+			// Follow the highlighted model's container log
+			if m.modelsList != nil && m.selectedModel < len(m.modelsList.Models) {
+				modelID := m.modelsList.Models[m.selectedModel].ModelID
+				m.showingModels = false
+				m.modelsList = nil
+				m.modelsErr = nil
+				m.modelsIter = nil
+				return m, m.startLogs(modelID)
+			}
+			return m, nil
+		case "y":
+			// Copy the highlighted model's ID
+			if m.modelsList != nil && m.selectedModel < len(m.modelsList.Models) {
+				m.yank("model ID", m.modelsList.Models[m.selectedModel].ModelID)
+			}
+			return m, nil
 		case "s":
 			// Switch to spindown mode
+			if m.selectedReadOnly() {
+				return m, nil
+			}
 			m.showingModels = false
 			m.spindowning = true
 			m.spindownMessage = ""
@@ -321,9 +418,7 @@ func (m *DashboardModel) updateModelsMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *DashboardModel) updateSpindownMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case modelsMsg:
-		m.modelsList = msg.models
-		m.modelsErr = msg.err
-		return m, nil
+		return m, m.addModelsPage(msg)
 
 	case spindownMsg:
 		m.spindownInFlight = false
@@ -331,8 +426,9 @@ func (m *DashboardModel) updateSpindownMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.spindownSuccess = msg.success
 		if msg.success {
 			m.modelsList = nil
+			m.modelsIter = nil
 			m.fetchSequence++
-			return m, fetchSnapshot(m.client, m.timeout, m.selected, m.fetchSequence)
+			return m, fetchSnapshot(m.ctx, m.client, m.timeout, m.selected, m.fetchSequence)
 		}
 		return m, nil
 
@@ -342,6 +438,7 @@ func (m *DashboardModel) updateSpindownMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.spindowning = false
 			m.modelsList = nil
 			m.modelsErr = nil
+			m.modelsIter = nil
 			m.spindownMessage = ""
 			m.spindownSuccess = false
 			m.spindownInFlight = false
@@ -359,8 +456,8 @@ func (m *DashboardModel) updateSpindownMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.spindownMessage = ""
 				m.spindownSuccess = false
 				ep := m.endpoints[m.selected]
-				spindownClient := client.New(ep.BaseURL, ep.Endpoint, m.timeout)
-				return m, spindownModel(spindownClient, m.timeout, modelID)
+				spindownClient := m.endpointClient(ep)
+				return m, spindownModel(m.ctx, spindownClient, m.timeout, modelID)
 			}
 			return m, nil
 		case "j", "down":
@@ -392,7 +489,7 @@ func (m *DashboardModel) updateOptimizeMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.optimizeRestartedModels = msg.restartedModels
 		if msg.success {
 			m.fetchSequence++
-			return m, fetchSnapshot(m.client, m.timeout, m.selected, m.fetchSequence)
+			return m, fetchSnapshot(m.ctx, m.client, m.timeout, m.selected, m.fetchSequence)
 		}
 		return m, nil
 
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+	"github.com/maxdcmn/blackbox-cli/internal/poller"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+// partialMaxAge is how old the last complete data may be to fill in a partial
+// response; past it the response is shown as it came, still marked partial
+const partialMaxAge = 5 * time.Minute
+
+// partialData is which parts of an aggregated response came back empty
+type partialData struct {
+	stats  bool // No samples in the window, but models were listed
+	models bool // No models, though VRAM is allocated
+}
+
+func (p partialData) any() bool {
+	return p.stats || p.models
+}
+
+// fromAggregated converts a poll's aggregated snapshot with the selected
+// endpoint's KV cache merge, after filling what it's missing
+func (m *DashboardModel) fromAggregated(agg *model.AggregatedSnapshot) (*model.Snapshot, *pollLoad) {
+	load := &pollLoad{running: agg.NumRequestsRunning.Avg, waiting: agg.NumRequestsWaiting.Max}
+	s := poller.MergeAggregated(m.fillPartial(agg), m.kvCacheMerge())
+	utils.Debug("Final snapshot: UsedKVCacheBytes=%d, Models count=%d, partial=%+v", s.UsedKVCacheBytes, len(s.Models), m.partial)
+	return &s, load
+}
+
+// kvCacheMerge is the selected endpoint's kv_cache_merge, the default when unset.
+// config.Load rejects unknown ones; endpoints that didn't come through it fall back.
+func (m *DashboardModel) kvCacheMerge() poller.Strategy {
+	if m.selected >= len(m.endpoints) {
+		return poller.DefaultStrategy
+	}
+	st, err := poller.ParseStrategy(m.endpoints[m.selected].KVCacheMerge)
+	if err != nil {
+		utils.Debug("%s: %v", m.endpoints[m.selected].Name, err)
+	}
+	return st
+}
+
+// fillPartial fills what agg is missing from the last complete poll and
+// marks those panels partial. A complete agg becomes the one later polls fill from.
+func (m *DashboardModel) fillPartial(agg *model.AggregatedSnapshot) *model.AggregatedSnapshot {
+	now := time.Now()
+	base := m.lastComplete
+	missing := partialData{
+		stats: agg.AllocatedVRAMBytes.Count == 0 && len(agg.Models) > 0,
+		// VRAM held outside any model is no sign of a gap unless models were there before
+		models: len(agg.Models) == 0 && agg.AllocatedVRAMBytes.Avg > 0 && base != nil && len(base.Models) > 0,
+	}
+	if !missing.any() {
+		m.lastComplete, m.lastCompleteAt = agg, now
+		m.partial = partialData{}
+		return agg
+	}
+	m.partial = missing
+	if base == nil || now.Sub(m.lastCompleteAt) > partialMaxAge {
+		utils.Debug("Partial aggregated data (%+v) with nothing recent to fill it from", missing)
+		return agg
+	}
+	filled := *agg
+	if missing.models {
+		filled.Models = base.Models
+	}
+	if missing.stats {
+		filled.TotalVRAMBytes = base.TotalVRAMBytes
+		filled.AllocatedVRAMBytes = base.AllocatedVRAMBytes
+		filled.UsedKVCacheBytes = base.UsedKVCacheBytes
+		filled.PrefixCacheHitRate = base.PrefixCacheHitRate
+	}
+	utils.Debug("Partial aggregated data (%+v) filled from data %s old", missing, now.Sub(m.lastCompleteAt).Truncate(time.Second))
+	return &filled
+}
+
+// partialNote describes where a partial panel's data came from, e.g.
+// "partial · from 12s ago"
+func (m *DashboardModel) partialNote() string {
+	if m.lastComplete == nil || time.Since(m.lastCompleteAt) > partialMaxAge {
+		return "partial · missing from the response"
+	}
+	return fmt.Sprintf("partial · from %s ago", time.Since(m.lastCompleteAt).Truncate(time.Second))
+}
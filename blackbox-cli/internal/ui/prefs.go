@@ -0,0 +1,332 @@
+package ui
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+// Values of the config's ui section; the first of each is the default
+var (
+	themeNames      = builtinThemeNames
+	unitNames       = []string{"gib", "gb", "mib", "percent"}
+	chartStyleNames = []string{"area", "line", "bars"}
+	layoutNames     = []string{"dashboard", "grid", "fleet", "usage"}
+)
+
+type theme struct {
+	focused, unfocused, text, muted, dim, italic, bg string
+	orange, yellow, cyan, green, red                 string
+	vram, blocks, fragmentation, prefixHitRate       string
+	throughput                                       string
+}
+
+// builtinThemeNames are the themes every config has, before its own ui.themes
+var builtinThemeNames = []string{"dark", "light", "high-contrast", "solarized", "mono"}
+
+// themes are the built-in themes and the config's own, by name
+var themes = builtinThemes()
+
+func builtinThemes() map[string]theme {
+	return map[string]theme{
+		"dark": {
+			focused: "46", unfocused: "15", text: "15", muted: "250", dim: "240", italic: "245", bg: "0",
+			orange: "214", yellow: "220", cyan: "39", green: "46", red: "196",
+			vram: "28", blocks: "34", fragmentation: "40", prefixHitRate: "38", throughput: "37",
+		},
+		"light": {
+			focused: "28", unfocused: "236", text: "232", muted: "238", dim: "246", italic: "242", bg: "255",
+			orange: "166", yellow: "136", cyan: "25", green: "28", red: "160",
+			vram: "22", blocks: "28", fragmentation: "29", prefixHitRate: "24", throughput: "23",
+		},
+		// Bright colors on black, for projectors and low vision
+		"high-contrast": {
+			focused: "226", unfocused: "231", text: "231", muted: "255", dim: "250", italic: "252", bg: "16",
+			orange: "208", yellow: "226", cyan: "51", green: "46", red: "196",
+			vram: "46", blocks: "51", fragmentation: "226", prefixHitRate: "201", throughput: "208",
+		},
+		// Solarized dark, https://ethanschoonover.com/solarized
+		"solarized": {
+			focused: "#859900", unfocused: "#586e75", text: "#93a1a1", muted: "#839496", dim: "#586e75", italic: "#657b83", bg: "#002b36",
+			orange: "#cb4b16", yellow: "#b58900", cyan: "#2aa198", green: "#859900", red: "#dc322f",
+			vram: "#268bd2", blocks: "#2aa198", fragmentation: "#859900", prefixHitRate: "#6c71c4", throughput: "#d33682",
+		},
+		// No colors at all, for recordings and terminals where they get in the way
+		"mono": {},
+	}
+}
+
+// registerThemes rebuilds themes from the built-in ones and the config's
+// ui.themes. Custom themes start from their base's colors, and a color that
+// isn't an ANSI number or a #rrggbb hex is warned about and left as the base's.
+func registerThemes(custom map[string]config.Palette) {
+	themes = builtinThemes()
+	themeNames = builtinThemeNames
+	names := make([]string, 0, len(custom))
+	for name := range custom {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := themes[strings.ToLower(name)]; ok {
+			utils.Warn("ui.themes: %q is a built-in theme, pick another name", name)
+			continue
+		}
+		p := custom[name]
+		base := option("themes."+name+".base", p.Base, builtinThemeNames)
+		t := themes[base]
+		for _, c := range []struct {
+			role  string
+			value string
+			into  *string
+		}{
+			{"focused", p.Focused, &t.focused}, {"unfocused", p.Unfocused, &t.unfocused},
+			{"text", p.Text, &t.text}, {"muted", p.Muted, &t.muted}, {"dim", p.Dim, &t.dim},
+			{"italic", p.Italic, &t.italic}, {"background", p.Background, &t.bg},
+			{"orange", p.Orange, &t.orange}, {"yellow", p.Yellow, &t.yellow}, {"cyan", p.Cyan, &t.cyan},
+			{"green", p.Green, &t.green}, {"red", p.Red, &t.red},
+			{"vram", p.VRAM, &t.vram}, {"blocks", p.Blocks, &t.blocks}, {"fragmentation", p.Fragmentation, &t.fragmentation},
+			{"prefix_hit_rate", p.PrefixHitRate, &t.prefixHitRate}, {"throughput", p.Throughput, &t.throughput},
+		} {
+			switch {
+			case c.value == "":
+			case validColor(c.value):
+				*c.into = c.value
+			default:
+				utils.Warn("ui.themes.%s.%s: %q is not an ANSI color (0-255) or #rrggbb, using %s's", name, c.role, c.value, base)
+			}
+		}
+		themes[name] = t
+		themeNames = append(themeNames[:len(themeNames):len(themeNames)], name)
+	}
+}
+
+// validColor is whether c is an ANSI 256 color number or a #rrggbb hex color
+func validColor(c string) bool {
+	if strings.HasPrefix(c, "#") {
+		if len(c) != 7 {
+			return false
+		}
+		_, err := strconv.ParseUint(c[1:], 16, 32)
+		return err == nil
+	}
+	n, err := strconv.Atoi(c)
+	return err == nil && n >= 0 && n <= 255
+}
+
+func init() {
+	applyTheme(themeNames[0])
+}
+
+// applyTheme switches every color and shared style to the named theme
+func applyTheme(name string) {
+	t := themes[name]
+	colorFocused, colorUnfocused, colorText = t.focused, t.unfocused, t.text
+	colorMuted, colorDim, colorItalic, colorBg = t.muted, t.dim, t.italic, t.bg
+	colorOrange, colorYellow, colorCyan, colorGreen, colorRed = t.orange, t.yellow, t.cyan, t.green, t.red
+	vramColor = lipgloss.Color(t.vram)
+	blocksColor = lipgloss.Color(t.blocks)
+	fragmentationColor = lipgloss.Color(t.fragmentation)
+	prefixHitRateColor = lipgloss.Color(t.prefixHitRate)
+	throughputColor = lipgloss.Color(t.throughput)
+	buildStyles()
+}
+
+// keyActions are the dashboard actions ui.keys can rebind, with their default keys
+var keyActions = map[string]string{
+	"quit":            "q",
+	"help":            "?",
+	"settings":        ",",
+	"threshold":       "t",
+	"smoothing":       "S",
+	"stat_view":       "a",
+	"add_endpoint":    "n",
+	"edit_endpoint":   "e",
+	"remove_endpoint": "d",
+	"deploy":          "D",
+	"models":          "m",
+	"spindown":        "s",
+	"optimize":        "o",
+	"search":          "/",
+	"refresh":         "r",
+	"grid":            "g",
+	"fleet":           "F",
+	"usage_calendar":  "H",
+	"logs":            "L",
+	"queue":           "Q",
+	"alerts":          "A",
+	"zoom_in":         "+",
+	"zoom_out":        "-",
+	"pan_back":        "h",
+	"pan_forward":     "l",
+	"units":           "u",
+	"timeline":        "P",
+	"poll_faster":     "[",
+	"poll_slower":     "]",
+	"group_endpoints": "T",
+	"collapse_group":  "z",
+	"carousel":        "C",
+	"pause":           "p",
+	"share":           "x",
+	"export":          "X",
+	"yank":            "y",
+}
+
+// keyMap turns a pressed key into the default key of the action bound to it.
+// Keys it doesn't list keep their default meaning; "" does nothing.
+type keyMap map[string]string
+
+// bindKeys builds the keyMap for ui.keys, skipping unknown actions and keys
+// bound to more than one action
+func bindKeys(overrides map[string]string) keyMap {
+	actions := make([]string, 0, len(overrides))
+	for action := range overrides {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	km := keyMap{}
+	for _, action := range actions {
+		key, def := overrides[action], keyActions[action]
+		switch {
+		case def == "":
+			utils.Warn("ui.keys: unknown action %q", action)
+		case key == "" || key == def:
+		case km[key] != "":
+			utils.Warn("ui.keys: %q is bound to more than one action, keeping the first", key)
+		default:
+			km[key] = def
+		}
+	}
+	// A rebound action's default key is freed, unless another action took it
+	var freed []string
+	for _, def := range km {
+		if _, taken := km[def]; !taken {
+			freed = append(freed, def)
+		}
+	}
+	for _, def := range freed {
+		km[def] = ""
+	}
+	return km
+}
+
+// resolve returns the default key of the action key is bound to
+func (k keyMap) resolve(key string) string {
+	if def, ok := k[key]; ok {
+		return def
+	}
+	return key
+}
+
+// key returns the key bound to action; "" when another action took its default key
+func (k keyMap) key(action string) string {
+	def := keyActions[action]
+	for key, d := range k {
+		if d == def {
+			return key
+		}
+	}
+	if _, taken := k[def]; taken {
+		return ""
+	}
+	return def
+}
+
+// option returns the one of names value matches, else the default
+func option(setting, value string, names []string) string {
+	for _, name := range names {
+		if strings.EqualFold(value, name) {
+			return name
+		}
+	}
+	if value != "" {
+		utils.Warn("unknown ui %s %q, using %s", setting, value, names[0])
+	}
+	return names[0]
+}
+
+// applyPrefs adopts the config's ui section. The layout is only read at
+// startup, see startLayout.
+func (m *DashboardModel) applyPrefs(p *config.UIPrefs) {
+	var prefs config.UIPrefs
+	if p != nil {
+		prefs = *p
+	}
+	// Only registered and rebound when changed, so their warnings aren't
+	// repeated on every reload
+	if m.keys == nil || !reflect.DeepEqual(prefs.Themes, m.prefs.Themes) {
+		registerThemes(prefs.Themes)
+	}
+	if m.keys == nil || !reflect.DeepEqual(prefs.Keys, m.prefs.Keys) {
+		m.keys = bindKeys(prefs.Keys)
+	}
+	prefs.Theme = option("theme", prefs.Theme, themeNames)
+	if strings.EqualFold(prefs.Units, "mb") {
+		// What MiB were called before GB meant 10^9 bytes
+		prefs.Units = "mib"
+	}
+	prefs.Units = option("units", prefs.Units, unitNames)
+	prefs.ChartStyle = option("chart_style", prefs.ChartStyle, chartStyleNames)
+	prefs.Layout = option("layout", prefs.Layout, layoutNames)
+	m.prefs = prefs
+	m.theme = prefs.Theme
+	if m.themeFlag != "" {
+		m.theme = option("theme", m.themeFlag, themeNames)
+	}
+	applyTheme(m.theme)
+	m.units = memUnits[prefs.Units]
+}
+
+// SetTheme uses the named theme for this run instead of ui.theme, without
+// saving it; "" keeps ui.theme. Picking a theme in the settings popup
+// replaces it.
+func (m *DashboardModel) SetTheme(name string) error {
+	if name == "" {
+		return nil
+	}
+	for _, t := range themeNames {
+		if strings.EqualFold(name, t) {
+			m.themeFlag = t
+			m.applyPrefs(&m.prefs)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown theme %q (themes: %s)", name, strings.Join(themeNames, ", "))
+}
+
+// startLayout opens the view ui.layout names; kiosk mode picks its own
+func (m *DashboardModel) startLayout() {
+	if m.kiosk {
+		return
+	}
+	switch m.prefs.Layout {
+	case "grid", "fleet":
+		m.showingGrid, m.gridPanels = true, m.prefs.Layout == "fleet"
+		m.hovered = m.selected
+	case "usage":
+		m.showingUsage = true
+		m.usageEndpoint = m.selected
+	}
+}
+
+// reboundKeys lists the rebound actions for the help popup, like its other lines
+func (m *DashboardModel) reboundKeys() []string {
+	var out []string
+	for action := range keyActions {
+		if key := m.keys.key(action); key != keyActions[action] {
+			if key == "" {
+				key = "(none)"
+			}
+			out = append(out, fmt.Sprintf("%-9s - %s", key, action))
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// queueRow is one line of the request queue view: a model's running and
+// waiting requests over its samples, oldest first
+type queueRow struct {
+	name             string
+	running, waiting []float64
+}
+
+// peak is the largest of values, 0 for none
+func peak(values []float64) float64 {
+	top := 0.0
+	for _, v := range values {
+		top = max(top, v)
+	}
+	return top
+}
+
+// queueRows are the selected endpoint's totals followed by each model with
+// samples, by ID
+func (m *DashboardModel) queueRows() []queueRow {
+	rows := []queueRow{{
+		name:    "All models",
+		running: m.getHistory(func(dp DataPoint) float64 { return dp.RequestsRunning }),
+		waiting: m.getHistory(func(dp DataPoint) float64 { return dp.RequestsWaiting }),
+	}}
+	ids := make([]string, 0, len(m.modelHistory))
+	for id := range m.modelHistory {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		row := queueRow{name: id}
+		for _, s := range m.modelHistory[id].list() {
+			row.running = append(row.running, s.RequestsRunning)
+			row.waiting = append(row.waiting, s.RequestsWaiting)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func (m *DashboardModel) updateQueueMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case m.keys.key("quit"), "ctrl+c":
+		return m.quit()
+	case m.keys.key("queue"), "esc":
+		m.showingQueue = false
+	case "j", "down":
+		if m.queueScroll < len(m.modelHistory) {
+			m.queueScroll++
+		}
+	case "k", "up":
+		if m.queueScroll > 0 {
+			m.queueScroll--
+		}
+	}
+	return m, nil
+}
+
+// renderQueue draws running and waiting requests per model as a pair of
+// sparklines each, on the scale of the row's busiest sample so they compare.
+// A queue building up on one model is the first sign it needs more capacity.
+func (m *DashboardModel) renderQueue(width, height int) string {
+	width, height = ensureMin(width, height, 40, 8)
+	if m.selected >= len(m.endpoints) || m.last == nil {
+		return m.renderEmptyState(width, height, "No data yet\n\nPress 'Q' to go back", colorUnfocused)
+	}
+
+	var b strings.Builder
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(colorText))
+	b.WriteString(title.Render("Request queue: "+truncateString(m.endpoints[m.selected].Name, width-40)) +
+		styleColor(colorMuted).Render(" · ") + styleColor(colorCyan).Render("running") +
+		styleColor(colorMuted).Render(" / ") + styleColor(colorYellow).Render("waiting") + "\n\n")
+
+	rows := m.queueRows()
+	const nameWidth, countWidth, peakWidth = 24, 10, 12
+	contentWidth := width - 4
+	sparkWidth := max(4, (contentWidth-nameWidth-2*countWidth-peakWidth-3)/2)
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(colorText)).Bold(true)
+
+	reported := false
+	visible := max(1, height-6)
+	start := min(m.queueScroll, max(0, len(rows)-visible))
+	for i, row := range rows {
+		if i > 0 && (peak(row.waiting) > 0 || peak(row.running) > 0) {
+			reported = true
+		}
+		if i < start || i >= start+visible {
+			continue
+		}
+		top := maxFloat(1, maxFloat(peak(row.running), peak(row.waiting)))
+		var running, waiting float64
+		if n := len(row.running); n > 0 {
+			running, waiting = row.running[n-1], row.waiting[n-1]
+		}
+		name := labelStyle.Render(fmt.Sprintf("%-*s", nameWidth, truncateString(row.name, nameWidth-1)))
+		b.WriteString(name +
+			styleColor(colorCyan).Render(fmt.Sprintf("%*.0f ", countWidth-1, running)) +
+			styleColor(colorCyan).Render(renderScaledSparkline(row.running, sparkWidth, top)) + " " +
+			styleColor(getWaitingColor(waiting)).Render(fmt.Sprintf("%*.0f ", countWidth-1, waiting)) +
+			styleColor(colorYellow).Render(renderScaledSparkline(row.waiting, sparkWidth, top)) +
+			styleColor(colorMuted).Render(fmt.Sprintf("  peak %.0f", peak(row.waiting))) + "\n")
+	}
+	if len(rows) > visible {
+		b.WriteString(styleColor(colorMuted).Render(fmt.Sprintf("\n[%d-%d of %d]", start+1, min(start+visible, len(rows)), len(rows))))
+	}
+	if !reported && len(rows) > 1 {
+		b.WriteString("\n" + styleColor(colorMuted).Render("No requests per model reported yet; older servers only send the totals"))
+	}
+
+	return lipgloss.NewStyle().Width(width).Height(height).Padding(1, 2).Render(b.String())
+}
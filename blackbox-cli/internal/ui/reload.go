@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+// configReloadDelay lets a burst of writes to the config settle before it is read
+const configReloadDelay = 200 * time.Millisecond
+
+// configReloadMsg is the config read again after its file changed
+type configReloadMsg struct {
+	cfg *config.Config
+	err error
+}
+
+// WatchConfig reloads the config whenever the file at path changes and hands
+// it to the running program, so endpoints added or edited by another command
+// or an editor show up without restarting the dashboard. The directory is
+// watched rather than the file, since editors replace the file when saving.
+// The returned func stops watching.
+func WatchConfig(p *tea.Program, path string) (func(), error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create config directory: %w", err)
+	}
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch config: %w", err)
+	}
+	if err := w.Add(dir); err != nil {
+		w.Close()
+		return nil, fmt.Errorf("failed to watch %s: %w", dir, err)
+	}
+
+	reload := func() {
+		// Mid-replace, or deleted: keep what's shown rather than fall back to the default endpoint
+		if _, err := os.Stat(path); err != nil {
+			return
+		}
+		cfg, err := config.Load()
+		p.Send(configReloadMsg{cfg: cfg, err: err})
+	}
+	go func() {
+		var timer *time.Timer
+		for {
+			select {
+			case ev, ok := <-w.Events:
+				if !ok {
+					if timer != nil {
+						timer.Stop()
+					}
+					return
+				}
+				if filepath.Clean(ev.Name) != filepath.Clean(path) || !ev.Has(fsnotify.Write) && !ev.Has(fsnotify.Create) && !ev.Has(fsnotify.Rename) {
+					continue
+				}
+				utils.Debug("config %s: %s", ev.Op, ev.Name)
+				if timer == nil {
+					timer = time.AfterFunc(configReloadDelay, reload)
+				} else {
+					timer.Reset(configReloadDelay)
+				}
+			case err, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+				utils.Warn("config watcher: %v", err)
+			}
+		}
+	}()
+	return func() { w.Close() }, nil
+}
+
+// applyConfig adopts a config read again from disk, ui preferences included.
+// The selected endpoint keeps its history and polling unless it was removed or edited.
+func (m *DashboardModel) applyConfig(msg configReloadMsg) tea.Cmd {
+	if msg.err != nil {
+		utils.Warn("config not reloaded: %v", msg.err)
+		m.configMessage = "✗ config: " + msg.err.Error()
+		return nil
+	}
+	old := m.config
+	m.config = msg.cfg
+	m.applyPrefs(msg.cfg.UI)
+	// Also what the dashboard's own saves look like
+	if old != nil && reflect.DeepEqual(old.Endpoints, msg.cfg.Endpoints) {
+		return nil
+	}
+
+	prev := make(map[string]config.Endpoint, len(m.endpoints))
+	for _, ep := range m.endpoints {
+		prev[ep.Name] = ep
+	}
+	var selectedName, hoveredName string
+	if m.selected < len(m.endpoints) {
+		selectedName = m.endpoints[m.selected].Name
+	}
+	if m.hovered < len(m.endpoints) {
+		hoveredName = m.endpoints[m.hovered].Name
+	}
+	m.setEndpoints(msg.cfg.Endpoints)
+	// Edited endpoints start over in the fleet with their new settings
+	for _, ep := range m.endpoints {
+		if was, ok := prev[ep.Name]; ok && !reflect.DeepEqual(was, ep) {
+			delete(m.fleet, ep.Name)
+		}
+	}
+	m.configMessage = "✓ config reloaded"
+	utils.Info("config reloaded: %d endpoints", len(m.endpoints))
+
+	if len(m.endpoints) == 0 {
+		// Nothing left to show, or --select hides everything
+		m.client = nil
+		m.last = nil
+		return m.syncFleet()
+	}
+	cmds := []tea.Cmd{m.syncFleet()}
+	idx := -1
+	for i, ep := range m.endpoints {
+		if ep.Name == selectedName {
+			idx = i
+			break
+		}
+	}
+	switch {
+	case idx == m.selected && m.client != nil:
+		if !reflect.DeepEqual(prev[selectedName], m.endpoints[idx]) {
+			// Its polls pick up the new client from the next one on
+			m.selectEndpoint(idx)
+			cmds = append(cmds, fetchSnapshot(m.ctx, m.client, m.timeout, m.selected, m.fetchSequence))
+		}
+	default:
+		// Moved or gone: polls for the old position stop on their own
+		if idx < 0 {
+			idx = min(m.selected, len(m.endpoints)-1)
+		}
+		m.selectEndpoint(idx)
+		cmds = append(cmds, startPolling(m.ctx, m.client, m.selected, m.fetchSequence))
+	}
+	for i, ep := range m.endpoints {
+		if ep.Name == hoveredName {
+			m.hovered = i
+			break
+		}
+	}
+	return tea.Batch(cmds...)
+}
@@ -5,7 +5,6 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
-	"github.com/maxdcmn/blackbox-cli/internal/config"
 )
 
 func (m *DashboardModel) renderMetricsGrid(width, height int, focused bool) string {
@@ -24,35 +23,62 @@ func (m *DashboardModel) renderMetricsGrid(width, height int, focused bool) stri
 
 	if m.last == nil || m.lastErr != nil {
 		rows = []string{
-			fmt.Sprintf("%s %s", labelStyle.Render("Allocated VRAM:"), styleColor(colorMuted).Render("-- GB")),
-			fmt.Sprintf("%s %s", labelStyle.Render("Used KV Cache:"), styleColor(colorMuted).Render("-- GB")),
+			fmt.Sprintf("%s %s", labelStyle.Render("Allocated VRAM:"), styleColor(colorMuted).Render("-- "+m.memUnitFor(0).label)),
+			fmt.Sprintf("%s %s", labelStyle.Render("Used KV Cache:"), styleColor(colorMuted).Render("-- "+m.memUnitFor(0).label)),
 		}
 	} else {
-		totalGB := float64(m.last.TotalVRAMBytes) / gbDivisor
-		allocatedGB := float64(m.last.AllocatedVRAMBytes) / gbDivisor
-		usedKVCacheGB := float64(m.last.UsedKVCacheBytes) / gbDivisor
+		// The window's p95, p99 or max instead of its average, picked with `a`
+		stat, view := m.statSnapshot(), ""
+		if m.windowAgg() != nil {
+			view = " (" + statViews[m.statView] + ")"
+		}
 		allocatedPercent := 0.0
-		if m.last.TotalVRAMBytes > 0 {
-			allocatedPercent = (float64(m.last.AllocatedVRAMBytes) / float64(m.last.TotalVRAMBytes)) * 100.0
+		if stat.TotalVRAMBytes > 0 {
+			allocatedPercent = (float64(stat.AllocatedVRAMBytes) / float64(stat.TotalVRAMBytes)) * 100.0
 		}
 
+		allocated, total := m.memOf(stat.AllocatedVRAMBytes, stat.TotalVRAMBytes)
 		rows = []string{
-			fmt.Sprintf("%s %s / %s GB", labelStyle.Render("Allocated VRAM:"),
-				styleColor(colorOrange).Render(fmt.Sprintf("%.2f", allocatedGB)),
-				styleColor(colorItalic).Render(fmt.Sprintf("%.2f", totalGB))),
-			fmt.Sprintf("%s %s", labelStyle.Render("Allocated %:"),
+			fmt.Sprintf("%s %s%s", labelStyle.Render("Allocated VRAM"+view+":"),
+				styleColor(colorOrange).Render(allocated), styleColor(colorItalic).Render(total)),
+			fmt.Sprintf("%s %s", labelStyle.Render("Allocated %"+view+":"),
 				styleColor(getPercentColor(allocatedPercent)).Render(fmt.Sprintf("%.1f%%", allocatedPercent))),
-			fmt.Sprintf("%s %s GB", labelStyle.Render("Used KV Cache:"),
-				styleColor(colorGreen).Render(fmt.Sprintf("%.2f", usedKVCacheGB))),
+			fmt.Sprintf("%s %s", labelStyle.Render("Used KV Cache"+view+":"),
+				styleColor(colorGreen).Render(m.mem(stat.UsedKVCacheBytes, stat.TotalVRAMBytes))),
+		}
+		if m.partial.stats {
+			rows = append(rows, labelStyle.Render("Stats:")+" "+styleColor(colorYellow).Render(m.partialNote()))
+		}
+
+		// Per-GPU breakdown, so one saturated device doesn't hide in the total
+		if len(m.last.GPUs) > 0 {
+			rows = append(rows, "", labelStyle.Render("GPUs:"))
+			for _, gpu := range m.last.GPUs {
+				gpuPercent := 0.0
+				if gpu.TotalVRAMBytes > 0 {
+					gpuPercent = float64(gpu.AllocatedVRAMBytes) / float64(gpu.TotalVRAMBytes) * 100.0
+				}
+				name := labelStyle.Render(fmt.Sprintf("  %d %s:", gpu.Index, truncateString(gpu.Name, 24)))
+				if gpu.UtilizationPercent > 0 {
+					name += " " + styleColor(getPercentColor(gpu.UtilizationPercent)).Render(fmt.Sprintf("%.0f%% util", gpu.UtilizationPercent))
+				}
+				rows = append(rows, name)
+				allocated, total := m.memOf(gpu.AllocatedVRAMBytes, gpu.TotalVRAMBytes)
+				rows = append(rows, fmt.Sprintf("%s %s%s %s", labelStyle.Render("    VRAM:"),
+					styleColor(colorOrange).Render(allocated), styleColor(colorItalic).Render(total),
+					styleColor(getPercentColor(gpuPercent)).Render(fmt.Sprintf("(%.1f%%)", gpuPercent))))
+			}
 		}
 
 		// Show per-model breakdown
-		if len(m.last.Models) > 0 {
+		if len(m.last.Models) > 0 || m.partial.models {
 			rows = append(rows, "")
-			rows = append(rows, labelStyle.Render("Models:"))
+			models := labelStyle.Render("Models:")
+			if m.partial.models {
+				models += " " + styleColor(colorYellow).Render(m.partialNote())
+			}
+			rows = append(rows, models)
 			for _, model := range m.last.Models {
-				modelAllocatedGB := float64(model.AllocatedVRAMBytes) / gbDivisor
-				modelUsedKVCacheGB := float64(model.UsedKVCacheBytes) / gbDivisor
 				modelName := model.ModelID
 				if len(modelName) > 20 {
 					modelName = modelName[:20] + "..."
@@ -62,10 +88,24 @@ func (m *DashboardModel) renderMetricsGrid(width, height int, focused bool) stri
 					styleColor(colorItalic).Render(fmt.Sprintf("(port %d)", model.Port))))
 				rows = append(rows, fmt.Sprintf("%s %s",
 					labelStyle.Render("    Used KV Cache:"),
-					styleColor(colorGreen).Render(fmt.Sprintf("%.2f GB", modelUsedKVCacheGB))))
+					styleColor(colorGreen).Render(m.mem(model.UsedKVCacheBytes, m.last.TotalVRAMBytes))))
 				rows = append(rows, fmt.Sprintf("%s %s",
 					labelStyle.Render("    Allocated VRAM:"),
-					styleColor(colorOrange).Render(fmt.Sprintf("%.2f GB", modelAllocatedGB))))
+					styleColor(colorOrange).Render(m.mem(model.AllocatedVRAMBytes, m.last.TotalVRAMBytes))))
+				if latency := modelLatencyRow(model, labelStyle); latency != "" {
+					rows = append(rows, latency)
+				}
+				if model.GenerationTokensPerSecond > 0 {
+					rows = append(rows, fmt.Sprintf("%s %s",
+						labelStyle.Render("    Throughput:"),
+						styleColor(colorCyan).Render(fmt.Sprintf("%.1f tok/s", model.GenerationTokensPerSecond))))
+				}
+				if model.NumRequestsRunning > 0 || model.NumRequestsWaiting > 0 {
+					rows = append(rows, fmt.Sprintf("%s %s %s",
+						labelStyle.Render("    Requests:"),
+						styleColor(colorCyan).Render(fmt.Sprintf("%.0f running", model.NumRequestsRunning)),
+						styleColor(getWaitingColor(model.NumRequestsWaiting)).Render(fmt.Sprintf("%.0f waiting", model.NumRequestsWaiting))))
+				}
 			}
 		}
 	}
@@ -124,10 +164,17 @@ func (m *DashboardModel) renderEndpointsPanel(width, height int, focused bool) s
 		headerColor = colorGreen
 	}
 	header := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(headerColor)).Render("Endpoints")
-	b.WriteString(header + "\n\n")
+	b.WriteString(header + "\n")
+	// The line under the header describes the hovered endpoint
+	if m.hovered >= 0 && m.hovered < len(m.endpoints) && m.endpoints[m.hovered].Description != "" {
+		b.WriteString(styleColor(colorItalic).Italic(true).Render(truncateString(m.endpoints[m.hovered].Description, max(1, width-4))))
+	}
+	b.WriteString("\n")
 
 	innerHeight := max(1, height-3)
 	totalEndpoints := len(m.endpoints)
+	rows := m.endpointRows()
+	hoveredRow := 0
 
 	if m.endpointsScroll < 0 {
 		m.endpointsScroll = 0
@@ -142,30 +189,63 @@ func (m *DashboardModel) renderEndpointsPanel(width, height int, focused bool) s
 		if m.selected >= totalEndpoints {
 			m.selected = totalEndpoints - 1
 		}
-		if m.selected < m.endpointsScroll {
-			m.endpointsScroll = m.selected
-		} else if m.selected >= m.endpointsScroll+innerHeight {
-			m.endpointsScroll = m.selected - innerHeight + 1
+		if m.hovered < 0 || m.hovered >= totalEndpoints {
+			m.hovered = m.selected
+		}
+		// Scroll by rows; group headers take a line too
+		hoveredRow = m.hoveredRow(rows)
+		if hoveredRow < m.endpointsScroll {
+			m.endpointsScroll = hoveredRow
+		} else if hoveredRow >= m.endpointsScroll+innerHeight {
+			m.endpointsScroll = hoveredRow - innerHeight + 1
 		}
-		if m.endpointsScroll > totalEndpoints-innerHeight {
-			m.endpointsScroll = max(0, totalEndpoints-innerHeight)
+		if m.endpointsScroll > len(rows)-innerHeight {
+			m.endpointsScroll = max(0, len(rows)-innerHeight)
 		}
 	}
 
-	var visibleEndpoints []config.Endpoint
-	if totalEndpoints > 0 && m.endpointsScroll < totalEndpoints {
-		visibleEndpoints = m.endpoints[m.endpointsScroll:]
-		if len(visibleEndpoints) > innerHeight {
-			visibleEndpoints = visibleEndpoints[:innerHeight]
+	var visibleRows []endpointRow
+	if len(rows) > 0 && m.endpointsScroll < len(rows) {
+		visibleRows = rows[m.endpointsScroll:]
+		if len(visibleRows) > innerHeight {
+			visibleRows = visibleRows[:innerHeight]
 		}
 	}
 
 	availableWidth := max(0, width-4)
-	for i, ep := range visibleEndpoints {
-		actualIndex := m.endpointsScroll + i
-		name := truncateString(ep.Name, max(1, availableWidth))
+	showSpark := availableWidth >= sparklineWidth+8
+	for i, row := range visibleRows {
+		ep := m.endpoints[row.idx]
+		// The active endpoint is marked; the highlight follows the cursor
+		marker := "  "
+		if row.header {
+			// A collapsed group is marked when it holds the active endpoint
+			if m.collapsed[row.group] && m.groupOf(m.endpoints[m.selected]) == row.group {
+				marker = "● "
+			}
+		} else if row.idx == m.selected {
+			marker = "● "
+		}
+		// Endpoints are indented under their group's header
+		label, indent := ep.Name, ""
+		if row.header {
+			label = m.groupHeader(row)
+		} else if m.groupBy != "" {
+			indent = "  "
+		}
+		name := marker + indent + truncateString(label, max(1, availableWidth-2-len(indent)))
+		if showSpark && !row.header {
+			// Name on the left, VRAM% trend from the fleet poller on the right
+			nameWidth := availableWidth - sparklineWidth - 1
+			name = marker + indent + truncateString(ep.Name, nameWidth-2-len(indent))
+			var spark []float64
+			if st := m.fleet[ep.Name]; st != nil {
+				spark = st.vramPercent
+			}
+			name += strings.Repeat(" ", nameWidth-lipgloss.Width(name)+1) + renderSparkline(spark, sparklineWidth)
+		}
 
-		if actualIndex == m.selected {
+		if m.endpointsScroll+i == hoveredRow && m.hoverable(row) {
 			style := lipgloss.NewStyle().
 				Background(lipgloss.Color(colorText)).
 				Foreground(lipgloss.Color(colorBg)).
@@ -177,6 +257,7 @@ func (m *DashboardModel) renderEndpointsPanel(width, height int, focused bool) s
 			style := lipgloss.NewStyle().
 				Background(lipgloss.Color(colorBg)).
 				Foreground(lipgloss.Color(colorText)).
+				Bold(row.header).
 				Width(availableWidth).
 				Align(lipgloss.Left)
 			b.WriteString(style.Render(name) + "\n")
@@ -193,7 +274,7 @@ func (m *DashboardModel) renderDataPanel(width, height int, focused bool) string
 		borderColor = colorUnfocused
 	}
 
-	if m.client == nil {
+	if m.client == nil && m.viewing == nil {
 		return m.renderEmptyState(width, height, "No endpoint selected\n\nPress 'n' to create one", borderColor)
 	}
 
@@ -202,37 +283,96 @@ func (m *DashboardModel) renderDataPanel(width, height int, focused bool) string
 	}
 
 	if m.lastErr != nil && m.last == nil {
-		return m.renderEmptyState(width, height, fmt.Sprintf("Error: %s\n\nPress 'r' to retry", m.lastErr.Error()), borderColor)
+		return m.renderEmptyState(width, height, fmt.Sprintf("Error: %s\n\nPress 'r' to retry", errorText(m.lastErr)), borderColor)
+	}
+
+	charts := m.visibleCharts()
+	gpuCharts := charts[len(charts)-1].needs == "gpu"
+	footer := []string{m.renderRequestsLine(width)}
+	if m.chartModel != "" {
+		footer = []string{m.renderModelLine(width)}
+	} else if !gpuCharts && m.hasTelemetry() {
+		footer = append(footer, m.renderGPULine(width))
 	}
 
 	innerHeight := height - 2
-	availableHeight := innerHeight - 2
-	boxHeight := max(5, availableHeight/3)
+	// A gap goes under each chart, then the footer lines
+	availableHeight := innerHeight - len(charts) - len(footer)
+	boxHeight := max(5, availableHeight/len(charts))
+	if m.chartModel != "" {
+		return m.renderDataLines(m.renderModelCharts(boxHeight, width), footer, width, height, focused)
+	}
 
-	allocatedMB := int(m.last.AllocatedVRAMBytes / (1024 * 1024))
-	totalMB := int(m.last.TotalVRAMBytes / (1024 * 1024))
-	vramMax := maxFloat(100.0, m.maxVRAMSeen)
+	stat := m.statSnapshot()
+	allocatedMB := int(stat.AllocatedVRAMBytes / (1024 * 1024))
+	totalMB := int(stat.TotalVRAMBytes / (1024 * 1024))
+	vramMax := m.memChartMax(m.maxVRAMSeen, stat.TotalVRAMBytes)
 	vramContent := m.renderMetricContent("Allocated VRAM", boxHeight, width, allocatedMB, totalMB, 0, m.getVRAMHistory(), vramColor, vramMax)
 
-	usedKVCacheMB := int(m.last.UsedKVCacheBytes / (1024 * 1024))
-	kvCacheMax := maxFloat(100.0, m.maxBlocksSeen)
+	usedKVCacheMB := int(stat.UsedKVCacheBytes / (1024 * 1024))
+	kvCacheMax := m.memChartMax(m.maxBlocksSeen, stat.TotalVRAMBytes)
 	kvCacheContent := m.renderMetricContent("Used KV Cache", boxHeight, width, usedKVCacheMB, 0, 0, m.getBlocksHistory(), blocksColor, kvCacheMax)
 
-	prefixHitRate := int(m.last.PrefixCacheHitRate)
+	prefixHitRate := int(stat.PrefixCacheHitRate)
 	prefixHitRateMax := maxFloat(100.0, m.maxPrefixHitRateSeen)
 	prefixHitRateContent := m.renderMetricContent("Prefix Cache Hit Rate", boxHeight, width, prefixHitRate, 0, 0, m.getPrefixCacheHitRateHistory(), prefixHitRateColor, prefixHitRateMax)
 
+	// Scaled to the highest rate seen, as there's no natural top
+	throughput := m.getHistory(func(dp DataPoint) float64 { return dp.Throughput })
+	throughputContent := m.renderMetricContent("Throughput", boxHeight, width, int(m.last.Throughput()), int(m.maxThroughputSeen), 0, throughput, throughputColor, maxFloat(10.0, m.maxThroughputSeen))
+
+	contents := []string{vramContent, kvCacheContent, prefixHitRateContent, throughputContent}
+	if m.hasLatency() {
+		contents = append(contents, m.renderLatencyChart(boxHeight, width))
+	}
+	if gpuCharts {
+		contents = append(contents, m.renderGPUCharts(boxHeight, width)...)
+	}
+	return m.renderDataLines(contents, footer, width, height, focused)
+}
+
+// renderDataLines stacks the data panel's charts, a gap under each, over its footer lines
+func (m *DashboardModel) renderDataLines(contents, footer []string, width, height int, focused bool) string {
 	emptyLine := lipgloss.NewStyle().Background(lipgloss.Color(colorBg)).Render(strings.Repeat(" ", max(0, width-2)))
-	combined := strings.Join([]string{
-		strings.TrimRight(vramContent, "\n"),
-		emptyLine,
-		strings.TrimRight(kvCacheContent, "\n"),
-		emptyLine,
-		strings.TrimRight(prefixHitRateContent, "\n"),
-	}, "\n")
+	var lines []string
+	for _, content := range contents {
+		lines = append(lines, strings.TrimRight(content, "\n"), emptyLine)
+	}
+	combined := strings.Join(append(lines, footer...), "\n")
 	return borderStyle(width, height, focused).Render(combined)
 }
 
+// renderRequestsLine draws running and waiting requests as a pair of
+// sparklines, both on the scale of the busiest sample so they compare
+func (m *DashboardModel) renderRequestsLine(width int) string {
+	running := m.getHistory(func(dp DataPoint) float64 { return dp.RequestsRunning })
+	waiting := m.getHistory(func(dp DataPoint) float64 { return dp.RequestsWaiting })
+	top := 1.0
+	for i := range running {
+		top = max(top, running[i], waiting[i])
+	}
+
+	title := lipgloss.NewStyle().Foreground(lipgloss.Color(colorText)).Bold(true).Render("Requests")
+	runningText := "  running " + styleColor(colorCyan).Render(fmt.Sprintf("%.0f", m.last.NumRequestsRunning))
+	waitingText := "  waiting " + styleColor(getWaitingColor(m.last.NumRequestsWaiting)).Render(fmt.Sprintf("%.0f", m.last.NumRequestsWaiting))
+	line := title + runningText + waitingText
+
+	sparkWidth := (width - 4 - lipgloss.Width(line) - 2) / 2
+	if sparkWidth >= 4 {
+		line = title + runningText + " " + styleColor(colorCyan).Render(renderScaledSparkline(running, sparkWidth, top)) +
+			waitingText + " " + styleColor(colorYellow).Render(renderScaledSparkline(waiting, sparkWidth, top))
+	}
+	return lipgloss.NewStyle().Background(lipgloss.Color(colorBg)).Width(max(0, width-2)).MaxWidth(max(0, width-2)).Render(line)
+}
+
+// getWaitingColor flags a request queue building up
+func getWaitingColor(waiting float64) string {
+	if waiting >= 1 {
+		return colorOrange
+	}
+	return colorGreen
+}
+
 func (m *DashboardModel) renderEmptyState(width, height int, message string, borderColor string) string {
 	width, height = ensureMin(width, height, 10, 3)
 
@@ -256,9 +396,26 @@ func (m *DashboardModel) renderMetricContent(title string, height, width int, va
 	width, height = ensureMin(width, height, 10, 5)
 
 	var b strings.Builder
+	if !m.scrubbing && m.selected < len(m.endpoints) && m.flashRed(m.endpoints[m.selected].Name, title) {
+		color = lipgloss.Color(colorRed)
+	}
 	titleStyle := lipgloss.NewStyle().Foreground(color).Bold(true)
 	valuesText := m.formatMetricValues(title, val1, val2, val3)
-	b.WriteString(fmt.Sprintf("%s  %s\n", titleStyle.Render(title), valuesText))
+	titleText := title
+	if m.focusedPanel == 2 && m.selectedChartDef().title == title {
+		titleText = "▶ " + title
+	}
+	if label := m.statLabel(); label != "" && statCharts[title] {
+		valuesText += styleColor(colorItalic).Render("  " + label)
+	}
+	if label := m.zoomLabel(); label != "" {
+		valuesText += styleColor(colorItalic).Render("  " + label)
+	}
+	if m.smoothedCharts[title] {
+		history = ema(history, m.smoothingAlpha)
+		valuesText += styleColor(colorItalic).Render(fmt.Sprintf("  EMA α=%.2g", m.smoothingAlpha))
+	}
+	b.WriteString(fmt.Sprintf("%s  %s\n", titleStyle.Render(titleText), valuesText))
 
 	if len(history) >= 1 {
 		chartHeight := max(4, height-1)
@@ -291,9 +448,43 @@ func (m *DashboardModel) renderStatusBar(width, height int, endpointsFocused boo
 	width, height = ensureMin(width, height, 10, 1)
 
 	helpText := styleColor(colorItalic).Render("?: help")
+	if poll := m.pollStatus(); poll != "" {
+		helpText = styleColor(colorMuted).Render(poll) + "  " + helpText
+	}
+	if m.cycling {
+		helpText = styleColor(colorCyan).Render(fmt.Sprintf("⟳ %s", m.cycleDwell)) + "  " + helpText
+	}
+	if m.paused {
+		helpText = styleColor(colorYellow).Bold(true).Render("⏸ paused") + "  " + helpText
+	}
+	if m.shareMessage != "" {
+		helpText += "  " + styleColor(colorCyan).Render(m.shareMessage)
+	}
+	if m.configMessage != "" {
+		helpText += "  " + styleColor(colorCyan).Render(m.configMessage)
+	}
+	if m.selected < len(m.endpoints) && m.viewing == nil {
+		ep := m.endpoints[m.selected]
+		if a, ok := m.latestAnomaly(); ok {
+			helpText = styleColor(colorOrange).Bold(true).Render(string(anomalyRune)+" "+anomalySummary(a)) + "  " + helpText
+		}
+		if breached := m.breachedAlerts(ep.Name, m.last); len(breached) > 0 {
+			helpText = styleColor(colorRed).Bold(true).Render("▲ "+alertSummary(breached, ep)) + "  " + helpText
+		}
+	}
 	leftContent := helpText
 	if endpointsFocused {
-		leftText := styleColor(colorItalic).Render("n: new  e: edit  d: delete  D: deploy  q: quit")
+		hint := "Enter: switch  n: new  e: edit  d: delete  D: deploy  q: quit"
+		if m.selectedReadOnly() {
+			hint = "Enter: switch  n: new  e: edit  d: delete  (read-only)  q: quit"
+		}
+		if m.groupBy != "" {
+			hint = "Enter: switch  z: fold group  T: regroup  n: new  e: edit  d: delete  q: quit"
+		}
+		leftText := styleColor(colorItalic).Render(hint)
+		leftContent = helpText + "  " + leftText
+	} else if m.focusedPanel == 2 {
+		leftText := styleColor(colorItalic).Render("j/k: select chart  t: alert threshold  S: smooth  q: quit")
 		leftContent = helpText + "  " + leftText
 	}
 
@@ -302,12 +493,16 @@ func (m *DashboardModel) renderStatusBar(width, height int, endpointsFocused boo
 	githubTextStyled := styleColor(colorYellow).Underline(true).Bold(true).Render("GitHub")
 	githubTextLinked := fmt.Sprintf("\x1b]8;;%s\x1b\\%s\x1b]8;;\x1b\\", githubURL, githubTextStyled)
 	versionV := styleColor(colorGreen).Bold(true).Render("v")
-	versionNum := styleColor(colorGreen).Render(version)
+	versionNum := styleColor(colorGreen).Render(Version)
 	rightContent := star + " " + githubTextLinked + "  " + versionV + versionNum
 
 	availableWidth := width - 2
 	leftLen := lipgloss.Width(leftContent)
 	rightLen := lipgloss.Width(rightContent)
+	// Alerts and anomalies come first, so the links give way to them
+	if leftLen+1+rightLen > availableWidth {
+		rightContent, rightLen = "", 0
+	}
 	spacerLen := max(1, availableWidth-leftLen-rightLen)
 
 	content := leftContent + strings.Repeat(" ", spacerLen) + rightContent
@@ -322,33 +517,3 @@ func (m *DashboardModel) fillToHeight(b *strings.Builder, content string, width,
 		b.WriteString(bgFill + "\n")
 	}
 }
-
-type vllmMetrics struct {
-	requestsRunning float64
-	requestsWaiting float64
-	kvCacheUsage    float64
-}
-
-func parseVLLMMetrics(metricsStr string) vllmMetrics {
-	result := vllmMetrics{-1, -1, -1}
-	lines := strings.Split(metricsStr, "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "#") || line == "" {
-			continue
-		}
-		if idx := strings.LastIndex(line, " "); idx >= 0 {
-			var val float64
-			if _, err := fmt.Sscanf(line[idx+1:], "%f", &val); err == nil {
-				if strings.HasPrefix(line, "vllm:num_requests_running") {
-					result.requestsRunning = val
-				} else if strings.HasPrefix(line, "vllm:num_requests_waiting") {
-					result.requestsWaiting = val
-				} else if strings.HasPrefix(line, "vllm:kv_cache_usage_perc") {
-					result.kvCacheUsage = val
-				}
-			}
-		}
-	}
-	return result
-}
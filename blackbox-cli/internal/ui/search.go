@@ -0,0 +1,153 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/maxdcmn/blackbox-cli/internal/client"
+)
+
+const searchMaxVisible = 10
+
+// searchMsg carries the fleet-wide matches for query
+type searchMsg struct {
+	query string
+	locs  []client.ModelLocation
+	errs  []error
+}
+
+// searchFleet lists models on every endpoint and keeps those matching query.
+// The Multi is built here because the endpoint client cache isn't goroutine safe.
+func (m *DashboardModel) searchFleet(query string) tea.Cmd {
+	multi, ctx := client.NewMultiFunc(m.endpoints, m.timeout, m.endpointClient), m.ctx
+	return func() tea.Msg {
+		locs, errs := client.FindModels(ctx, multi, query)
+		return searchMsg{query: query, locs: locs, errs: errs}
+	}
+}
+
+func (m *DashboardModel) startSearch() {
+	m.searching = true
+	m.searchQuery = ""
+	m.searchRan = ""
+	m.searchPending = false
+	m.searchResults = nil
+	m.searchErrs = nil
+	m.searchSelected = 0
+}
+
+func (m *DashboardModel) updateSearchMode(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case searchMsg:
+		if !m.searchPending || msg.query != m.searchRan {
+			return m, nil
+		}
+		m.searchPending = false
+		m.searchResults = msg.locs
+		m.searchErrs = msg.errs
+		m.searchSelected = 0
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.searching = false
+			return m, nil
+		case "enter":
+			query := strings.TrimSpace(m.searchQuery)
+			if query == "" {
+				return m, nil
+			}
+			// A second Enter on the same query jumps to the selected result
+			if query == m.searchRan && !m.searchPending && m.searchSelected < len(m.searchResults) {
+				return m, m.jumpToResult(m.searchResults[m.searchSelected])
+			}
+			m.searchRan = query
+			m.searchPending = true
+			m.searchResults = nil
+			m.searchErrs = nil
+			return m, m.searchFleet(query)
+		case "down", "ctrl+n":
+			if m.searchSelected < len(m.searchResults)-1 {
+				m.searchSelected++
+			}
+		case "up", "ctrl+p":
+			if m.searchSelected > 0 {
+				m.searchSelected--
+			}
+		case "backspace":
+			if r := []rune(m.searchQuery); len(r) > 0 {
+				m.searchQuery = string(r[:len(r)-1])
+			}
+		case "ctrl+u":
+			m.searchQuery = ""
+		default:
+			if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+				m.searchQuery += string(msg.Runes)
+			}
+		}
+	}
+	return m, nil
+}
+
+// jumpToResult closes the search and selects the endpoint hosting loc
+func (m *DashboardModel) jumpToResult(loc client.ModelLocation) tea.Cmd {
+	m.searching = false
+	for i, ep := range m.endpoints {
+		if ep.Name != loc.Endpoint {
+			continue
+		}
+		if i == m.selected {
+			return nil
+		}
+		m.selectEndpoint(i)
+		return startPolling(m.ctx, m.client, m.selected, m.fetchSequence)
+	}
+	return nil
+}
+
+func (m *DashboardModel) renderSearchMode() string {
+	var b strings.Builder
+	b.WriteString("Find Model\n\n")
+	b.WriteString(fieldStyle.Render("Model: ") + activeFieldStyle.Render(m.searchQuery+"█") + "\n\n")
+
+	switch {
+	case m.searchPending:
+		b.WriteString(styleColor(colorMuted).Render(fmt.Sprintf("Searching %d endpoints...", len(m.endpoints))))
+	case m.searchRan == "":
+		b.WriteString(styleColor(colorMuted).Render("Glob or substring of a model ID, e.g. llama-3-70b or meta-llama/*"))
+	case len(m.searchResults) == 0:
+		b.WriteString(fmt.Sprintf("No model matching %q", m.searchRan))
+	default:
+		start := max(0, m.searchSelected-searchMaxVisible+1)
+		end := min(start+searchMaxVisible, len(m.searchResults))
+		for i := start; i < end; i++ {
+			loc := m.searchResults[i]
+			status := styleColor(colorGreen).Render("●")
+			if !loc.Running {
+				status = styleColor(colorRed).Render("○")
+			}
+			addr := loc.Host
+			if loc.Port > 0 {
+				addr = fmt.Sprintf("%s:%d", loc.Host, loc.Port)
+			}
+			line := fmt.Sprintf("%s %s  %s  %s", status, truncateString(loc.Endpoint, 16), styleColor(colorMuted).Render(addr), truncateString(loc.ModelID, 40))
+			if i == m.searchSelected {
+				line = activeFieldStyle.Render("> " + line)
+			} else {
+				line = "  " + line
+			}
+			b.WriteString(line + "\n")
+		}
+		if len(m.searchResults) > searchMaxVisible {
+			b.WriteString(fmt.Sprintf("\n[%d-%d of %d]", start+1, end, len(m.searchResults)))
+		}
+	}
+	if len(m.searchErrs) > 0 {
+		b.WriteString("\n" + styleColor(colorRed).Render(fmt.Sprintf("%d endpoints not searched: %s", len(m.searchErrs), truncateString(errorText(m.searchErrs[0]), 50))))
+	}
+
+	b.WriteString("\n\nEnter: search, again to jump  ↑/↓: select  Esc: close")
+	return popupStyle.Width(80).Render(b.String())
+}
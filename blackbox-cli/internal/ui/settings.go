@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+)
+
+// settingsRows are the preferences the settings popup changes, in order
+var settingsRows = []struct {
+	label   string
+	options func() []string // Read when used, since ui.themes adds to themeNames
+	value   func(p *config.UIPrefs) *string
+}{
+	{"Theme", func() []string { return themeNames }, func(p *config.UIPrefs) *string { return &p.Theme }},
+	{"Units", func() []string { return unitNames }, func(p *config.UIPrefs) *string { return &p.Units }},
+	{"Chart style", func() []string { return chartStyleNames }, func(p *config.UIPrefs) *string { return &p.ChartStyle }},
+	{"Start in", func() []string { return layoutNames }, func(p *config.UIPrefs) *string { return &p.Layout }},
+}
+
+func (m *DashboardModel) startSettings() {
+	m.settingsActive = true
+	m.settingsField = 0
+	m.settingsMessage = ""
+}
+
+func (m *DashboardModel) updateSettingsMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", m.keys.key("settings"):
+		m.settingsActive = false
+	case "ctrl+c":
+		return m.quit()
+	case "j", "down", "tab":
+		m.settingsField = (m.settingsField + 1) % len(settingsRows)
+	case "k", "up", "shift+tab":
+		m.settingsField = (m.settingsField + len(settingsRows) - 1) % len(settingsRows)
+	case "l", "right", "enter", " ":
+		m.changeSetting(1)
+	case "h", "left":
+		m.changeSetting(-1)
+	}
+	return m, nil
+}
+
+// unitsSetting is the Units row of settingsRows, which u also steps through
+const unitsSetting = 1
+
+func (m *DashboardModel) changeSetting(step int) {
+	m.settingsMessage = m.stepSetting(m.settingsField, step)
+}
+
+// stepSetting moves settingsRows[field] to its next (or previous) value,
+// shows it right away and saves it to the config, saying where
+func (m *DashboardModel) stepSetting(field, step int) string {
+	row := settingsRows[field]
+	prefs := m.prefs
+	value := row.value(&prefs)
+	if row.label == "Theme" {
+		// Picking a theme here replaces the one --theme chose for this run
+		*value, m.themeFlag = m.theme, ""
+	}
+	options := row.options()
+	i := 0
+	for j, option := range options {
+		if option == *value {
+			i = j
+		}
+	}
+	*value = options[(i+step+len(options))%len(options)]
+	m.applyPrefs(&prefs)
+	if m.config == nil {
+		return ""
+	}
+	if err := config.SetUIPrefs(m.config, prefs); err != nil {
+		return "✗ " + err.Error()
+	}
+	return "✓ saved to " + config.Path()
+}
+
+func (m *DashboardModel) renderSettingsMode() string {
+	var b strings.Builder
+	b.WriteString("Settings\n\n")
+	for i, row := range settingsRows {
+		value := *row.value(&m.prefs)
+		if row.label == "Theme" {
+			value = m.theme
+		}
+		line := fmt.Sprintf("%-12s ◀ %s ▶", row.label, value)
+		if i == m.settingsField {
+			b.WriteString(activeFieldStyle.Render("> "+line) + "\n")
+		} else {
+			b.WriteString(fieldStyle.Render("  "+line) + "\n")
+		}
+	}
+	b.WriteString("\n")
+	if rebound := m.reboundKeys(); len(rebound) > 0 {
+		b.WriteString(styleColor(colorMuted).Render(fmt.Sprintf("%d keys rebound in ui.keys (see ?)", len(rebound))) + "\n")
+	} else {
+		b.WriteString(styleColor(colorMuted).Render("Rebind keys with ui.keys in the config") + "\n")
+	}
+	if m.settingsMessage != "" {
+		color := colorGreen
+		if strings.HasPrefix(m.settingsMessage, "✗") {
+			color = colorRed
+		}
+		b.WriteString("\n" + styleColor(color).Render(m.settingsMessage))
+	}
+	b.WriteString("\n\nj/k: select  h/l: change  Esc: close")
+	return popupStyle.Width(60).Render(b.String())
+}
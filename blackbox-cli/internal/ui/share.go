@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+	"github.com/maxdcmn/blackbox-cli/internal/share"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+const gistTimeout = 15 * time.Second
+
+type shareMsg struct {
+	text string
+	err  error
+}
+
+// bundle captures the selected endpoint's snapshot and history for sharing
+func (m *DashboardModel) bundle() *share.Bundle {
+	ep := m.endpoints[m.selected]
+	b := &share.Bundle{Endpoint: ep.Name, BaseURL: ep.BaseURL, CapturedAt: time.Now(), Snapshot: m.last}
+	for _, dp := range m.history {
+		b.History = append(b.History, share.Sample(dp))
+	}
+	return b
+}
+
+// fileStem names the files b is saved to, e.g. "blackbox-local-20260102-150405"
+func fileStem(b *share.Bundle) string {
+	safeName := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ' ' || r == ':' {
+			return '_'
+		}
+		return r
+	}, b.Endpoint)
+	return fmt.Sprintf("blackbox-%s-%s", safeName, b.CapturedAt.Format("20060102-150405"))
+}
+
+// shareSnapshot writes the current view to a .bbx file in the working directory
+// and, when GITHUB_TOKEN is set, also posts it as a secret gist
+func (m *DashboardModel) shareSnapshot() tea.Cmd {
+	if m.selected >= len(m.endpoints) || m.last == nil {
+		m.shareMessage = "✗ nothing to share yet"
+		return nil
+	}
+	b := m.bundle().Redacted()
+	blob, err := share.Encode(b)
+	if err != nil {
+		m.shareMessage = "✗ share failed: " + err.Error()
+		return nil
+	}
+	filename := fileStem(b) + ".bbx"
+	if err := os.WriteFile(filename, []byte(blob+"\n"), 0644); err != nil {
+		m.shareMessage = "✗ share failed: " + err.Error()
+		return nil
+	}
+	m.shareMessage = "✓ saved " + filename
+	utils.Info("shared snapshot of %s to %s (%d bytes)", b.Endpoint, filename, len(blob))
+
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		return nil
+	}
+	m.shareMessage += ", uploading gist..."
+	desc := fmt.Sprintf("blackbox snapshot of %s at %s (open with: blackbox view <url>)", b.Endpoint, b.CapturedAt.Format(time.RFC3339))
+	root := m.ctx
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(root, gistTimeout)
+		defer cancel()
+		link, err := share.PublishGist(ctx, token, filename, desc, blob)
+		if err != nil {
+			return shareMsg{err: err}
+		}
+		return shareMsg{text: fmt.Sprintf("✓ saved %s, gist %s", filename, link)}
+	}
+}
+
+func (m *DashboardModel) updateShare(msg shareMsg) {
+	if msg.err != nil {
+		m.shareMessage = "✗ " + msg.err.Error()
+		return
+	}
+	m.shareMessage = msg.text
+}
+
+// NewViewer opens a shared bundle read-only: nothing is polled and only q, esc
+// and ctrl+c do anything
+func NewViewer(b *share.Bundle) *DashboardModel {
+	m := NewDashboard(&config.Config{Endpoints: []config.Endpoint{{Name: b.Endpoint, BaseURL: b.BaseURL}}}, 0, 0)
+	m.client = nil // Init polls nothing without a client
+	m.kiosk = true
+	m.viewing = b
+	m.last = b.Snapshot
+	m.loaded = true
+	for _, s := range b.History {
+		m.history = append(m.history, DataPoint(s))
+	}
+	if len(m.history) == 0 {
+		t := b.Snapshot.Telemetry()
+		ttft, interToken := b.Snapshot.Latency()
+		m.history = append(m.history, DataPoint{
+			Time:               b.CapturedAt,
+			AllocatedVRAMBytes: b.Snapshot.AllocatedVRAMBytes,
+			UsedKVCacheBytes:   b.Snapshot.UsedKVCacheBytes,
+			PrefixCacheHitRate: b.Snapshot.PrefixCacheHitRate,
+			RequestsRunning:    b.Snapshot.NumRequestsRunning,
+			RequestsWaiting:    b.Snapshot.NumRequestsWaiting,
+			GPUTemperatureC:    t.TemperatureC,
+			GPUPowerWatts:      t.PowerWatts,
+			GPUUtilization:     t.UtilizationPercent,
+			TTFTSeconds:        ttft,
+			InterTokenSeconds:  interToken,
+			Throughput:         b.Snapshot.Throughput(),
+		})
+	}
+	for _, dp := range m.history {
+		m.trackMax(dp)
+	}
+	return m
+}
@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+)
+
+// statViews are the statistics of the poll window `a` cycles the Properties
+// panel and the chart values through; the first is the default
+var statViews = []string{"avg", "p95", "p99", "max"}
+
+// statCharts are the charts whose values follow the stat view
+var statCharts = map[string]bool{
+	"Allocated VRAM":        true,
+	"Used KV Cache":         true,
+	"Prefix Cache Hit Rate": true,
+}
+
+func (m *DashboardModel) cycleStatView() {
+	m.statView = (m.statView + 1) % len(statViews)
+}
+
+// windowAgg is the last poll's aggregates when a view other than avg is
+// picked and the window held more than one sample (vLLM and local endpoints
+// send one, where every stat is the same reading), and not while scrubbing
+// back, as it's the newest poll's; nil means show m.last as it is
+func (m *DashboardModel) windowAgg() *model.AggregatedSnapshot {
+	if m.statView == 0 || m.scrubbing || m.lastAgg == nil || m.lastAgg.AllocatedVRAMBytes.Count < 2 {
+		return nil
+	}
+	return m.lastAgg
+}
+
+// statSnapshot is m.last with VRAM, KV cache and prefix hit rate swapped for
+// the stat view's figures
+func (m *DashboardModel) statSnapshot() *model.Snapshot {
+	agg := m.windowAgg()
+	if agg == nil || m.last == nil {
+		return m.last
+	}
+	view := statViews[m.statView]
+	s := *m.last
+	s.AllocatedVRAMBytes = int64(agg.AllocatedVRAMBytes.Stat(view))
+	s.UsedKVCacheBytes = int64(agg.UsedKVCacheBytes.Stat(view))
+	s.PrefixCacheHitRate = agg.PrefixCacheHitRate.Stat(view)
+	return &s
+}
+
+// statLabel names the stat view in use, e.g. "p95 of 5s"; "" on the avg view
+func (m *DashboardModel) statLabel() string {
+	agg := m.windowAgg()
+	if agg == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s of %ds", statViews[m.statView], agg.WindowSeconds)
+}
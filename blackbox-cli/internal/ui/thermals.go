@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+)
+
+// minChartHeight is the fewest lines renderMetricContent draws a chart in
+const minChartHeight = 7
+
+// hasTelemetry says whether the last snapshot came with GPU thermals, power or utilization
+func (m *DashboardModel) hasTelemetry() bool {
+	return m.last != nil && m.last.Telemetry().Reported()
+}
+
+// gpuChartsFit says whether the GPU charts get drawn: when reported and the
+// data panel has room for that many charts with a gap under each, plus the
+// requests line. Otherwise they're summed up on one line by renderGPULine.
+func (m *DashboardModel) gpuChartsFit(charts int) bool {
+	if !m.hasTelemetry() {
+		return false
+	}
+	innerHeight := calculateContainerSizes(m.width, m.height).Data.Height - 2
+	return innerHeight >= charts*(minChartHeight+1)+1
+}
+
+// renderGPUCharts draws the GPU charts, each colored by how close it runs to its limit
+func (m *DashboardModel) renderGPUCharts(boxHeight, width int) []string {
+	t := m.last.Telemetry()
+
+	temperatureHistory := m.getHistory(func(dp DataPoint) float64 { return dp.GPUTemperatureC })
+	temperatureContent := m.renderMetricContent("GPU Temperature", boxHeight, width, int(t.TemperatureC), 0, 0,
+		temperatureHistory, lipgloss.Color(getTemperatureColor(t.TemperatureC)), maxFloat(100.0, findMax(temperatureHistory)))
+
+	// Without a reported limit the chart scales to the highest draw seen
+	powerHistory := m.getHistory(func(dp DataPoint) float64 { return dp.GPUPowerWatts })
+	powerContent := m.renderMetricContent("GPU Power", boxHeight, width, int(t.PowerWatts), int(t.PowerLimitWatts), 0,
+		powerHistory, lipgloss.Color(getPowerColor(t)), t.PowerLimitWatts)
+
+	utilizationContent := m.renderMetricContent("SM Utilization", boxHeight, width, int(t.UtilizationPercent), 0, 0,
+		m.getHistory(func(dp DataPoint) float64 { return dp.GPUUtilization }), lipgloss.Color(colorCyan), 100.0)
+
+	return []string{temperatureContent, powerContent, utilizationContent}
+}
+
+// renderGPULine sums up the GPU charts on one line with a sparkline each,
+// like the requests line, for a data panel too short to draw them
+func (m *DashboardModel) renderGPULine(width int) string {
+	t := m.last.Telemetry()
+	temperature := m.getHistory(func(dp DataPoint) float64 { return dp.GPUTemperatureC })
+	power := m.getHistory(func(dp DataPoint) float64 { return dp.GPUPowerWatts })
+	utilization := m.getHistory(func(dp DataPoint) float64 { return dp.GPUUtilization })
+	powerTop := t.PowerLimitWatts
+	if powerTop <= 0 {
+		powerTop = findMax(power)
+	}
+
+	title := lipgloss.NewStyle().Foreground(lipgloss.Color(colorText)).Bold(true).Render("GPU")
+	temperatureColor, powerColor := getTemperatureColor(t.TemperatureC), getPowerColor(t)
+	temperatureText := "  " + styleColor(temperatureColor).Render(fmt.Sprintf("%.0f°C", t.TemperatureC))
+	powerText := "  " + styleColor(powerColor).Render(fmt.Sprintf("%.0f W", t.PowerWatts))
+	utilizationText := "  " + styleColor(colorCyan).Render(fmt.Sprintf("%.0f%% SM", t.UtilizationPercent))
+	line := title + temperatureText + powerText + utilizationText
+
+	sparkWidth := (width - 4 - lipgloss.Width(line) - 3) / 3
+	if sparkWidth >= 4 {
+		line = title +
+			temperatureText + " " + styleColor(temperatureColor).Render(renderScaledSparkline(temperature, sparkWidth, maxFloat(100.0, findMax(temperature)))) +
+			powerText + " " + styleColor(powerColor).Render(renderScaledSparkline(power, sparkWidth, powerTop)) +
+			utilizationText + " " + styleColor(colorCyan).Render(renderScaledSparkline(utilization, sparkWidth, 100.0))
+	}
+	return lipgloss.NewStyle().Background(lipgloss.Color(colorBg)).Width(max(0, width-2)).MaxWidth(max(0, width-2)).Render(line)
+}
+
+// getTemperatureColor colors a GPU temperature by how close it is to throttling
+func getTemperatureColor(celsius float64) string {
+	if celsius >= 85 {
+		return colorRed
+	} else if celsius >= 75 {
+		return colorOrange
+	} else if celsius >= 65 {
+		return colorYellow
+	}
+	return colorGreen
+}
+
+// getPowerColor colors the power draw by its share of the power limit
+func getPowerColor(t model.GPUTelemetry) string {
+	if t.PowerLimitWatts <= 0 {
+		return colorCyan
+	}
+	return getPercentColor(t.PowerWatts / t.PowerLimitWatts * 100)
+}
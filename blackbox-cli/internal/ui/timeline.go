@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+)
+
+// startTimeline scrubs the panels back through the history the charts keep,
+// from the newest poll or the one they're panned to
+func (m *DashboardModel) startTimeline() {
+	if len(m.history) < 3 {
+		m.configMessage = "✗ nothing to scrub through yet"
+		return
+	}
+	m.scrubbing = true
+	m.chartPan = min(m.chartPan, len(m.history)-2)
+}
+
+// stopTimeline goes back to the newest poll
+func (m *DashboardModel) stopTimeline() {
+	m.scrubbing = false
+	m.chartPan = 0
+}
+
+// scrubKey moves the timeline for the (resolved) keys it takes, reporting
+// whether key was one; the rest work as they do live
+func (m *DashboardModel) scrubKey(key string) bool {
+	switch key {
+	case "esc", "P":
+		m.stopTimeline()
+	case "left", "h":
+		m.scrubTo(m.chartPan + 1)
+	case "right", "l":
+		m.scrubTo(m.chartPan - 1)
+	case "pgup":
+		m.panCharts(1)
+	case "pgdown":
+		m.panCharts(-1)
+	case "home":
+		m.scrubTo(len(m.history))
+	case "end":
+		m.scrubTo(0)
+	default:
+		return false
+	}
+	return true
+}
+
+// scrubTo shows the poll pan polls before the newest, as far back as the
+// second oldest so the charts still have a line to end on
+func (m *DashboardModel) scrubTo(pan int) {
+	m.chartPan = max(0, min(pan, len(m.history)-2))
+}
+
+// scrubPoint is the poll the timeline is on
+func (m *DashboardModel) scrubPoint() DataPoint {
+	return m.history[max(0, len(m.history)-1-m.chartPan)]
+}
+
+// scrubSnapshot rebuilds the snapshot of the poll the timeline is on from the
+// history: the totals, each model's sample of that poll, and the GPUs as one.
+// What history doesn't keep, like the VRAM total and ports, is the newest poll's.
+func (m *DashboardModel) scrubSnapshot() *model.Snapshot {
+	if m.last == nil || len(m.history) == 0 {
+		return m.last
+	}
+	dp := m.scrubPoint()
+	s := *m.last
+	s.AllocatedVRAMBytes = dp.AllocatedVRAMBytes
+	s.UsedKVCacheBytes = dp.UsedKVCacheBytes
+	s.PrefixCacheHitRate = dp.PrefixCacheHitRate
+	s.NumRequestsRunning = dp.RequestsRunning
+	s.NumRequestsWaiting = dp.RequestsWaiting
+
+	ports := make(map[string]int, len(m.last.Models))
+	for _, info := range m.last.Models {
+		ports[info.ModelID] = info.Port
+	}
+	s.Models = nil
+	for id, ring := range m.modelHistory {
+		for _, sample := range ring.samples {
+			if !sample.Time.Equal(dp.Time) {
+				continue
+			}
+			s.Models = append(s.Models, model.ModelInfo{
+				ModelID:                   id,
+				Port:                      ports[id],
+				AllocatedVRAMBytes:        sample.AllocatedVRAMBytes,
+				UsedKVCacheBytes:          sample.UsedKVCacheBytes,
+				TTFTSeconds:               sample.TTFTSeconds,
+				InterTokenLatencySeconds:  sample.InterTokenSeconds,
+				GenerationTokensPerSecond: sample.Throughput,
+				NumRequestsRunning:        sample.RequestsRunning,
+				NumRequestsWaiting:        sample.RequestsWaiting,
+			})
+		}
+	}
+	sort.Slice(s.Models, func(i, j int) bool { return s.Models[i].ModelID < s.Models[j].ModelID })
+
+	// History keeps the GPUs' telemetry summed up, so they come back as one
+	if len(m.last.GPUs) > 0 {
+		gpu := model.GPUStats{
+			Name:               m.last.GPUs[0].Name,
+			TotalVRAMBytes:     m.last.TotalVRAMBytes,
+			AllocatedVRAMBytes: dp.AllocatedVRAMBytes,
+			UtilizationPercent: dp.GPUUtilization,
+			TemperatureC:       dp.GPUTemperatureC,
+			PowerWatts:         dp.GPUPowerWatts,
+			PowerLimitWatts:    m.last.Telemetry().PowerLimitWatts,
+		}
+		if len(m.last.GPUs) > 1 {
+			gpu.Name = fmt.Sprintf("%d GPUs", len(m.last.GPUs))
+		}
+		s.GPUs = []model.GPUStats{gpu}
+	}
+	return &s
+}
+
+// renderTimelineBar replaces the status bar while scrubbing, e.g.
+// "⏪ 14:03:25, 5m12s ago (poll 437 of 500)"
+func (m *DashboardModel) renderTimelineBar(width int) string {
+	dp := m.scrubPoint()
+	at := styleColor(colorYellow).Bold(true).Render("⏪ " + dp.Time.Local().Format("15:04:05"))
+	if m.chartPan == 0 {
+		at = styleColor(colorGreen).Bold(true).Render("● newest poll")
+	}
+	where := fmt.Sprintf(", %s ago (poll %d of %d)", time.Since(dp.Time).Truncate(time.Second),
+		len(m.history)-m.chartPan, len(m.history))
+	keys := styleColor(colorItalic).Render("←/→: scrub  PgUp/PgDn: faster  Home/End: oldest/newest  Esc: live")
+	if m.shareMessage != "" {
+		keys = styleColor(colorCyan).Render(m.shareMessage)
+	}
+	return statusBarStyle.Width(width).Height(1).Render(at + styleColor(colorMuted).Render(where) + "  " + keys)
+}
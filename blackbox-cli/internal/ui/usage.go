@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+	"github.com/maxdcmn/blackbox-cli/internal/usage"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+// usageSaveInterval is how often new daily peaks are written while the dashboard runs
+const usageSaveInterval = time.Minute
+
+// usageLevelColors shade calendar days from the lowest level to the highest
+var usageLevelColors = [usage.Levels]string{"22", "28", "34", colorGreen}
+
+// SetUsageStore makes the dashboard record every endpoint's daily peaks into
+// s, for the usage calendar (H) and HTML reports. Without one nothing is recorded.
+func (m *DashboardModel) SetUsageStore(s *usage.Store) {
+	m.usage = s
+}
+
+// recordUsage notes a poll of name in the usage store and returns a save
+// when the last one is older than usageSaveInterval. load is nil when the
+// poll didn't report requests.
+func (m *DashboardModel) recordUsage(name string, s *model.Snapshot, load *pollLoad) tea.Cmd {
+	if m.usage == nil || s == nil {
+		return nil
+	}
+	pct := 0.0
+	if s.TotalVRAMBytes > 0 {
+		pct = float64(s.AllocatedVRAMBytes) / float64(s.TotalVRAMBytes) * 100
+	}
+	var requests *float64
+	if load != nil {
+		requests = &load.running
+	}
+	now := time.Now()
+	m.usage.Observe(name, now, pct, requests)
+	if m.usageSaved.IsZero() {
+		m.usageSaved = now // Nothing to catch up on right after starting
+	}
+	if now.Sub(m.usageSaved) < usageSaveInterval || !m.usage.Dirty() {
+		return nil
+	}
+	m.usageSaved = now
+	store := m.usage
+	return func() tea.Msg {
+		if err := store.Save(); err != nil {
+			utils.Warn("failed to save usage: %v", err)
+		}
+		return nil
+	}
+}
+
+func (m *DashboardModel) updateUsageMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case m.keys.key("quit"), "ctrl+c":
+		return m.quit()
+	case m.keys.key("usage_calendar"), "esc":
+		m.showingUsage = false
+	case "tab":
+		m.usageMetric = usage.Metrics[(int(m.usageMetric)+1)%len(usage.Metrics)]
+	case "j", "down":
+		if m.usageEndpoint < len(m.endpoints)-1 {
+			m.usageEndpoint++
+		}
+	case "k", "up":
+		if m.usageEndpoint > 0 {
+			m.usageEndpoint--
+		}
+	}
+	return m, nil
+}
+
+// renderUsage draws a GitHub-style calendar of one endpoint's daily peaks,
+// one column per week with Monday on top, and each weekday's average
+func (m *DashboardModel) renderUsage(width, height int) string {
+	width, height = ensureMin(width, height, 40, 12)
+	if m.usageEndpoint >= len(m.endpoints) {
+		return m.renderEmptyState(width, height, "No endpoints configured\n\nPress 'H' to go back", colorUnfocused)
+	}
+	name := m.endpoints[m.usageEndpoint].Name
+	var days []usage.Day
+	if m.usage != nil {
+		days = m.usage.Days(name)
+	}
+	const labelWidth, avgWidth = 5, 12
+	weeks := max(1, min(53, (width-4-labelWidth-avgWidth)/2))
+	cal := usage.NewCalendar(days, m.usageMetric, time.Now(), weeks)
+
+	var b strings.Builder
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(colorText))
+	b.WriteString(title.Render("Usage: "+truncateString(name, width-30)) + styleColor(colorMuted).Render(" · "+m.usageMetric.String()) + "\n\n")
+
+	// Month names over the week they start in, where there's room
+	months := []rune(strings.Repeat(" ", labelWidth+2*weeks))
+	free := 0
+	for w := range cal.Weeks {
+		first := cal.Weeks[w][0].Date
+		if w > 0 && first.Month() == cal.Weeks[w-1][0].Date.Month() {
+			continue
+		}
+		if col := labelWidth + 2*w; col >= free && col+3 <= len(months) {
+			copy(months[col:], []rune(first.Format("Jan")))
+			free = col + 4
+		}
+	}
+	b.WriteString(styleColor(colorMuted).Render(string(months)) + "\n")
+
+	for d := 0; d < 7; d++ {
+		label := "     "
+		if d%2 == 0 {
+			label = fmt.Sprintf("%-5s", usage.WeekdayNames[d])
+		}
+		b.WriteString(styleColor(colorMuted).Render(label))
+		for w := range cal.Weeks {
+			c := cal.Weeks[w][d]
+			switch {
+			case c.After:
+				b.WriteString("  ")
+			case !c.Has:
+				b.WriteString(styleColor(colorDim).Render("· "))
+			default:
+				b.WriteString(styleColor(usageLevelColors[c.Level-1]).Render("■ "))
+			}
+		}
+		if cal.WeekdayDays[d] > 0 {
+			b.WriteString(styleColor(colorMuted).Render(" avg " + m.usageMetric.Format(cal.WeekdayAvg[d])))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n" + styleColor(colorMuted).Render(strings.Repeat(" ", labelWidth)+"Less "))
+	for _, color := range usageLevelColors {
+		b.WriteString(styleColor(color).Render("■ "))
+	}
+	b.WriteString(styleColor(colorMuted).Render("More"))
+	if busiest := cal.Busiest(); busiest >= 0 {
+		b.WriteString("\n\n" + styleColor(colorMuted).Render(fmt.Sprintf("Busiest day: %s (avg %s) · highest %s", usage.WeekdayNames[busiest], m.usageMetric.Format(cal.WeekdayAvg[busiest]), m.usageMetric.Format(cal.Max))))
+	} else {
+		b.WriteString("\n\n" + styleColor(colorMuted).Render("No "+m.usageMetric.String()+" recorded for this endpoint yet; the dashboard records it while it runs"))
+	}
+
+	return lipgloss.NewStyle().Width(width).Height(height).Padding(1, 2).Render(b.String())
+}
@@ -1,26 +1,34 @@
 package ui
 
 import (
+	"errors"
+
 	"github.com/charmbracelet/lipgloss"
+	"github.com/maxdcmn/blackbox-cli/internal/client"
+	"github.com/maxdcmn/blackbox-cli/internal/collector"
 )
 
 const (
 	maxHistorySize = 50
 	maxThreads     = 10
-	version        = "0.1.0"
+	Version        = "0.1.0"
 	gbDivisor      = 1024 * 1024 * 1024
-	colorFocused   = "46"
-	colorUnfocused = "15"
-	colorText      = "15"
-	colorMuted     = "250"
-	colorDim       = "240"
-	colorItalic    = "245"
-	colorBg        = "0"
-	colorOrange    = "214"
-	colorYellow    = "220"
-	colorCyan      = "39"
-	colorGreen     = "46"
-	colorRed       = "196"
+)
+
+// Colors of the theme in use, set by applyTheme
+var (
+	colorFocused   string
+	colorUnfocused string
+	colorText      string
+	colorMuted     string
+	colorDim       string
+	colorItalic    string
+	colorBg        string
+	colorOrange    string
+	colorYellow    string
+	colorCyan      string
+	colorGreen     string
+	colorRed       string
 )
 
 func maxFloat(a, b float64) float64 {
@@ -63,6 +71,21 @@ func findMax(values []float64) float64 {
 	return max
 }
 
+const defaultSmoothingAlpha = 0.3
+
+// ema returns the exponential moving average of values, seeded with the first value
+func ema(values []float64, alpha float64) []float64 {
+	out := make([]float64, len(values))
+	for i, v := range values {
+		if i == 0 {
+			out[i] = v
+			continue
+		}
+		out[i] = alpha*v + (1-alpha)*out[i-1]
+	}
+	return out
+}
+
 func normalizeValue(value, min, max float64) float64 {
 	if max == min {
 		return 0
@@ -90,6 +113,25 @@ func truncateString(s string, maxLen int) string {
 	return s[:maxLen-3] + "..."
 }
 
+// errorText turns client error classes into a hint, falling back to the raw message
+func errorText(err error) string {
+	switch {
+	case errors.Is(err, client.ErrUnauthorized):
+		return "auth required - set the endpoint's headers in config (" + err.Error() + ")"
+	case errors.Is(err, client.ErrNotFound):
+		return "not found - check the endpoint path (" + err.Error() + ")"
+	case errors.Is(err, client.ErrTimeout):
+		return "timed out - is the server reachable? (" + err.Error() + ")"
+	case errors.Is(err, client.ErrBadSchema):
+		return "unexpected response - is this a blackbox-server? (" + err.Error() + ")"
+	case errors.Is(err, client.ErrUnsupported):
+		return "not available on vllm/local endpoints - needs blackbox-server (" + err.Error() + ")"
+	case errors.Is(err, collector.ErrNoDriver):
+		return "nvidia-smi failed - is the NVIDIA driver installed? (" + err.Error() + ")"
+	}
+	return err.Error()
+}
+
 func styleColor(color string) lipgloss.Style {
 	return lipgloss.NewStyle().Foreground(lipgloss.Color(color))
 }
@@ -119,31 +161,41 @@ func borderStyle(width, height int, focused bool) lipgloss.Style {
 		Height(height)
 }
 
+// Styles and chart colors of the theme in use, set by applyTheme
 var (
+	statusBarStyle   lipgloss.Style
+	popupStyle       lipgloss.Style
+	fieldStyle       lipgloss.Style
+	activeFieldStyle lipgloss.Style
+
+	vramColor          lipgloss.Color
+	blocksColor        lipgloss.Color
+	fragmentationColor lipgloss.Color
+	prefixHitRateColor lipgloss.Color
+	throughputColor    lipgloss.Color
+)
+
+// buildStyles makes the shared styles from the theme's colors
+func buildStyles() {
 	statusBarStyle = lipgloss.NewStyle().
-			Height(1).
-			Foreground(lipgloss.Color(colorMuted)).
-			Background(lipgloss.Color(colorBg)).
-			Padding(0, 1).
-			Bold(false)
+		Height(1).
+		Foreground(lipgloss.Color(colorMuted)).
+		Background(lipgloss.Color(colorBg)).
+		Padding(0, 1).
+		Bold(false)
 
 	popupStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color(colorUnfocused)).
-			Padding(1, 2).
-			Background(lipgloss.Color(colorBg)).
-			Foreground(lipgloss.Color(colorMuted))
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(colorUnfocused)).
+		Padding(1, 2).
+		Background(lipgloss.Color(colorBg)).
+		Foreground(lipgloss.Color(colorMuted))
 
 	fieldStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color(colorItalic))
+		Foreground(lipgloss.Color(colorItalic))
 
 	activeFieldStyle = lipgloss.NewStyle().
-				Background(lipgloss.Color(colorText)).
-				Foreground(lipgloss.Color(colorBg)).
-				Bold(true)
-
-	vramColor          = lipgloss.Color("28")
-	blocksColor        = lipgloss.Color("34")
-	fragmentationColor = lipgloss.Color("40")
-	prefixHitRateColor = lipgloss.Color("38")
-)
+		Background(lipgloss.Color(colorText)).
+		Foreground(lipgloss.Color(colorBg)).
+		Bold(true)
+}
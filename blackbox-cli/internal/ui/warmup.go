@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/maxdcmn/blackbox-cli/internal/client"
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+type warmupMsg struct {
+	modelID string
+	result  *client.WarmupResult
+	err     error
+}
+
+// startWarmup warms up the model just deployed from the popup, if the
+// endpoint hasn't turned warm-ups off. port is the one the deploy reported;
+// without it the typed port is used, else the model listing is asked.
+func (m *DashboardModel) startWarmup(port int) tea.Cmd {
+	if m.selected >= len(m.endpoints) || m.deployClient == nil {
+		return nil
+	}
+	ep := m.endpoints[m.selected]
+	timeout := warmupTimeout(ep)
+	if timeout <= 0 {
+		return nil
+	}
+	if port <= 0 {
+		port, _ = strconv.Atoi(m.deployPort)
+	}
+	m.deployWarming = m.deployModelID
+	opts := client.WarmupOptions{Prompt: ep.WarmupPrompt, MaxTokens: ep.WarmupMaxTokens, Scheme: ep.WarmupScheme}
+	return warmupModel(m.ctx, m.deployClient, m.timeout, timeout, m.deployModelID, port, opts)
+}
+
+// warmupTimeout reads ep's warmup_timeout; zero means warm-ups are off
+func warmupTimeout(ep config.Endpoint) time.Duration {
+	if ep.WarmupTimeout == "" {
+		return client.DefaultWarmupTimeout
+	}
+	d, err := utils.ParseDuration(ep.WarmupTimeout)
+	if err != nil {
+		utils.Warn("Invalid warmup_timeout %q for %s, using %s", ep.WarmupTimeout, ep.Name, client.DefaultWarmupTimeout)
+		return client.DefaultWarmupTimeout
+	}
+	return d
+}
+
+func warmupModel(ctx context.Context, c client.MetricsClient, timeout, warmupTimeout time.Duration, modelID string, port int, opts client.WarmupOptions) tea.Cmd {
+	return func() tea.Msg {
+		if port <= 0 {
+			port = deployedPort(ctx, c, timeout, modelID)
+		}
+		ctx, cancel := context.WithTimeout(ctx, warmupTimeout)
+		defer cancel()
+		res, err := c.Warmup(ctx, modelID, port, opts)
+		return warmupMsg{modelID: modelID, result: res, err: err}
+	}
+}
+
+// deployedPort looks up the port modelID serves on, or returns 0
+func deployedPort(ctx context.Context, c client.MetricsClient, timeout time.Duration, modelID string) int {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	resp, err := c.ListModels(ctx, client.ListModelsOptions{})
+	if err != nil || resp == nil {
+		return 0
+	}
+	for _, dm := range resp.Models {
+		if dm.ModelID == modelID && dm.Port > 0 {
+			return dm.Port
+		}
+	}
+	return 0
+}
+
+func (m *DashboardModel) updateWarmup(msg warmupMsg) (tea.Model, tea.Cmd) {
+	if msg.modelID != m.deployWarming {
+		return m, nil // popup was closed or another deploy started
+	}
+	m.deployWarming = ""
+	switch {
+	case msg.err != nil:
+		// Not errorText: its hints are about the endpoint, not the model's server
+		m.deployMessage += " · " + msg.err.Error()
+	case msg.result != nil:
+		m.deployMessage += " · warm-up " + msg.result.Latency.Round(100*time.Millisecond).String()
+		if msg.result.Tokens > 0 {
+			m.deployMessage += fmt.Sprintf(" (%d tokens)", msg.result.Tokens)
+		}
+	}
+	return m, nil
+}
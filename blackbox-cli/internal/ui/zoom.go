@@ -0,0 +1,78 @@
+package ui
+
+import "fmt"
+
+const (
+	// maxChartZoom is how many times + halves the charts' time range
+	maxChartZoom = 5
+	// minChartSpan is the fewest points a zoomed-in chart shows
+	minChartSpan = 5
+)
+
+// chartSpan is how many of n points a chart as wide as width shows: what
+// fits at zoom 0, halved on each zoom in
+func (m *DashboardModel) chartSpan(n, width int) int {
+	span := min(n, max(2, width-2))
+	return min(span, max(minChartSpan, span>>m.chartZoom))
+}
+
+// chartWindow is the range of n points the charts show: chartSpan of them,
+// ending chartPan points before the newest. Scrubbing back, they end on the
+// poll scrubbed to even if fewer points come before it.
+func (m *DashboardModel) chartWindow(n, width int) (start, end int) {
+	span := m.chartSpan(n, width)
+	end = n - min(m.chartPan, m.maxPan(n, span))
+	return max(0, end-span), end
+}
+
+// maxPan is how far back chartPan goes in n points with span of them shown:
+// to the oldest whole window, or scrubbing, to the second oldest point
+func (m *DashboardModel) maxPan(n, span int) int {
+	if m.scrubbing {
+		return max(0, n-2)
+	}
+	return n - span
+}
+
+// zoomCharts narrows the charts' time range on step > 0 and widens it back
+// on step < 0
+func (m *DashboardModel) zoomCharts(step int) {
+	m.chartZoom = max(0, min(maxChartZoom, m.chartZoom+step))
+}
+
+// panCharts moves the charts back through history on step > 0 and toward
+// the newest point on step < 0, by half the points they show
+func (m *DashboardModel) panCharts(step int) {
+	n := len(m.history)
+	if m.chartModel != "" {
+		if ring := m.modelHistory[m.chartModel]; ring != nil {
+			n = len(ring.samples)
+		}
+	}
+	span := m.chartSpan(n, m.chartsWidth())
+	m.chartPan = max(0, min(m.maxPan(n, span), m.chartPan+step*max(1, span/2)))
+}
+
+// chartsWidth is the width of a chart in the data panel, as rendered last
+func (m *DashboardModel) chartsWidth() int {
+	if m.lastChartWidth > 0 {
+		return m.lastChartWidth
+	}
+	return m.width
+}
+
+// zoomLabel describes a zoomed or panned chart for its title, e.g.
+// "×4, 20 polls back"; "" when it shows the latest of what fits
+func (m *DashboardModel) zoomLabel() string {
+	label := ""
+	if m.chartZoom > 0 {
+		label = fmt.Sprintf("×%d", 1<<m.chartZoom)
+	}
+	if m.chartPan > 0 {
+		if label != "" {
+			label += ", "
+		}
+		label += fmt.Sprintf("%d polls back", m.chartPan)
+	}
+	return label
+}
@@ -0,0 +1,138 @@
+package usage
+
+import (
+	"fmt"
+	"time"
+)
+
+// Metric is what a calendar shows for each day
+type Metric int
+
+const (
+	PeakVRAM Metric = iota
+	PeakRequests
+)
+
+// Metrics lists every Metric, in the order views cycle through them
+var Metrics = []Metric{PeakVRAM, PeakRequests}
+
+func (m Metric) String() string {
+	if m == PeakRequests {
+		return "daily peak requests"
+	}
+	return "daily peak VRAM"
+}
+
+// Format renders a value of the metric, e.g. "82%" or "14.5"
+func (m Metric) Format(v float64) string {
+	if m == PeakRequests {
+		return fmt.Sprintf("%.1f", v)
+	}
+	return fmt.Sprintf("%.0f%%", v)
+}
+
+// Value is the day's value of m, and whether it was recorded
+func (d Day) Value(m Metric) (float64, bool) {
+	if m == PeakRequests {
+		if d.PeakRequests == nil {
+			return 0, false
+		}
+		return *d.PeakRequests, true
+	}
+	return d.PeakVRAMPercent, true
+}
+
+// Levels is how many shades a calendar uses for days with data
+const Levels = 4
+
+// Cell is one day of a calendar
+type Cell struct {
+	Date  time.Time
+	Value float64
+	Has   bool // A value was recorded
+	Level int  // 1..Levels when Has; 0 otherwise
+	After bool // Past the calendar's last day, so left blank
+}
+
+// Calendar lays days out GitHub-style: one column per week, Monday on top
+type Calendar struct {
+	Metric Metric
+	Weeks  [][7]Cell
+	Max    float64 // Largest value shown
+	// WeekdayAvg is the average value per weekday, Monday first, for days
+	// with data; WeekdayDays counts them
+	WeekdayAvg  [7]float64
+	WeekdayDays [7]int
+}
+
+// NewCalendar lays out the weeks weeks up to and including end's week.
+// VRAM is shaded on a fixed 0-100% scale; requests relative to the busiest day.
+func NewCalendar(days []Day, metric Metric, end time.Time, weeks int) Calendar {
+	values := make(map[string]float64, len(days))
+	for _, d := range days {
+		if v, ok := d.Value(metric); ok {
+			values[d.Date] = v
+		}
+	}
+	end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, end.Location())
+	start := end.AddDate(0, 0, -weekday(end)-7*(weeks-1))
+
+	cal := Calendar{Metric: metric, Weeks: make([][7]Cell, weeks)}
+	var sums [7]float64
+	for w := range cal.Weeks {
+		for d := 0; d < 7; d++ {
+			date := start.AddDate(0, 0, 7*w+d)
+			c := Cell{Date: date, After: date.After(end)}
+			if v, ok := values[date.Format(dateLayout)]; ok && !c.After {
+				c.Value, c.Has = v, true
+				cal.Max = max(cal.Max, v)
+				sums[d] += v
+				cal.WeekdayDays[d]++
+			}
+			cal.Weeks[w][d] = c
+		}
+	}
+	for d := range sums {
+		if cal.WeekdayDays[d] > 0 {
+			cal.WeekdayAvg[d] = sums[d] / float64(cal.WeekdayDays[d])
+		}
+	}
+	for w := range cal.Weeks {
+		for d := range cal.Weeks[w] {
+			if c := &cal.Weeks[w][d]; c.Has {
+				c.Level = cal.level(c.Value)
+			}
+		}
+	}
+	return cal
+}
+
+func (c Calendar) level(v float64) int {
+	scale := 100.0
+	if c.Metric == PeakRequests {
+		scale = c.Max
+	}
+	if scale <= 0 {
+		return 1
+	}
+	return min(Levels, 1+int(v/scale*Levels))
+}
+
+// Busiest is the weekday (0 is Monday) with the highest average, or -1 without data
+func (c Calendar) Busiest() int {
+	best := -1
+	for d, n := range c.WeekdayDays {
+		if n > 0 && (best < 0 || c.WeekdayAvg[d] > c.WeekdayAvg[best]) {
+			best = d
+		}
+	}
+	return best
+}
+
+// WeekdayNames are the calendar's rows, Monday first
+var WeekdayNames = [7]string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+// weekday is t's row: 0 for Monday through 6 for Sunday
+func weekday(t time.Time) int {
+	return (int(t.Weekday()) + 6) % 7
+}
@@ -0,0 +1,177 @@
+// Package usage keeps each endpoint's daily peaks on disk, so the dashboard
+// and reports can show how usage runs over the weeks for capacity planning.
+package usage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/config"
+)
+
+const (
+	fileName = "usage.json"
+	// KeepDays is how far back days are kept: a full 53-week calendar
+	KeepDays = 53 * 7
+	// dateLayout is a day in local time
+	dateLayout = "2006-01-02"
+)
+
+// Day is one endpoint's peaks over one local calendar day
+type Day struct {
+	Date            string  `json:"date"`
+	PeakVRAMPercent float64 `json:"peak_vram_percent"`
+	// PeakRequests is the most requests running at once, averaged over the
+	// server's 5s window; nil when nothing reported requests that day
+	PeakRequests *float64 `json:"peak_requests,omitempty"`
+}
+
+// Store is the daily peaks of every endpoint seen. Observe updates it in
+// memory and Save merges it into the file, so several dashboards can share one.
+type Store struct {
+	path string
+
+	mu    sync.Mutex
+	days  map[string]map[string]*Day // By endpoint name, then date
+	dirty bool
+}
+
+// Path is where Open keeps the store by default: next to the config
+func Path() string {
+	return filepath.Join(config.Dir(), fileName)
+}
+
+// Open reads the store at path. A missing or unreadable file gives an empty
+// store; the next Save replaces it.
+func Open(path string) *Store {
+	s := &Store{path: path, days: make(map[string]map[string]*Day)}
+	s.merge(readFile(path))
+	return s
+}
+
+func readFile(path string) map[string][]Day {
+	var stored map[string][]Day
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &stored)
+	}
+	return stored
+}
+
+// merge keeps the higher peak of each day; callers hold mu or own s
+func (s *Store) merge(stored map[string][]Day) {
+	for name, days := range stored {
+		for _, d := range days {
+			s.observeDay(name, d)
+		}
+	}
+}
+
+func (s *Store) observeDay(name string, d Day) bool {
+	byDate := s.days[name]
+	if byDate == nil {
+		byDate = make(map[string]*Day)
+		s.days[name] = byDate
+	}
+	cur := byDate[d.Date]
+	if cur == nil {
+		byDate[d.Date] = &d
+		return true
+	}
+	changed := false
+	if d.PeakVRAMPercent > cur.PeakVRAMPercent {
+		cur.PeakVRAMPercent = d.PeakVRAMPercent
+		changed = true
+	}
+	if d.PeakRequests != nil && (cur.PeakRequests == nil || *d.PeakRequests > *cur.PeakRequests) {
+		v := *d.PeakRequests
+		cur.PeakRequests = &v
+		changed = true
+	}
+	return changed
+}
+
+// Observe records a sample of endpoint taken at at. requests is nil when the
+// source doesn't report them.
+func (s *Store) Observe(endpoint string, at time.Time, vramPercent float64, requests *float64) {
+	d := Day{Date: at.Local().Format(dateLayout), PeakVRAMPercent: vramPercent}
+	if requests != nil {
+		v := *requests
+		d.PeakRequests = &v
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.observeDay(endpoint, d) {
+		s.dirty = true
+	}
+}
+
+// Dirty reports whether there are peaks Save hasn't written yet
+func (s *Store) Dirty() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dirty
+}
+
+// Save merges the file's peaks, which other dashboards may have written
+// since Open, drops days older than KeepDays and writes the result.
+func (s *Store) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.merge(readFile(s.path))
+	cutoff := time.Now().AddDate(0, 0, -KeepDays).Format(dateLayout)
+	out := make(map[string][]Day, len(s.days))
+	for name := range s.days {
+		for date := range s.days[name] {
+			if date < cutoff {
+				delete(s.days[name], date)
+			}
+		}
+		if len(s.days[name]) > 0 {
+			out[name] = s.sorted(name)
+		}
+	}
+
+	data, err := json.Marshal(out)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	// Written aside and renamed, so a reader never sees half a file
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to save %s: %w", fileName, err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to save %s: %w", fileName, err)
+	}
+	s.dirty = false
+	return nil
+}
+
+// Days returns endpoint's days, oldest first
+func (s *Store) Days(endpoint string) []Day {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sorted(endpoint)
+}
+
+func (s *Store) sorted(endpoint string) []Day {
+	days := make([]Day, 0, len(s.days[endpoint]))
+	for _, d := range s.days[endpoint] {
+		c := *d
+		if d.PeakRequests != nil {
+			v := *d.PeakRequests
+			c.PeakRequests = &v
+		}
+		days = append(days, c)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Date < days[j].Date })
+	return days
+}
@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDuration reads a Go duration ("1m30s", "500ms"), a number of days
+// ("7d") or a bare number of seconds ("5", "0.5"). A decimal comma ("1,5s")
+// is read as a point.
+func ParseDuration(s string) (time.Duration, error) {
+	v := strings.TrimSpace(s)
+	if strings.Count(v, ",") == 1 && !strings.Contains(v, ".") {
+		v = strings.Replace(v, ",", ".", 1)
+	}
+	var d time.Duration
+	if secs, err := strconv.ParseFloat(v, 64); err == nil {
+		d = time.Duration(secs * float64(time.Second))
+	} else if days, err := strconv.ParseFloat(strings.TrimSuffix(v, "d"), 64); err == nil && strings.HasSuffix(v, "d") {
+		d = time.Duration(days * float64(24*time.Hour))
+	} else if d, err = time.ParseDuration(v); err != nil {
+		return 0, fmt.Errorf("%q is not a duration (use e.g. 5s, 500ms, 1m or a number of seconds)", s)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("%q is negative", s)
+	}
+	return d, nil
+}
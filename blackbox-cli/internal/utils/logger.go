@@ -7,6 +7,7 @@ import (
 )
 
 var debugEnabled = false
+var traceHTTP = false
 var logFile *os.File
 
 func InitLogger(debug bool, logPath string) error {
@@ -33,13 +34,30 @@ func log(level, msg string, args ...interface{}) {
 	}
 	
 	timestamp := time.Now().Format("2006-01-02 15:04:05.000")
-	formatted := fmt.Sprintf(msg, args...)
+	formatted := Redact(fmt.Sprintf(msg, args...))
 	line := fmt.Sprintf("[%s] %s: %s\n", timestamp, level, formatted)
 	
 	if logFile != nil {
 		logFile.WriteString(line)
 	} else {
-		fmt.Fprintf(os.Stderr, line)
+		fmt.Fprint(os.Stderr, line)
+	}
+}
+
+// SetHTTPTrace turns logging of every HTTP exchange on or off
+func SetHTTPTrace(enabled bool) {
+	traceHTTP = enabled
+}
+
+func HTTPTraceEnabled() bool {
+	return traceHTTP
+}
+
+// Trace logs an HTTP exchange when tracing is enabled. Lines are redacted like
+// all log output, but callers should still mask headers with RedactHeaders.
+func Trace(msg string, args ...interface{}) {
+	if traceHTTP {
+		log("TRACE", msg, args...)
 	}
 }
 
@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const redacted = "[REDACTED]"
+
+var redactPatterns = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	// Hugging Face tokens wherever they appear
+	{regexp.MustCompile(`hf_[A-Za-z0-9]{8,}`), redacted},
+	// JSON fields holding credentials
+	{regexp.MustCompile(`("(?:hf_token|token|access_token|api_key|password|secret)"\s*:\s*")[^"]*(")`), "${1}" + redacted + "${2}"},
+	{regexp.MustCompile(`(?i)((?:bearer|basic)\s+)[A-Za-z0-9._~+/=-]+`), "${1}" + redacted},
+	// Credential headers written out as text ("X-Api-Key: ...", "api-key=...")
+	{regexp.MustCompile(`(?i)(\b(?:x-api-key|api-key|apikey|x-auth-token|hf-token)\s*[:=]\s*)[^\s,;"&]+`), "${1}" + redacted},
+	// Credentials in query strings
+	{regexp.MustCompile(`([?&](?:token|access_token|api_key|hf_token)=)[^&\s]+`), "${1}" + redacted},
+}
+
+// minSecretLen keeps short values like "1" or "on" from masking half of every line
+const minSecretLen = 4
+
+var (
+	secretsMu sync.RWMutex
+	secrets   []string
+)
+
+// AddSecrets registers literal values, such as credential header values from
+// config or labels the user asked to hide, that Redact masks wherever they appear
+func AddSecrets(values ...string) {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if len(v) < minSecretLen || containsString(secrets, v) {
+			continue
+		}
+		secrets = append(secrets, v)
+	}
+	// Longest first, so a secret containing another is masked whole
+	sort.Slice(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact masks tokens, credentials and registered secrets in free-form text.
+// Every log line goes through it, as do shared snapshots and exports.
+func Redact(s string) string {
+	for _, p := range redactPatterns {
+		s = p.re.ReplaceAllString(s, p.repl)
+	}
+	secretsMu.RLock()
+	defer secretsMu.RUnlock()
+	for _, v := range secrets {
+		s = strings.ReplaceAll(s, v, redacted)
+	}
+	return s
+}
+
+// RedactURL renders u with any userinfo password and credential query params masked
+func RedactURL(u *url.URL) string {
+	return Redact(u.Redacted())
+}
+
+// RedactRawURL is RedactURL for a URL string, falling back to Redact when it doesn't parse
+func RedactRawURL(raw string) string {
+	if u, err := url.Parse(raw); err == nil {
+		return RedactURL(u)
+	}
+	return Redact(raw)
+}
+
+// SensitiveHeader reports whether a header carries credentials; RedactHeaders masks its value
+func SensitiveHeader(name string) bool {
+	name = strings.ToLower(name)
+	switch name {
+	case "authorization", "proxy-authorization", "cookie", "set-cookie":
+		return true
+	}
+	return strings.Contains(name, "token") || strings.Contains(name, "secret") ||
+		strings.Contains(name, "api-key") || strings.Contains(name, "apikey")
+}
+
+// RedactHeaders formats h as "Name: value" pairs with credential headers masked
+func RedactHeaders(h http.Header) string {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		value := strings.Join(h[name], ", ")
+		if SensitiveHeader(name) {
+			value = redacted
+		} else {
+			value = Redact(value)
+		}
+		parts = append(parts, name+": "+value)
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,283 @@
+// Package watch evaluates conditions like "allocated_vram_percent > 95" on
+// snapshots and decides when a condition that holds should trigger an action.
+package watch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expr is a parsed condition: comparisons of metrics and numbers joined by
+// &&, || and !, e.g. "allocated_vram_percent > 95 && prefix_cache_hit_rate < 40"
+type Expr struct {
+	src  string
+	root node
+}
+
+type node interface {
+	eval(vars map[string]float64) bool
+}
+
+type (
+	orNode  struct{ l, r node }
+	andNode struct{ l, r node }
+	notNode struct{ x node }
+	cmpNode struct {
+		op   string
+		l, r operand
+	}
+)
+
+// operand is a metric name or a number
+type operand struct {
+	metric string
+	value  float64
+}
+
+func (n orNode) eval(vars map[string]float64) bool  { return n.l.eval(vars) || n.r.eval(vars) }
+func (n andNode) eval(vars map[string]float64) bool { return n.l.eval(vars) && n.r.eval(vars) }
+func (n notNode) eval(vars map[string]float64) bool { return !n.x.eval(vars) }
+
+func (n cmpNode) eval(vars map[string]float64) bool {
+	l, r := n.l.get(vars), n.r.get(vars)
+	switch n.op {
+	case ">":
+		return l > r
+	case ">=":
+		return l >= r
+	case "<":
+		return l < r
+	case "<=":
+		return l <= r
+	case "==":
+		return l == r
+	default: // "!="
+		return l != r
+	}
+}
+
+func (o operand) get(vars map[string]float64) float64 {
+	if o.metric != "" {
+		return vars[o.metric]
+	}
+	return o.value
+}
+
+// Parse parses a condition. Metric names must be among Metrics.
+func Parse(src string) (*Expr, error) {
+	toks, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	root, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, fmt.Errorf("unexpected %q at the end of the condition", p.toks[p.pos])
+	}
+	return &Expr{src: strings.TrimSpace(src), root: root}, nil
+}
+
+// Eval reports whether the condition holds for the given metric values
+func (e *Expr) Eval(vars map[string]float64) bool {
+	return e.root.eval(vars)
+}
+
+// Metrics lists the metrics the condition reads, in order of appearance
+func (e *Expr) Metrics() []string {
+	var out []string
+	seen := map[string]bool{}
+	var walk func(n node)
+	add := func(o operand) {
+		if o.metric != "" && !seen[o.metric] {
+			seen[o.metric] = true
+			out = append(out, o.metric)
+		}
+	}
+	walk = func(n node) {
+		switch n := n.(type) {
+		case orNode:
+			walk(n.l)
+			walk(n.r)
+		case andNode:
+			walk(n.l)
+			walk(n.r)
+		case notNode:
+			walk(n.x)
+		case cmpNode:
+			add(n.l)
+			add(n.r)
+		}
+	}
+	walk(e.root)
+	return out
+}
+
+func (e *Expr) String() string { return e.src }
+
+// tokenize splits src into identifiers, numbers, operators and parentheses
+func tokenize(src string) ([]string, error) {
+	var toks []string
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(' || c == ')':
+			toks = append(toks, string(c))
+			i++
+		case strings.ContainsRune("<>=!&|", c):
+			j := i + 1
+			if j < len(src) && strings.ContainsRune("=&|", rune(src[j])) {
+				j++
+			}
+			op := src[i:j]
+			switch op {
+			case "<", "<=", ">", ">=", "==", "!=", "!", "&&", "||":
+			case "=":
+				op = "=="
+			default:
+				return nil, fmt.Errorf("unknown operator %q", op)
+			}
+			toks = append(toks, op)
+			i = j
+		case c == '_' || c == '.' || c == '-' || unicode.IsLetter(c) || unicode.IsDigit(c):
+			j := i + 1
+			// A number's exponent may carry a sign, as in 1e-5
+			number := c == '.' || c == '-' || unicode.IsDigit(c)
+			for j < len(src) && (src[j] == '_' || src[j] == '.' || unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j])) ||
+				number && (src[j] == '-' || src[j] == '+') && (src[j-1] == 'e' || src[j-1] == 'E')) {
+				j++
+			}
+			toks = append(toks, src[i:j])
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected %q in the condition", c)
+		}
+	}
+	if len(toks) == 0 {
+		return nil, fmt.Errorf("empty condition")
+	}
+	return toks, nil
+}
+
+type parser struct {
+	toks []string
+	pos  int
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos]
+	}
+	return ""
+}
+
+func (p *parser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *parser) or() (node, error) {
+	l, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t == "||" || t == "or"; t = p.peek() {
+		p.next()
+		r, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		l = orNode{l, r}
+	}
+	return l, nil
+}
+
+func (p *parser) and() (node, error) {
+	l, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t == "&&" || t == "and"; t = p.peek() {
+		p.next()
+		r, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		l = andNode{l, r}
+	}
+	return l, nil
+}
+
+func (p *parser) unary() (node, error) {
+	switch p.peek() {
+	case "!", "not":
+		p.next()
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{x}, nil
+	case "(":
+		p.next()
+		x, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t != ")" {
+			return nil, fmt.Errorf("expected ) but got %q", t)
+		}
+		return x, nil
+	}
+	return p.cmp()
+}
+
+func (p *parser) cmp() (node, error) {
+	l, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	op := p.next()
+	switch op {
+	case "<", "<=", ">", ">=", "==", "!=":
+	case "":
+		return nil, fmt.Errorf("expected a comparison after %q, e.g. %s > 90", l.String(), l.String())
+	default:
+		return nil, fmt.Errorf("expected a comparison operator but got %q", op)
+	}
+	r, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	return cmpNode{op: op, l: l, r: r}, nil
+}
+
+func (p *parser) operand() (operand, error) {
+	t := p.next()
+	if t == "" {
+		return operand{}, fmt.Errorf("unexpected end of the condition")
+	}
+	if v, err := strconv.ParseFloat(t, 64); err == nil {
+		return operand{value: v}, nil
+	}
+	if strings.ContainsAny(t[:1], "<>=!&|()") {
+		return operand{}, fmt.Errorf("expected a metric or number but got %q", t)
+	}
+	if _, ok := metrics[t]; !ok {
+		return operand{}, fmt.Errorf("unknown metric %q (have: %s)", t, strings.Join(Metrics(), ", "))
+	}
+	return operand{metric: t}, nil
+}
+
+func (o operand) String() string {
+	if o.metric != "" {
+		return o.metric
+	}
+	return strconv.FormatFloat(o.value, 'g', -1, 64)
+}
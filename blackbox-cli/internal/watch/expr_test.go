@@ -0,0 +1,93 @@
+package watch
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	vars := map[string]float64{
+		"allocated_vram_percent": 96,
+		"prefix_cache_hit_rate":  30,
+		"models":                 2,
+		"ttft_ms":                0.00002,
+	}
+
+	tests := []struct {
+		src     string
+		want    bool
+		metrics []string
+	}{
+		{"allocated_vram_percent > 95", true, []string{"allocated_vram_percent"}},
+		{"allocated_vram_percent >= 96", true, []string{"allocated_vram_percent"}},
+		{"allocated_vram_percent < 96", false, []string{"allocated_vram_percent"}},
+		{"allocated_vram_percent <= 96", true, []string{"allocated_vram_percent"}},
+		{"models == 2", true, []string{"models"}},
+		{"models = 2", true, []string{"models"}},
+		{"models != 2", false, []string{"models"}},
+		{"95 < allocated_vram_percent", true, []string{"allocated_vram_percent"}},
+		{"allocated_vram_percent > 95 && prefix_cache_hit_rate < 40", true, []string{"allocated_vram_percent", "prefix_cache_hit_rate"}},
+		{"allocated_vram_percent > 99 || models > 1", true, []string{"allocated_vram_percent", "models"}},
+		{"allocated_vram_percent > 95 and models > 2", false, []string{"allocated_vram_percent", "models"}},
+		{"models > 5 or models < 3", true, []string{"models"}},
+		{"!(models > 1)", false, []string{"models"}},
+		{"not models > 5", true, []string{"models"}},
+		// && binds tighter than ||
+		{"models > 5 && models > 0 || models == 2", true, []string{"models"}},
+		{"models > 5 && (models > 0 || models == 2)", false, []string{"models"}},
+		{"models>1&&models<3", true, []string{"models"}},
+		{"ttft_ms > 1e-5", true, []string{"ttft_ms"}},
+		{"ttft_ms < 2.5E+1", true, []string{"ttft_ms"}},
+		{"prefix_cache_hit_rate > -1.5", true, []string{"prefix_cache_hit_rate"}},
+		{"prefix_cache_hit_rate > .5", true, []string{"prefix_cache_hit_rate"}},
+		{"used_kv_cache_gb > 1", false, []string{"used_kv_cache_gb"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			e, err := Parse(tt.src)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if got := e.Eval(vars); got != tt.want {
+				t.Errorf("Eval = %t, want %t", got, tt.want)
+			}
+			if got := e.Metrics(); !slices.Equal(got, tt.metrics) {
+				t.Errorf("Metrics = %v, want %v", got, tt.metrics)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"", "empty condition"},
+		{"   ", "empty condition"},
+		{"vram > 95", `unknown metric "vram"`},
+		{"models", "expected a comparison after"},
+		{"models 5", "expected a comparison operator"},
+		{"models >", "unexpected end of the condition"},
+		{"models > 1 &", `unknown operator "&"`},
+		{"models => 1", `expected a metric or number but got ">"`},
+		{"models > (1)", `expected a metric or number but got "("`},
+		{"(models > 1", "expected ) but got"},
+		{"models > 1)", `unexpected ")" at the end`},
+		{"models > 1 models", `unexpected "models" at the end`},
+		{"models > 1e", `unknown metric "1e"`},
+		{"models > $1", "unexpected '$'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := Parse(tt.src)
+			if err == nil {
+				t.Fatalf("Parse succeeded, want an error containing %q", tt.want)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %q, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package watch
+
+import (
+	"sort"
+
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+)
+
+const gib = 1024 * 1024 * 1024
+
+// metrics are the values a condition can read from a snapshot. GB figures
+// are GiB like the dashboard's; percentages are 0-100.
+var metrics = map[string]func(s *model.Snapshot) float64{
+	"allocated_vram_percent":       func(s *model.Snapshot) float64 { return percent(s.AllocatedVRAMBytes, s.TotalVRAMBytes) },
+	"used_kv_cache_percent":        func(s *model.Snapshot) float64 { return percent(s.UsedKVCacheBytes, s.TotalVRAMBytes) },
+	"allocated_vram_gb":            func(s *model.Snapshot) float64 { return float64(s.AllocatedVRAMBytes) / gib },
+	"used_kv_cache_gb":             func(s *model.Snapshot) float64 { return float64(s.UsedKVCacheBytes) / gib },
+	"free_vram_gb":                 func(s *model.Snapshot) float64 { return float64(s.TotalVRAMBytes-s.AllocatedVRAMBytes) / gib },
+	"total_vram_gb":                func(s *model.Snapshot) float64 { return float64(s.TotalVRAMBytes) / gib },
+	"prefix_cache_hit_rate":        func(s *model.Snapshot) float64 { return s.PrefixCacheHitRate },
+	"models":                       func(s *model.Snapshot) float64 { return float64(len(s.Models)) },
+	"gpu_temperature_c":            func(s *model.Snapshot) float64 { return s.Telemetry().TemperatureC },
+	"gpu_power_w":                  func(s *model.Snapshot) float64 { return s.Telemetry().PowerWatts },
+	"gpu_utilization_percent":      func(s *model.Snapshot) float64 { return s.Telemetry().UtilizationPercent },
+	"ttft_ms":                      func(s *model.Snapshot) float64 { ttft, _ := s.Latency(); return ttft * 1000 },
+	"inter_token_latency_ms":       func(s *model.Snapshot) float64 { _, itl := s.Latency(); return itl * 1000 },
+	"generation_tokens_per_second": func(s *model.Snapshot) float64 { return s.Throughput() },
+}
+
+func percent(part, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
+
+// Metrics lists the metric names a condition may use, sorted
+func Metrics() []string {
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Values reads every metric from s
+func Values(s *model.Snapshot) map[string]float64 {
+	vars := make(map[string]float64, len(metrics))
+	for name, f := range metrics {
+		vars[name] = f(s)
+	}
+	return vars
+}
@@ -0,0 +1,42 @@
+package watch
+
+import "time"
+
+// Trigger decides when a condition that holds fires its action. It fires
+// when the condition has held for For, once per stretch of time it holds;
+// with Repeat set it fires again every Repeat while it keeps holding.
+// Cooldown is the least time between two firings. One Trigger follows one
+// endpoint; the zero Trigger fires as soon as the condition holds.
+type Trigger struct {
+	For      time.Duration
+	Repeat   time.Duration
+	Cooldown time.Duration
+
+	since time.Time // When the condition started holding; zero while it doesn't
+	fired time.Time // Last firing
+}
+
+// Observe records whether the condition holds at now and reports whether the action should run
+func (t *Trigger) Observe(holds bool, now time.Time) bool {
+	if !holds {
+		t.since = time.Time{}
+		return false
+	}
+	if t.since.IsZero() {
+		t.since = now
+	}
+	if now.Sub(t.since) < t.For {
+		return false
+	}
+	if !t.fired.IsZero() {
+		if now.Sub(t.fired) < t.Cooldown {
+			return false
+		}
+		// Already fired since the condition started holding
+		if !t.fired.Before(t.since) && (t.Repeat <= 0 || now.Sub(t.fired) < t.Repeat) {
+			return false
+		}
+	}
+	t.fired = now
+	return true
+}
@@ -0,0 +1,181 @@
+package webui
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/maxdcmn/blackbox-cli/internal/client"
+	"github.com/maxdcmn/blackbox-cli/internal/model"
+	"github.com/maxdcmn/blackbox-cli/internal/utils"
+)
+
+//go:embed index.html
+var indexHTML []byte
+
+// Source is one endpoint the server polls
+type Source struct {
+	Name    string
+	BaseURL string
+	Client  client.MetricsClient
+	Timeout time.Duration
+	// Interval and HistorySize override the server's for this source when set
+	Interval    time.Duration
+	HistorySize int
+}
+
+// Sample is one point of an endpoint's history
+type Sample struct {
+	Time               time.Time `json:"time"`
+	AllocatedVRAMBytes int64     `json:"allocated_vram_bytes"`
+	UsedKVCacheBytes   int64     `json:"used_kv_cache_bytes"`
+	PrefixCacheHitRate float64   `json:"prefix_cache_hit_rate"`
+}
+
+// EndpointStatus is what /api/fleet reports for one endpoint
+type EndpointStatus struct {
+	Name      string          `json:"name"`
+	BaseURL   string          `json:"base_url"`
+	Updated   time.Time       `json:"updated,omitempty"`
+	Error     string          `json:"error,omitempty"`
+	LastOK    time.Time       `json:"last_ok,omitempty"`
+	Snapshot  *model.Snapshot `json:"snapshot,omitempty"`
+	History   []Sample        `json:"history"`
+	PollCount int             `json:"poll_count"`
+	FailCount int             `json:"fail_count"`
+}
+
+// Server polls every source in the background and serves the cached results
+// read-only: the page never reaches an endpoint itself, so it can't deploy,
+// spin down or leak per-endpoint credentials.
+type Server struct {
+	sources     []Source
+	interval    time.Duration
+	historySize int
+
+	mu     sync.RWMutex
+	status []EndpointStatus
+}
+
+func New(sources []Source, interval time.Duration, historySize int) *Server {
+	if historySize <= 0 {
+		historySize = 120
+	}
+	s := &Server{sources: sources, interval: interval, historySize: historySize}
+	s.status = make([]EndpointStatus, len(sources))
+	for i, src := range sources {
+		s.status[i] = EndpointStatus{Name: src.Name, BaseURL: utils.RedactRawURL(src.BaseURL), History: []Sample{}}
+	}
+	return s
+}
+
+// Run polls until ctx is cancelled
+func (s *Server) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := range s.sources {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			interval := s.interval
+			if s.sources[i].Interval > 0 {
+				interval = s.sources[i].Interval
+			}
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				s.poll(ctx, i)
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func (s *Server) poll(ctx context.Context, i int) {
+	src := s.sources[i]
+	pctx, cancel := context.WithTimeout(ctx, src.Timeout)
+	snap, err := src.Client.Snapshot(pctx)
+	cancel()
+	if ctx.Err() != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := &s.status[i]
+	st.Updated = time.Now()
+	st.PollCount++
+	if err != nil {
+		st.FailCount++
+		st.Error = err.Error()
+		utils.Debug("serve-ui: %s poll failed: %v", src.Name, err)
+		return
+	}
+	st.Error = ""
+	st.LastOK = st.Updated
+	st.Snapshot = snap
+	st.History = append(st.History, Sample{
+		Time:               st.Updated,
+		AllocatedVRAMBytes: snap.AllocatedVRAMBytes,
+		UsedKVCacheBytes:   snap.UsedKVCacheBytes,
+		PrefixCacheHitRate: snap.PrefixCacheHitRate,
+	})
+	size := s.historySize
+	if src.HistorySize > 0 {
+		size = src.HistorySize
+	}
+	if len(st.History) > size {
+		st.History = st.History[len(st.History)-size:]
+	}
+}
+
+// Handler serves the page at / and the cached fleet state at /api/fleet
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		if !readOnly(w, r) {
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(indexHTML)
+	})
+	mux.HandleFunc("/api/fleet", func(w http.ResponseWriter, r *http.Request) {
+		if !readOnly(w, r) {
+			return
+		}
+		s.mu.RLock()
+		body, err := json.Marshal(struct {
+			Interval  string           `json:"interval"`
+			Endpoints []EndpointStatus `json:"endpoints"`
+		}{s.interval.String(), s.status})
+		s.mu.RUnlock()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.Write(body)
+	})
+	return mux
+}
+
+func readOnly(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return true
+	}
+	w.Header().Set("Allow", "GET, HEAD")
+	http.Error(w, "read-only", http.StatusMethodNotAllowed)
+	return false
+}
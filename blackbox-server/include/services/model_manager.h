@@ -33,6 +33,11 @@ struct ModelListResponse {
     int max_allowed;
 };
 
+struct ContainerLogLine {
+    std::string time;  // RFC 3339 timestamp from docker, empty when the line had none
+    std::string text;
+};
+
 struct OptimizationResult {
     bool optimized;
     std::vector<std::string> restarted_models;
@@ -47,6 +52,8 @@ bool canDeployModel();
 int getNextAvailablePort(int preferred_port = 0);
 std::string getContainerName(const std::string& model_id);
 bool spindownModel(const std::string& model_id_or_container);
+std::string resolveModelContainer(const std::string& model_id_or_container);
+std::vector<ContainerLogLine> getContainerLogs(const std::string& container, int tail, const std::string& since);
 void updateModelVRAMUsage(const std::string& container_name, double vram_percent);
 void registerModelDeployment(const std::string& model_id, const std::string& container_name, 
                              double configured_max_gpu_utilization, const std::string& gpu_type, unsigned int pid);
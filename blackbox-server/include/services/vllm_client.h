@@ -13,6 +13,9 @@ struct ModelBlockData {
     double prefix_cache_hit_rate;
     unsigned int num_requests_running;
     unsigned int num_requests_waiting;
+    double ttft_seconds;                 // Mean time to first token since the model started
+    double inter_token_latency_seconds;  // Mean time between output tokens since the model started
+    double generation_tokens_per_second; // Tokens generated per second since the previous fetch
     bool available;
 };
 
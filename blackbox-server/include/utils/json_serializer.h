@@ -3,6 +3,10 @@
 #include "vram_types.h"
 #include <string>
 
+// Snapshot schema version of the responses, sent as schema_version. Bump it
+// when a field is renamed or removed, so clients know to translate.
+static const int SCHEMA_VERSION = 2;
+
 std::string createDetailedResponse(const DetailedVRAMInfo& info);
 std::string createAggregatedResponse(const AggregatedVRAMInfo& info);
 
@@ -44,6 +44,22 @@ struct ModelVRAMInfo {
     int port;
     unsigned long long allocated_vram_bytes;  // VRAM allocated for this model
     unsigned long long used_kv_cache_bytes;   // Actual used KV cache bytes for this model
+    double ttft_seconds;                      // Mean time to first token (0 when not reported)
+    double inter_token_latency_seconds;       // Mean time between output tokens (0 when not reported)
+    double generation_tokens_per_second;      // Tokens generated per second (0 when not reported)
+    unsigned int num_requests_running;        // Requests this model is serving
+    unsigned int num_requests_waiting;        // Requests queued for this model
+};
+
+struct GPUInfo {
+    unsigned int index;
+    std::string name;
+    unsigned long long total_vram_bytes;
+    unsigned long long allocated_vram_bytes;
+    double utilization_percent;  // SM utilization (0.0-100.0)
+    double temperature_c;        // Core temperature
+    double power_watts;          // Power draw
+    double power_limit_watts;    // Enforced power limit
 };
 
 struct DetailedVRAMInfo {
@@ -63,6 +79,7 @@ struct DetailedVRAMInfo {
     unsigned long long used_kv_cache_bytes;  // Total actual used KV cache bytes (sum across all models)
     double prefix_cache_hit_rate;            // Prefix cache hit rate (0.0-100.0)
     std::vector<ModelVRAMInfo> models;        // Per-model breakdown
+    std::vector<GPUInfo> gpus;                // The monitored device; empty without NVML
 };
 
 struct VLLMBlockData {
@@ -90,6 +107,7 @@ struct AggregatedVRAMInfo {
     AggregatedStats num_requests_running;
     AggregatedStats num_requests_waiting;
     std::vector<ModelVRAMInfo> models;
+    std::vector<GPUInfo> gpus;  // Latest reading
     unsigned long long window_seconds;
     unsigned int sample_count;
 };
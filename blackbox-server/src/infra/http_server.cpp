@@ -5,6 +5,7 @@
 #include "utils/logger.h"
 #include "services/deploy_service.h"
 #include "services/spindown_service.h"
+#include "services/logs_service.h"
 #include "services/optimization_service.h"
 #include "services/model_manager.h"
 #include "services/vram_tracker.h"
@@ -206,6 +207,10 @@ void handleRequest(http::request<http::string_body>& req, tcp::socket& socket) {
             LOG_DEBUG("Listing deployed models");
             handleListModelsRequest(req, socket);
             return;
+        } else if (target == "/logs" || target.find("/logs?") == 0) {
+            LOG_DEBUG("Reading model container logs");
+            handleLogsRequest(req, socket);
+            return;
         }
     } else if (req.method() == http::verb::post) {
         if (target == "/deploy") {
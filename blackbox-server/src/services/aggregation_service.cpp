@@ -116,6 +116,7 @@ AggregatedVRAMInfo collectAggregatedMetrics(unsigned int window_seconds) {
             result.models.push_back(model);
         }
     }
+    result.gpus = final_info.gpus;
     
     return result;
 }
@@ -0,0 +1,101 @@
+#include "services/logs_service.h"
+#include "services/model_manager.h"
+#include "utils/logger.h"
+#include <nlohmann/json.hpp>
+#include <boost/beast/core.hpp>
+#include <boost/beast/http.hpp>
+#include <regex>
+#include <string>
+
+static const int DEFAULT_LOG_TAIL = 200;
+static const int MAX_LOG_TAIL = 1000;
+
+// Returns the value of name in the target's query string, empty when it's missing
+static std::string queryParam(const std::string& target, const std::string& name) {
+    size_t query_pos = target.find('?');
+    if (query_pos == std::string::npos) return "";
+    std::regex param_regex("(?:^|&)" + name + "=([^&]*)");
+    std::smatch match;
+    std::string query = target.substr(query_pos + 1);
+    if (std::regex_search(query, match, param_regex)) {
+        return match[1].str();
+    }
+    return "";
+}
+
+static void writeResponse(http::response<http::string_body>& res, tcp::socket& socket) {
+    res.prepare_payload();
+    try {
+        http::write(socket, res);
+    } catch (const boost::system::system_error& e) {
+        auto ec = e.code();
+        if (ec == boost::asio::error::broken_pipe || 
+            ec == boost::asio::error::connection_reset ||
+            ec == boost::asio::error::eof) {
+            return;
+        }
+        throw;
+    }
+}
+
+void handleLogsRequest(http::request<http::string_body>& req, tcp::socket& socket) {
+    std::string target = std::string(req.target());
+    std::string model = queryParam(target, "model");
+    std::string since = queryParam(target, "since");
+    
+    http::response<http::string_body> res;
+    res.version(req.version());
+    res.keep_alive(req.keep_alive());
+    res.set(http::field::content_type, "application/json");
+    
+    nlohmann::json response_json;
+    // Only plain names and Unix timestamps reach the docker command line
+    if (model.empty() || !std::regex_match(model, std::regex("[A-Za-z0-9_.-]+")) ||
+        (!since.empty() && !std::regex_match(since, std::regex(R"(\d+(\.\d+)?)")))) {
+        res.result(http::status::bad_request);
+        response_json["success"] = false;
+        response_json["message"] = "model (a model ID, container name or container ID) is required; since must be a Unix timestamp";
+        res.body() = response_json.dump();
+        writeResponse(res, socket);
+        return;
+    }
+    
+    int tail = DEFAULT_LOG_TAIL;
+    std::string tail_str = queryParam(target, "tail");
+    if (!tail_str.empty()) {
+        try {
+            tail = std::stoi(tail_str);
+            if (tail < 1) tail = 1;
+            if (tail > MAX_LOG_TAIL) tail = MAX_LOG_TAIL;
+        } catch (...) {
+            tail = DEFAULT_LOG_TAIL;
+        }
+    }
+    
+    std::string container = resolveModelContainer(model);
+    if (container.empty()) {
+        res.result(http::status::not_found);
+        response_json["success"] = false;
+        response_json["message"] = "No model container found for: " + model;
+        res.body() = response_json.dump();
+        writeResponse(res, socket);
+        return;
+    }
+    
+    LOG_DEBUG("Reading logs of " + container + " (tail " + std::to_string(tail) + (since.empty() ? "" : ", since " + since) + ")");
+    nlohmann::json lines = nlohmann::json::array();
+    for (const auto& line : getContainerLogs(container, tail, since)) {
+        nlohmann::json line_json;
+        if (!line.time.empty()) {
+            line_json["time"] = line.time;
+        }
+        line_json["text"] = line.text;
+        lines.push_back(line_json);
+    }
+    
+    res.result(http::status::ok);
+    response_json["container_name"] = container;
+    response_json["lines"] = lines;
+    res.body() = response_json.dump();
+    writeResponse(res, socket);
+}
@@ -5,6 +5,7 @@
 #include "utils/logger.h"
 #include <cstdio>
 #include <cstdlib>
+#include <cctype>
 #include <sstream>
 #include <string>
 #include <regex>
@@ -150,6 +151,79 @@ std::vector<DeployedModel> listDeployedModels() {
     return models;
 }
 
+// Resolves a model ID, container name or container ID to the name of its vLLM
+// container, including stopped and restarting ones. Returns empty for anything
+// else, so only model containers' logs can be read.
+std::string resolveModelContainer(const std::string& model_id_or_container) {
+    if (model_id_or_container.empty()) return "";
+    for (const auto& model : listDeployedModels()) {
+        if (model.container_id == model_id_or_container || model.container_name == model_id_or_container ||
+            model.model_id == model_id_or_container) {
+            return model.container_name;
+        }
+    }
+    std::string container_name = model_id_or_container.find("vllm-") == 0
+        ? "vllm-" + std::regex_replace(model_id_or_container.substr(5), std::regex("[^a-zA-Z0-9]"), "-")
+        : getContainerName(model_id_or_container);
+    
+    std::string docker_cmd = getDockerCmd();
+    std::string cmd = absl::StrCat("timeout 5 ", docker_cmd, " ps -a --filter name=^", container_name, "$ --format {{.Names}} 2>/dev/null");
+    FILE* pipe = popen(cmd.c_str(), "r");
+    if (!pipe) return "";
+    
+    char buffer[256];
+    std::string found;
+    if (fgets(buffer, sizeof(buffer), pipe)) {
+        found = buffer;
+        found.erase(found.find_last_not_of(" \t\n\r") + 1);
+    }
+    pclose(pipe);
+    return found == container_name ? found : "";
+}
+
+// Reads a container's latest log lines with docker's timestamps, stdout and
+// stderr interleaved. since is a Unix timestamp, empty for the last tail lines.
+std::vector<ContainerLogLine> getContainerLogs(const std::string& container, int tail, const std::string& since) {
+    std::vector<ContainerLogLine> lines;
+    
+    std::string docker_cmd = getDockerCmd();
+    std::string cmd = absl::StrCat("timeout 5 ", docker_cmd, " logs --timestamps --tail ", tail);
+    if (!since.empty()) {
+        cmd = absl::StrCat(cmd, " --since ", since);
+    }
+    cmd = absl::StrCat(cmd, " ", container, " 2>&1");
+    FILE* pipe = popen(cmd.c_str(), "r");
+    if (!pipe) return lines;
+    
+    char buffer[4096];
+    std::string pending;
+    auto flush = [&]() {
+        if (!pending.empty() && pending.back() == '\r') pending.pop_back();
+        ContainerLogLine line;
+        size_t space = pending.find(' ');
+        // docker puts "2024-01-15T10:30:00.123456789Z " in front of every line
+        if (space != std::string::npos && space >= 20 && std::isdigit(static_cast<unsigned char>(pending[0])) && pending[4] == '-') {
+            line.time = pending.substr(0, space);
+            line.text = pending.substr(space + 1);
+        } else {
+            line.text = pending;
+        }
+        lines.push_back(line);
+        pending.clear();
+    };
+    while (fgets(buffer, sizeof(buffer), pipe)) {
+        pending += buffer;
+        // Lines longer than the buffer come in several reads
+        if (pending.back() != '\n') continue;
+        pending.pop_back();
+        flush();
+    }
+    if (!pending.empty()) flush();
+    pclose(pipe);
+    
+    return lines;
+}
+
 bool isModelDeployed(const std::string& model_id) {
     std::string container_name = getContainerName(model_id);
     
@@ -85,7 +85,7 @@ void shutdownNVML() {
 }
 
 DetailedVRAMInfo getDetailedVRAMUsage() {
-    DetailedVRAMInfo detailed = {0, 0, 0, 0, {}, {}, {}, 0, 0, 0, 0ULL, 0.0, {}, 0ULL, 0.0, {}};
+    DetailedVRAMInfo detailed = {0, 0, 0, 0, {}, {}, {}, 0, 0, 0, 0ULL, 0.0, {}, 0ULL, 0.0, {}, {}};
     if (!initNVML()) {
         return detailed;
     }
@@ -98,6 +98,31 @@ DetailedVRAMInfo getDetailedVRAMUsage() {
         detailed.reserved = memory.used;
     }
 
+    // Thermals, power and SM utilization of the same device; readings it
+    // doesn't support stay 0
+    GPUInfo gpu{0, "", detailed.total, detailed.used, 0.0, 0.0, 0.0, 0.0};
+    nvmlDeviceGetIndex(g_device, &gpu.index);
+    char gpu_name[NVML_DEVICE_NAME_BUFFER_SIZE] = {0};
+    if (nvmlDeviceGetName(g_device, gpu_name, sizeof(gpu_name)) == NVML_SUCCESS) {
+        gpu.name = gpu_name;
+    }
+    nvmlUtilization_t utilization;
+    if (nvmlDeviceGetUtilizationRates(g_device, &utilization) == NVML_SUCCESS) {
+        gpu.utilization_percent = utilization.gpu;
+    }
+    unsigned int temperature = 0;
+    if (nvmlDeviceGetTemperature(g_device, NVML_TEMPERATURE_GPU, &temperature) == NVML_SUCCESS) {
+        gpu.temperature_c = temperature;
+    }
+    unsigned int milliwatts = 0;
+    if (nvmlDeviceGetPowerUsage(g_device, &milliwatts) == NVML_SUCCESS) {
+        gpu.power_watts = milliwatts / 1000.0;
+    }
+    if (nvmlDeviceGetEnforcedPowerLimit(g_device, &milliwatts) == NVML_SUCCESS) {
+        gpu.power_limit_watts = milliwatts / 1000.0;
+    }
+    detailed.gpus.push_back(gpu);
+
     unsigned int processCount = 64;
     nvmlProcessInfo_t processes[64];
     unsigned long long total_atomic_allocations = 0;
@@ -215,6 +240,11 @@ DetailedVRAMInfo getDetailedVRAMUsage() {
         model_info.port = model_data.port;
         model_info.allocated_vram_bytes = 0;
         model_info.used_kv_cache_bytes = 0;
+        model_info.ttft_seconds = model_data.ttft_seconds;
+        model_info.inter_token_latency_seconds = model_data.inter_token_latency_seconds;
+        model_info.generation_tokens_per_second = model_data.generation_tokens_per_second;
+        model_info.num_requests_running = model_data.num_requests_running;
+        model_info.num_requests_waiting = model_data.num_requests_waiting;
         
         LOG_DEBUG("Processing model " + model_data.model_id + ": available=" + (model_data.available ? "true" : "false") + 
                  ", num_gpu_blocks=" + std::to_string(model_data.num_gpu_blocks) +
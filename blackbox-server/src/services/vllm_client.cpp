@@ -5,10 +5,59 @@
 #include <cstdio>
 #include <cstdlib>
 #include <cctype>
+#include <chrono>
 #include <iostream>
+#include <map>
+#include <mutex>
 #include <string>
 #include <sstream>
 
+// Reads the value of a Prometheus sample line: the number after the labels
+static bool parseSampleValue(const std::string& line_str, double& value) {
+    size_t brace = line_str.find_last_of('}');
+    if (brace == std::string::npos || brace + 1 >= line_str.length()) {
+        return false;
+    }
+    try {
+        value = std::stod(line_str.substr(brace + 1));
+        return true;
+    } catch (...) {
+        return false;
+    }
+}
+
+// The last generation_tokens_total read per port, to turn the counter into a rate
+struct GenerationCounter {
+    double tokens;
+    std::chrono::steady_clock::time_point at;
+    double rate;
+};
+static std::map<int, GenerationCounter> g_generation_counters;
+static std::mutex g_generation_mutex;
+
+// Tokens generated per second on port since the previous read; 0 on the first
+// read and after the counter resets (the model restarted). Reads closer together
+// than the minimum interval keep the last rate instead of a noisy one.
+static double generationRate(int port, double tokens) {
+    constexpr double min_interval_seconds = 0.25;
+    auto now = std::chrono::steady_clock::now();
+    std::lock_guard<std::mutex> lock(g_generation_mutex);
+    auto it = g_generation_counters.find(port);
+    if (it == g_generation_counters.end() || tokens < it->second.tokens) {
+        g_generation_counters[port] = GenerationCounter{tokens, now, 0.0};
+        return 0.0;
+    }
+    GenerationCounter& prev = it->second;
+    double elapsed = std::chrono::duration<double>(now - prev.at).count();
+    if (elapsed < min_interval_seconds) {
+        return prev.rate;
+    }
+    prev.rate = (tokens - prev.tokens) / elapsed;
+    prev.tokens = tokens;
+    prev.at = now;
+    return prev.rate;
+}
+
 VLLMBlockData fetchVLLMBlockData() {
     VLLMBlockData data{0, 0, 0.0, 0.0, false};
     
@@ -232,6 +281,9 @@ std::vector<ModelBlockData> fetchPerModelBlockData() {
         model_data.prefix_cache_hit_rate = 0.0;
         model_data.num_requests_running = 0;
         model_data.num_requests_waiting = 0;
+        model_data.ttft_seconds = 0.0;
+        model_data.inter_token_latency_seconds = 0.0;
+        model_data.generation_tokens_per_second = 0.0;
         model_data.available = false;
         
         // Use timeout wrapper to ensure curl doesn't hang
@@ -258,6 +310,10 @@ std::vector<ModelBlockData> fetchPerModelBlockData() {
         unsigned long long cache_query_hit = 0;
         unsigned int requests_running = 0;
         unsigned int requests_waiting = 0;
+        double ttft_sum = 0.0, ttft_count = 0.0;
+        double itl_sum = 0.0, itl_count = 0.0;
+        double tpot_sum = 0.0, tpot_count = 0.0;
+        double generation_tokens = 0.0;
         bool found_cache_config = false;
         
         while (fgets(line, sizeof(line), curl)) {
@@ -384,6 +440,26 @@ std::vector<ModelBlockData> fetchPerModelBlockData() {
                     }
                 }
             }
+            
+            // Latency histograms: only their _sum and _count are needed for the means.
+            // time_per_output_token is the older name of inter_token_latency.
+            if (line_str[0] != '#') {
+                if (line_str.find("vllm:time_to_first_token_seconds_sum") != std::string::npos) {
+                    parseSampleValue(line_str, ttft_sum);
+                } else if (line_str.find("vllm:time_to_first_token_seconds_count") != std::string::npos) {
+                    parseSampleValue(line_str, ttft_count);
+                } else if (line_str.find("vllm:inter_token_latency_seconds_sum") != std::string::npos) {
+                    parseSampleValue(line_str, itl_sum);
+                } else if (line_str.find("vllm:inter_token_latency_seconds_count") != std::string::npos) {
+                    parseSampleValue(line_str, itl_count);
+                } else if (line_str.find("vllm:time_per_output_token_seconds_sum") != std::string::npos) {
+                    parseSampleValue(line_str, tpot_sum);
+                } else if (line_str.find("vllm:time_per_output_token_seconds_count") != std::string::npos) {
+                    parseSampleValue(line_str, tpot_count);
+                } else if (line_str.find("vllm:generation_tokens_total") != std::string::npos) {
+                    parseSampleValue(line_str, generation_tokens);
+                }
+            }
         }
         
         int curl_status = pclose(curl);
@@ -408,6 +484,15 @@ std::vector<ModelBlockData> fetchPerModelBlockData() {
             model_data.prefix_cache_hit_rate = model_prefix_hit_rate;
             model_data.num_requests_running = requests_running;
             model_data.num_requests_waiting = requests_waiting;
+            if (ttft_count > 0) {
+                model_data.ttft_seconds = ttft_sum / ttft_count;
+            }
+            if (itl_count > 0) {
+                model_data.inter_token_latency_seconds = itl_sum / itl_count;
+            } else if (tpot_count > 0) {
+                model_data.inter_token_latency_seconds = tpot_sum / tpot_count;
+            }
+            model_data.generation_tokens_per_second = generationRate(model.port, generation_tokens);
             model_data.available = true;
             LOG_DEBUG("Model " + model.model_id + " metrics: blocks=" + std::to_string(model_blocks) +
                      ", kv_usage=" + std::to_string(model_kv_usage) +
@@ -2,6 +2,52 @@
 #include <sstream>
 #include <iomanip>
 
+// Writes the entries of a models array; latency, throughput and requests only for the models that report them
+static void writeModels(std::ostringstream& oss, const std::vector<ModelVRAMInfo>& models) {
+    for (size_t i = 0; i < models.size(); ++i) {
+        if (i > 0) oss << ",";
+        const auto& model = models[i];
+        oss << R"({"model_id":")" << model.model_id << R"(")"
+            << R"(,"port":)" << model.port
+            << R"(,"allocated_vram_bytes":)" << model.allocated_vram_bytes
+            << R"(,"used_kv_cache_bytes":)" << model.used_kv_cache_bytes;
+        if (model.ttft_seconds > 0.0) {
+            oss << R"(,"ttft_seconds":)" << std::fixed << std::setprecision(4) << model.ttft_seconds;
+        }
+        if (model.inter_token_latency_seconds > 0.0) {
+            oss << R"(,"inter_token_latency_seconds":)" << std::fixed << std::setprecision(4) << model.inter_token_latency_seconds;
+        }
+        if (model.generation_tokens_per_second > 0.0) {
+            oss << R"(,"generation_tokens_per_second":)" << std::fixed << std::setprecision(2) << model.generation_tokens_per_second;
+        }
+        if (model.num_requests_running > 0 || model.num_requests_waiting > 0) {
+            oss << R"(,"num_requests_running":)" << model.num_requests_running
+                << R"(,"num_requests_waiting":)" << model.num_requests_waiting;
+        }
+        oss << "}";
+    }
+}
+
+// Appends ,"gpus":[...] when there are any, so responses without NVML stay as they were
+static void writeGPUs(std::ostringstream& oss, const std::vector<GPUInfo>& gpus) {
+    if (gpus.empty()) return;
+    oss << R"(,"gpus":[)";
+    for (size_t i = 0; i < gpus.size(); ++i) {
+        if (i > 0) oss << ",";
+        const auto& gpu = gpus[i];
+        oss << R"({"index":)" << gpu.index
+            << R"(,"name":")" << gpu.name << R"(")"
+            << R"(,"total_vram_bytes":)" << gpu.total_vram_bytes
+            << R"(,"allocated_vram_bytes":)" << gpu.allocated_vram_bytes
+            << R"(,"utilization_percent":)" << std::fixed << std::setprecision(2) << gpu.utilization_percent
+            << R"(,"temperature_c":)" << gpu.temperature_c
+            << R"(,"power_watts":)" << gpu.power_watts
+            << R"(,"power_limit_watts":)" << gpu.power_limit_watts
+            << "}";
+    }
+    oss << "]";
+}
+
 std::string createDetailedResponse(const DetailedVRAMInfo& info) {
     std::ostringstream oss;
     // Simplified response: total VRAM, allocated VRAM, used KV cache bytes, prefix cache hit rate, and per-model breakdown
@@ -11,17 +57,10 @@ std::string createDetailedResponse(const DetailedVRAMInfo& info) {
         << R"(,"prefix_cache_hit_rate":)" << std::fixed << std::setprecision(2) << info.prefix_cache_hit_rate
         << R"(,"models":[)";
     
-    for (size_t i = 0; i < info.models.size(); ++i) {
-        if (i > 0) oss << ",";
-        const auto& model = info.models[i];
-        oss << R"({"model_id":")" << model.model_id << R"(")"
-            << R"(,"port":)" << model.port
-            << R"(,"allocated_vram_bytes":)" << model.allocated_vram_bytes
-            << R"(,"used_kv_cache_bytes":)" << model.used_kv_cache_bytes
-            << "}";
-    }
-    
-    oss << "]}";
+    writeModels(oss, info.models);
+    oss << "]";
+    writeGPUs(oss, info.gpus);
+    oss << R"(,"schema_version":)" << SCHEMA_VERSION << "}";
     return oss.str();
 }
 
@@ -64,17 +103,10 @@ std::string createAggregatedResponse(const AggregatedVRAMInfo& info) {
         << R"(,"count":)" << info.num_requests_waiting.count << "}"
         << R"(,"models":[)";
     
-    for (size_t i = 0; i < info.models.size(); ++i) {
-        if (i > 0) oss << ",";
-        const auto& model = info.models[i];
-        oss << R"({"model_id":")" << model.model_id << R"(")"
-            << R"(,"port":)" << model.port
-            << R"(,"allocated_vram_bytes":)" << model.allocated_vram_bytes
-            << R"(,"used_kv_cache_bytes":)" << model.used_kv_cache_bytes
-            << "}";
-    }
-    
-    oss << "]}";
+    writeModels(oss, info.models);
+    oss << "]";
+    writeGPUs(oss, info.gpus);
+    oss << R"(,"schema_version":)" << SCHEMA_VERSION << "}";
     return oss.str();
 }
 